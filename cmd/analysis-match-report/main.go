@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	ID1 string `help:"Full ID of a submission (or a corpus entry)." arg:""`
+	ID2 string `help:"Full ID of another submission (or a corpus entry)." arg:""`
+
+	HTML string `help:"Write an HTML report (with highlighted code) to this path instead of outputting JSON." type:"path"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Show the matching regions of code between two submissions (or a submission and a corpus entry)."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	pairwiseKey, err := analysis.NewMatchReportKey(args.ID1, args.ID2)
+	if err != nil {
+		log.Fatal("Invalid submission IDs.", err)
+	}
+
+	report, err := analysis.PairwiseMatchReport(pairwiseKey)
+	if err != nil {
+		log.Fatal("Failed to build match report.", err, log.NewAttr("submission-ids", pairwiseKey))
+	}
+
+	if args.HTML == "" {
+		fmt.Println(util.MustToJSONIndent(report))
+		return
+	}
+
+	err = util.WriteFile(analysis.RenderMatchReportHTML(report), args.HTML)
+	if err != nil {
+		log.Fatal("Failed to write HTML report.", err, log.NewAttr("path", args.HTML))
+	}
+
+	fmt.Printf("Wrote HTML report to '%s'.\n", args.HTML)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/report"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -17,6 +18,8 @@ var args struct {
 	Course     string `help:"ID of the course." arg:""`
 	Assignment string `help:"ID of the assignment." arg:""`
 	HTML       bool   `help:"Output report as html." default:"false"`
+
+	model.CourseUserFilter
 }
 
 func main() {
@@ -34,7 +37,7 @@ func main() {
 
 	assignment := db.MustGetAssignment(args.Course, args.Assignment)
 
-	report, err := report.GetAssignmentScoringReport(assignment)
+	report, err := report.GetAssignmentScoringReport(assignment, &args.CourseUserFilter)
 	if err != nil {
 		log.Fatal("Failed to get scoring report.", assignment, err)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/email"
 	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/report"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -18,6 +19,8 @@ var args struct {
 	Course string   `help:"ID of the course." arg:""`
 	Email  []string `help:"Email addresses to send the report to (as HTML)." short:"e"`
 	HTML   bool     `help:"Output report as html." default:"false"`
+
+	model.CourseUserFilter
 }
 
 func main() {
@@ -35,7 +38,7 @@ func main() {
 
 	course := db.MustGetCourse(args.Course)
 
-	report, err := report.GetCourseScoringReport(course)
+	report, err := report.GetCourseScoringReport(course, &args.CourseUserFilter)
 	if err != nil {
 		log.Fatal("Failed to get scoring report.", course, err)
 	}
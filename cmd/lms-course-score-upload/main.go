@@ -8,7 +8,7 @@ import (
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/log"
-	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/procedures/grades"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -33,7 +33,7 @@ func main() {
 
 	course := db.MustGetCourse(args.Course)
 
-	result, err := scoring.FullCourseScoringAndUpload(course, args.DryRun)
+	result, err := grades.FullCourseScoringAndUpload(course, args.DryRun)
 	if err != nil {
 		log.Fatal("Failed to score and upload assignment.", err, course)
 	}
@@ -0,0 +1,316 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The minimum number of values needed before a value can be flagged as being outside of the class's distribution.
+const MIN_ANOMALY_DISTRIBUTION_SIZE = 5
+
+type AnomalyOptions struct {
+	// The minimum fraction of a submission's previous code that must be removed for it to be flagged as a code drop.
+	// Non-positive values will use the config default.
+	CodeDropFraction float64 `json:"code-drop-fraction"`
+
+	// The fraction of the max score that counts as near zero (and near full) when flagging score jumps.
+	// Non-positive values will use the config default.
+	NearZeroFraction float64 `json:"near-zero-fraction"`
+
+	// How many interquartile ranges outside of the class's 25th/75th percentiles a value must be to be flagged as an outlier.
+	// Non-positive values will use the config default.
+	OutlierFactor float64 `json:"outlier-factor"`
+}
+
+type anomalyMetric struct {
+	name        string
+	description string
+
+	// Deltas and velocities are only available for submissions that have a previous submission.
+	needsPrevious bool
+
+	// Whether values far outside of the class's distribution should be flagged.
+	checkOutliers bool
+
+	getValue func(*model.IndividualAnalysis) float64
+}
+
+var anomalyMetrics []anomalyMetric = []anomalyMetric{
+	anomalyMetric{
+		name:          "lines-of-code",
+		description:   "Lines of code",
+		checkOutliers: true,
+		getValue: func(result *model.IndividualAnalysis) float64 {
+			return float64(result.LinesOfCode)
+		},
+	},
+	anomalyMetric{
+		name:          "lines-of-code-delta",
+		description:   "Change in lines of code",
+		needsPrevious: true,
+		getValue: func(result *model.IndividualAnalysis) float64 {
+			return float64(result.LinesOfCodeDelta)
+		},
+	},
+	anomalyMetric{
+		name:          "score-delta",
+		description:   "Change in score",
+		needsPrevious: true,
+		getValue: func(result *model.IndividualAnalysis) float64 {
+			return result.ScoreDelta
+		},
+	},
+	anomalyMetric{
+		name:          "lines-of-code-per-hour",
+		description:   "Lines of code per hour",
+		needsPrevious: true,
+		checkOutliers: true,
+		getValue: func(result *model.IndividualAnalysis) float64 {
+			return result.LinesOfCodeVelocity
+		},
+	},
+	anomalyMetric{
+		name:          "score-per-hour",
+		description:   "Score per hour",
+		needsPrevious: true,
+		checkOutliers: true,
+		getValue: func(result *model.IndividualAnalysis) float64 {
+			return result.ScoreVelocity
+		},
+	},
+}
+
+// Flag suspicious events in the submission timelines of individual analysis results:
+// sudden large drops in code, jumps from a near-zero score to a near-full score,
+// and values far outside of the class's distribution.
+// The class's distribution is made up of all the (non-failed) results passed in,
+// so results should include all the submissions for an assignment (or course) that should be compared.
+// Flags are ranked by their severity (most severe first).
+func DetectAnomalies(results []*model.IndividualAnalysis, options AnomalyOptions) []*model.AnomalyFlag {
+	codeDropFraction := options.CodeDropFraction
+	if codeDropFraction <= 0.0 {
+		codeDropFraction = config.ANALYSIS_ANOMALY_CODE_DROP.Get()
+	}
+
+	nearZeroFraction := options.NearZeroFraction
+	if nearZeroFraction <= 0.0 {
+		nearZeroFraction = config.ANALYSIS_ANOMALY_NEAR_ZERO.Get()
+	}
+
+	outlierFactor := options.OutlierFactor
+	if outlierFactor <= 0.0 {
+		outlierFactor = config.ANALYSIS_ANOMALY_OUTLIER_FACTOR.Get()
+	}
+
+	validResults := make([]*model.IndividualAnalysis, 0, len(results))
+	classMaxScore := 0.0
+
+	for _, result := range results {
+		if (result == nil) || result.Failure {
+			continue
+		}
+
+		validResults = append(validResults, result)
+		classMaxScore = max(classMaxScore, result.Score)
+	}
+
+	// {metric: [value, ...], ...}
+	distributions := make(map[string][]float64, len(anomalyMetrics))
+	for _, metric := range anomalyMetrics {
+		values := make([]float64, 0, len(validResults))
+		for _, result := range validResults {
+			if metric.needsPrevious && !hasPreviousSubmission(result) {
+				continue
+			}
+
+			values = append(values, metric.getValue(result))
+		}
+
+		slices.Sort(values)
+		distributions[metric.name] = values
+	}
+
+	flags := make([]*model.AnomalyFlag, 0)
+
+	for _, result := range validResults {
+		flag := checkCodeDrop(result, codeDropFraction, distributions)
+		if flag != nil {
+			flags = append(flags, flag)
+		}
+
+		flag = checkScoreJump(result, nearZeroFraction, classMaxScore, distributions)
+		if flag != nil {
+			flags = append(flags, flag)
+		}
+
+		flags = append(flags, checkOutliers(result, outlierFactor, distributions)...)
+	}
+
+	slices.SortFunc(flags, func(a *model.AnomalyFlag, b *model.AnomalyFlag) int {
+		if a.Severity != b.Severity {
+			if a.Severity > b.Severity {
+				return -1
+			}
+
+			return 1
+		}
+
+		if a.SubmissionID != b.SubmissionID {
+			return strings.Compare(a.SubmissionID, b.SubmissionID)
+		}
+
+		if a.Type != b.Type {
+			return strings.Compare(string(a.Type), string(b.Type))
+		}
+
+		return strings.Compare(a.Metric, b.Metric)
+	})
+
+	for i, flag := range flags {
+		flag.Rank = i + 1
+	}
+
+	return flags
+}
+
+// Deltas are only computed when a submission has a previous submission (which will always be earlier).
+func hasPreviousSubmission(result *model.IndividualAnalysis) bool {
+	return (result.SubmissionTimeDelta != 0)
+}
+
+func checkCodeDrop(result *model.IndividualAnalysis, codeDropFraction float64, distributions map[string][]float64) *model.AnomalyFlag {
+	if !hasPreviousSubmission(result) || (result.LinesOfCodeDelta >= 0) {
+		return nil
+	}
+
+	previousLOC := result.LinesOfCode - result.LinesOfCodeDelta
+	if previousLOC <= 0 {
+		return nil
+	}
+
+	fraction := float64(-result.LinesOfCodeDelta) / float64(previousLOC)
+	if fraction < codeDropFraction {
+		return nil
+	}
+
+	value := float64(result.LinesOfCodeDelta)
+	percentile := util.PercentileRank(distributions["lines-of-code-delta"], value)
+
+	return &model.AnomalyFlag{
+		Type:         model.ANOMALY_TYPE_CODE_DROP,
+		SubmissionID: result.FullID,
+		UserEmail:    result.UserEmail,
+		Metric:       "lines-of-code-delta",
+		Value:        value,
+		Percentile:   percentile,
+		Severity:     min(1.0, fraction),
+		Explanation: fmt.Sprintf("Lines of code dropped from %d to %d (%s%% of the previous submission's code was removed) %s after the previous submission, which is at the %s percentile of the class's changes in lines of code.",
+			previousLOC, result.LinesOfCode, formatAnomalyValue(100.0*fraction), formatSubmissionTimeDelta(result), formatAnomalyValue(percentile)),
+	}
+}
+
+func checkScoreJump(result *model.IndividualAnalysis, nearZeroFraction float64, classMaxScore float64, distributions map[string][]float64) *model.AnomalyFlag {
+	if !hasPreviousSubmission(result) {
+		return nil
+	}
+
+	// Older results may not have a max score, so fall back to the best score in the class.
+	maxScore := result.MaxScore
+	if maxScore <= 0.0 {
+		maxScore = classMaxScore
+	}
+
+	if maxScore <= 0.0 {
+		return nil
+	}
+
+	previousScore := result.Score - result.ScoreDelta
+	if (previousScore > (nearZeroFraction * maxScore)) || (result.Score < ((1.0 - nearZeroFraction) * maxScore)) {
+		return nil
+	}
+
+	percentile := util.PercentileRank(distributions["score-delta"], result.ScoreDelta)
+
+	return &model.AnomalyFlag{
+		Type:         model.ANOMALY_TYPE_SCORE_JUMP,
+		SubmissionID: result.FullID,
+		UserEmail:    result.UserEmail,
+		Metric:       "score-delta",
+		Value:        result.ScoreDelta,
+		Percentile:   percentile,
+		Severity:     max(0.0, min(1.0, result.ScoreDelta/maxScore)),
+		Explanation: fmt.Sprintf("Score jumped from %s to %s (out of %s) %s after the previous submission, which is at the %s percentile of the class's changes in score.",
+			formatAnomalyValue(previousScore), formatAnomalyValue(result.Score), formatAnomalyValue(maxScore), formatSubmissionTimeDelta(result), formatAnomalyValue(percentile)),
+	}
+}
+
+// Check for values outside of the class's fences (based on the interquartile range).
+// Metrics where most of the class has the same value (an interquartile range of zero) are not checked.
+func checkOutliers(result *model.IndividualAnalysis, outlierFactor float64, distributions map[string][]float64) []*model.AnomalyFlag {
+	flags := make([]*model.AnomalyFlag, 0)
+
+	for _, metric := range anomalyMetrics {
+		if !metric.checkOutliers {
+			continue
+		}
+
+		if metric.needsPrevious && !hasPreviousSubmission(result) {
+			continue
+		}
+
+		values := distributions[metric.name]
+		if len(values) < MIN_ANOMALY_DISTRIBUTION_SIZE {
+			continue
+		}
+
+		lowQuartile := util.Percentile(values, 25.0)
+		highQuartile := util.Percentile(values, 75.0)
+
+		interquartileRange := highQuartile - lowQuartile
+		if util.IsZero(interquartileRange) {
+			continue
+		}
+
+		value := metric.getValue(result)
+
+		direction := ""
+		if value < (lowQuartile - (outlierFactor * interquartileRange)) {
+			direction = "below"
+		} else if value > (highQuartile + (outlierFactor * interquartileRange)) {
+			direction = "above"
+		} else {
+			continue
+		}
+
+		percentile := util.PercentileRank(values, value)
+
+		flags = append(flags, &model.AnomalyFlag{
+			Type:         model.ANOMALY_TYPE_OUTLIER,
+			SubmissionID: result.FullID,
+			UserEmail:    result.UserEmail,
+			Metric:       metric.name,
+			Value:        value,
+			Percentile:   percentile,
+			Severity:     math.Abs(percentile-50.0) / 50.0,
+			Explanation: fmt.Sprintf("%s (%s) is far %s the class's typical range (%s - %s, the 25th - 75th percentiles), which is at the %s percentile of the class.",
+				metric.description, formatAnomalyValue(value), direction, formatAnomalyValue(lowQuartile), formatAnomalyValue(highQuartile), formatAnomalyValue(percentile)),
+		})
+	}
+
+	return flags
+}
+
+func formatAnomalyValue(value float64) string {
+	return util.FloatToStr(util.RoundWithPrecision(value, 2))
+}
+
+func formatSubmissionTimeDelta(result *model.IndividualAnalysis) string {
+	return (time.Duration(result.SubmissionTimeDelta) * time.Millisecond).String()
+}
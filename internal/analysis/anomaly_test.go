@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestDetectAnomaliesBase(test *testing.T) {
+	expected := []*model.AnomalyFlag{
+		&model.AnomalyFlag{
+			Rank:         1,
+			Type:         model.ANOMALY_TYPE_SCORE_JUMP,
+			SubmissionID: "course101::hw0::f@test.edulinq.org::1",
+			UserEmail:    "f@test.edulinq.org",
+			Metric:       "score-delta",
+			Value:        10,
+			Percentile:   91.67,
+			Severity:     1,
+			Explanation:  "Score jumped from 0 to 10 (out of 10) 1m0s after the previous submission, which is at the 91.67 percentile of the class's changes in score.",
+		},
+		&model.AnomalyFlag{
+			Rank:         2,
+			Type:         model.ANOMALY_TYPE_OUTLIER,
+			SubmissionID: "course101::hw0::e@test.edulinq.org::1",
+			UserEmail:    "e@test.edulinq.org",
+			Metric:       "lines-of-code",
+			Value:        20,
+			Percentile:   7.14,
+			Severity:     0.86,
+			Explanation:  "Lines of code (20) is far below the class's typical range (47.5 - 53.5, the 25th - 75th percentiles), which is at the 7.14 percentile of the class.",
+		},
+		&model.AnomalyFlag{
+			Rank:         3,
+			Type:         model.ANOMALY_TYPE_OUTLIER,
+			SubmissionID: "course101::hw0::f@test.edulinq.org::1",
+			UserEmail:    "f@test.edulinq.org",
+			Metric:       "lines-of-code-per-hour",
+			Value:        120,
+			Percentile:   91.67,
+			Severity:     0.83,
+			Explanation:  "Lines of code per hour (120) is far above the class's typical range (-2.5 - 13.75, the 25th - 75th percentiles), which is at the 91.67 percentile of the class.",
+		},
+		&model.AnomalyFlag{
+			Rank:         4,
+			Type:         model.ANOMALY_TYPE_OUTLIER,
+			SubmissionID: "course101::hw0::f@test.edulinq.org::1",
+			UserEmail:    "f@test.edulinq.org",
+			Metric:       "score-per-hour",
+			Value:        600,
+			Percentile:   91.67,
+			Severity:     0.83,
+			Explanation:  "Score per hour (600) is far above the class's typical range (0.25 - 1.75, the 25th - 75th percentiles), which is at the 91.67 percentile of the class.",
+		},
+		&model.AnomalyFlag{
+			Rank:         5,
+			Type:         model.ANOMALY_TYPE_CODE_DROP,
+			SubmissionID: "course101::hw0::e@test.edulinq.org::1",
+			UserEmail:    "e@test.edulinq.org",
+			Metric:       "lines-of-code-delta",
+			Value:        -40,
+			Percentile:   8.33,
+			Severity:     0.67,
+			Explanation:  "Lines of code dropped from 60 to 20 (66.67% of the previous submission's code was removed) 1h0m0s after the previous submission, which is at the 8.33 percentile of the class's changes in lines of code.",
+		},
+	}
+
+	actual := DetectAnomalies(getTestAnomalyResults(), AnomalyOptions{})
+	for _, flag := range actual {
+		flag.RoundWithPrecision(2)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Flags not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+}
+
+func TestDetectAnomaliesOptions(test *testing.T) {
+	testCases := []struct {
+		options       AnomalyOptions
+		expectedTypes []model.AnomalyType
+	}{
+		{
+			AnomalyOptions{},
+			[]model.AnomalyType{model.ANOMALY_TYPE_SCORE_JUMP, model.ANOMALY_TYPE_OUTLIER, model.ANOMALY_TYPE_OUTLIER, model.ANOMALY_TYPE_OUTLIER, model.ANOMALY_TYPE_CODE_DROP},
+		},
+		{
+			AnomalyOptions{CodeDropFraction: 0.9, OutlierFactor: 1000},
+			[]model.AnomalyType{model.ANOMALY_TYPE_SCORE_JUMP},
+		},
+		{
+			AnomalyOptions{CodeDropFraction: 0.1, OutlierFactor: 1000},
+			[]model.AnomalyType{model.ANOMALY_TYPE_SCORE_JUMP, model.ANOMALY_TYPE_CODE_DROP, model.ANOMALY_TYPE_CODE_DROP},
+		},
+		{
+			AnomalyOptions{CodeDropFraction: 0.9, NearZeroFraction: 0.5, OutlierFactor: 1000},
+			[]model.AnomalyType{model.ANOMALY_TYPE_SCORE_JUMP, model.ANOMALY_TYPE_SCORE_JUMP, model.ANOMALY_TYPE_SCORE_JUMP, model.ANOMALY_TYPE_SCORE_JUMP},
+		},
+	}
+
+	for i, testCase := range testCases {
+		flags := DetectAnomalies(getTestAnomalyResults(), testCase.options)
+
+		actualTypes := make([]model.AnomalyType, 0, len(flags))
+		for _, flag := range flags {
+			actualTypes = append(actualTypes, flag.Type)
+		}
+
+		if !reflect.DeepEqual(testCase.expectedTypes, actualTypes) {
+			test.Errorf("Case %d: Flag types not as expected. Expected: '%v', Actual: '%v'.", i, testCase.expectedTypes, actualTypes)
+			continue
+		}
+	}
+}
+
+func TestDetectAnomaliesEmpty(test *testing.T) {
+	testCases := [][]*model.IndividualAnalysis{
+		nil,
+		[]*model.IndividualAnalysis{},
+		[]*model.IndividualAnalysis{nil, &model.IndividualAnalysis{Failure: true}},
+		// Too few submissions to find outliers.
+		getTestAnomalyResults()[0:3],
+	}
+
+	for i, testCase := range testCases {
+		flags := DetectAnomalies(testCase, AnomalyOptions{})
+		if len(flags) != 0 {
+			test.Errorf("Case %d: Found unexpected flags: '%s'.", i, util.MustToJSONIndent(flags))
+		}
+	}
+}
+
+func getTestAnomalyResults() []*model.IndividualAnalysis {
+	return []*model.IndividualAnalysis{
+		newTestAnomalyResult("a", 50, 5, 5, 1, 60, 5, 1),
+		newTestAnomalyResult("b", 55, 10, 6, 2, 60, 10, 2),
+		newTestAnomalyResult("c", 45, -5, 4, 0, 60, -5, 0),
+		newTestAnomalyResult("d", 60, 15, 7, 1, 60, 15, 1),
+		// A large drop in code (and few lines overall).
+		newTestAnomalyResult("e", 20, -40, 5, 0, 60, -40, 0),
+		// A quick jump to a full score.
+		newTestAnomalyResult("f", 52, 2, 10, 10, 1, 120, 600),
+		// A first submission (no deltas).
+		newTestAnomalyResult("g", 50, 0, 0, 0, 0, 0, 0),
+		// A failure.
+		&model.IndividualAnalysis{
+			FullID:  "course101::hw0::h@test.edulinq.org::1",
+			Failure: true,
+		},
+		nil,
+	}
+}
+
+func newTestAnomalyResult(user string, loc int, locDelta int, score float64, scoreDelta float64, minutes int64, locVelocity float64, scoreVelocity float64) *model.IndividualAnalysis {
+	email := user + "@test.edulinq.org"
+
+	return &model.IndividualAnalysis{
+		FullID:              "course101::hw0::" + email + "::1",
+		ShortID:             "1",
+		CourseID:            "course101",
+		AssignmentID:        "hw0",
+		UserEmail:           email,
+		Score:               score,
+		MaxScore:            10,
+		LinesOfCode:         loc,
+		SubmissionTimeDelta: minutes * 60 * 1000,
+		LinesOfCodeDelta:    locDelta,
+		ScoreDelta:          scoreDelta,
+		LinesOfCodeVelocity: locVelocity,
+		ScoreVelocity:       scoreVelocity,
+	}
+}
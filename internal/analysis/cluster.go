@@ -0,0 +1,267 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+const MAX_COMMUNITY_ITERATIONS = 100
+
+type ClusterOptions struct {
+	// The minimum (total mean) similarity for two submissions to be linked.
+	// Non-positive values will use the config default.
+	Threshold float64 `json:"threshold"`
+
+	// The minimum number of members for a cluster to be reported.
+	// Non-positive values will use the config default.
+	MinSize int `json:"min-size"`
+
+	// The maximum number of evidence pairs to report for each cluster.
+	// Non-positive values will report all pairs.
+	MaxEvidence int `json:"max-evidence"`
+}
+
+// Group pairwise analysis results into ranked clusters of similar submissions.
+// A similarity graph is built from all (non-failed) results with a score of at least the threshold,
+// and then communities are detected in that graph (using weighted label propagation).
+// This means that a weak link between two strong groups will not merge them into a single cluster.
+// Clusters are ranked by their mean score (and then by size).
+func ClusterPairwiseResults(results []*model.PairwiseAnalysis, options ClusterOptions) []*model.SimilarityCluster {
+	threshold := options.Threshold
+	if threshold <= 0.0 {
+		threshold = config.ANALYSIS_CLUSTER_THRESHOLD.Get()
+	}
+
+	minSize := options.MinSize
+	if minSize <= 0 {
+		minSize = config.ANALYSIS_CLUSTER_MIN_SIZE.Get()
+	}
+
+	// A cluster always needs at least a pair.
+	minSize = max(2, minSize)
+
+	neighbors := make(map[string]map[string]float64)
+	corpora := make(map[string]string)
+
+	for _, result := range results {
+		if (result == nil) || result.Failure {
+			continue
+		}
+
+		if result.TotalMeanSimilarity < threshold {
+			continue
+		}
+
+		ids := result.SubmissionIDs
+		if ids[0] == ids[1] {
+			continue
+		}
+
+		for i := 0; i < 2; i++ {
+			if neighbors[ids[i]] == nil {
+				neighbors[ids[i]] = make(map[string]float64)
+			}
+
+			neighbors[ids[i]][ids[1-i]] = result.TotalMeanSimilarity
+		}
+
+		if result.Corpus != "" {
+			corpora[ids[1]] = result.Corpus
+		}
+	}
+
+	nodes := make([]string, 0, len(neighbors))
+	for node, _ := range neighbors {
+		nodes = append(nodes, node)
+	}
+
+	slices.Sort(nodes)
+
+	labels := detectCommunities(nodes, neighbors)
+
+	clusters := make([]*model.SimilarityCluster, 0)
+	seen := make(map[string]bool, len(nodes))
+
+	for _, node := range nodes {
+		if seen[node] {
+			continue
+		}
+
+		// Communities may not be connected, so each cluster is a connected component within a community.
+		members := collectCommunityComponent(node, labels, neighbors, seen)
+		if len(members) < minSize {
+			continue
+		}
+
+		clusters = append(clusters, newSimilarityCluster(members, neighbors, corpora, options.MaxEvidence))
+	}
+
+	slices.SortFunc(clusters, func(a *model.SimilarityCluster, b *model.SimilarityCluster) int {
+		if a.MeanScore != b.MeanScore {
+			if a.MeanScore > b.MeanScore {
+				return -1
+			}
+
+			return 1
+		}
+
+		if len(a.Members) != len(b.Members) {
+			return len(b.Members) - len(a.Members)
+		}
+
+		return strings.Compare(a.Members[0].ID, b.Members[0].ID)
+	})
+
+	for i, cluster := range clusters {
+		cluster.Rank = i + 1
+	}
+
+	return clusters
+}
+
+// Assign each node a community label using (asynchronous, weighted) label propagation.
+// Each node repeatedly takes the label with the highest total edge weight among its neighbors.
+// To keep results deterministic, nodes are always visited in order and ties keep the current label (or take the smallest label).
+func detectCommunities(nodes []string, neighbors map[string]map[string]float64) map[string]string {
+	labels := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		labels[node] = node
+	}
+
+	for iteration := 0; iteration < MAX_COMMUNITY_ITERATIONS; iteration++ {
+		changed := false
+
+		for _, node := range nodes {
+			weights := make(map[string]float64)
+			for neighbor, score := range neighbors[node] {
+				weights[labels[neighbor]] += score
+			}
+
+			bestLabel := ""
+			bestWeight := -1.0
+
+			for label, weight := range weights {
+				if (weight > bestWeight) || ((weight == bestWeight) && (label < bestLabel)) {
+					bestLabel = label
+					bestWeight = weight
+				}
+			}
+
+			if (bestLabel == "") || (weights[labels[node]] == bestWeight) {
+				continue
+			}
+
+			labels[node] = bestLabel
+			changed = true
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	return labels
+}
+
+// Get all the nodes reachable from the start node (without leaving the start node's community).
+// The returned members will be sorted.
+func collectCommunityComponent(start string, labels map[string]string, neighbors map[string]map[string]float64, seen map[string]bool) []string {
+	members := []string{start}
+	seen[start] = true
+
+	for i := 0; i < len(members); i++ {
+		for neighbor, _ := range neighbors[members[i]] {
+			if seen[neighbor] || (labels[neighbor] != labels[start]) {
+				continue
+			}
+
+			seen[neighbor] = true
+			members = append(members, neighbor)
+		}
+	}
+
+	slices.Sort(members)
+
+	return members
+}
+
+func newSimilarityCluster(ids []string, neighbors map[string]map[string]float64, corpora map[string]string, maxEvidence int) *model.SimilarityCluster {
+	cluster := &model.SimilarityCluster{
+		Members:  make([]*model.SimilarityClusterMember, 0, len(ids)),
+		Evidence: make([]*model.SimilarityClusterEvidence, 0),
+	}
+
+	totalScore := 0.0
+
+	for i, id := range ids {
+		member := &model.SimilarityClusterMember{
+			ID:     id,
+			Corpus: corpora[id],
+		}
+
+		if member.Corpus == "" {
+			_, _, member.UserEmail, _, _ = common.SplitFullSubmissionID(id)
+		}
+
+		for j, otherID := range ids {
+			score, ok := neighbors[id][otherID]
+			if !ok {
+				continue
+			}
+
+			member.MaxScore = max(member.MaxScore, score)
+
+			// Only add each pair once.
+			if j <= i {
+				continue
+			}
+
+			cluster.Evidence = append(cluster.Evidence, &model.SimilarityClusterEvidence{
+				SubmissionIDs: clusterEdgeKey(id, otherID, corpora),
+				Score:         score,
+			})
+
+			totalScore += score
+			cluster.MaxScore = max(cluster.MaxScore, score)
+		}
+
+		cluster.Members = append(cluster.Members, member)
+	}
+
+	if len(cluster.Evidence) > 0 {
+		cluster.MeanScore = totalScore / float64(len(cluster.Evidence))
+	}
+
+	slices.SortStableFunc(cluster.Evidence, func(a *model.SimilarityClusterEvidence, b *model.SimilarityClusterEvidence) int {
+		if a.Score > b.Score {
+			return -1
+		} else if a.Score < b.Score {
+			return 1
+		}
+
+		return 0
+	})
+
+	if (maxEvidence > 0) && (len(cluster.Evidence) > maxEvidence) {
+		cluster.Evidence = cluster.Evidence[:maxEvidence]
+	}
+
+	return cluster
+}
+
+// Get the key for an edge, matching the key of the original pairwise result (corpus entries are always on the RHS).
+func clusterEdgeKey(a string, b string, corpora map[string]string) model.PairwiseKey {
+	if corpora[a] != "" {
+		return model.PairwiseKey{b, a}
+	}
+
+	if corpora[b] != "" {
+		return model.PairwiseKey{a, b}
+	}
+
+	return model.NewPairwiseKey(a, b)
+}
@@ -0,0 +1,203 @@
+package analysis
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const testClusterCorpusID = "corpus::public::solution::abc"
+
+func TestClusterPairwiseResultsBase(test *testing.T) {
+	testCases := []struct {
+		options  ClusterOptions
+		expected [][]string
+	}{
+		// Defaults: a ring of five, and a pair (with a corpus entry) weakly linked to the ring.
+		{
+			ClusterOptions{},
+			[][]string{
+				[]string{testClusterID("a"), testClusterID("b"), testClusterID("c"), testClusterID("d"), testClusterID("e")},
+				[]string{testClusterCorpusID, testClusterID("f"), testClusterID("g")},
+				[]string{testClusterID("h"), testClusterID("i")},
+			},
+		},
+		// Higher threshold.
+		{
+			ClusterOptions{Threshold: 0.85},
+			[][]string{
+				[]string{testClusterID("f"), testClusterID("g")},
+				[]string{testClusterID("a"), testClusterID("b"), testClusterID("c"), testClusterID("d"), testClusterID("e")},
+			},
+		},
+		// Lower threshold.
+		{
+			ClusterOptions{Threshold: 0.01},
+			[][]string{
+				[]string{testClusterID("a"), testClusterID("b"), testClusterID("c"), testClusterID("d"), testClusterID("e")},
+				[]string{testClusterCorpusID, testClusterID("f"), testClusterID("g")},
+				[]string{testClusterID("h"), testClusterID("i"), testClusterID("j")},
+			},
+		},
+		// Larger clusters.
+		{
+			ClusterOptions{MinSize: 4},
+			[][]string{
+				[]string{testClusterID("a"), testClusterID("b"), testClusterID("c"), testClusterID("d"), testClusterID("e")},
+			},
+		},
+		// Clusters too large.
+		{
+			ClusterOptions{MinSize: 6},
+			[][]string{},
+		},
+	}
+
+	results := getTestClusterResults()
+
+	for i, testCase := range testCases {
+		clusters := ClusterPairwiseResults(results, testCase.options)
+
+		actual := make([][]string, 0, len(clusters))
+		for j, cluster := range clusters {
+			if cluster.Rank != (j + 1) {
+				test.Errorf("Case %d: Cluster %d has the wrong rank. Expected: %d, Actual: %d.", i, j, j+1, cluster.Rank)
+			}
+
+			ids := make([]string, 0, len(cluster.Members))
+			for _, member := range cluster.Members {
+				ids = append(ids, member.ID)
+			}
+
+			actual = append(actual, ids)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected clusters. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+		}
+	}
+}
+
+func TestClusterPairwiseResultsEvidence(test *testing.T) {
+	options := ClusterOptions{
+		MinSize:     3,
+		MaxEvidence: 2,
+	}
+
+	clusters := ClusterPairwiseResults(getTestClusterResults(), options)
+
+	expected := []*model.SimilarityCluster{
+		&model.SimilarityCluster{
+			Rank: 1,
+			Members: []*model.SimilarityClusterMember{
+				&model.SimilarityClusterMember{ID: testClusterID("a"), UserEmail: "a@test.edulinq.org", MaxScore: 0.9},
+				&model.SimilarityClusterMember{ID: testClusterID("b"), UserEmail: "b@test.edulinq.org", MaxScore: 0.9},
+				&model.SimilarityClusterMember{ID: testClusterID("c"), UserEmail: "c@test.edulinq.org", MaxScore: 0.9},
+				&model.SimilarityClusterMember{ID: testClusterID("d"), UserEmail: "d@test.edulinq.org", MaxScore: 0.9},
+				&model.SimilarityClusterMember{ID: testClusterID("e"), UserEmail: "e@test.edulinq.org", MaxScore: 0.9},
+			},
+			Evidence: []*model.SimilarityClusterEvidence{
+				&model.SimilarityClusterEvidence{
+					SubmissionIDs: model.NewPairwiseKey(testClusterID("a"), testClusterID("b")),
+					Score:         0.9,
+				},
+				&model.SimilarityClusterEvidence{
+					SubmissionIDs: model.NewPairwiseKey(testClusterID("a"), testClusterID("c")),
+					Score:         0.9,
+				},
+			},
+			MeanScore: 0.9,
+			MaxScore:  0.9,
+		},
+		&model.SimilarityCluster{
+			Rank: 2,
+			Members: []*model.SimilarityClusterMember{
+				&model.SimilarityClusterMember{
+					ID:       testClusterCorpusID,
+					Corpus:   "public",
+					MaxScore: 0.8,
+				},
+				&model.SimilarityClusterMember{
+					ID:        testClusterID("f"),
+					UserEmail: "f@test.edulinq.org",
+					MaxScore:  0.95,
+				},
+				&model.SimilarityClusterMember{
+					ID:        testClusterID("g"),
+					UserEmail: "g@test.edulinq.org",
+					MaxScore:  0.95,
+				},
+			},
+			Evidence: []*model.SimilarityClusterEvidence{
+				&model.SimilarityClusterEvidence{
+					SubmissionIDs: model.NewPairwiseKey(testClusterID("f"), testClusterID("g")),
+					Score:         0.95,
+				},
+				&model.SimilarityClusterEvidence{
+					SubmissionIDs: model.PairwiseKey{testClusterID("f"), testClusterCorpusID},
+					Score:         0.8,
+				},
+			},
+			MeanScore: 0.85,
+			MaxScore:  0.95,
+		},
+	}
+
+	for _, cluster := range clusters {
+		cluster.RoundWithPrecision(4)
+	}
+
+	if !reflect.DeepEqual(expected, clusters) {
+		test.Fatalf("Unexpected clusters. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(clusters))
+	}
+}
+
+func testClusterID(user string) string {
+	return fmt.Sprintf("course101::hw0::%s@test.edulinq.org::1697406256", user)
+}
+
+// Get results for:
+//   - A ring of five (a-e) that are all very similar.
+//   - A pair (f, g) that is similar to a corpus entry and weakly linked to the ring (e-f).
+//   - A pair (h, i) that is just over the default threshold.
+//   - A submission (j) that is barely similar to i.
+//   - A failed result.
+func getTestClusterResults() []*model.PairwiseAnalysis {
+	results := make([]*model.PairwiseAnalysis, 0)
+
+	ring := []string{"a", "b", "c", "d", "e"}
+	for i, user := range ring {
+		for _, otherUser := range ring[(i + 1):] {
+			results = append(results, newTestClusterResult(testClusterID(user), testClusterID(otherUser), 0.9))
+		}
+	}
+
+	results = append(results, newTestClusterResult(testClusterID("e"), testClusterID("f"), 0.55))
+	results = append(results, newTestClusterResult(testClusterID("f"), testClusterID("g"), 0.95))
+	results = append(results, newTestClusterResult(testClusterID("h"), testClusterID("i"), 0.6))
+	results = append(results, newTestClusterResult(testClusterID("i"), testClusterID("j"), 0.05))
+
+	for _, user := range []string{"f", "g"} {
+		result := newTestClusterResult(testClusterID(user), testClusterCorpusID, 0.8)
+		result.SubmissionIDs = model.PairwiseKey{testClusterID(user), testClusterCorpusID}
+		result.Corpus = "public"
+		results = append(results, result)
+	}
+
+	failure := newTestClusterResult(testClusterID("a"), testClusterID("h"), 1.0)
+	failure.Failure = true
+	results = append(results, failure)
+
+	return results
+}
+
+func newTestClusterResult(id1 string, id2 string, score float64) *model.PairwiseAnalysis {
+	return &model.PairwiseAnalysis{
+		SubmissionIDs:       model.NewPairwiseKey(id1, id2),
+		TotalMeanSimilarity: score,
+	}
+}
@@ -28,6 +28,10 @@ type AnalysisOptions struct {
 	// Wait for the entire analysis to complete and return all results.
 	WaitForCompletion bool `json:"wait-for-completion"`
 
+	// For pairwise analysis, the names of corpora to also compare each submission against
+	// (the corpora come from each submission's assignment, use "*" for all corpora).
+	Corpora []string `json:"corpora,omitempty"`
+
 	ResolvedSubmissionIDs []string `json:"-"`
 }
 
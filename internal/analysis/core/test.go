@@ -20,6 +20,11 @@ var (
 func RunEngineTestComputeFileSimilarityBase(test *testing.T, engine SimilarityEngine, includeTemplate bool, expected *model.FileSimilarity) {
 	docker.EnsureOrSkipForTest(test)
 
+	RunEngineTestComputeFileSimilarityNoDocker(test, engine, includeTemplate, expected)
+}
+
+// Same as RunEngineTestComputeFileSimilarityBase(), but for engines that do not require docker.
+func RunEngineTestComputeFileSimilarityNoDocker(test *testing.T, engine SimilarityEngine, includeTemplate bool, expected *model.FileSimilarity) {
 	paths := [2]string{
 		filepath.Join(util.RootDirForTesting(), solutionRelPath),
 		filepath.Join(util.RootDirForTesting(), partialRelPath),
@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Corpora are stored on disk in the assignment's corpora dir: <corpora dir>/<corpus>/<entry>/<files>.
+// Entries are never edited in place, adding an entry with an existing name will replace the old entry.
+
+// Get all of an assignment's corpus entries, keyed by corpus name.
+func ListCorpora(assignment *model.Assignment) (map[string][]*model.CorpusEntry, error) {
+	lockKey := getCorpusLockKey(assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	return listCorpora(assignment)
+}
+
+// Get the entries for the named corpora (or all of the assignment's corpora if model.CORPUS_ALL is included).
+// Unknown corpora are ignored.
+func GetCorpusEntries(assignment *model.Assignment, corpora []string) ([]*model.CorpusEntry, error) {
+	if len(corpora) == 0 {
+		return nil, nil
+	}
+
+	allCorpora, err := ListCorpora(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	useAll := slices.Contains(corpora, model.CORPUS_ALL)
+
+	names := make([]string, 0, len(allCorpora))
+	for name, _ := range allCorpora {
+		if useAll || slices.Contains(corpora, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	entries := make([]*model.CorpusEntry, 0)
+	for _, name := range names {
+		entries = append(entries, allCorpora[name]...)
+	}
+
+	return entries, nil
+}
+
+// Add the files in a directory as an entry in an assignment's corpus (replacing any existing entry with the same name).
+func AddCorpusEntry(assignment *model.Assignment, corpus string, entry string, sourceDir string) (*model.CorpusEntry, error) {
+	corpus, err := model.ValidateCorpusName(corpus)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err = model.ValidateCorpusName(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	lockKey := getCorpusLockKey(assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	entryDir := filepath.Join(assignment.GetCorporaDir(), corpus, entry)
+
+	err = util.RemoveDirent(entryDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to remove existing corpus entry: '%w'.", err)
+	}
+
+	err = util.MkDir(entryDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make corpus entry dir: '%w'.", err)
+	}
+
+	err = util.CopyDirContents(sourceDir, entryDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to copy corpus entry files: '%w'.", err)
+	}
+
+	return loadCorpusEntry(assignment, corpus, entry)
+}
+
+// Add an existing submission as an entry in an assignment's corpus.
+// If no entry name is given, one will be built from the submission ID.
+// The submission may be from any course/assignment (e.g., the same assignment in a previous term).
+func AddCorpusSubmission(assignment *model.Assignment, corpus string, entry string, fullSubmissionID string) (*model.CorpusEntry, error) {
+	if entry == "" {
+		entry = strings.ReplaceAll(fullSubmissionID, common.SUBMISSION_ID_DELIM, "_")
+	}
+
+	tempDir, err := util.MkDirTemp("analysis-corpus-submission-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make temp dir: '%w'.", err)
+	}
+	defer util.RemoveDirent(tempDir)
+
+	_, _, err = fetchSubmission(fullSubmissionID, tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch submission '%s': '%w'.", fullSubmissionID, err)
+	}
+
+	return AddCorpusEntry(assignment, corpus, entry, tempDir)
+}
+
+// Remove an entry from a corpus, or an entire corpus (if the entry is empty).
+// Returns true if anything was removed.
+func RemoveCorpus(assignment *model.Assignment, corpus string, entry string) (bool, error) {
+	corpus, err := model.ValidateCorpusName(corpus)
+	if err != nil {
+		return false, err
+	}
+
+	path := filepath.Join(assignment.GetCorporaDir(), corpus)
+
+	if entry != "" {
+		entry, err = model.ValidateCorpusName(entry)
+		if err != nil {
+			return false, err
+		}
+
+		path = filepath.Join(path, entry)
+	}
+
+	lockKey := getCorpusLockKey(assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	if !util.PathExists(path) {
+		return false, nil
+	}
+
+	err = util.RemoveDirent(path)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove corpus dir: '%w'.", err)
+	}
+
+	return true, nil
+}
+
+// Copy the files for a corpus entry (identified by its ID) into a directory.
+func copyCorpusEntry(assignment *model.Assignment, entryID string, destDir string) error {
+	corpus, entry, _, err := model.SplitCorpusEntryID(entryID)
+	if err != nil {
+		return err
+	}
+
+	lockKey := getCorpusLockKey(assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	entryDir := filepath.Join(assignment.GetCorporaDir(), corpus, entry)
+	if !util.IsDir(entryDir) {
+		return fmt.Errorf("Could not find corpus entry '%s'.", entryID)
+	}
+
+	err = util.MkDir(destDir)
+	if err != nil {
+		return fmt.Errorf("Failed to make corpus entry dir: '%w'.", err)
+	}
+
+	return util.CopyDirContents(entryDir, destDir)
+}
+
+func listCorpora(assignment *model.Assignment) (map[string][]*model.CorpusEntry, error) {
+	corpora := make(map[string][]*model.CorpusEntry)
+
+	baseDir := assignment.GetCorporaDir()
+	if !util.IsDir(baseDir) {
+		return corpora, nil
+	}
+
+	corpusDirents, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read corpora dir: '%w'.", err)
+	}
+
+	for _, corpusDirent := range corpusDirents {
+		if !corpusDirent.IsDir() {
+			continue
+		}
+
+		corpus := corpusDirent.Name()
+
+		entryDirents, err := os.ReadDir(filepath.Join(baseDir, corpus))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read corpus dir '%s': '%w'.", corpus, err)
+		}
+
+		entries := make([]*model.CorpusEntry, 0, len(entryDirents))
+		for _, entryDirent := range entryDirents {
+			if !entryDirent.IsDir() {
+				continue
+			}
+
+			entry, err := loadCorpusEntry(assignment, corpus, entryDirent.Name())
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, entry)
+		}
+
+		corpora[corpus] = entries
+	}
+
+	return corpora, nil
+}
+
+func loadCorpusEntry(assignment *model.Assignment, corpus string, entry string) (*model.CorpusEntry, error) {
+	entryDir := filepath.Join(assignment.GetCorporaDir(), corpus, entry)
+
+	relpaths, err := util.GetAllDirents(entryDir, true, true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list files for corpus entry '%s/%s': '%w'.", corpus, entry, err)
+	}
+
+	slices.Sort(relpaths)
+
+	// The hash covers both the paths and contents of all files.
+	var hashContent strings.Builder
+	for _, relpath := range relpaths {
+		fileHash, err := util.MD5FileHex(filepath.Join(entryDir, relpath))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to hash corpus entry file '%s/%s/%s': '%w'.", corpus, entry, relpath, err)
+		}
+
+		hashContent.WriteString(relpath + "\n" + fileHash + "\n")
+	}
+
+	hash, err := util.MD5StringHex(hashContent.String())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to hash corpus entry '%s/%s': '%w'.", corpus, entry, err)
+	}
+
+	return &model.CorpusEntry{
+		Corpus: corpus,
+		Name:   entry,
+		Hash:   hash,
+		Files:  relpaths,
+	}, nil
+}
+
+func getCorpusLockKey(assignment *model.Assignment) string {
+	return fmt.Sprintf("analysis-corpus-%s", assignment.FullID())
+}
@@ -0,0 +1,213 @@
+package analysis
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var testCorpusSourceDir string = filepath.Join(util.RootDirForTesting(), "testdata", "files", "sim_engine", "test-submissions", "solution")
+
+func TestCorpusBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	defer util.RemoveDirent(assignment.GetCorporaDir())
+
+	corpora, err := ListCorpora(assignment)
+	if err != nil {
+		test.Fatalf("Failed to list empty corpora: '%v'.", err)
+	}
+
+	if len(corpora) != 0 {
+		test.Fatalf("Found corpora when there should be none: '%s'.", util.MustToJSONIndent(corpora))
+	}
+
+	solution, err := AddCorpusEntry(assignment, "public", "solution", testCorpusSourceDir)
+	if err != nil {
+		test.Fatalf("Failed to add corpus entry: '%v'.", err)
+	}
+
+	prior, err := AddCorpusSubmission(assignment, "prior-term", "", "course101::hw0::course-student@test.edulinq.org::1697406256")
+	if err != nil {
+		test.Fatalf("Failed to add corpus submission: '%v'.", err)
+	}
+
+	expected := map[string][]*model.CorpusEntry{
+		"public": []*model.CorpusEntry{
+			&model.CorpusEntry{
+				Corpus: "public",
+				Name:   "solution",
+				Hash:   solution.Hash,
+				Files:  []string{"submission.py"},
+			},
+		},
+		"prior-term": []*model.CorpusEntry{
+			&model.CorpusEntry{
+				Corpus: "prior-term",
+				Name:   "course101_hw0_course-student@test.edulinq.org_1697406256",
+				Hash:   prior.Hash,
+				Files:  []string{"submission.py"},
+			},
+		},
+	}
+
+	corpora, err = ListCorpora(assignment)
+	if err != nil {
+		test.Fatalf("Failed to list corpora: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, corpora) {
+		test.Fatalf("Unexpected corpora. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(corpora))
+	}
+
+	// Different files have different hashes.
+	if solution.Hash == prior.Hash {
+		test.Fatalf("Different entries have the same hash: '%s'.", solution.Hash)
+	}
+
+	entries, err := GetCorpusEntries(assignment, []string{model.CORPUS_ALL})
+	if err != nil {
+		test.Fatalf("Failed to get all corpus entries: '%v'.", err)
+	}
+
+	if len(entries) != 2 {
+		test.Fatalf("Unexpected number of entries. Expected: 2, Actual: %d.", len(entries))
+	}
+
+	entries, err = GetCorpusEntries(assignment, []string{"public", "zzz"})
+	if err != nil {
+		test.Fatalf("Failed to get corpus entries: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected["public"], entries) {
+		test.Fatalf("Unexpected entries. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected["public"]), util.MustToJSONIndent(entries))
+	}
+
+	removed, err := RemoveCorpus(assignment, "public", "solution")
+	if err != nil {
+		test.Fatalf("Failed to remove corpus entry: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Corpus entry was not removed.")
+	}
+
+	removed, err = RemoveCorpus(assignment, "public", "solution")
+	if err != nil {
+		test.Fatalf("Failed to remove missing corpus entry: '%v'.", err)
+	}
+
+	if removed {
+		test.Fatalf("Missing corpus entry was removed.")
+	}
+
+	removed, err = RemoveCorpus(assignment, "prior-term", "")
+	if err != nil {
+		test.Fatalf("Failed to remove corpus: '%v'.", err)
+	}
+
+	if !removed {
+		test.Fatalf("Corpus was not removed.")
+	}
+}
+
+func TestCorpusBadNames(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	defer util.RemoveDirent(assignment.GetCorporaDir())
+
+	names := []string{"", " ", ".hidden", "a/b", "..", "a::b"}
+	for i, name := range names {
+		_, err := AddCorpusEntry(assignment, name, "entry", testCorpusSourceDir)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on bad corpus name '%s'.", i, name)
+		}
+
+		_, err = AddCorpusEntry(assignment, "corpus", name, testCorpusSourceDir)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on bad entry name '%s'.", i, name)
+		}
+	}
+}
+
+func TestPairwiseAnalysisCorpus(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	defer util.RemoveDirent(assignment.GetCorporaDir())
+
+	entry, err := AddCorpusEntry(assignment, "public", "solution", testCorpusSourceDir)
+	if err != nil {
+		test.Fatalf("Failed to add corpus entry: '%v'.", err)
+	}
+
+	// Another corpus that is not requested.
+	_, err = AddCorpusEntry(assignment, "other", "solution", testCorpusSourceDir)
+	if err != nil {
+		test.Fatalf("Failed to add corpus entry: '%v'.", err)
+	}
+
+	ids := []string{
+		"course101::hw0::course-student@test.edulinq.org::1697406256",
+		"course101::hw0::course-student@test.edulinq.org::1697406265",
+	}
+
+	options := AnalysisOptions{
+		ResolvedSubmissionIDs: ids,
+		WaitForCompletion:     true,
+		Corpora:               []string{"public"},
+	}
+
+	results, pendingCount, err := PairwiseAnalysis(options, "server-admin@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to do pairwise analysis: '%v'.", err)
+	}
+
+	if pendingCount != 0 {
+		test.Fatalf("Found %d pending results, when 0 were expected.", pendingCount)
+	}
+
+	expectedKeys := map[model.PairwiseKey]string{
+		model.NewPairwiseKey(ids[0], ids[1]):  "",
+		model.PairwiseKey{ids[0], entry.ID()}: "public",
+		model.PairwiseKey{ids[1], entry.ID()}: "public",
+	}
+
+	actualKeys := make(map[model.PairwiseKey]string, len(results))
+	for _, result := range results {
+		if result.Failure {
+			test.Fatalf("Got a failed result: '%s'.", util.MustToJSONIndent(result))
+		}
+
+		if len(result.Similarities) != 1 {
+			test.Fatalf("Unexpected number of similarities. Expected: 1, Actual: %d.", len(result.Similarities))
+		}
+
+		actualKeys[result.SubmissionIDs] = result.Corpus
+	}
+
+	if !reflect.DeepEqual(expectedKeys, actualKeys) {
+		test.Fatalf("Unexpected keys. Expected: '%v', Actual: '%v'.", expectedKeys, actualKeys)
+	}
+
+	// Run again to get the cached results.
+	options.WaitForCompletion = false
+
+	results, pendingCount, err = PairwiseAnalysis(options, "server-admin@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to do cached pairwise analysis: '%v'.", err)
+	}
+
+	if (pendingCount != 0) || (len(results) != len(expectedKeys)) {
+		test.Fatalf("Unexpected cached results. Expected: (0 pending, %d results), Actual: (%d pending, %d results).", len(expectedKeys), pendingCount, len(results))
+	}
+}
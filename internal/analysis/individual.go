@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"slices"
 
+	"github.com/edulinq/autograder/internal/analysis/native"
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
@@ -209,6 +210,7 @@ func computeSingleIndividualAnalysis(fullSubmissionID string, computeDeltas bool
 
 		SubmissionStartTime: gradingResult.Info.GradingStartTime,
 		Score:               gradingResult.Info.Score,
+		MaxScore:            gradingResult.Info.MaxPoints,
 	}
 
 	fileInfos, skipped, loc, err := individualFileAnalysis(submissionDir, assignment)
@@ -222,6 +224,7 @@ func computeSingleIndividualAnalysis(fullSubmissionID string, computeDeltas bool
 	analysis.Files = fileInfos
 	analysis.SkippedFiles = skipped
 	analysis.LinesOfCode = loc
+	setCodeMetrics(analysis)
 
 	if computeDeltas {
 		err = computeDelta(analysis, assignment, gradingResult)
@@ -302,6 +305,19 @@ func individualFileAnalysis(submissionDir string, assignment *model.Assignment)
 			LinesOfCode:      loc,
 		}
 
+		metrics, err := native.ComputeFileMetrics(path)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("Unable to compute code metrics for '%s': '%w'.", relpath, err)
+		}
+
+		if metrics != nil {
+			info.Language = metrics.Language
+			info.CyclomaticComplexity = metrics.CyclomaticComplexity
+			info.FunctionCount = metrics.FunctionCount
+			info.CommentRatio = metrics.CommentRatio()
+			info.MaxNestingDepth = metrics.MaxNestingDepth
+		}
+
 		totalLOC += loc
 		infos = append(infos, info)
 	}
@@ -309,6 +325,29 @@ func individualFileAnalysis(submissionDir string, assignment *model.Assignment)
 	return infos, skipped, totalLOC, nil
 }
 
+// Set the submission-level code metrics from the file-level metrics.
+func setCodeMetrics(analysis *model.IndividualAnalysis) {
+	commentLOC := 0.0
+	metricsLOC := 0
+
+	for _, info := range analysis.Files {
+		if info.Language == "" {
+			continue
+		}
+
+		analysis.CyclomaticComplexity += info.CyclomaticComplexity
+		analysis.FunctionCount += info.FunctionCount
+		analysis.MaxNestingDepth = max(analysis.MaxNestingDepth, info.MaxNestingDepth)
+
+		commentLOC += info.CommentRatio * float64(info.LinesOfCode)
+		metricsLOC += info.LinesOfCode
+	}
+
+	if metricsLOC > 0 {
+		analysis.CommentRatio = commentLOC / float64(metricsLOC)
+	}
+}
+
 func collectIndividualStats(fullSubmissionIDs []string, totalRunTime int64, initiatorEmail string) {
 	collectAnalysisStats(fullSubmissionIDs, totalRunTime, initiatorEmail, "individual")
 }
@@ -36,14 +36,22 @@ func TestIndividualAnalysisBase(test *testing.T) {
 
 			SubmissionStartTime: timestamp.FromMSecs(1697406266000),
 			Score:               1,
+			MaxScore:            2,
 
 			Files: []model.AnalysisFileInfo{
 				model.AnalysisFileInfo{
-					Filename:    "submission.py",
-					LinesOfCode: 4,
+					Filename:             "submission.py",
+					LinesOfCode:          4,
+					Language:             "python",
+					CyclomaticComplexity: 2,
+					FunctionCount:        2,
+					MaxNestingDepth:      1,
 				},
 			},
-			LinesOfCode: 4,
+			LinesOfCode:          4,
+			CyclomaticComplexity: 2,
+			FunctionCount:        2,
+			MaxNestingDepth:      1,
 
 			SubmissionTimeDelta: 10000,
 			LinesOfCodeDelta:    0,
@@ -0,0 +1,215 @@
+package analysis
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/analysis/native"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The number of distinct highlight colors in an HTML match report.
+const MATCH_REPORT_NUM_COLORS = 6
+
+// Find the matching regions of code between the files of two submissions (or a submission and a corpus entry).
+// Matching uses the native similarity engine (regardless of what engines are used for pairwise analysis),
+// and will ignore any code that matches the assignment's template files.
+func PairwiseMatchReport(pairwiseKey model.PairwiseKey) (*model.PairwiseMatchReport, error) {
+	tempDir, err := util.MkDirTemp("pairwise-match-report-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make temp dir: '%w'.", err)
+	}
+	defer util.RemoveDirent(tempDir)
+
+	submissionDirs, assignment, err := fetchPairwiseSubmissions(pairwiseKey, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	templateFileStore := NewTemplateFileStore()
+	defer templateFileStore.Close()
+
+	templateDir, err := templateFileStore.GetTemplatePath(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get template files: '%w'.", err)
+	}
+
+	renames, matches, unmatches, skipped, err := preparePairwiseFiles(submissionDirs, assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := native.GetEngine()
+
+	report := &model.PairwiseMatchReport{
+		SubmissionIDs:  pairwiseKey,
+		Files:          make([]*model.FileMatches, 0, len(matches)),
+		UnmatchedFiles: unmatches,
+		SkippedFiles:   skipped,
+	}
+
+	for _, relpath := range matches {
+		templatePath := filepath.Join(templateDir, relpath)
+		if !util.IsFile(templatePath) {
+			templatePath = ""
+		}
+
+		paths := [2]string{
+			filepath.Join(submissionDirs[0], relpath),
+			filepath.Join(submissionDirs[1], relpath),
+		}
+
+		fileMatches := &model.FileMatches{
+			Filename:         relpath,
+			OriginalFilename: renames[relpath],
+		}
+
+		for i, path := range paths {
+			fileMatches.Contents[i], err = util.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read file '%s': '%w'.", relpath, err)
+			}
+		}
+
+		fileMatches.Matches, err = engine.ComputeFileMatches(paths, templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to compute matches for '%s': '%w'.", relpath, err)
+		}
+
+		report.Files = append(report.Files, fileMatches)
+	}
+
+	return report, nil
+}
+
+// Build the key for a match report from two ids (full submission ids or a full submission id and a corpus entry id).
+// Corpus entries are always placed on the RHS.
+func NewMatchReportKey(id1 string, id2 string) (model.PairwiseKey, error) {
+	if model.IsCorpusEntryID(id1) {
+		id1, id2 = id2, id1
+	}
+
+	if model.IsCorpusEntryID(id1) {
+		return model.PairwiseKey{}, fmt.Errorf("Two corpus entries cannot be compared, at least one submission is required.")
+	}
+
+	ids := []string{id1}
+	if !model.IsCorpusEntryID(id2) {
+		ids = append(ids, id2)
+	}
+
+	for _, id := range ids {
+		_, _, _, _, err := common.SplitFullSubmissionID(id)
+		if err != nil {
+			return model.PairwiseKey{}, err
+		}
+	}
+
+	if len(ids) == 1 {
+		return model.PairwiseKey{id1, id2}, nil
+	}
+
+	if id1 == id2 {
+		return model.PairwiseKey{}, fmt.Errorf("Cannot compare a submission against itself: '%s'.", id1)
+	}
+
+	return model.NewPairwiseKey(id1, id2), nil
+}
+
+// Render a match report as a standalone HTML page that shows each file side-by-side with matching regions highlighted.
+func RenderMatchReportHTML(report *model.PairwiseMatchReport) string {
+	var builder strings.Builder
+
+	title := fmt.Sprintf("Match Report: %s vs %s", report.SubmissionIDs[0], report.SubmissionIDs[1])
+
+	builder.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	builder.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	builder.WriteString("<style>\n")
+	builder.WriteString("table.code { border-collapse: collapse; width: 100%; table-layout: fixed; }\n")
+	builder.WriteString("table.code td { vertical-align: top; width: 50%; }\n")
+	builder.WriteString("pre { margin: 0; }\n")
+	builder.WriteString(".line { display: block; }\n")
+	builder.WriteString(".line-number { color: #888888; }\n")
+
+	colors := []string{"#ffd6d6", "#d6ffd6", "#d6d6ff", "#ffffc2", "#ffd6ff", "#c2ffff"}
+	for i := 0; i < MATCH_REPORT_NUM_COLORS; i++ {
+		builder.WriteString(fmt.Sprintf(".match-%d { background-color: %s; }\n", i, colors[i]))
+	}
+
+	builder.WriteString("</style>\n</head>\n<body>\n")
+	builder.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(title)))
+
+	for _, file := range report.Files {
+		builder.WriteString(fmt.Sprintf("<h2>%s</h2>\n", html.EscapeString(file.Filename)))
+
+		if len(file.Matches) == 0 {
+			builder.WriteString("<p>No matches.</p>\n")
+		} else {
+			builder.WriteString("<table class=\"matches\">\n")
+			builder.WriteString("<tr><th>Match</th><th>Lines (Left)</th><th>Lines (Right)</th><th>Length (Tokens)</th></tr>\n")
+			for i, match := range file.Matches {
+				builder.WriteString(fmt.Sprintf("<tr class=\"match-%d\"><td>%d</td><td>%d - %d</td><td>%d - %d</td><td>%d</td></tr>\n",
+					i%MATCH_REPORT_NUM_COLORS, i+1,
+					match.Ranges[0].Start, match.Ranges[0].End,
+					match.Ranges[1].Start, match.Ranges[1].End,
+					match.Length))
+			}
+			builder.WriteString("</table>\n")
+		}
+
+		builder.WriteString("<table class=\"code\">\n<tr>\n")
+		builder.WriteString(fmt.Sprintf("<th>%s</th><th>%s</th>\n", html.EscapeString(report.SubmissionIDs[0]), html.EscapeString(report.SubmissionIDs[1])))
+		builder.WriteString("</tr>\n<tr>\n")
+
+		for side := 0; side < 2; side++ {
+			builder.WriteString("<td><pre>")
+			renderMatchReportCode(&builder, file, side)
+			builder.WriteString("</pre></td>\n")
+		}
+
+		builder.WriteString("</tr>\n</table>\n")
+	}
+
+	if len(report.UnmatchedFiles) > 0 {
+		builder.WriteString("<h2>Unmatched Files</h2>\n<ul>\n")
+		for _, unmatch := range report.UnmatchedFiles {
+			builder.WriteString(fmt.Sprintf("<li>%s</li>\n", html.EscapeString(strings.Join(unmatch[:], " / "))))
+		}
+		builder.WriteString("</ul>\n")
+	}
+
+	if len(report.SkippedFiles) > 0 {
+		builder.WriteString("<h2>Skipped Files</h2>\n<ul>\n")
+		for _, skipped := range report.SkippedFiles {
+			builder.WriteString(fmt.Sprintf("<li>%s</li>\n", html.EscapeString(skipped)))
+		}
+		builder.WriteString("</ul>\n")
+	}
+
+	builder.WriteString("</body>\n</html>\n")
+
+	return builder.String()
+}
+
+// Write out the code for one side of a file, with each line highlighted by the (first) match that covers it.
+func renderMatchReportCode(builder *strings.Builder, file *model.FileMatches, side int) {
+	lines := strings.Split(strings.TrimSuffix(file.Contents[side], "\n"), "\n")
+
+	for i, line := range lines {
+		lineNumber := i + 1
+
+		attributes := "class=\"line\""
+		for matchIndex, match := range file.Matches {
+			if (lineNumber >= match.Ranges[side].Start) && (lineNumber <= match.Ranges[side].End) {
+				attributes = fmt.Sprintf("class=\"line match-%d\" title=\"Match %d\"", matchIndex%MATCH_REPORT_NUM_COLORS, matchIndex+1)
+				break
+			}
+		}
+
+		builder.WriteString(fmt.Sprintf("<span %s><span class=\"line-number\">%4d</span> %s</span>", attributes, lineNumber, html.EscapeString(line)))
+	}
+}
@@ -0,0 +1,199 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestPairwiseMatchReportBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	ids := []string{
+		"course101::hw0::course-student@test.edulinq.org::1697406256",
+		"course101::hw0::course-student@test.edulinq.org::1697406265",
+		"course101::hw0::course-student@test.edulinq.org::1697406272",
+	}
+
+	contents := []string{
+		"def function1():\n    return NotImplemented\n\ndef function2(val):\n    return NotImplemented\n",
+		"def function1():\n    return True\n\ndef function2(val):\n    return NotImplemented\n",
+		"def function1():\n    return True\n\ndef function2(val):\n    return val + 1\n",
+	}
+
+	// Use corpus entries to get copies of submissions.
+	assignment := db.MustGetTestAssignment()
+	defer util.RemoveDirent(assignment.GetCorporaDir())
+
+	templateEntry, err := AddCorpusSubmission(assignment, "test", "", ids[0])
+	if err != nil {
+		test.Fatalf("Failed to add corpus submission: '%v'.", err)
+	}
+
+	entry, err := AddCorpusSubmission(assignment, "test", "", ids[2])
+	if err != nil {
+		test.Fatalf("Failed to add corpus submission: '%v'.", err)
+	}
+
+	testCases := []struct {
+		key      model.PairwiseKey
+		expected *model.PairwiseMatchReport
+	}{
+		// The shared code is too short to match.
+		{
+			model.NewPairwiseKey(ids[0], ids[1]),
+			&model.PairwiseMatchReport{
+				SubmissionIDs: model.NewPairwiseKey(ids[0], ids[1]),
+				Files: []*model.FileMatches{
+					&model.FileMatches{
+						Filename: "submission.py",
+						Contents: [2]string{contents[0], contents[1]},
+						Matches:  []*model.MatchRegion{},
+					},
+				},
+				UnmatchedFiles: [][2]string{},
+				SkippedFiles:   []string{},
+			},
+		},
+		// An exact copy of the template code is ignored.
+		{
+			model.PairwiseKey{ids[0], templateEntry.ID()},
+			&model.PairwiseMatchReport{
+				SubmissionIDs: model.PairwiseKey{ids[0], templateEntry.ID()},
+				Files: []*model.FileMatches{
+					&model.FileMatches{
+						Filename: "submission.py",
+						Contents: [2]string{contents[0], contents[0]},
+						Matches:  []*model.MatchRegion{},
+					},
+				},
+				UnmatchedFiles: [][2]string{},
+				SkippedFiles:   []string{},
+			},
+		},
+		// An exact copy.
+		{
+			model.PairwiseKey{ids[2], entry.ID()},
+			&model.PairwiseMatchReport{
+				SubmissionIDs: model.PairwiseKey{ids[2], entry.ID()},
+				Files: []*model.FileMatches{
+					&model.FileMatches{
+						Filename: "submission.py",
+						Contents: [2]string{contents[2], contents[2]},
+						Matches: []*model.MatchRegion{
+							&model.MatchRegion{
+								Ranges: [2]model.LineRange{
+									model.LineRange{Start: 1, End: 5},
+									model.LineRange{Start: 1, End: 5},
+								},
+								Length: 17,
+							},
+						},
+					},
+				},
+				UnmatchedFiles: [][2]string{},
+				SkippedFiles:   []string{},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		report, err := PairwiseMatchReport(testCase.key)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get match report: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, report) {
+			test.Errorf("Case %d: Unexpected report. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(report))
+			continue
+		}
+	}
+}
+
+func TestRenderMatchReportHTMLBase(test *testing.T) {
+	report := &model.PairwiseMatchReport{
+		SubmissionIDs: model.PairwiseKey{"a", "b"},
+		Files: []*model.FileMatches{
+			&model.FileMatches{
+				Filename: "submission.py",
+				Contents: [2]string{"x = '<b>'\ny = 1\n", "z = 2\nx = '<b>'\n"},
+				Matches: []*model.MatchRegion{
+					&model.MatchRegion{
+						Ranges: [2]model.LineRange{
+							model.LineRange{Start: 1, End: 1},
+							model.LineRange{Start: 2, End: 2},
+						},
+						Length: 3,
+					},
+				},
+			},
+		},
+		SkippedFiles: []string{"README.md"},
+	}
+
+	html := RenderMatchReportHTML(report)
+
+	expectedParts := []string{
+		"<title>Match Report: a vs b</title>",
+		"<h2>submission.py</h2>",
+		"<tr class=\"match-0\"><td>1</td><td>1 - 1</td><td>2 - 2</td><td>3</td></tr>",
+		"<span class=\"line match-0\" title=\"Match 1\"><span class=\"line-number\">   1</span> x = &#39;&lt;b&gt;&#39;</span><span class=\"line\"><span class=\"line-number\">   2</span> y = 1</span>",
+		"<span class=\"line\"><span class=\"line-number\">   1</span> z = 2</span><span class=\"line match-0\" title=\"Match 1\"><span class=\"line-number\">   2</span> x = &#39;&lt;b&gt;&#39;</span>",
+		"<li>README.md</li>",
+	}
+
+	for i, part := range expectedParts {
+		if !strings.Contains(html, part) {
+			test.Errorf("Case %d: Could not find expected part '%s' in HTML: '%s'.", i, part, html)
+		}
+	}
+}
+
+func TestNewMatchReportKey(test *testing.T) {
+	sub1 := "course101::hw0::course-student@test.edulinq.org::1697406256"
+	sub2 := "course101::hw0::course-student@test.edulinq.org::1697406265"
+	corpus := "corpus::public::solution::abc"
+
+	testCases := []struct {
+		ids      [2]string
+		expected model.PairwiseKey
+		hasError bool
+	}{
+		{[2]string{sub1, sub2}, model.PairwiseKey{sub1, sub2}, false},
+		{[2]string{sub2, sub1}, model.PairwiseKey{sub1, sub2}, false},
+		{[2]string{sub2, corpus}, model.PairwiseKey{sub2, corpus}, false},
+		{[2]string{corpus, sub2}, model.PairwiseKey{sub2, corpus}, false},
+
+		{[2]string{corpus, corpus}, model.PairwiseKey{}, true},
+		{[2]string{sub1, sub1}, model.PairwiseKey{}, true},
+		{[2]string{sub1, "zzz"}, model.PairwiseKey{}, true},
+		{[2]string{"zzz", corpus}, model.PairwiseKey{}, true},
+	}
+
+	for i, testCase := range testCases {
+		actual, err := NewMatchReportKey(testCase.ids[0], testCase.ids[1])
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected key. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// A similarity engine that runs in-process (without Docker or any external tools).
+// Files are tokenized (using a language inferred from the file extension),
+// fingerprinted with winnowing, and then compared by their shared fingerprints.
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	NAME    = "native"
+	VERSION = "1.0.0"
+
+	DEFAULT_KGRAM_SIZE  = 12
+	DEFAULT_WINDOW_SIZE = 8
+)
+
+type NativeEngine struct {
+	// The number of tokens in each hashed k-gram.
+	KGramSize int
+	// The number of k-grams in each winnowing window.
+	WindowSize int
+}
+
+func GetEngine() *NativeEngine {
+	return &NativeEngine{
+		KGramSize:  DEFAULT_KGRAM_SIZE,
+		WindowSize: DEFAULT_WINDOW_SIZE,
+	}
+}
+
+func (this *NativeEngine) GetName() string {
+	return NAME
+}
+
+func (this *NativeEngine) IsAvailable() bool {
+	return true
+}
+
+func (this *NativeEngine) ComputeFileSimilarity(paths [2]string, templatePath string) (*model.FileSimilarity, int64, error) {
+	startTime := timestamp.Now()
+
+	// Any k-gram that appears in the template is ignored.
+	templateHashes, err := this.getTemplateHashes(templatePath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var fingerprints [2]map[uint64]bool
+	for i, path := range paths {
+		tokens, err := TokenizeFile(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed to tokenize file: '%w'.", err)
+		}
+
+		fingerprints[i] = fingerprintSet(Winnow(tokens, this.KGramSize, this.WindowSize), templateHashes)
+	}
+
+	result := model.FileSimilarity{
+		Filename: filepath.Base(paths[0]),
+		Tool:     NAME,
+		Version:  VERSION,
+		Score:    computeScore(fingerprints[0], fingerprints[1]),
+	}
+
+	// This engine is fast, so make sure that a run is always counted.
+	runTime := max(1, (timestamp.Now() - startTime).ToMSecs())
+
+	return &result, runTime, nil
+}
+
+func (this *NativeEngine) LogValue() []*log.Attr {
+	return []*log.Attr{
+		log.NewAttr("similarity-engine", NAME),
+		log.NewAttr("version", VERSION),
+	}
+}
+
+// Find the regions of code that match between two files (ignoring any code that matches the template file).
+func (this *NativeEngine) ComputeFileMatches(paths [2]string, templatePath string) ([]*model.MatchRegion, error) {
+	templateHashes, err := this.getTemplateHashes(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens [2][]*Token
+	for i, path := range paths {
+		tokens[i], err = TokenizeFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to tokenize file: '%w'.", err)
+		}
+	}
+
+	matches := FindMatches(tokens, this.KGramSize, templateHashes)
+
+	regions := make([]*model.MatchRegion, 0, len(matches))
+	for _, match := range matches {
+		region := &model.MatchRegion{
+			Length: match.Length,
+		}
+
+		for i := 0; i < 2; i++ {
+			region.Ranges[i] = model.LineRange{
+				Start: tokens[i][match.Starts[i]].StartLine,
+				End:   tokens[i][match.Starts[i]+match.Length-1].EndLine,
+			}
+		}
+
+		regions = append(regions, region)
+	}
+
+	return regions, nil
+}
+
+// Get the hashes of all the k-grams in a template file (if one is given).
+func (this *NativeEngine) getTemplateHashes(templatePath string) (map[uint64]bool, error) {
+	templateHashes := make(map[uint64]bool)
+	if templatePath == "" {
+		return templateHashes, nil
+	}
+
+	tokens, err := TokenizeFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to tokenize template file: '%w'.", err)
+	}
+
+	for _, hash := range HashKGrams(tokens, this.KGramSize) {
+		templateHashes[hash] = true
+	}
+
+	return templateHashes, nil
+}
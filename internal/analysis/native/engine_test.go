@@ -0,0 +1,40 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/analysis/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestNativeComputeFileSimilarityBase(test *testing.T) {
+	expected := &model.FileSimilarity{
+		Filename: "submission.py",
+		Tool:     NAME,
+		Version:  VERSION,
+		Score:    0.848485,
+	}
+
+	// Lower the k-gram size for testing.
+	engine := GetEngine()
+	engine.KGramSize = 5
+	engine.WindowSize = 4
+
+	core.RunEngineTestComputeFileSimilarityNoDocker(test, engine, false, expected)
+}
+
+func TestNativeComputeFileSimilarityWithIgnoreBase(test *testing.T) {
+	expected := &model.FileSimilarity{
+		Filename: "submission.py",
+		Tool:     NAME,
+		Version:  VERSION,
+		Score:    0.814815,
+	}
+
+	// Lower the k-gram size for testing.
+	engine := GetEngine()
+	engine.KGramSize = 5
+	engine.WindowSize = 4
+
+	core.RunEngineTestComputeFileSimilarityNoDocker(test, engine, true, expected)
+}
@@ -0,0 +1,200 @@
+package native
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	DEFAULT_LANGUAGE = "text"
+
+	LANG_C          = "c"
+	LANG_CPP        = "cpp"
+	LANG_JAVA       = "java"
+	LANG_JAVASCRIPT = "javascript"
+	LANG_PYTHON3    = "python"
+)
+
+// The information needed to tokenize a language.
+type language struct {
+	Name string
+
+	LineComments  []string
+	BlockComments [][2]string
+	// Longer delimiters should come first (e.g., Python's triple quotes).
+	StringDelimiters []string
+
+	Keywords map[string]bool
+	// Multi-character operators (single characters are always operators).
+	Operators []string
+
+	// Replace identifiers (that are not keywords) with a generic token,
+	// so renaming variables does not change the token stream.
+	NormalizeIdentifiers bool
+}
+
+// {extension (with period): language name, ...}
+var extensionToLanguage map[string]string = map[string]string{
+	".c": LANG_C,
+	".h": LANG_C,
+
+	".cpp": LANG_CPP,
+	".c++": LANG_CPP,
+	".cxx": LANG_CPP,
+	".cc":  LANG_CPP,
+	".cp":  LANG_CPP,
+	".h++": LANG_CPP,
+	".hxx": LANG_CPP,
+	".hh":  LANG_CPP,
+	".hpp": LANG_CPP,
+
+	".java": LANG_JAVA,
+
+	".js":  LANG_JAVASCRIPT,
+	".mjs": LANG_JAVASCRIPT,
+	".cjs": LANG_JAVASCRIPT,
+
+	".py":  LANG_PYTHON3,
+	".py3": LANG_PYTHON3,
+}
+
+var cOperators []string = []string{
+	"<<=", ">>=", "->", "++", "--", "<<", ">>", "<=", ">=", "==", "!=", "&&", "||",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "::",
+}
+
+var cKeywords []string = []string{
+	"auto", "break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum", "extern",
+	"float", "for", "goto", "if", "inline", "int", "long", "register", "restrict", "return", "short", "signed",
+	"sizeof", "static", "struct", "switch", "typedef", "union", "unsigned", "void", "volatile", "while",
+	"bool", "true", "false", "NULL",
+	"define", "include", "ifdef", "ifndef", "endif", "pragma",
+}
+
+var cppKeywords []string = []string{
+	"catch", "class", "constexpr", "delete", "explicit", "friend", "namespace", "new", "nullptr", "operator",
+	"private", "protected", "public", "template", "this", "throw", "try", "typename", "using", "virtual",
+}
+
+var languages map[string]*language = map[string]*language{
+	LANG_C: &language{
+		Name:                 LANG_C,
+		LineComments:         []string{"//"},
+		BlockComments:        [][2]string{{"/*", "*/"}},
+		StringDelimiters:     []string{"\"", "'"},
+		Keywords:             makeKeywords(cKeywords),
+		Operators:            cOperators,
+		NormalizeIdentifiers: true,
+	},
+	LANG_CPP: &language{
+		Name:                 LANG_CPP,
+		LineComments:         []string{"//"},
+		BlockComments:        [][2]string{{"/*", "*/"}},
+		StringDelimiters:     []string{"\"", "'"},
+		Keywords:             makeKeywords(cKeywords, cppKeywords),
+		Operators:            cOperators,
+		NormalizeIdentifiers: true,
+	},
+	LANG_JAVA: &language{
+		Name:             LANG_JAVA,
+		LineComments:     []string{"//"},
+		BlockComments:    [][2]string{{"/*", "*/"}},
+		StringDelimiters: []string{"\"\"\"", "\"", "'"},
+		Keywords: makeKeywords([]string{
+			"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const", "continue",
+			"default", "do", "double", "else", "enum", "extends", "final", "finally", "float", "for", "if",
+			"implements", "import", "instanceof", "int", "interface", "long", "new", "package", "private",
+			"protected", "public", "return", "short", "static", "super", "switch", "synchronized", "this", "throw",
+			"throws", "try", "var", "void", "while", "true", "false", "null",
+		}),
+		Operators: []string{
+			">>>=", "<<=", ">>=", ">>>", "->", "++", "--", "<<", ">>", "<=", ">=", "==", "!=", "&&", "||",
+			"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "::",
+		},
+		NormalizeIdentifiers: true,
+	},
+	LANG_JAVASCRIPT: &language{
+		Name:             LANG_JAVASCRIPT,
+		LineComments:     []string{"//"},
+		BlockComments:    [][2]string{{"/*", "*/"}},
+		StringDelimiters: []string{"`", "\"", "'"},
+		Keywords: makeKeywords([]string{
+			"async", "await", "break", "case", "catch", "class", "const", "continue", "default", "delete", "do",
+			"else", "export", "extends", "finally", "for", "function", "if", "import", "in", "instanceof", "let",
+			"new", "of", "return", "static", "super", "switch", "this", "throw", "try", "typeof", "var", "void",
+			"while", "yield", "true", "false", "null", "undefined",
+		}),
+		Operators: []string{
+			">>>=", "===", "!==", "**=", "<<=", ">>=", ">>>", "...", "=>", "?.", "??", "**", "++", "--", "<<", ">>",
+			"<=", ">=", "==", "!=", "&&", "||", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+		},
+		NormalizeIdentifiers: true,
+	},
+	LANG_PYTHON3: &language{
+		Name:             LANG_PYTHON3,
+		LineComments:     []string{"#"},
+		StringDelimiters: []string{"\"\"\"", "'''", "\"", "'"},
+		Keywords: makeKeywords([]string{
+			"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else",
+			"except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not",
+			"or", "pass", "raise", "return", "try", "while", "with", "yield", "True", "False", "None",
+		}),
+		Operators: []string{
+			"**=", "//=", ">>=", "<<=", "**", "//", "<<", ">>", "<=", ">=", "==", "!=", "->", ":=",
+			"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "@=",
+		},
+		NormalizeIdentifiers: true,
+	},
+
+	// Plain text is compared word-by-word.
+	DEFAULT_LANGUAGE: &language{
+		Name:     DEFAULT_LANGUAGE,
+		Keywords: map[string]bool{},
+	},
+}
+
+func init() {
+	// Operators are matched greedily, so make sure longer operators are checked first.
+	for _, lang := range languages {
+		slices.SortStableFunc(lang.Operators, func(a string, b string) int {
+			return len(b) - len(a)
+		})
+	}
+}
+
+func makeKeywords(lists ...[]string) map[string]bool {
+	keywords := make(map[string]bool)
+	for _, list := range lists {
+		for _, keyword := range list {
+			keywords[keyword] = true
+		}
+	}
+
+	return keywords
+}
+
+func getLanguageName(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	language, ok := extensionToLanguage[ext]
+	if ok {
+		return language
+	}
+
+	return DEFAULT_LANGUAGE
+}
+
+func getLanguage(path string) *language {
+	return languages[getLanguageName(path)]
+}
+
+// Get a language by name, unknown languages will get the default language.
+func getLanguageByName(name string) *language {
+	lang := languages[name]
+	if lang == nil {
+		lang = languages[DEFAULT_LANGUAGE]
+	}
+
+	return lang
+}
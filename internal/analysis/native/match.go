@@ -0,0 +1,114 @@
+package native
+
+import (
+	"slices"
+)
+
+// A run of (normalized) tokens that appears in two token lists.
+type Match struct {
+	// The index of the first matching token in each token list.
+	Starts [2]int
+	// The number of matching tokens.
+	Length int
+}
+
+// Find the non-overlapping runs of matching tokens between two token lists.
+// Matches are seeded by shared k-grams (ignoring any k-grams with hashes in the ignore set, e.g., template code),
+// extended as far as the shared k-grams go, and then chosen greedily from longest to shortest (so each token is only in one match).
+// Returned matches are ordered by their position in the first token list.
+func FindMatches(tokens [2][]*Token, k int, ignore map[uint64]bool) []*Match {
+	if (len(tokens[0]) == 0) || (len(tokens[1]) == 0) {
+		return []*Match{}
+	}
+
+	k = max(1, min(k, len(tokens[0]), len(tokens[1])))
+
+	hashes := [2][]uint64{
+		HashKGrams(tokens[0], k),
+		HashKGrams(tokens[1], k),
+	}
+
+	// {hash: [index, ...], ...}
+	rightIndexes := make(map[uint64][]int)
+	for index, hash := range hashes[1] {
+		if ignore[hash] {
+			continue
+		}
+
+		rightIndexes[hash] = append(rightIndexes[hash], index)
+	}
+
+	isShared := func(left int, right int) bool {
+		if (left < 0) || (right < 0) || (left >= len(hashes[0])) || (right >= len(hashes[1])) {
+			return false
+		}
+
+		return (hashes[0][left] == hashes[1][right]) && !ignore[hashes[0][left]]
+	}
+
+	// Find all maximal runs of shared k-grams.
+	candidates := make([]*Match, 0)
+	for left, hash := range hashes[0] {
+		for _, right := range rightIndexes[hash] {
+			// Only start at the beginning of a run.
+			if isShared(left-1, right-1) {
+				continue
+			}
+
+			kgramCount := 1
+			for isShared(left+kgramCount, right+kgramCount) {
+				kgramCount++
+			}
+
+			candidates = append(candidates, &Match{
+				Starts: [2]int{left, right},
+				Length: kgramCount + k - 1,
+			})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a *Match, b *Match) int {
+		return b.Length - a.Length
+	})
+
+	// Greedily take the longest matches that do not overlap with any taken match.
+	used := [2][]bool{
+		make([]bool, len(tokens[0])),
+		make([]bool, len(tokens[1])),
+	}
+
+	matches := make([]*Match, 0)
+	for _, candidate := range candidates {
+		if overlaps(used[0], candidate.Starts[0], candidate.Length) || overlaps(used[1], candidate.Starts[1], candidate.Length) {
+			continue
+		}
+
+		for i := 0; i < 2; i++ {
+			for offset := 0; offset < candidate.Length; offset++ {
+				used[i][candidate.Starts[i]+offset] = true
+			}
+		}
+
+		matches = append(matches, candidate)
+	}
+
+	slices.SortFunc(matches, func(a *Match, b *Match) int {
+		if a.Starts[0] != b.Starts[0] {
+			return a.Starts[0] - b.Starts[0]
+		}
+
+		return a.Starts[1] - b.Starts[1]
+	})
+
+	return matches
+}
+
+func overlaps(used []bool, start int, length int) bool {
+	for offset := 0; offset < length; offset++ {
+		if used[start+offset] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package native
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestFindMatchesBase(test *testing.T) {
+	testCases := []struct {
+		sources  [2]string
+		k        int
+		ignore   []string
+		expected []*Match
+	}{
+		// Identical.
+		{
+			[2]string{"a = 1\nb = 2\n", "x = 3\ny = 4\n"},
+			3,
+			nil,
+			[]*Match{
+				&Match{[2]int{0, 0}, 6},
+			},
+		},
+		// No matches.
+		{
+			[2]string{"a = 1\n", "if x:\n    pass\n"},
+			3,
+			nil,
+			[]*Match{},
+		},
+		// Empty.
+		{
+			[2]string{"", "a = 1\n"},
+			3,
+			nil,
+			[]*Match{},
+		},
+		// Reordered blocks.
+		{
+			[2]string{
+				"def f(a):\n    return a + 1\n\nwhile True:\n    pass\n",
+				"while True:\n    pass\n\ndef g(b):\n    return b + 1\n",
+			},
+			3,
+			nil,
+			[]*Match{
+				&Match{[2]int{0, 4}, 10},
+				&Match{[2]int{10, 0}, 4},
+			},
+		},
+		// Ignored k-grams (e.g., a template) split up a match.
+		{
+			[2]string{"a = 1\nb = 2\nc = 3\n", "x = 1\ny = 2\nz = 3\n"},
+			3,
+			[]string{"b = 2\n"},
+			[]*Match{
+				&Match{[2]int{1, 1}, 4},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		tokens := [2][]*Token{
+			Tokenize(testCase.sources[0], "python"),
+			Tokenize(testCase.sources[1], "python"),
+		}
+
+		ignore := make(map[uint64]bool)
+		for _, source := range testCase.ignore {
+			for _, hash := range HashKGrams(Tokenize(source, "python"), testCase.k) {
+				ignore[hash] = true
+			}
+		}
+
+		actual := FindMatches(tokens, testCase.k, ignore)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected matches. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+		}
+	}
+}
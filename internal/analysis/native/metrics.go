@@ -0,0 +1,248 @@
+package native
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Code quality metrics for a single source file.
+type Metrics struct {
+	Language string
+
+	// McCabe's cyclomatic complexity for the entire file:
+	// the number of decision points plus one for each function (or one if there are no functions).
+	CyclomaticComplexity int
+	FunctionCount        int
+
+	// The number of lines that have a comment on them.
+	CommentLines int
+	// The number of lines that have code or comments on them.
+	Lines int
+
+	// The deepest level of nested blocks (by braces or, for Python, indentation).
+	// Top-level code has a depth of zero.
+	MaxNestingDepth int
+}
+
+// Tokens (after normalization) that add a decision point to the cyclomatic complexity.
+// Note that Python's "if" covers ternaries and comprehensions.
+var decisionTokens map[string]map[string]bool = map[string]map[string]bool{
+	LANG_C:          makeKeywords([]string{"if", "for", "while", "case", "&&", "||", "?"}),
+	LANG_CPP:        makeKeywords([]string{"if", "for", "while", "case", "catch", "&&", "||", "?"}),
+	LANG_JAVA:       makeKeywords([]string{"if", "for", "while", "case", "catch", "&&", "||", "?"}),
+	LANG_JAVASCRIPT: makeKeywords([]string{"if", "for", "while", "case", "catch", "&&", "||", "??", "?"}),
+	LANG_PYTHON3:    makeKeywords([]string{"if", "elif", "for", "while", "except", "and", "or"}),
+}
+
+// Tokens (after normalization) that may appear between the closing paren of a function's parameters and the opening brace of its body.
+var functionSuffixTokens map[string]bool = makeKeywords([]string{TOKEN_IDENTIFIER, "const", "throws", ",", "."})
+
+// Compute code quality metrics for a file.
+// Returns nil if the file's language is not supported (i.e., it is not a known programming language).
+func ComputeFileMetrics(path string) (*Metrics, error) {
+	lang := getLanguage(path)
+	if lang.Name == DEFAULT_LANGUAGE {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read file '%s': '%w'.", path, err)
+	}
+
+	return ComputeMetrics(string(data), lang.Name), nil
+}
+
+// Compute code quality metrics for source code.
+// Returns nil if the language is not supported.
+func ComputeMetrics(source string, languageName string) *Metrics {
+	lang := getLanguageByName(languageName)
+	if lang.Name == DEFAULT_LANGUAGE {
+		return nil
+	}
+
+	tokens, commentLines := scan(source, lang)
+
+	metrics := &Metrics{
+		Language:     lang.Name,
+		CommentLines: len(commentLines),
+	}
+
+	lines := make(map[int]bool, len(commentLines))
+	for line, _ := range commentLines {
+		lines[line] = true
+	}
+
+	decisions := 0
+	for _, token := range tokens {
+		for line := token.StartLine; line <= token.EndLine; line++ {
+			lines[line] = true
+		}
+
+		if decisionTokens[lang.Name][token.Text] {
+			decisions++
+		}
+	}
+
+	metrics.Lines = len(lines)
+
+	if lang.Name == LANG_PYTHON3 {
+		metrics.FunctionCount = countPythonFunctions(tokens)
+		metrics.MaxNestingDepth = computePythonNestingDepth(source, tokens)
+	} else {
+		metrics.FunctionCount = countBraceFunctions(tokens)
+		metrics.MaxNestingDepth = computeBraceNestingDepth(tokens)
+	}
+
+	metrics.CyclomaticComplexity = decisions + max(1, metrics.FunctionCount)
+
+	return metrics
+}
+
+// Get the ratio of lines with comments to all lines with code or comments.
+func (this *Metrics) CommentRatio() float64 {
+	if this.Lines == 0 {
+		return 0.0
+	}
+
+	return float64(this.CommentLines) / float64(this.Lines)
+}
+
+func countPythonFunctions(tokens []*Token) int {
+	count := 0
+	for _, token := range tokens {
+		if (token.Text == "def") || (token.Text == "lambda") {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Count functions in brace languages (C, C++, Java, JavaScript).
+// A function is either a JavaScript "function" or arrow function,
+// or a body ('{') following a parameter list that follows a name (e.g., "int foo(int x) {").
+func countBraceFunctions(tokens []*Token) int {
+	count := 0
+
+	for i, token := range tokens {
+		if (token.Text == "function") || (token.Text == "=>") {
+			count++
+			continue
+		}
+
+		if token.Text != "{" {
+			continue
+		}
+
+		// Skip back over any qualifiers to find the end of the parameters.
+		index := i - 1
+		for (index >= 0) && (tokens[index].Text != ")") && functionSuffixTokens[tokens[index].Text] {
+			index--
+		}
+
+		if (index < 0) || (tokens[index].Text != ")") {
+			continue
+		}
+
+		// Find the start of the parameters.
+		depth := 0
+		for ; index >= 0; index-- {
+			if tokens[index].Text == ")" {
+				depth++
+			} else if tokens[index].Text == "(" {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+
+		// Control statements (e.g., "if (x) {") have a keyword instead of a name.
+		nameIndex := index - 1
+		if (nameIndex < 0) || (tokens[nameIndex].Text != TOKEN_IDENTIFIER) {
+			continue
+		}
+
+		// Skip JavaScript named functions (which were already counted) and anonymous classes.
+		if (nameIndex > 0) && ((tokens[nameIndex-1].Text == "function") || (tokens[nameIndex-1].Text == "new")) {
+			continue
+		}
+
+		count++
+	}
+
+	return count
+}
+
+func computeBraceNestingDepth(tokens []*Token) int {
+	depth := 0
+	maxDepth := 0
+
+	for _, token := range tokens {
+		if token.Text == "{" {
+			depth++
+			maxDepth = max(maxDepth, depth)
+		} else if token.Text == "}" {
+			depth = max(0, depth-1)
+		}
+	}
+
+	return maxDepth
+}
+
+// Python nesting is based on the indentation of each logical line (lines inside brackets or strings are ignored).
+func computePythonNestingDepth(source string, tokens []*Token) int {
+	sourceLines := strings.Split(source, "\n")
+
+	// The indentation of each open block (the top-level is always open).
+	indents := []int{0}
+	maxDepth := 0
+
+	bracketDepth := 0
+	lastLine := 0
+
+	for _, token := range tokens {
+		if (token.StartLine != lastLine) && (bracketDepth == 0) && (token.StartLine <= len(sourceLines)) {
+			indent := getIndent(sourceLines[token.StartLine-1])
+
+			for (len(indents) > 1) && (indent < indents[len(indents)-1]) {
+				indents = indents[:len(indents)-1]
+			}
+
+			if indent > indents[len(indents)-1] {
+				indents = append(indents, indent)
+			}
+
+			maxDepth = max(maxDepth, len(indents)-1)
+		}
+
+		lastLine = token.EndLine
+
+		switch token.Text {
+		case "(", "[", "{":
+			bracketDepth++
+		case ")", "]", "}":
+			bracketDepth = max(0, bracketDepth-1)
+		}
+	}
+
+	return maxDepth
+}
+
+// Get the width of a line's leading whitespace (tabs count as four spaces).
+func getIndent(line string) int {
+	indent := 0
+	for _, char := range line {
+		if char == ' ' {
+			indent++
+		} else if char == '\t' {
+			indent += 4
+		} else {
+			break
+		}
+	}
+
+	return indent
+}
@@ -0,0 +1,173 @@
+package native
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestComputeMetricsBase(test *testing.T) {
+	testCases := []struct {
+		language string
+		source   string
+		expected *Metrics
+	}{
+		{
+			LANG_PYTHON3,
+			`# A comment.
+def f(x):
+    """ Docstring. """
+    if x and (x > 0 or
+            x < -10):
+        for i in range(x):
+            print(i)
+    return [y for y in range(x) if y % 2]
+
+g = lambda: 0
+`,
+			&Metrics{
+				Language:             LANG_PYTHON3,
+				CyclomaticComplexity: 8,
+				FunctionCount:        2,
+				CommentLines:         1,
+				Lines:                9,
+				MaxNestingDepth:      3,
+			},
+		},
+		{
+			LANG_PYTHON3,
+			"x = 1\n",
+			&Metrics{
+				Language:             LANG_PYTHON3,
+				CyclomaticComplexity: 1,
+				FunctionCount:        0,
+				CommentLines:         0,
+				Lines:                1,
+				MaxNestingDepth:      0,
+			},
+		},
+		{
+			LANG_C,
+			`/* Block
+   comment. */
+int main(int argc, char ** argv) {
+    if (argc > 1 && argv != NULL) { // Line comment.
+        return argc > 2 ? 1 : 0;
+    }
+
+    while (1) {
+        switch (argc) {
+            case 1:
+                break;
+            case 2:
+                break;
+        }
+    }
+
+    return 0;
+}
+`,
+			&Metrics{
+				Language:             LANG_C,
+				CyclomaticComplexity: 7,
+				FunctionCount:        1,
+				CommentLines:         3,
+				Lines:                16,
+				MaxNestingDepth:      3,
+			},
+		},
+		{
+			LANG_JAVA,
+			`public class Foo {
+    public Foo() {
+    }
+
+    public int bar(int x) throws IOException, Exception {
+        try {
+            return x;
+        } catch (Exception ex) {
+            return 0;
+        }
+    }
+
+    public Runnable baz() {
+        return new Runnable() {
+            public void run() {
+            }
+        };
+    }
+}
+`,
+			&Metrics{
+				Language:             LANG_JAVA,
+				CyclomaticComplexity: 5,
+				FunctionCount:        4,
+				CommentLines:         0,
+				Lines:                17,
+				MaxNestingDepth:      4,
+			},
+		},
+		{
+			LANG_JAVASCRIPT,
+			`function foo(a) {
+    return a ?? 1;
+}
+
+const bar = function(b) {
+    return b;
+};
+
+const baz = (c) => {
+    for (let i = 0; i < c; i++) {
+    }
+};
+
+class Qux {
+    method(d) {
+        return d || 0;
+    }
+}
+`,
+			&Metrics{
+				Language:             LANG_JAVASCRIPT,
+				CyclomaticComplexity: 7,
+				FunctionCount:        4,
+				CommentLines:         0,
+				Lines:                15,
+				MaxNestingDepth:      2,
+			},
+		},
+		{
+			DEFAULT_LANGUAGE,
+			"Some text.\n",
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := ComputeMetrics(testCase.source, testCase.language)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected metrics. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+		}
+	}
+}
+
+func TestMetricsCommentRatio(test *testing.T) {
+	testCases := []struct {
+		metrics  Metrics
+		expected float64
+	}{
+		{Metrics{}, 0.0},
+		{Metrics{CommentLines: 1, Lines: 4}, 0.25},
+		{Metrics{CommentLines: 2, Lines: 2}, 1.0},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.metrics.CommentRatio()
+		if !util.IsClose(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected comment ratio. Expected: %f, Actual: %f.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package native
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const (
+	TOKEN_IDENTIFIER = "ID"
+	TOKEN_NUMBER     = "NUM"
+	TOKEN_STRING     = "STR"
+)
+
+// A single (normalized) token from a source file.
+type Token struct {
+	Text string
+	// The (1-indexed) lines this token starts and ends on.
+	StartLine int
+	EndLine   int
+}
+
+// Tokenize a file using the language inferred from its extension.
+func TokenizeFile(path string) ([]*Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read file '%s': '%w'.", path, err)
+	}
+
+	return Tokenize(string(data), getLanguageName(path)), nil
+}
+
+// Tokenize source code.
+// Comments and whitespace are dropped, and literals (and identifiers, for code) are replaced with generic tokens.
+// Unknown languages will be tokenized as plain text.
+func Tokenize(source string, languageName string) []*Token {
+	tokens, _ := scan(source, getLanguageByName(languageName))
+	return tokens
+}
+
+// Tokenize source code and also get the (1-indexed) lines that have comments on them.
+func scan(source string, lang *language) ([]*Token, map[int]bool) {
+	runes := []rune(source)
+	tokens := make([]*Token, 0)
+	commentLines := make(map[int]bool)
+
+	line := 1
+	index := 0
+
+	for index < len(runes) {
+		char := runes[index]
+
+		if char == '\n' {
+			line++
+			index++
+			continue
+		}
+
+		if unicode.IsSpace(char) {
+			index++
+			continue
+		}
+
+		// Line comments.
+		if hasAnyPrefix(runes, index, lang.LineComments) != "" {
+			commentLines[line] = true
+
+			for (index < len(runes)) && (runes[index] != '\n') {
+				index++
+			}
+
+			continue
+		}
+
+		// Block comments.
+		comment := getBlockComment(runes, index, lang.BlockComments)
+		if comment != nil {
+			startIndex := index
+			index = skipUntil(runes, index+len([]rune(comment[0])), comment[1], false)
+
+			endLine := line + countNewlines(runes[startIndex:index])
+			for commentLine := line; commentLine <= endLine; commentLine++ {
+				commentLines[commentLine] = true
+			}
+
+			line = endLine
+			continue
+		}
+
+		// Strings.
+		delimiter := hasAnyPrefix(runes, index, lang.StringDelimiters)
+		if delimiter != "" {
+			startIndex := index
+			startLine := line
+
+			index = skipUntil(runes, index+len([]rune(delimiter)), delimiter, true)
+			line += countNewlines(runes[startIndex:index])
+
+			tokens = append(tokens, &Token{TOKEN_STRING, startLine, line})
+			continue
+		}
+
+		// Identifiers and keywords.
+		if unicode.IsLetter(char) || (char == '_') || (char == '$') {
+			startIndex := index
+			for (index < len(runes)) && (unicode.IsLetter(runes[index]) || unicode.IsDigit(runes[index]) || (runes[index] == '_') || (runes[index] == '$')) {
+				index++
+			}
+
+			text := string(runes[startIndex:index])
+			if lang.NormalizeIdentifiers && !lang.Keywords[text] {
+				text = TOKEN_IDENTIFIER
+			}
+
+			tokens = append(tokens, &Token{text, line, line})
+			continue
+		}
+
+		// Numbers.
+		if unicode.IsDigit(char) {
+			for (index < len(runes)) && (unicode.IsLetter(runes[index]) || unicode.IsDigit(runes[index]) || (runes[index] == '_') || (runes[index] == '.')) {
+				index++
+			}
+
+			tokens = append(tokens, &Token{TOKEN_NUMBER, line, line})
+			continue
+		}
+
+		// Operators and punctuation.
+		operator := hasAnyPrefix(runes, index, lang.Operators)
+		if operator == "" {
+			operator = string(char)
+		}
+
+		index += len([]rune(operator))
+		tokens = append(tokens, &Token{operator, line, line})
+	}
+
+	return tokens, commentLines
+}
+
+// Get the first prefix that appears at the given index (or an empty string).
+func hasAnyPrefix(runes []rune, index int, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if hasPrefix(runes, index, prefix) {
+			return prefix
+		}
+	}
+
+	return ""
+}
+
+func hasPrefix(runes []rune, index int, prefix string) bool {
+	for _, char := range prefix {
+		if (index >= len(runes)) || (runes[index] != char) {
+			return false
+		}
+
+		index++
+	}
+
+	return true
+}
+
+// Get the block comment (start and end) that starts at this index (or nil).
+func getBlockComment(runes []rune, index int, comments [][2]string) *[2]string {
+	for i, comment := range comments {
+		if hasPrefix(runes, index, comment[0]) {
+			return &comments[i]
+		}
+	}
+
+	return nil
+}
+
+// Get the index just after the next occurrence of end (or the end of the runes).
+func skipUntil(runes []rune, index int, end string, allowEscapes bool) int {
+	for index < len(runes) {
+		if allowEscapes && (runes[index] == '\\') {
+			index += 2
+			continue
+		}
+
+		if hasPrefix(runes, index, end) {
+			return index + len([]rune(end))
+		}
+
+		index++
+	}
+
+	return min(index, len(runes))
+}
+
+func countNewlines(runes []rune) int {
+	return strings.Count(string(runes), "\n")
+}
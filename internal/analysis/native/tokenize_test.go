@@ -0,0 +1,82 @@
+package native
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeBase(test *testing.T) {
+	testCases := []struct {
+		language string
+		source   string
+		expected []string
+	}{
+		{
+			LANG_PYTHON3,
+			"# Comment.\ndef foo(a, b):\n    \"\"\"\n    Doc.\n    \"\"\"\n    return a + 1.5 ** b\n",
+			[]string{"def", "ID", "(", "ID", ",", "ID", ")", ":", "STR", "return", "ID", "+", "NUM", "**", "ID"},
+		},
+		{
+			LANG_JAVA,
+			"/* Block\n comment. */\nint x = y >>> 2; // Line comment.\nString s = \"a \\\" b\";",
+			[]string{"int", "ID", "=", "ID", ">>>", "NUM", ";", "ID", "ID", "=", "STR", ";"},
+		},
+		{
+			LANG_C,
+			"#include <stdio.h>\nint main() { return 0; }",
+			[]string{"#", "include", "<", "ID", ".", "ID", ">", "int", "ID", "(", ")", "{", "return", "NUM", ";", "}"},
+		},
+		{
+			LANG_CPP,
+			"std::vector<int> v; v->size();",
+			[]string{"ID", "::", "ID", "<", "int", ">", "ID", ";", "ID", "->", "ID", "(", ")", ";"},
+		},
+		{
+			LANG_JAVASCRIPT,
+			"const f = (a) => a === `x\n${a}`;",
+			[]string{"const", "ID", "=", "(", "ID", ")", "=>", "ID", "===", "STR", ";"},
+		},
+		{
+			DEFAULT_LANGUAGE,
+			"Some text, # not a comment.",
+			[]string{"Some", "text", ",", "#", "not", "a", "comment", "."},
+		},
+		{
+			"zzz",
+			"Unknown language.",
+			[]string{"Unknown", "language", "."},
+		},
+	}
+
+	for i, testCase := range testCases {
+		tokens := Tokenize(testCase.source, testCase.language)
+
+		actual := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			actual = append(actual, token.Text)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected tokens. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestTokenizeLines(test *testing.T) {
+	source := "x = 1\n'''\nmulti\n'''\n\ny = 2"
+	tokens := Tokenize(source, LANG_PYTHON3)
+
+	expected := []*Token{
+		&Token{"ID", 1, 1},
+		&Token{"=", 1, 1},
+		&Token{"NUM", 1, 1},
+		&Token{"STR", 2, 4},
+		&Token{"ID", 6, 6},
+		&Token{"=", 6, 6},
+		&Token{"NUM", 6, 6},
+	}
+
+	if !reflect.DeepEqual(expected, tokens) {
+		test.Fatalf("Unexpected tokens. Expected: '%v', Actual: '%v'.", expected, tokens)
+	}
+}
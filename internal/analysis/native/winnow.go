@@ -0,0 +1,97 @@
+package native
+
+import (
+	"hash/fnv"
+)
+
+// A selected hash of a k-gram of tokens.
+type Fingerprint struct {
+	Hash uint64
+	// The index of the first token in the k-gram.
+	Start int
+}
+
+// Hash every k-gram of tokens.
+// If there are fewer than k tokens (but more than zero), then all the tokens are hashed as a single k-gram.
+func HashKGrams(tokens []*Token, k int) []uint64 {
+	if len(tokens) == 0 {
+		return []uint64{}
+	}
+
+	k = max(1, min(k, len(tokens)))
+
+	hashes := make([]uint64, 0, len(tokens)-k+1)
+	for start := 0; start <= (len(tokens) - k); start++ {
+		hasher := fnv.New64a()
+		for _, token := range tokens[start:(start + k)] {
+			hasher.Write([]byte(token.Text))
+			hasher.Write([]byte{0})
+		}
+
+		hashes = append(hashes, hasher.Sum64())
+	}
+
+	return hashes
+}
+
+// Select fingerprints from the k-gram hashes of tokens using winnowing:
+// the minimum hash (rightmost on ties) of every window of w consecutive hashes is selected (once).
+// Any match of at least (w + k - 1) tokens is guaranteed to share a fingerprint.
+func Winnow(tokens []*Token, k int, w int) []*Fingerprint {
+	hashes := HashKGrams(tokens, k)
+	if len(hashes) == 0 {
+		return []*Fingerprint{}
+	}
+
+	w = max(1, min(w, len(hashes)))
+
+	fingerprints := make([]*Fingerprint, 0)
+	lastSelected := -1
+
+	for start := 0; start <= (len(hashes) - w); start++ {
+		minIndex := start
+		for i := start + 1; i < (start + w); i++ {
+			if hashes[i] <= hashes[minIndex] {
+				minIndex = i
+			}
+		}
+
+		if minIndex != lastSelected {
+			fingerprints = append(fingerprints, &Fingerprint{hashes[minIndex], minIndex})
+			lastSelected = minIndex
+		}
+	}
+
+	return fingerprints
+}
+
+// Get the set of hashes for some fingerprints, ignoring any hashes in the given ignore set.
+func fingerprintSet(fingerprints []*Fingerprint, ignore map[uint64]bool) map[uint64]bool {
+	result := make(map[uint64]bool, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		if ignore[fingerprint.Hash] {
+			continue
+		}
+
+		result[fingerprint.Hash] = true
+	}
+
+	return result
+}
+
+// Compute the similarity (Sørensen–Dice coefficient) of two sets of fingerprint hashes.
+// Two empty sets have no similarity.
+func computeScore(a map[uint64]bool, b map[uint64]bool) float64 {
+	if (len(a) + len(b)) == 0 {
+		return 0.0
+	}
+
+	shared := 0
+	for hash, _ := range a {
+		if b[hash] {
+			shared++
+		}
+	}
+
+	return float64(2*shared) / float64(len(a)+len(b))
+}
@@ -0,0 +1,86 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestWinnowBase(test *testing.T) {
+	tokens := Tokenize("a b c d e f g h i j", DEFAULT_LANGUAGE)
+
+	hashes := HashKGrams(tokens, 3)
+	if len(hashes) != 8 {
+		test.Fatalf("Unexpected number of k-gram hashes. Expected: 8, Actual: %d.", len(hashes))
+	}
+
+	fingerprints := Winnow(tokens, 3, 4)
+	if (len(fingerprints) == 0) || (len(fingerprints) > 5) {
+		test.Fatalf("Unexpected number of fingerprints: %d.", len(fingerprints))
+	}
+
+	// Every window must contain a selected fingerprint.
+	for start := 0; start <= (len(hashes) - 4); start++ {
+		found := false
+		for _, fingerprint := range fingerprints {
+			if (fingerprint.Start >= start) && (fingerprint.Start < (start + 4)) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			test.Errorf("Window starting at %d does not have a fingerprint.", start)
+		}
+	}
+}
+
+func TestWinnowShort(test *testing.T) {
+	if len(Winnow([]*Token{}, 3, 4)) != 0 {
+		test.Fatalf("Empty tokens produced fingerprints.")
+	}
+
+	tokens := Tokenize("a b", DEFAULT_LANGUAGE)
+	if len(Winnow(tokens, 3, 4)) != 1 {
+		test.Fatalf("Short tokens did not produce exactly one fingerprint.")
+	}
+}
+
+func TestNativeSimilarityNormalization(test *testing.T) {
+	testCases := []struct {
+		language string
+		a        string
+		b        string
+		expected float64
+	}{
+		// Renamed identifiers, changed literals, comments, and whitespace.
+		{
+			LANG_PYTHON3,
+			"def total(values):\n    result = 0\n    for value in values:\n        result += value\n    return result\n",
+			"# Sum things.\ndef add_all(xs):\n    s = 10\n\n    for x in xs:\n        s += x  # Add.\n    return s\n",
+			1.0,
+		},
+		{
+			LANG_JAVA,
+			"int total(int[] values) { int result = 0; for (int v : values) { result += v; } return result; }",
+			"/* Sum. */ int sum(int[] xs) {\n int s = 1;\n for (int x : xs) {\n s += x; // Add.\n }\n return s;\n}",
+			1.0,
+		},
+		{
+			LANG_PYTHON3,
+			"def total(values):\n    result = 0\n    for value in values:\n        result += value\n    return result\n",
+			"class Foo:\n    pass\n\nwhile True:\n    print('Hello, World!')\n",
+			0.0,
+		},
+	}
+
+	for i, testCase := range testCases {
+		a := fingerprintSet(Winnow(Tokenize(testCase.a, testCase.language), 5, 4), nil)
+		b := fingerprintSet(Winnow(Tokenize(testCase.b, testCase.language), 5, 4), nil)
+
+		actual := computeScore(a, b)
+		if !util.IsClose(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"path/filepath"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/edulinq/autograder/internal/analysis/core"
 	"github.com/edulinq/autograder/internal/analysis/dolos"
 	"github.com/edulinq/autograder/internal/analysis/jplag"
+	"github.com/edulinq/autograder/internal/analysis/native"
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
@@ -24,6 +26,7 @@ var testFailPairwiseAnalysis bool = false
 var defaultSimilarityEngines []core.SimilarityEngine = []core.SimilarityEngine{
 	dolos.GetEngine(),
 	jplag.GetEngine(),
+	native.GetEngine(),
 }
 
 // Unit testing will use a fake engine.
@@ -94,9 +97,63 @@ func createPairwiseKeys(fullSubmissionIDs []string) []model.PairwiseKey {
 	return allKeys
 }
 
+// Get the keys comparing each submission against each entry of the requested corpora.
+// Corpus keys always have the submission on the LHS and the corpus entry ID on the RHS.
+func createCorpusKeys(options AnalysisOptions) ([]model.PairwiseKey, error) {
+	if len(options.Corpora) == 0 {
+		return nil, nil
+	}
+
+	fullSubmissionIDs := slices.Clone(options.ResolvedSubmissionIDs)
+	slices.Sort(fullSubmissionIDs)
+
+	// {assignmentFullID: entries, ...}
+	assignmentEntries := make(map[string][]*model.CorpusEntry)
+
+	keys := make([]model.PairwiseKey, 0)
+	for _, fullSubmissionID := range slices.Compact(fullSubmissionIDs) {
+		courseID, assignmentID, _, _, err := common.SplitFullSubmissionID(fullSubmissionID)
+		if err != nil {
+			return nil, err
+		}
+
+		assignmentFullID := courseID + common.SUBMISSION_ID_DELIM + assignmentID
+
+		entries, ok := assignmentEntries[assignmentFullID]
+		if !ok {
+			assignment, err := db.GetAssignment(courseID, assignmentID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to fetch assignment %s.%s: '%w'.", courseID, assignmentID, err)
+			}
+
+			if assignment != nil {
+				entries, err = GetCorpusEntries(assignment, options.Corpora)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get corpus entries for assignment '%s': '%w'.", assignmentFullID, err)
+				}
+			}
+
+			assignmentEntries[assignmentFullID] = entries
+		}
+
+		for _, entry := range entries {
+			keys = append(keys, model.PairwiseKey{fullSubmissionID, entry.ID()})
+		}
+	}
+
+	return keys, nil
+}
+
 func getCachedPairwiseResults(options AnalysisOptions) ([]*model.PairwiseAnalysis, []model.PairwiseKey, error) {
 	allKeys := createPairwiseKeys(options.ResolvedSubmissionIDs)
 
+	corpusKeys, err := createCorpusKeys(options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create corpus keys: '%w'.", err)
+	}
+
+	allKeys = append(allKeys, corpusKeys...)
+
 	// If we are overwriting the cache, don't query the DB for any of the cached results.
 	if options.OverwriteCache {
 		return make([]*model.PairwiseAnalysis, 0), allKeys, nil
@@ -238,16 +295,50 @@ func computeSinglePairwiseAnalysis(pairwiseKey model.PairwiseKey, templateFileSt
 	}
 	defer util.RemoveDirent(tempDir)
 
-	var optionsAssignment *model.Assignment = nil
+	submissionDirs, optionsAssignment, err := fetchPairwiseSubmissions(pairwiseKey, tempDir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fileSimilarities, unmatches, skipped, totalRunTime, err := computeFileSims(submissionDirs, optionsAssignment, templateFileStore)
+	if err != nil {
+		message := fmt.Sprintf("Failed to compute similarities for %v: '%s'.", pairwiseKey, err.Error())
+		analysis := model.NewFailedPairwiseAnalysis(pairwiseKey, optionsAssignment, message)
+		return analysis, 0, nil
+	}
+
+	analysis := model.NewPairwiseAnalysis(pairwiseKey, optionsAssignment, fileSimilarities, unmatches, skipped)
 
-	// Collect both submissions in a temp dir.
+	return analysis, totalRunTime, nil
+}
+
+// Collect both sides of a pairwise key (submissions or corpus entries) in a dir.
+// Returns the dir for each side and the assignment whose options should be used.
+func fetchPairwiseSubmissions(pairwiseKey model.PairwiseKey, baseDir string) ([2]string, *model.Assignment, error) {
+	var optionsAssignment *model.Assignment = nil
 	var submissionDirs [2]string
+
 	for i, fullSubmissionID := range pairwiseKey {
-		submissionDir := filepath.Join(tempDir, fullSubmissionID)
+		submissionDir := filepath.Join(baseDir, fullSubmissionID)
+
+		// Corpus entries always come after a submission (from the same assignment).
+		if model.IsCorpusEntryID(fullSubmissionID) {
+			if optionsAssignment == nil {
+				return submissionDirs, nil, fmt.Errorf("Corpus entry '%s' is not paired with a submission.", fullSubmissionID)
+			}
+
+			err := copyCorpusEntry(optionsAssignment, fullSubmissionID, submissionDir)
+			if err != nil {
+				return submissionDirs, nil, err
+			}
+
+			submissionDirs[i] = submissionDir
+			continue
+		}
 
 		_, assignment, err := fetchSubmission(fullSubmissionID, submissionDir)
 		if err != nil {
-			return nil, 0, err
+			return submissionDirs, nil, err
 		}
 
 		if optionsAssignment == nil {
@@ -257,16 +348,7 @@ func computeSinglePairwiseAnalysis(pairwiseKey model.PairwiseKey, templateFileSt
 		submissionDirs[i] = submissionDir
 	}
 
-	fileSimilarities, unmatches, skipped, totalRunTime, err := computeFileSims(submissionDirs, optionsAssignment, templateFileStore)
-	if err != nil {
-		message := fmt.Sprintf("Failed to compute similarities for %v: '%s'.", pairwiseKey, err.Error())
-		analysis := model.NewFailedPairwiseAnalysis(pairwiseKey, optionsAssignment, message)
-		return analysis, 0, nil
-	}
-
-	analysis := model.NewPairwiseAnalysis(pairwiseKey, optionsAssignment, fileSimilarities, unmatches, skipped)
-
-	return analysis, totalRunTime, nil
+	return submissionDirs, optionsAssignment, nil
 }
 
 func computeFileSims(inputDirs [2]string, assignment *model.Assignment, templateFileStore *TemplateFileStore) (map[string][]*model.FileSimilarity, [][2]string, []string, int64, error) {
@@ -288,38 +370,15 @@ func computeFileSims(inputDirs [2]string, assignment *model.Assignment, template
 		}
 	}
 
-	// When preparing source code, we may rename files (e.g. for iPython notebooks).
-	// {newRelpath: oldRelpath, ...}
-	renames := make(map[string]string, 0)
-	for _, inputDir := range inputDirs {
-		partialRenames, err := prepSourceFiles(inputDir)
-		if err != nil {
-			return nil, nil, nil, 0, fmt.Errorf("Failed to prepare source files: '%w'.", err)
-		}
-
-		// Note that we may be overriding some paths, but they shuold have the same information.
-		for newRelpath, oldRelpath := range partialRenames {
-			renames[newRelpath] = oldRelpath
-		}
-	}
-
-	// Figure out what files need to be analyzed.
-	matches, unmatches, err := util.MatchFiles(inputDirs)
+	renames, matches, unmatches, skipped, err := preparePairwiseFiles(inputDirs, assignment)
 	if err != nil {
-		return nil, nil, nil, 0, fmt.Errorf("Failed to find matching files: '%w'.", err)
+		return nil, nil, nil, 0, err
 	}
 
 	totalRunTime := int64(0)
 	similarities := make(map[string][]*model.FileSimilarity, len(matches))
-	skipped := make([]string, 0)
 
 	for _, relpath := range matches {
-		// Check if this file should be skipped because of inclusions/exclusions.
-		if (assignment != nil) && (assignment.AssignmentAnalysisOptions != nil) && !assignment.AssignmentAnalysisOptions.MatchRelpath(relpath) {
-			skipped = append(skipped, relpath)
-			continue
-		}
-
 		// Check for the template file.
 		templatePath := ""
 		if templateDir != "" {
@@ -391,18 +450,88 @@ func computeFileSims(inputDirs [2]string, assignment *model.Assignment, template
 	return similarities, unmatches, skipped, totalRunTime, nil
 }
 
+// Prepare the source files in both dirs and figure out which files need to be analyzed.
+// Returns: (renames {newRelpath: oldRelpath}, matched relpaths, unmatched relpaths, skipped relpaths, error).
+func preparePairwiseFiles(inputDirs [2]string, assignment *model.Assignment) (map[string]string, []string, [][2]string, []string, error) {
+	// When preparing source code, we may rename files (e.g. for iPython notebooks).
+	// {newRelpath: oldRelpath, ...}
+	renames := make(map[string]string, 0)
+	for _, inputDir := range inputDirs {
+		partialRenames, err := prepSourceFiles(inputDir)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("Failed to prepare source files: '%w'.", err)
+		}
+
+		// Note that we may be overriding some paths, but they shuold have the same information.
+		for newRelpath, oldRelpath := range partialRenames {
+			renames[newRelpath] = oldRelpath
+		}
+	}
+
+	// Figure out what files need to be analyzed.
+	allMatches, unmatches, err := util.MatchFiles(inputDirs)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("Failed to find matching files: '%w'.", err)
+	}
+
+	matches := make([]string, 0, len(allMatches))
+	skipped := make([]string, 0)
+
+	for _, relpath := range allMatches {
+		// Check if this file should be skipped because of inclusions/exclusions.
+		if (assignment != nil) && (assignment.AssignmentAnalysisOptions != nil) && !assignment.AssignmentAnalysisOptions.MatchRelpath(relpath) {
+			skipped = append(skipped, relpath)
+			continue
+		}
+
+		matches = append(matches, relpath)
+	}
+
+	return renames, matches, unmatches, skipped, nil
+}
+
 func getEngines() ([]core.SimilarityEngine, error) {
 	if !forceDefaultEnginesForTesting && config.UNIT_TESTING_MODE.Get() {
 		return []core.SimilarityEngine{&fakeSimiliartyEngine{}}, nil
 	}
 
+	// Engines may be restricted in the config.
+	allowedNames := make(map[string]bool)
+	for _, name := range strings.Split(config.ANALYSIS_PAIRWISE_ENGINES.Get(), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			allowedNames[name] = true
+		}
+	}
+
 	engines := make([]core.SimilarityEngine, 0, len(defaultSimilarityEngines))
+	knownNames := make(map[string]bool, len(defaultSimilarityEngines))
+
 	for _, engine := range defaultSimilarityEngines {
+		knownNames[engine.GetName()] = true
+
+		if (len(allowedNames) > 0) && !allowedNames[engine.GetName()] {
+			continue
+		}
+
 		if engine.IsAvailable() {
 			engines = append(engines, engine)
 		}
 	}
 
+	unknownNames := make([]string, 0)
+	for name, _ := range allowedNames {
+		if !knownNames[name] {
+			unknownNames = append(unknownNames, name)
+		}
+	}
+
+	if len(unknownNames) > 0 {
+		slices.Sort(unknownNames)
+
+		return nil, fmt.Errorf("Unknown similarity engines in config (%s): '%s'.", config.ANALYSIS_PAIRWISE_ENGINES.Key, strings.Join(unknownNames, ", "))
+	}
+
 	if len(engines) == 0 {
 		return nil, fmt.Errorf("No similarity engines are currently available (are you using docker?).")
 	}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/edulinq/autograder/internal/analysis/jplag"
+	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/docker"
 	"github.com/edulinq/autograder/internal/model"
@@ -817,3 +818,53 @@ func TestPairwiseAnalysisFailureBase(test *testing.T) {
 		test.Fatalf("Failure message does not contain expected substring. Expected Substring: '%s', Actual: '%s'.", expectedMessageSubstring, results[0].FailureMessage)
 	}
 }
+
+func TestGetEnginesConfig(test *testing.T) {
+	forceDefaultEnginesForTesting = true
+	defer func() {
+		forceDefaultEnginesForTesting = false
+	}()
+
+	oldEngines := config.ANALYSIS_PAIRWISE_ENGINES.Get()
+	defer config.ANALYSIS_PAIRWISE_ENGINES.Set(oldEngines)
+
+	testCases := []struct {
+		value       string
+		expected    []string
+		errorSubstr string
+	}{
+		{"native", []string{"native"}, ""},
+		{" Native , ", []string{"native"}, ""},
+		{"native,zzz", nil, "'zzz'"},
+		{"native, zzz, aaa", nil, "'aaa, zzz'"},
+	}
+
+	for i, testCase := range testCases {
+		config.ANALYSIS_PAIRWISE_ENGINES.Set(testCase.value)
+
+		engines, err := getEngines()
+		if err != nil {
+			if testCase.errorSubstr == "" {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstr) {
+				test.Errorf("Case %d: Error does not contain expected text. Expected: '%s', Actual: '%v'.", i, testCase.errorSubstr, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstr != "" {
+			test.Errorf("Case %d: Did not get expected error.", i)
+			continue
+		}
+
+		names := make([]string, 0, len(engines))
+		for _, engine := range engines {
+			names = append(names, engine.GetName())
+		}
+
+		if !reflect.DeepEqual(testCase.expected, names) {
+			test.Errorf("Case %d: Unexpected engines. Expected: '%v', Actual: '%v'.", i, testCase.expected, names)
+		}
+	}
+}
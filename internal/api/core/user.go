@@ -41,6 +41,9 @@ type CourseUserInfo struct {
 	BaseUserInfo
 	Role  model.CourseUserRole `json:"role"`
 	LMSID string               `json:"lms-id"`
+
+	Sections []string          `json:"sections,omitempty"`
+	Groups   map[string]string `json:"groups,omitempty"`
 }
 
 func NewServerUserInfo(user *model.ServerUser) *ServerUserInfo {
@@ -104,6 +107,9 @@ func NewCourseUserInfo(user *model.CourseUser) *CourseUserInfo {
 		},
 		Role:  user.Role,
 		LMSID: user.GetLMSID(),
+
+		Sections: user.Sections,
+		Groups:   user.Groups,
 	}
 
 	return info
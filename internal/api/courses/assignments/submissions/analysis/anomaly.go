@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type AnomalyRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+
+	analysis.AnalysisOptions
+	analysis.AnomalyOptions
+}
+
+type AnomalyResponse struct {
+	Complete       bool                     `json:"complete"`
+	Options        analysis.AnalysisOptions `json:"options"`
+	AnomalyOptions analysis.AnomalyOptions  `json:"anomaly-options"`
+	PendingCount   int                      `json:"pending-count"`
+	Flags          []*model.AnomalyFlag     `json:"flags"`
+}
+
+// Flag anomalies (e.g., large code drops or sudden score jumps) in the submission timelines of the specified submissions.
+// The specified submissions also make up the class distribution that each submission is compared against.
+// When the analysis is not complete, flags are only computed from the available results.
+func HandleAnomaly(request *AnomalyRequest) (*AnomalyResponse, *core.APIError) {
+	fullSubmissionIDs, courses, userErrors, systemErrors := analysis.ResolveSubmissionSpecs(request.RawSubmissionSpecs)
+
+	if systemErrors != nil {
+		return nil, core.NewUserContextInternalError("-668", &request.APIRequestUserContext, "Failed to resolve submission specs.").
+			Err(systemErrors)
+	}
+
+	if userErrors != nil {
+		return nil, core.NewBadUserRequestError("-669", &request.APIRequestUserContext,
+			fmt.Sprintf("Failed to resolve submission specs: '%s'.", userErrors.Error())).
+			Err(userErrors)
+	}
+
+	if !checkPermissions(request.ServerUser, courses) {
+		return nil, core.NewBadUserRequestError("-670", &request.APIRequestUserContext,
+			"User does not have permissions (server admin or course admin in all present courses.")
+	}
+
+	request.ResolvedSubmissionIDs = fullSubmissionIDs
+
+	results, pendingCount, err := analysis.IndividualAnalysis(request.AnalysisOptions, request.ServerUser.Email)
+	if err != nil {
+		return nil, core.NewUserContextInternalError("-671", &request.APIRequestUserContext, "Failed to perform individual analysis.").
+			Err(err)
+	}
+
+	response := AnomalyResponse{
+		Complete:       (pendingCount == 0),
+		Options:        request.AnalysisOptions,
+		AnomalyOptions: request.AnomalyOptions,
+		PendingCount:   pendingCount,
+		Flags:          analysis.DetectAnomalies(results, request.AnomalyOptions),
+	}
+
+	return &response, nil
+}
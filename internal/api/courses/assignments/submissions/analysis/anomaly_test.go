@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestAnomalyBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	submissions := []string{
+		"course101::hw0::course-student@test.edulinq.org::1697406256",
+		"course101::hw0::course-student@test.edulinq.org::1697406265",
+		"course101::hw0::course-student@test.edulinq.org::1697406272",
+	}
+
+	testCases := []struct {
+		nearZeroFraction float64
+		expected         []*model.AnomalyFlag
+	}{
+		// No score gets close enough to zero and full with the default options.
+		{
+			0.0,
+			[]*model.AnomalyFlag{},
+		},
+		{
+			0.5,
+			[]*model.AnomalyFlag{
+				&model.AnomalyFlag{
+					Rank:         1,
+					Type:         model.ANOMALY_TYPE_SCORE_JUMP,
+					SubmissionID: submissions[1],
+					UserEmail:    "course-student@test.edulinq.org",
+					Metric:       "score-delta",
+					Value:        1,
+					Percentile:   50,
+					Severity:     0.5,
+					Explanation:  "Score jumped from 0 to 1 (out of 2) 10s after the previous submission, which is at the 50 percentile of the class's changes in score.",
+				},
+				&model.AnomalyFlag{
+					Rank:         2,
+					Type:         model.ANOMALY_TYPE_SCORE_JUMP,
+					SubmissionID: submissions[2],
+					UserEmail:    "course-student@test.edulinq.org",
+					Metric:       "score-delta",
+					Value:        1,
+					Percentile:   50,
+					Severity:     0.5,
+					Explanation:  "Score jumped from 1 to 2 (out of 2) 7s after the previous submission, which is at the 50 percentile of the class's changes in score.",
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"submissions":         submissions,
+			"wait-for-completion": true,
+			"near-zero-fraction":  testCase.nearZeroFraction,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/anomaly`, fields, nil, "server-admin")
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent AnomalyResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		for _, flag := range responseContent.Flags {
+			flag.RoundWithPrecision(2)
+		}
+
+		expected := AnomalyResponse{
+			Complete: true,
+			Options: analysis.AnalysisOptions{
+				RawSubmissionSpecs: submissions,
+				WaitForCompletion:  true,
+			},
+			AnomalyOptions: analysis.AnomalyOptions{
+				NearZeroFraction: testCase.nearZeroFraction,
+			},
+			PendingCount: 0,
+			Flags:        testCase.expected,
+		}
+
+		if !reflect.DeepEqual(expected, responseContent) {
+			test.Errorf("Case %d: Response is not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(expected), util.MustToJSONIndent(responseContent))
+			continue
+		}
+	}
+}
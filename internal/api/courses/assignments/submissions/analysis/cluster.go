@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ClusterRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+
+	analysis.AnalysisOptions
+	analysis.ClusterOptions
+}
+
+type ClusterResponse struct {
+	Complete       bool                       `json:"complete"`
+	Options        analysis.AnalysisOptions   `json:"options"`
+	ClusterOptions analysis.ClusterOptions    `json:"cluster-options"`
+	PendingCount   int                        `json:"pending-count"`
+	Clusters       []*model.SimilarityCluster `json:"clusters"`
+}
+
+// Group the results of a pairwise analysis for the specified submissions into ranked clusters of similar submissions.
+// When the analysis is not complete, clusters are only built from the available results.
+func HandleCluster(request *ClusterRequest) (*ClusterResponse, *core.APIError) {
+	fullSubmissionIDs, courses, userErrors, systemErrors := analysis.ResolveSubmissionSpecs(request.RawSubmissionSpecs)
+
+	if systemErrors != nil {
+		return nil, core.NewUserContextInternalError("-658", &request.APIRequestUserContext, "Failed to resolve submission specs.").
+			Err(systemErrors)
+	}
+
+	if userErrors != nil {
+		return nil, core.NewBadUserRequestError("-659", &request.APIRequestUserContext,
+			fmt.Sprintf("Failed to resolve submission specs: '%s'.", userErrors.Error())).
+			Err(userErrors)
+	}
+
+	if !checkPermissions(request.ServerUser, courses) {
+		return nil, core.NewBadUserRequestError("-660", &request.APIRequestUserContext,
+			"User does not have permissions (server admin or course admin in all present courses.")
+	}
+
+	request.ResolvedSubmissionIDs = fullSubmissionIDs
+
+	results, pendingCount, err := analysis.PairwiseAnalysis(request.AnalysisOptions, request.ServerUser.Email)
+	if err != nil {
+		return nil, core.NewUserContextInternalError("-661", &request.APIRequestUserContext, "Failed to perform pairwise analysis.").
+			Err(err)
+	}
+
+	response := ClusterResponse{
+		Complete:       (pendingCount == 0),
+		Options:        request.AnalysisOptions,
+		ClusterOptions: request.ClusterOptions,
+		PendingCount:   pendingCount,
+		Clusters:       analysis.ClusterPairwiseResults(results, request.ClusterOptions),
+	}
+
+	return &response, nil
+}
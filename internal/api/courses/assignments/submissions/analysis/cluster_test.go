@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestClusterBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	submissions := []string{
+		"course101::hw0::course-student@test.edulinq.org::1697406256",
+		"course101::hw0::course-student@test.edulinq.org::1697406265",
+	}
+
+	testCases := []struct {
+		threshold float64
+		expected  []*model.SimilarityCluster
+	}{
+		// The default threshold is higher than the fake engine's score.
+		{
+			0.0,
+			[]*model.SimilarityCluster{},
+		},
+		{
+			0.1,
+			[]*model.SimilarityCluster{
+				&model.SimilarityCluster{
+					Rank: 1,
+					Members: []*model.SimilarityClusterMember{
+						&model.SimilarityClusterMember{
+							ID:        submissions[0],
+							UserEmail: "course-student@test.edulinq.org",
+							MaxScore:  0.13,
+						},
+						&model.SimilarityClusterMember{
+							ID:        submissions[1],
+							UserEmail: "course-student@test.edulinq.org",
+							MaxScore:  0.13,
+						},
+					},
+					Evidence: []*model.SimilarityClusterEvidence{
+						&model.SimilarityClusterEvidence{
+							SubmissionIDs: model.NewPairwiseKey(submissions[0], submissions[1]),
+							Score:         0.13,
+						},
+					},
+					MeanScore: 0.13,
+					MaxScore:  0.13,
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"submissions":         submissions,
+			"wait-for-completion": true,
+			"threshold":           testCase.threshold,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/cluster`, fields, nil, "server-admin")
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent ClusterResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		expected := ClusterResponse{
+			Complete: true,
+			Options: analysis.AnalysisOptions{
+				RawSubmissionSpecs: submissions,
+				WaitForCompletion:  true,
+			},
+			ClusterOptions: analysis.ClusterOptions{
+				Threshold: testCase.threshold,
+			},
+			PendingCount: 0,
+			Clusters:     testCase.expected,
+		}
+
+		if !reflect.DeepEqual(expected, responseContent) {
+			test.Errorf("Case %d: Response is not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(expected), util.MustToJSONIndent(responseContent))
+			continue
+		}
+	}
+}
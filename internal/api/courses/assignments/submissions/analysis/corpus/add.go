@@ -0,0 +1,36 @@
+package corpus
+
+import (
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type AddRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+	Files core.POSTFiles `json:"-"`
+
+	Corpus string `json:"corpus"`
+	Entry  string `json:"entry"`
+}
+
+type AddResponse struct {
+	Entry *model.CorpusEntry `json:"entry"`
+}
+
+// Add the given files as an entry in one of the assignment's corpora (replacing any existing entry with the same name).
+func HandleAdd(request *AddRequest) (*AddResponse, *core.APIError) {
+	apiErr := validateCorpusNames("-648", &request.APIRequestCourseUserContext, request.Corpus, request.Entry)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	entry, err := analysis.AddCorpusEntry(request.Assignment, request.Corpus, request.Entry, request.Files.TempDir)
+	if err != nil {
+		return nil, core.NewInternalError("-649", &request.APIRequestCourseUserContext, "Failed to add corpus entry.").
+			Err(err).Add("corpus", request.Corpus).Add("entry", request.Entry)
+	}
+
+	return &AddResponse{entry}, nil
+}
@@ -0,0 +1,34 @@
+package corpus
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func validateCorpusNames(locator string, request *core.APIRequestCourseUserContext, names ...string) *core.APIError {
+	for _, name := range names {
+		_, err := model.ValidateCorpusName(name)
+		if err != nil {
+			return core.NewBadCourseRequestError(locator, request, err.Error()).Err(err)
+		}
+	}
+
+	return nil
+}
+
+// Check that a user may read submissions from all of the given (source) courses.
+func checkPermissions(user *model.ServerUser, courses []string) bool {
+	// Admins can do whatever they want.
+	if user.Role >= model.ServerRoleAdmin {
+		return true
+	}
+
+	// Regular server users need to be at least a grader in every course they are importing from.
+	for _, course := range courses {
+		if user.GetCourseRole(course) < model.CourseRoleGrader {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,121 @@
+package corpus
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCorpusBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	defer util.RemoveDirent(assignment.GetCorporaDir())
+
+	path := filepath.Join(util.RootDirForTesting(), "testdata", "files", "sim_engine", "test-submissions", "solution", "submission.py")
+
+	// Add.
+	fields := map[string]any{
+		"corpus": "public",
+		"entry":  "solution",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/corpus/add`, fields, []string{path}, "course-admin")
+	if !response.Success {
+		test.Fatalf("Add response is not a success when it should be: '%v'.", response)
+	}
+
+	var addContent AddResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &addContent)
+
+	if (addContent.Entry == nil) || (addContent.Entry.Name != "solution") || (len(addContent.Entry.Files) != 1) {
+		test.Fatalf("Unexpected added entry: '%s'.", util.MustToJSONIndent(addContent.Entry))
+	}
+
+	// Import.
+	fields = map[string]any{
+		"corpus":      "prior-term",
+		"submissions": []string{"course101::hw0::course-student@test.edulinq.org::1697406256"},
+	}
+
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/corpus/import`, fields, nil, "course-admin")
+	if !response.Success {
+		test.Fatalf("Import response is not a success when it should be: '%v'.", response)
+	}
+
+	var importContent ImportResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &importContent)
+
+	if len(importContent.Entries) != 1 {
+		test.Fatalf("Unexpected imported entries: '%s'.", util.MustToJSONIndent(importContent))
+	}
+
+	// List.
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/corpus/list`, nil, nil, "course-grader")
+	if !response.Success {
+		test.Fatalf("List response is not a success when it should be: '%v'.", response)
+	}
+
+	var listContent ListResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &listContent)
+
+	if (len(listContent.Corpora["public"]) != 1) || (len(listContent.Corpora["prior-term"]) != 1) {
+		test.Fatalf("Unexpected corpora: '%s'.", util.MustToJSONIndent(listContent))
+	}
+
+	// Remove.
+	fields = map[string]any{
+		"corpus": "public",
+	}
+
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/corpus/remove`, fields, nil, "course-admin")
+	if !response.Success {
+		test.Fatalf("Remove response is not a success when it should be: '%v'.", response)
+	}
+
+	var removeContent RemoveResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &removeContent)
+
+	if !removeContent.Found {
+		test.Fatalf("Corpus was not found for removal.")
+	}
+}
+
+func TestCorpusErrors(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	defer util.RemoveDirent(assignment.GetCorporaDir())
+
+	testCases := []struct {
+		endpoint string
+		email    string
+		fields   map[string]any
+		locator  string
+	}{
+		{`courses/assignments/submissions/analysis/corpus/import`, "course-admin", map[string]any{"corpus": ".bad"}, "-650"},
+		{`courses/assignments/submissions/analysis/corpus/import`, "course-admin", map[string]any{"corpus": "ok", "submissions": []string{"zzz"}}, "-652"},
+		{`courses/assignments/submissions/analysis/corpus/remove`, "course-admin", map[string]any{"corpus": ""}, "-656"},
+		{`courses/assignments/submissions/analysis/corpus/remove`, "course-admin", map[string]any{"corpus": "ok", "entry": "a/b"}, "-656"},
+		{`courses/assignments/submissions/analysis/corpus/remove`, "course-grader", map[string]any{"corpus": "ok"}, "-020"},
+		{`courses/assignments/submissions/analysis/corpus/list`, "course-student", nil, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, testCase.endpoint, testCase.fields, nil, testCase.email)
+		if response.Success {
+			test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response)
+			continue
+		}
+
+		if response.Locator != testCase.locator {
+			test.Errorf("Case %d: Incorrect locator. Expected: '%s', Actual: '%s'.", i, testCase.locator, response.Locator)
+			continue
+		}
+	}
+}
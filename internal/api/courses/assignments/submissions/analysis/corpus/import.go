@@ -0,0 +1,64 @@
+package corpus
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ImportRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	Corpus             string   `json:"corpus"`
+	RawSubmissionSpecs []string `json:"submissions"`
+}
+
+type ImportResponse struct {
+	Entries []*model.CorpusEntry `json:"entries"`
+}
+
+// Add existing submissions (e.g., from a prior term) as entries in one of the assignment's corpora.
+// Entries are named after their submission IDs.
+func HandleImport(request *ImportRequest) (*ImportResponse, *core.APIError) {
+	apiErr := validateCorpusNames("-650", &request.APIRequestCourseUserContext, request.Corpus)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	fullSubmissionIDs, courses, userErrors, systemErrors := analysis.ResolveSubmissionSpecs(request.RawSubmissionSpecs)
+
+	if systemErrors != nil {
+		return nil, core.NewInternalError("-651", &request.APIRequestCourseUserContext, "Failed to resolve submission specs.").
+			Err(systemErrors)
+	}
+
+	if userErrors != nil {
+		return nil, core.NewBadCourseRequestError("-652", &request.APIRequestCourseUserContext,
+			fmt.Sprintf("Failed to resolve submission specs: '%s'.", userErrors.Error())).
+			Err(userErrors)
+	}
+
+	if !checkPermissions(request.ServerUser, courses) {
+		return nil, core.NewBadCourseRequestError("-653", &request.APIRequestCourseUserContext,
+			"User does not have permissions (server admin or course grader in all source courses).")
+	}
+
+	response := ImportResponse{
+		Entries: make([]*model.CorpusEntry, 0, len(fullSubmissionIDs)),
+	}
+
+	for _, fullSubmissionID := range fullSubmissionIDs {
+		entry, err := analysis.AddCorpusSubmission(request.Assignment, request.Corpus, "", fullSubmissionID)
+		if err != nil {
+			return nil, core.NewInternalError("-654", &request.APIRequestCourseUserContext, "Failed to import submission into corpus.").
+				Err(err).Add("corpus", request.Corpus).Add("submission-id", fullSubmissionID)
+		}
+
+		response.Entries = append(response.Entries, entry)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,27 @@
+package corpus
+
+import (
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+}
+
+type ListResponse struct {
+	Corpora map[string][]*model.CorpusEntry `json:"corpora"`
+}
+
+// List the entries in all of the assignment's corpora.
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	corpora, err := analysis.ListCorpora(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-655", &request.APIRequestCourseUserContext, "Failed to list corpora.").
+			Err(err)
+	}
+
+	return &ListResponse{corpora}, nil
+}
@@ -0,0 +1,39 @@
+package corpus
+
+import (
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	Corpus string `json:"corpus"`
+	Entry  string `json:"entry"`
+}
+
+type RemoveResponse struct {
+	Found bool `json:"found"`
+}
+
+// Remove an entry from one of the assignment's corpora, or the entire corpus if no entry is given.
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	names := []string{request.Corpus}
+	if request.Entry != "" {
+		names = append(names, request.Entry)
+	}
+
+	apiErr := validateCorpusNames("-656", &request.APIRequestCourseUserContext, names...)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	found, err := analysis.RemoveCorpus(request.Assignment, request.Corpus, request.Entry)
+	if err != nil {
+		return nil, core.NewInternalError("-657", &request.APIRequestCourseUserContext, "Failed to remove corpus.").
+			Err(err).Add("corpus", request.Corpus).Add("entry", request.Entry)
+	}
+
+	return &RemoveResponse{found}, nil
+}
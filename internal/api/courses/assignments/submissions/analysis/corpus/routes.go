@@ -0,0 +1,18 @@
+package corpus
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/corpus/add`, HandleAdd),
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/corpus/import`, HandleImport),
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/corpus/list`, HandleList),
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/corpus/remove`, HandleRemove),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
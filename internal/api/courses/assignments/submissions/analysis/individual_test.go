@@ -101,6 +101,34 @@ func TestIndividualBase(test *testing.T) {
 				Min:    4,
 				Max:    4,
 			},
+			AggregateCyclomaticComplexity: util.AggregateValues{
+				Count:  2,
+				Mean:   2,
+				Median: 2,
+				Min:    2,
+				Max:    2,
+			},
+			AggregateFunctionCount: util.AggregateValues{
+				Count:  2,
+				Mean:   2,
+				Median: 2,
+				Min:    2,
+				Max:    2,
+			},
+			AggregateCommentRatio: util.AggregateValues{
+				Count:  2,
+				Mean:   0,
+				Median: 0,
+				Min:    0,
+				Max:    0,
+			},
+			AggregateMaxNestingDepth: util.AggregateValues{
+				Count:  2,
+				Mean:   1,
+				Median: 1,
+				Min:    1,
+				Max:    1,
+			},
 			AggregateSubmissionTimeDelta: util.AggregateValues{
 				Count:  2,
 				Mean:   5000,
@@ -148,48 +176,64 @@ func TestIndividualBase(test *testing.T) {
 		},
 		Results: []*model.IndividualAnalysis{
 			&model.IndividualAnalysis{
-				Options:             assignment.AssignmentAnalysisOptions,
-				AnalysisTimestamp:   timestamp.Zero(),
-				FullID:              "course101::hw0::course-student@test.edulinq.org::1697406256",
-				ShortID:             "1697406256",
-				CourseID:            "course101",
-				AssignmentID:        "hw0",
-				UserEmail:           "course-student@test.edulinq.org",
-				SubmissionStartTime: timestamp.FromMSecs(1697406256000),
-				Score:               0,
-				LinesOfCode:         4,
-				SubmissionTimeDelta: 0,
-				LinesOfCodeDelta:    0,
-				ScoreDelta:          0,
-				LinesOfCodeVelocity: 0,
-				ScoreVelocity:       0,
+				Options:              assignment.AssignmentAnalysisOptions,
+				AnalysisTimestamp:    timestamp.Zero(),
+				FullID:               "course101::hw0::course-student@test.edulinq.org::1697406256",
+				ShortID:              "1697406256",
+				CourseID:             "course101",
+				AssignmentID:         "hw0",
+				UserEmail:            "course-student@test.edulinq.org",
+				SubmissionStartTime:  timestamp.FromMSecs(1697406256000),
+				Score:                0,
+				MaxScore:             2,
+				LinesOfCode:          4,
+				CyclomaticComplexity: 2,
+				FunctionCount:        2,
+				MaxNestingDepth:      1,
+				SubmissionTimeDelta:  0,
+				LinesOfCodeDelta:     0,
+				ScoreDelta:           0,
+				LinesOfCodeVelocity:  0,
+				ScoreVelocity:        0,
 				Files: []model.AnalysisFileInfo{
 					model.AnalysisFileInfo{
-						Filename:    "submission.py",
-						LinesOfCode: 4,
+						Filename:             "submission.py",
+						LinesOfCode:          4,
+						Language:             "python",
+						CyclomaticComplexity: 2,
+						FunctionCount:        2,
+						MaxNestingDepth:      1,
 					},
 				},
 			},
 			&model.IndividualAnalysis{
-				Options:             assignment.AssignmentAnalysisOptions,
-				AnalysisTimestamp:   timestamp.Zero(),
-				FullID:              "course101::hw0::course-student@test.edulinq.org::1697406265",
-				ShortID:             "1697406265",
-				CourseID:            "course101",
-				AssignmentID:        "hw0",
-				UserEmail:           "course-student@test.edulinq.org",
-				SubmissionStartTime: timestamp.FromMSecs(1697406266000),
-				Score:               1,
-				LinesOfCode:         4,
-				SubmissionTimeDelta: 10000,
-				LinesOfCodeDelta:    0,
-				ScoreDelta:          1,
-				LinesOfCodeVelocity: 0,
-				ScoreVelocity:       360,
+				Options:              assignment.AssignmentAnalysisOptions,
+				AnalysisTimestamp:    timestamp.Zero(),
+				FullID:               "course101::hw0::course-student@test.edulinq.org::1697406265",
+				ShortID:              "1697406265",
+				CourseID:             "course101",
+				AssignmentID:         "hw0",
+				UserEmail:            "course-student@test.edulinq.org",
+				SubmissionStartTime:  timestamp.FromMSecs(1697406266000),
+				Score:                1,
+				MaxScore:             2,
+				LinesOfCode:          4,
+				CyclomaticComplexity: 2,
+				FunctionCount:        2,
+				MaxNestingDepth:      1,
+				SubmissionTimeDelta:  10000,
+				LinesOfCodeDelta:     0,
+				ScoreDelta:           1,
+				LinesOfCodeVelocity:  0,
+				ScoreVelocity:        360,
 				Files: []model.AnalysisFileInfo{
 					model.AnalysisFileInfo{
-						Filename:    "submission.py",
-						LinesOfCode: 4,
+						Filename:             "submission.py",
+						LinesOfCode:          4,
+						Language:             "python",
+						CyclomaticComplexity: 2,
+						FunctionCount:        2,
+						MaxNestingDepth:      1,
 					},
 				},
 			},
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type MatchRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+
+	// Two full submission ids, or a full submission id and a corpus entry id.
+	SubmissionIDs []string `json:"submission-ids"`
+
+	IncludeHTML bool `json:"include-html"`
+}
+
+type MatchResponse struct {
+	Report *model.PairwiseMatchReport `json:"report"`
+	HTML   string                     `json:"html,omitempty"`
+}
+
+// Get the matching regions of code between two submissions (or a submission and a corpus entry).
+func HandleMatch(request *MatchRequest) (*MatchResponse, *core.APIError) {
+	if len(request.SubmissionIDs) != 2 {
+		return nil, core.NewBadUserRequestError("-662", &request.APIRequestUserContext,
+			fmt.Sprintf("Exactly two submission ids are required, found %d.", len(request.SubmissionIDs)))
+	}
+
+	pairwiseKey, err := analysis.NewMatchReportKey(request.SubmissionIDs[0], request.SubmissionIDs[1])
+	if err != nil {
+		return nil, core.NewBadUserRequestError("-663", &request.APIRequestUserContext,
+			fmt.Sprintf("Invalid submission ids: '%s'.", err.Error())).Err(err)
+	}
+
+	// Make sure that all the submissions exist (corpus entries are checked when the report is built).
+	submissionIDs := make([]string, 0, 2)
+	for _, id := range pairwiseKey {
+		if !model.IsCorpusEntryID(id) {
+			submissionIDs = append(submissionIDs, id)
+		}
+	}
+
+	fullSubmissionIDs, courses, userErrors, systemErrors := analysis.ResolveSubmissionSpecs(submissionIDs)
+
+	if systemErrors != nil {
+		return nil, core.NewUserContextInternalError("-664", &request.APIRequestUserContext, "Failed to resolve submission ids.").
+			Err(systemErrors)
+	}
+
+	if (userErrors != nil) || (len(fullSubmissionIDs) != len(submissionIDs)) {
+		return nil, core.NewBadUserRequestError("-665", &request.APIRequestUserContext, "Could not find all submissions.").
+			Err(userErrors)
+	}
+
+	if !checkPermissions(request.ServerUser, courses) {
+		return nil, core.NewBadUserRequestError("-666", &request.APIRequestUserContext,
+			"User does not have permissions (server admin or course admin in all present courses.")
+	}
+
+	report, err := analysis.PairwiseMatchReport(pairwiseKey)
+	if err != nil {
+		return nil, core.NewUserContextInternalError("-667", &request.APIRequestUserContext, "Failed to build match report.").
+			Err(err).Add("submission-ids", pairwiseKey)
+	}
+
+	response := MatchResponse{
+		Report: report,
+	}
+
+	if request.IncludeHTML {
+		response.HTML = analysis.RenderMatchReportHTML(report)
+	}
+
+	return &response, nil
+}
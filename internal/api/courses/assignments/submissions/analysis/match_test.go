@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestMatchBase(test *testing.T) {
+	ids := []string{
+		"course101::hw0::course-student@test.edulinq.org::1697406265",
+		"course101::hw0::course-student@test.edulinq.org::1697406272",
+	}
+
+	testCases := []struct {
+		email       string
+		ids         []string
+		includeHTML bool
+		locator     string
+	}{
+		{"server-admin", ids, false, ""},
+		{"course-grader", []string{ids[1], ids[0]}, true, ""},
+
+		// Bad ids.
+		{"server-admin", ids[:1], false, "-662"},
+		{"server-admin", []string{ids[0], ids[0]}, false, "-663"},
+		{"server-admin", []string{ids[0], "zzz"}, false, "-663"},
+		{"server-admin", []string{ids[0], "course101::hw0::course-student@test.edulinq.org::123"}, false, "-665"},
+		{"server-admin", []string{ids[0], "corpus::zzz::zzz::zzz"}, false, "-667"},
+
+		// Bad permissions.
+		{"course-student", ids, false, "-666"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"submission-ids": testCase.ids,
+			"include-html":   testCase.includeHTML,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/analysis/match`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect locator. Expected: '%s', Actual: '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent MatchResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if responseContent.Report.SubmissionIDs != model.NewPairwiseKey(ids[0], ids[1]) {
+			test.Errorf("Case %d: Unexpected submission ids: '%v'.", i, responseContent.Report.SubmissionIDs)
+			continue
+		}
+
+		if len(responseContent.Report.Files) != 1 {
+			test.Errorf("Case %d: Unexpected number of files. Expected: 1, Actual: %d.", i, len(responseContent.Report.Files))
+			continue
+		}
+
+		hasHTML := strings.Contains(responseContent.HTML, "<html>")
+		if testCase.includeHTML != hasHTML {
+			test.Errorf("Case %d: Unexpected HTML. Expected: %v, Actual: %v.", i, testCase.includeHTML, hasHTML)
+			continue
+		}
+	}
+}
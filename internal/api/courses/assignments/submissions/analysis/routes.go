@@ -4,13 +4,22 @@ package analysis
 
 import (
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions/analysis/corpus"
 )
 
-var routes []core.Route = []core.Route{
+var baseRoutes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/anomaly`, HandleAnomaly),
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/cluster`, HandleCluster),
 	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/individual`, HandleIndividual),
+	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/match`, HandleMatch),
 	core.MustNewAPIRoute(`courses/assignments/submissions/analysis/pairwise`, HandlePairwise),
 }
 
 func GetRoutes() *[]core.Route {
+	routes := make([]core.Route, 0)
+
+	routes = append(routes, baseRoutes...)
+	routes = append(routes, *(corpus.GetRoutes())...)
+
 	return &routes
 }
@@ -11,6 +11,7 @@ type FetchCourseAttemptsRequest struct {
 	core.MinCourseRoleGrader
 
 	FilterRole model.CourseUserRole `json:"filter-role"`
+	model.CourseUserFilter
 }
 
 type FetchCourseAttemptsResponse struct {
@@ -25,5 +26,11 @@ func HandleFetchCourseAttempts(request *FetchCourseAttemptsRequest) (*FetchCours
 			Err(err).Assignment(request.Assignment.GetID())
 	}
 
+	results, err = db.FilterByCourseUsers(request.Course, &request.CourseUserFilter, results)
+	if err != nil {
+		return nil, core.NewInternalError("-646", &request.APIRequestCourseUserContext, "Failed to filter submissions.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
 	return &FetchCourseAttemptsResponse{results}, nil
 }
@@ -12,6 +12,9 @@ type FetchCourseScoresRequest struct {
 
 	// Filter results to only users with this role.
 	FilterRole model.CourseUserRole `json:"filter-role"`
+
+	// Filter results to only users in this section/group.
+	model.CourseUserFilter
 }
 
 type FetchCourseScoresResponse struct {
@@ -26,5 +29,11 @@ func HandleFetchCourseScores(request *FetchCourseScoresRequest) (*FetchCourseSco
 			Err(err).Assignment(request.Assignment.GetID())
 	}
 
+	submissionInfos, err = db.FilterByCourseUsers(request.Course, &request.CourseUserFilter, submissionInfos)
+	if err != nil {
+		return nil, core.NewInternalError("-645", &request.APIRequestCourseUserContext, "Failed to filter submission summaries.").
+			Err(err).Assignment(request.Assignment.GetID())
+	}
+
 	return &FetchCourseScoresResponse{submissionInfos}, nil
 }
@@ -41,6 +41,15 @@ func HandleFetchUserAttempt(request *FetchUserAttemptRequest) (*FetchUserAttempt
 		return &response, nil
 	}
 
+	// Students should not see the details of hidden test cases or unreleased scores.
+	if request.User.Role < model.CourseRoleGrader {
+		gradingResult.Info.RedactHiddenTestCases()
+
+		if !request.Assignment.IsScoreReleased() {
+			gradingResult.WithholdScores(request.Assignment.ScoreRelease)
+		}
+	}
+
 	response.FoundSubmission = true
 	response.GradingResult = gradingResult
 
@@ -38,6 +38,13 @@ func HandleFetchUserHistory(request *FetchUserHistoryRequest) (*FetchUserHistory
 			Add("target-user", request.TargetUser.Email)
 	}
 
+	// Students should not see unreleased scores.
+	if (request.User.Role < model.CourseRoleGrader) && !request.Assignment.IsScoreReleased() {
+		for _, item := range history {
+			item.WithholdScores(request.Assignment.ScoreRelease)
+		}
+	}
+
 	response.History = history
 
 	return &response, nil
@@ -41,6 +41,15 @@ func HandleFetchUserPeek(request *FetchUserPeekRequest) (*FetchUserPeekResponse,
 		return &response, nil
 	}
 
+	// Students should not see the details of hidden test cases or unreleased scores.
+	if request.User.Role < model.CourseRoleGrader {
+		submissionResult.RedactHiddenTestCases()
+
+		if !request.Assignment.IsScoreReleased() {
+			submissionResult.WithholdScores(request.Assignment.ScoreRelease)
+		}
+	}
+
 	response.FoundSubmission = true
 	response.GradingInfo = submissionResult
 
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -123,3 +126,83 @@ func TestFetchUserPeek(test *testing.T) {
 		}
 	}
 }
+
+func TestFetchUserPeekWithheldScores(test *testing.T) {
+	defer db.ResetForTesting()
+
+	future := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	past := timestamp.FromMSecs(1000)
+
+	testCases := []struct {
+		email            string
+		policy           *model.ScoreReleasePolicy
+		expectedScore    float64
+		expectedWithheld bool
+		expectedPassed   *bool
+	}{
+		// Released.
+		{"course-student", nil, 2.0, false, nil},
+		{"course-student", &model.ScoreReleasePolicy{Mode: model.ScoreReleaseFull}, 2.0, false, nil},
+		{"course-student", &model.ScoreReleasePolicy{Mode: model.ScoreReleaseReceived, ReleaseTime: &past}, 2.0, false, nil},
+
+		// Withheld.
+		{"course-student", &model.ScoreReleasePolicy{Mode: model.ScoreReleaseReceived}, 0.0, true, nil},
+		{"course-student", &model.ScoreReleasePolicy{Mode: model.ScoreReleaseReceived, ReleaseTime: &future}, 0.0, true, nil},
+		{"course-student", &model.ScoreReleasePolicy{Mode: model.ScoreReleasePassFail, ReleaseTime: &future}, 0.0, true, util.BoolPointer(true)},
+
+		// Staff always see full results.
+		{"course-grader", &model.ScoreReleasePolicy{Mode: model.ScoreReleaseReceived}, 2.0, false, nil},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestAssignment()
+		assignment.ScoreRelease = testCase.policy
+
+		if testCase.policy != nil {
+			err := testCase.policy.Validate()
+			if err != nil {
+				test.Errorf("Case %d: Failed to validate policy: '%v'.", i, err)
+				continue
+			}
+		}
+
+		db.MustSaveAssignment(assignment)
+
+		fields := map[string]any{
+			"target-email": "course-student@test.edulinq.org",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/peek`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent FetchUserPeekResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		info := responseContent.GradingInfo
+		if !util.IsClose(testCase.expectedScore, info.Score) {
+			test.Errorf("Case %d: Unexpected score. Expected: '%+v', actual: '%+v'.", i, testCase.expectedScore, info.Score)
+		}
+
+		if testCase.expectedWithheld != info.ScoresWithheld {
+			test.Errorf("Case %d: Unexpected withheld value. Expected: '%v', actual: '%v'.", i, testCase.expectedWithheld, info.ScoresWithheld)
+		}
+
+		if util.MustToJSON(testCase.expectedPassed) != util.MustToJSON(info.Passed) {
+			test.Errorf("Case %d: Unexpected passed value. Expected: '%s', actual: '%s'.",
+				i, util.MustToJSON(testCase.expectedPassed), util.MustToJSON(info.Passed))
+		}
+
+		if testCase.expectedWithheld {
+			for _, question := range info.Questions {
+				if question.Score != 0.0 {
+					test.Errorf("Case %d: Question '%s' was not withheld.", i, question.Name)
+				}
+			}
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 var baseRoutes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`courses/assignments/submissions/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/submissions/select`, HandleSelect),
 	core.MustNewAPIRoute(`courses/assignments/submissions/submit`, HandleSubmit),
 }
 
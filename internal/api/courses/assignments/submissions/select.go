@@ -0,0 +1,60 @@
+package submissions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type SelectRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser       core.TargetCourseUserSelfOrGrader `json:"target-email"`
+	TargetSubmission string                            `json:"target-submission"`
+}
+
+type SelectResponse struct {
+	FoundUser       bool `json:"found-user"`
+	FoundSubmission bool `json:"found-submission"`
+}
+
+// Select a submission as the final submission used for scoring. An empty submission clears the selection.
+func HandleSelect(request *SelectRequest) (*SelectResponse, *core.APIError) {
+	if request.Assignment.GetSubmissionSelection() != model.SubmissionSelectionStudentSelected {
+		return nil, core.NewBadRequestError("-632", &request.APIRequest, "Assignment does not allow selecting a final submission.").
+			Add("submission-selection", request.Assignment.GetSubmissionSelection())
+	}
+
+	response := SelectResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	if request.TargetSubmission != "" {
+		result, err := db.GetSubmissionResult(request.Assignment, request.TargetUser.Email, request.TargetSubmission)
+		if err != nil {
+			return nil, core.NewInternalError("-633", &request.APIRequestCourseUserContext, "Failed to get submission result.").
+				Err(err).Assignment(request.Assignment.GetID()).
+				Add("target-user", request.TargetUser.Email).Add("submission", request.TargetSubmission)
+		}
+
+		if result == nil {
+			return &response, nil
+		}
+
+		response.FoundSubmission = true
+	}
+
+	err := db.SetFinalSubmissionSelection(request.Assignment, request.TargetUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-634", &request.APIRequestCourseUserContext, "Failed to set the final submission.").
+			Err(err).Assignment(request.Assignment.GetID()).
+			Add("target-user", request.TargetUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,114 @@
+package submissions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSelect(test *testing.T) {
+	// Leave the course in a good state after the test.
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email            string
+		policy           model.SubmissionSelectionPolicy
+		targetEmail      string
+		targetSubmission string
+		foundUser        bool
+		foundSubmission  bool
+		expectedFinal    string
+		locator          string
+	}{
+		// Student, self.
+		{"course-student", model.SubmissionSelectionStudentSelected, "", "1697406256", true, true, "1697406256", ""},
+		{"course-student", model.SubmissionSelectionStudentSelected, "course-student@test.edulinq.org", "1697406265", true, true, "1697406265", ""},
+		{"course-student", model.SubmissionSelectionStudentSelected, "", "course101::hw0::course-student@test.edulinq.org::1697406265", true, true, "1697406265", ""},
+
+		// Student, self, clear.
+		{"course-student", model.SubmissionSelectionStudentSelected, "", "", true, false, "", ""},
+
+		// Student, self, missing.
+		{"course-student", model.SubmissionSelectionStudentSelected, "", "ZZZ", true, false, "", ""},
+
+		// Grader, other.
+		{"course-grader", model.SubmissionSelectionStudentSelected, "course-student@test.edulinq.org", "1697406256", true, true, "1697406256", ""},
+
+		// Grader, missing user.
+		{"course-grader", model.SubmissionSelectionStudentSelected, "ZZZ@test.edulinq.org", "1697406256", false, false, "", ""},
+
+		// Student, other.
+		{"course-student", model.SubmissionSelectionStudentSelected, "course-grader@test.edulinq.org", "1697406256", false, false, "", "-033"},
+
+		// Wrong policy.
+		{"course-student", "", "", "1697406256", false, false, "", "-632"},
+		{"course-student", model.SubmissionSelectionHighestScore, "", "1697406256", false, false, "", "-632"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestAssignment()
+		assignment.SubmissionSelection = testCase.policy
+
+		err := db.SaveAssignment(assignment)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save assignment: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/select`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent SelectResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Found user does not match. Expected: '%v', actual: '%v'.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.foundSubmission != responseContent.FoundSubmission {
+			test.Errorf("Case %d: Found submission does not match. Expected: '%v', actual: '%v'.", i, testCase.foundSubmission, responseContent.FoundSubmission)
+			continue
+		}
+
+		selections, err := db.GetFinalSubmissionSelections(assignment)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get final submissions: '%v'.", i, err)
+			continue
+		}
+
+		targetEmail := testCase.targetEmail
+		if targetEmail == "" {
+			targetEmail = testCase.email + "@test.edulinq.org"
+		}
+
+		if testCase.expectedFinal != selections[targetEmail] {
+			test.Errorf("Case %d: Unexpected final submission. Expected: '%s', actual: '%s'.", i, testCase.expectedFinal, selections[targetEmail])
+			continue
+		}
+	}
+}
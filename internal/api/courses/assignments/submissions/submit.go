@@ -61,6 +61,15 @@ func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
 		return &response, nil
 	}
 
+	// Students should not see the details of hidden test cases or unreleased scores.
+	if request.User.Role < model.CourseRoleGrader {
+		result.Info.RedactHiddenTestCases()
+
+		if !request.Assignment.IsScoreReleased() {
+			result.Info.WithholdScores(request.Assignment.ScoreRelease)
+		}
+	}
+
 	response.GradingSuccess = true
 	response.GradingInfo = result.Info
 
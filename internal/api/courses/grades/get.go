@@ -0,0 +1,48 @@
+package grades
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/grades"
+)
+
+type GetRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleStudent
+
+	TargetUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+}
+
+type GetResponse struct {
+	FoundUser bool                `json:"found-user"`
+	Grade     *grades.CourseGrade `json:"grade"`
+}
+
+// Get the current and projected course grade for a user.
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	if request.Course.GetGradeScheme() == nil {
+		return nil, core.NewBadRequestError("-635", &request.APIRequest, "Course does not have a grade scheme.").
+			Course(request.Course.GetID())
+	}
+
+	response := GetResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	// Students should not see unreleased scores.
+	includeUnreleased := (request.User.Role >= model.CourseRoleGrader)
+
+	grade, err := grades.ComputeCourseGrade(request.Course, request.TargetUser.Email, includeUnreleased)
+	if err != nil {
+		return nil, core.NewInternalError("-636", &request.APIRequestCourseUserContext, "Failed to compute course grade.").
+			Err(err).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.Grade = grade
+
+	return &response, nil
+}
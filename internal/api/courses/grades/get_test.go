@@ -0,0 +1,125 @@
+package grades
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGet(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setTestGradeScheme(test)
+
+	testCases := []struct {
+		email            string
+		target           string
+		permError        bool
+		locator          string
+		expectedFound    bool
+		expectedCurrent  float64
+		expectedLetter   string
+		expectedStatuses []string
+	}{
+		// Self.
+		{"course-student", "", false, "", true, 100, "A", []string{"graded"}},
+		{"course-grader", "", false, "", true, 0, "", []string{"pending"}},
+
+		// Other, bad permissions.
+		{"course-student", "course-grader@test.edulinq.org", true, "-033", false, 0, "", nil},
+
+		// Other, good permissions.
+		{"course-grader", "course-student@test.edulinq.org", false, "", true, 100, "A", []string{"graded"}},
+		{"course-admin", "course-student@test.edulinq.org", false, "", true, 100, "A", []string{"graded"}},
+
+		// Missing.
+		{"course-admin", "ZZZ@test.edulinq.org", false, "", false, 0, "", nil},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email": testCase.target,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/grades/get`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.permError {
+				if testCase.locator != response.Locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+						i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.permError {
+			test.Errorf("Case %d: Did not get an expected permissions error.", i)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedFound != responseContent.FoundUser {
+			test.Errorf("Case %d: Found user does not match. Expected: '%v', actual: '%v'.", i, testCase.expectedFound, responseContent.FoundUser)
+			continue
+		}
+
+		if !testCase.expectedFound {
+			continue
+		}
+
+		grade := responseContent.Grade
+		if testCase.expectedCurrent != grade.CurrentPercent {
+			test.Errorf("Case %d: Unexpected current percent. Expected: %f, actual: %f.", i, testCase.expectedCurrent, grade.CurrentPercent)
+		}
+
+		if testCase.expectedLetter != grade.CurrentLetter {
+			test.Errorf("Case %d: Unexpected current letter. Expected: '%s', actual: '%s'.", i, testCase.expectedLetter, grade.CurrentLetter)
+		}
+
+		statuses := make([]string, 0)
+		for _, assignmentGrade := range grade.Categories[0].Assignments {
+			statuses = append(statuses, string(assignmentGrade.Status))
+		}
+
+		if util.MustToJSON(testCase.expectedStatuses) != util.MustToJSON(statuses) {
+			test.Errorf("Case %d: Unexpected statuses. Expected: '%v', actual: '%v'.", i, testCase.expectedStatuses, statuses)
+		}
+	}
+}
+
+func TestGetNoScheme(test *testing.T) {
+	response := core.SendTestAPIRequestFull(test, `courses/grades/get`, nil, nil, "course-student")
+	if response.Success {
+		test.Fatalf("Response is a success when it should not be.")
+	}
+
+	expectedLocator := "-635"
+	if expectedLocator != response.Locator {
+		test.Fatalf("Incorrect error returned. Expected '%s', found '%s'.", expectedLocator, response.Locator)
+	}
+}
+
+func setTestGradeScheme(test *testing.T) {
+	course := db.MustGetTestCourse()
+	course.GradeScheme = &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{"hw0"}},
+		},
+		LetterGrades: []*model.LetterGrade{
+			&model.LetterGrade{Letter: "A", MinPercent: 90},
+		},
+	}
+
+	err := db.SaveCourse(course)
+	if err != nil {
+		test.Fatalf("Failed to save course: '%v'.", err)
+	}
+}
@@ -0,0 +1,54 @@
+package grades
+
+import (
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/grades"
+)
+
+type ListRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleGrader
+
+	FilterRole model.CourseUserRole `json:"filter-role"`
+	model.CourseUserFilter
+}
+
+type ListResponse struct {
+	Grades []*grades.CourseGrade `json:"grades"`
+}
+
+// Get the current and projected course grades for all users (of an optional role, section, and/or group).
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	if request.Course.GetGradeScheme() == nil {
+		return nil, core.NewBadRequestError("-637", &request.APIRequest, "Course does not have a grade scheme.").
+			Course(request.Course.GetID())
+	}
+
+	courseGrades, err := grades.ComputeCourseGrades(request.Course, request.FilterRole, true)
+	if err != nil {
+		return nil, core.NewInternalError("-638", &request.APIRequestCourseUserContext, "Failed to compute course grades.").
+			Err(err)
+	}
+
+	courseGrades, err = db.FilterByCourseUsers(request.Course, &request.CourseUserFilter, courseGrades)
+	if err != nil {
+		return nil, core.NewInternalError("-647", &request.APIRequestCourseUserContext, "Failed to filter course grades.").
+			Err(err)
+	}
+
+	response := ListResponse{
+		Grades: make([]*grades.CourseGrade, 0, len(courseGrades)),
+	}
+
+	for _, grade := range courseGrades {
+		response.Grades = append(response.Grades, grade)
+	}
+
+	slices.SortFunc(response.Grades, grades.CompareCourseGrades)
+
+	return &response, nil
+}
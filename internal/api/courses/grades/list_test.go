@@ -0,0 +1,70 @@
+package grades
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	defer db.ResetForTesting()
+
+	setTestGradeScheme(test)
+
+	testCases := []struct {
+		email          string
+		filterRole     model.CourseUserRole
+		permError      bool
+		expectedEmails []string
+	}{
+		{"course-grader", model.CourseRoleStudent, false, []string{"course-student@test.edulinq.org"}},
+		{"course-admin", model.CourseRoleUnknown, false, []string{
+			"course-admin@test.edulinq.org",
+			"course-grader@test.edulinq.org",
+			"course-other@test.edulinq.org",
+			"course-owner@test.edulinq.org",
+			"course-student@test.edulinq.org",
+		}},
+		{"course-student", model.CourseRoleUnknown, true, nil},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"filter-role": testCase.filterRole,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/grades/list`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.permError {
+				expectedLocator := "-020"
+				if expectedLocator != response.Locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, expectedLocator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.permError {
+			test.Errorf("Case %d: Did not get an expected permissions error.", i)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		emails := make([]string, 0, len(responseContent.Grades))
+		for _, grade := range responseContent.Grades {
+			emails = append(emails, grade.Email)
+		}
+
+		if util.MustToJSON(testCase.expectedEmails) != util.MustToJSON(emails) {
+			test.Errorf("Case %d: Unexpected users. Expected: '%v', actual: '%v'.", i, testCase.expectedEmails, emails)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package grades
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/grades/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/grades/list`, HandleList),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,90 @@
+package integrity
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type AttachRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+	Files core.POSTFiles `json:"-"`
+
+	CaseID string `json:"case-id"`
+}
+
+type AttachResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Attach the given files to an academic integrity case (replacing any existing attachments with the same name).
+func HandleAttach(request *AttachRequest) (*AttachResponse, *core.APIError) {
+	allContents := make(map[string][]byte, len(request.Files.Filenames))
+
+	for _, filename := range request.Files.Filenames {
+		contents, err := util.ReadBinaryFile(filepath.Join(request.Files.TempDir, filename))
+		if err != nil {
+			return nil, core.NewInternalError("-680", &request.APIRequestCourseUserContext, "Failed to read attachment.").
+				Err(err).Add("case-id", request.CaseID).Add("filename", filename)
+		}
+
+		allContents[filename] = contents
+	}
+
+	now := timestamp.Now()
+
+	integrityCase, apiErr := updateCase(&request.APIRequestCourseUserContext, request.CaseID, func(integrityCase *model.IntegrityCase) *core.APIError {
+		for _, filename := range request.Files.Filenames {
+			attachment := &model.IntegrityCaseAttachment{
+				Filename:  filename,
+				Size:      len(allContents[filename]),
+				Author:    request.User.Email,
+				Timestamp: now,
+			}
+
+			// Replace any existing attachment with the same name.
+			replaced := false
+			for i, oldAttachment := range integrityCase.Attachments {
+				if oldAttachment.Filename == filename {
+					integrityCase.Attachments[i] = attachment
+					replaced = true
+					break
+				}
+			}
+
+			if !replaced {
+				integrityCase.Attachments = append(integrityCase.Attachments, attachment)
+			}
+
+			integrityCase.AddEvent(request.User.Email, model.IntegrityCaseActionAttachment,
+				fmt.Sprintf("Attached file '%s' (%d bytes).", filename, len(allContents[filename])))
+		}
+
+		// Validate the updated case before storing any files.
+		apiErr := validateCase(&request.APIRequestCourseUserContext, integrityCase, integrityCase.Users)
+		if apiErr != nil {
+			return apiErr
+		}
+
+		for _, filename := range request.Files.Filenames {
+			err := db.StoreIntegrityCaseAttachment(request.Course, integrityCase.ID, filename, allContents[filename])
+			if err != nil {
+				return core.NewInternalError("-681", &request.APIRequestCourseUserContext, "Failed to store attachment.").
+					Err(err).Add("case-id", request.CaseID).Add("filename", filename)
+			}
+		}
+
+		return nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &AttachResponse{integrityCase}, nil
+}
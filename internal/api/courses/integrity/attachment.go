@@ -0,0 +1,56 @@
+package integrity
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type AttachmentRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	CaseID   string `json:"case-id"`
+	Filename string `json:"filename"`
+}
+
+type AttachmentResponse struct {
+	Attachment *model.IntegrityCaseAttachment `json:"attachment"`
+
+	// Base64 encoded.
+	Contents []byte `json:"contents"`
+}
+
+// Fetch the contents of a file attached to an academic integrity case.
+func HandleAttachment(request *AttachmentRequest) (*AttachmentResponse, *core.APIError) {
+	integrityCase, apiErr := getCase(&request.APIRequestCourseUserContext, request.CaseID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var attachment *model.IntegrityCaseAttachment = nil
+	for _, caseAttachment := range integrityCase.Attachments {
+		if caseAttachment.Filename == request.Filename {
+			attachment = caseAttachment
+			break
+		}
+	}
+
+	if attachment == nil {
+		return nil, core.NewBadCourseRequestError("-682", &request.APIRequestCourseUserContext, "Unknown attachment.").
+			Add("case-id", request.CaseID).Add("filename", request.Filename)
+	}
+
+	contents, err := db.GetIntegrityCaseAttachment(request.Course, integrityCase.ID, attachment.Filename)
+	if err != nil {
+		return nil, core.NewInternalError("-683", &request.APIRequestCourseUserContext, "Failed to get attachment.").
+			Err(err).Add("case-id", request.CaseID).Add("filename", request.Filename)
+	}
+
+	if contents == nil {
+		return nil, core.NewInternalError("-684", &request.APIRequestCourseUserContext, "Attachment contents are missing.").
+			Add("case-id", request.CaseID).Add("filename", request.Filename)
+	}
+
+	return &AttachmentResponse{attachment, contents}, nil
+}
@@ -0,0 +1,138 @@
+package integrity
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get a case from the request's course.
+// A missing case is a bad request.
+func getCase(request *core.APIRequestCourseUserContext, caseID string) (*model.IntegrityCase, *core.APIError) {
+	integrityCase, err := db.GetIntegrityCase(request.Course, caseID)
+	if err != nil {
+		return nil, core.NewInternalError("-672", request, "Failed to get integrity case.").
+			Err(err).Add("case-id", caseID)
+	}
+
+	if integrityCase == nil {
+		return nil, core.NewBadCourseRequestError("-673", request, "Unknown integrity case.").
+			Add("case-id", caseID)
+	}
+
+	return integrityCase, nil
+}
+
+// Change an existing case and save it as a single database operation (so concurrent changes are not lost).
+// The changed case is validated (an invalid case is a bad request) before it is saved.
+// If changeFunc returns an error, the case is not saved.
+func updateCase(request *core.APIRequestCourseUserContext, caseID string, changeFunc func(*model.IntegrityCase) *core.APIError) (*model.IntegrityCase, *core.APIError) {
+	var apiErr *core.APIError = nil
+
+	integrityCase, err := db.UpdateIntegrityCase(request.Course, caseID, func(integrityCase *model.IntegrityCase) error {
+		oldUsers := slices.Clone(integrityCase.Users)
+
+		apiErr = changeFunc(integrityCase)
+		if apiErr == nil {
+			apiErr = validateCase(request, integrityCase, oldUsers)
+		}
+
+		if apiErr != nil {
+			return fmt.Errorf("Integrity case change was rejected.")
+		}
+
+		return nil
+	})
+
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if err != nil {
+		return nil, core.NewInternalError("-697", request, "Failed to save integrity case.").
+			Err(err).Add("case-id", caseID)
+	}
+
+	if integrityCase == nil {
+		return nil, core.NewBadCourseRequestError("-698", request, "Unknown integrity case.").
+			Add("case-id", caseID)
+	}
+
+	return integrityCase, nil
+}
+
+// Validate and save a new case.
+// An invalid case is a bad request.
+func saveCase(request *core.APIRequestCourseUserContext, integrityCase *model.IntegrityCase) *core.APIError {
+	apiErr := validateCase(request, integrityCase, nil)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	err := db.UpsertIntegrityCase(request.Course, integrityCase)
+	if err != nil {
+		return core.NewInternalError("-675", request, "Failed to save integrity case.").
+			Err(err).Add("case-id", integrityCase.ID)
+	}
+
+	return nil
+}
+
+// Validate a case and check that any users added to it (users not in oldUsers) are enrolled in the course.
+// Users that were already on a case may have since left the course, so they are not checked.
+func validateCase(request *core.APIRequestCourseUserContext, integrityCase *model.IntegrityCase, oldUsers []string) *core.APIError {
+	err := integrityCase.Validate()
+	if err != nil {
+		return core.NewBadCourseRequestError("-674", request, "Invalid integrity case.").
+			Err(err).Add("case-id", integrityCase.ID)
+	}
+
+	newUsers := make([]string, 0)
+	for _, email := range integrityCase.Users {
+		if !slices.Contains(oldUsers, email) {
+			newUsers = append(newUsers, email)
+		}
+	}
+
+	if len(newUsers) == 0 {
+		return nil
+	}
+
+	courseUsers, err := db.GetCourseUsers(request.Course)
+	if err != nil {
+		return core.NewInternalError("-693", request, "Failed to get course users.").
+			Err(err).Add("case-id", integrityCase.ID)
+	}
+
+	unknownUsers := make([]string, 0)
+	for _, email := range newUsers {
+		if courseUsers[email] == nil {
+			unknownUsers = append(unknownUsers, email)
+		}
+	}
+
+	if len(unknownUsers) > 0 {
+		return core.NewBadCourseRequestError("-694", request, "Integrity cases can only include users enrolled in the course.").
+			Add("case-id", integrityCase.ID).Add("unknown-users", unknownUsers)
+	}
+
+	return nil
+}
+
+// Check that an assignment (if given) is in the request's course.
+func validateAssignmentID(request *core.APIRequestCourseUserContext, assignmentID string) (string, *core.APIError) {
+	if assignmentID == "" {
+		return "", nil
+	}
+
+	assignment := request.Course.GetAssignment(assignmentID)
+	if assignment == nil {
+		return "", core.NewBadCourseRequestError("-676", request, "Unknown assignment.").
+			Add("assignment-id", assignmentID)
+	}
+
+	return assignment.GetID(), nil
+}
@@ -0,0 +1,51 @@
+package integrity
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type CreateRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	Title        string `json:"title"`
+	AssignmentID string `json:"assignment-id"`
+
+	Users         []string            `json:"users"`
+	SubmissionIDs []string            `json:"submission-ids"`
+	PairwiseKeys  []model.PairwiseKey `json:"pairwise-keys"`
+}
+
+type CreateResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Open a new academic integrity case linking users, submissions, and pairwise analysis results.
+func HandleCreate(request *CreateRequest) (*CreateResponse, *core.APIError) {
+	assignmentID, apiErr := validateAssignmentID(&request.APIRequestCourseUserContext, request.AssignmentID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	integrityCase := model.NewIntegrityCase(request.Course.GetID(), assignmentID, request.Title, request.User.Email)
+
+	if request.Users != nil {
+		integrityCase.Users = request.Users
+	}
+
+	if request.SubmissionIDs != nil {
+		integrityCase.SubmissionIDs = request.SubmissionIDs
+	}
+
+	if request.PairwiseKeys != nil {
+		integrityCase.PairwiseKeys = request.PairwiseKeys
+	}
+
+	apiErr = saveCase(&request.APIRequestCourseUserContext, integrityCase)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &CreateResponse{integrityCase}, nil
+}
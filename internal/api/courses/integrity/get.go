@@ -0,0 +1,27 @@
+package integrity
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type GetRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	CaseID string `json:"case-id"`
+}
+
+type GetResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Get an academic integrity case (including its notes, attachments, and history).
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	integrityCase, apiErr := getCase(&request.APIRequestCourseUserContext, request.CaseID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &GetResponse{integrityCase}, nil
+}
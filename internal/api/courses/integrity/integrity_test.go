@@ -0,0 +1,300 @@
+package integrity
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestIntegrityBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	submissionID := "course101::hw0::course-student@test.edulinq.org::1697406256"
+
+	// Create.
+	fields := map[string]any{
+		"title":          "Shared Code",
+		"assignment-id":  "hw0",
+		"submission-ids": []string{submissionID},
+	}
+
+	integrityCase := sendCaseRequest(test, `courses/integrity/create`, fields, nil)
+
+	if !reflect.DeepEqual([]string{"course-student@test.edulinq.org"}, integrityCase.Users) {
+		test.Fatalf("Unexpected users: '%v'.", integrityCase.Users)
+	}
+
+	caseID := integrityCase.ID
+
+	// Update.
+	fields = map[string]any{
+		"case-id": caseID,
+		"users":   []string{"course-other@test.edulinq.org"},
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/update`, fields, nil)
+
+	expectedUsers := []string{"course-other@test.edulinq.org", "course-student@test.edulinq.org"}
+	if !reflect.DeepEqual(expectedUsers, integrityCase.Users) {
+		test.Fatalf("Unexpected users. Expected: '%v', Actual: '%v'.", expectedUsers, integrityCase.Users)
+	}
+
+	// Note.
+	fields = map[string]any{
+		"case-id": caseID,
+		"text":    "Met with the students.",
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/note`, fields, nil)
+
+	if (len(integrityCase.Notes) != 1) || (integrityCase.Notes[0].Author != "course-admin@test.edulinq.org") {
+		test.Fatalf("Unexpected notes: '%s'.", util.MustToJSONIndent(integrityCase.Notes))
+	}
+
+	// Attach.
+	path := filepath.Join(util.RootDirForTesting(), "testdata", "files", "sim_engine", "test-submissions", "solution", "submission.py")
+	fields = map[string]any{
+		"case-id": caseID,
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/attach`, fields, []string{path})
+
+	if (len(integrityCase.Attachments) != 1) || (integrityCase.Attachments[0].Filename != "submission.py") {
+		test.Fatalf("Unexpected attachments: '%s'.", util.MustToJSONIndent(integrityCase.Attachments))
+	}
+
+	// Attachment.
+	fields = map[string]any{
+		"case-id":  caseID,
+		"filename": "submission.py",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/integrity/attachment`, fields, nil, "course-admin")
+	if !response.Success {
+		test.Fatalf("Attachment response is not a success when it should be: '%v'.", response)
+	}
+
+	var attachmentContent AttachmentResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &attachmentContent)
+
+	expectedContents, err := util.ReadFile(path)
+	if err != nil {
+		test.Fatalf("Failed to read attachment: '%v'.", err)
+	}
+
+	if expectedContents != string(attachmentContent.Contents) {
+		test.Fatalf("Unexpected attachment contents. Expected: '%s', Actual: '%s'.", expectedContents, string(attachmentContent.Contents))
+	}
+
+	// Penalty.
+	fields = map[string]any{
+		"case-id": caseID,
+		"penalty": map[string]any{
+			"type":           "percentage",
+			"value":          0.5,
+			"apply-to-score": true,
+		},
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/penalty`, fields, nil)
+
+	if (integrityCase.Penalty == nil) || (integrityCase.Penalty.Type != model.IntegrityPenaltyPercentage) {
+		test.Fatalf("Unexpected penalty: '%s'.", util.MustToJSONIndent(integrityCase.Penalty))
+	}
+
+	// Status.
+	for _, status := range []string{"under-review", "resolved"} {
+		fields = map[string]any{
+			"case-id": caseID,
+			"status":  status,
+		}
+
+		if status == "resolved" {
+			fields["outcome"] = "violation"
+		}
+
+		integrityCase = sendCaseRequest(test, `courses/integrity/status`, fields, nil)
+	}
+
+	if !integrityCase.IsPenaltyActive() {
+		test.Fatalf("Penalty is not active: '%s'.", util.MustToJSONIndent(integrityCase))
+	}
+
+	// Every change should be in the history.
+	expectedActions := []model.IntegrityCaseAction{
+		model.IntegrityCaseActionCreate,
+		model.IntegrityCaseActionUpdate,
+		model.IntegrityCaseActionNote,
+		model.IntegrityCaseActionAttachment,
+		model.IntegrityCaseActionPenalty,
+		model.IntegrityCaseActionStatus,
+		model.IntegrityCaseActionStatus,
+	}
+
+	// Get.
+	fields = map[string]any{
+		"case-id": caseID,
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/get`, fields, nil)
+
+	actualActions := make([]model.IntegrityCaseAction, 0, len(integrityCase.History))
+	for _, event := range integrityCase.History {
+		actualActions = append(actualActions, event.Action)
+	}
+
+	if !reflect.DeepEqual(expectedActions, actualActions) {
+		test.Fatalf("Unexpected history. Expected: '%v', Actual: '%v'.", expectedActions, actualActions)
+	}
+
+	// List.
+	testCases := []struct {
+		fields   map[string]any
+		expected int
+	}{
+		{nil, 1},
+		{map[string]any{"status": "resolved"}, 1},
+		{map[string]any{"status": "open"}, 0},
+		{map[string]any{"target-email": "course-other@test.edulinq.org"}, 1},
+		{map[string]any{"target-email": "course-grader@test.edulinq.org"}, 0},
+	}
+
+	for i, testCase := range testCases {
+		response = core.SendTestAPIRequestFull(test, `courses/integrity/list`, testCase.fields, nil, "course-admin")
+		if !response.Success {
+			test.Errorf("Case %d: List response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var listContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &listContent)
+
+		if testCase.expected != len(listContent.Cases) {
+			test.Errorf("Case %d: Unexpected number of cases. Expected: %d, Actual: %d.", i, testCase.expected, len(listContent.Cases))
+			continue
+		}
+	}
+}
+
+func TestIntegrityErrors(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+	integrityCase := model.NewIntegrityCase(course.GetID(), "hw0", "Test", "course-admin@test.edulinq.org")
+
+	err := db.UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to create case: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email    string
+		endpoint string
+		fields   map[string]any
+		locator  string
+	}{
+		{"course-admin", `courses/integrity/get`, map[string]any{"case-id": "ZZZ"}, "-673"},
+		{"course-admin", `courses/integrity/create`, map[string]any{"title": ""}, "-674"},
+		{"course-admin", `courses/integrity/create`, map[string]any{"title": "Test", "submission-ids": []string{"ZZZ"}}, "-674"},
+		{"course-admin", `courses/integrity/create`, map[string]any{"title": "Test", "assignment-id": "ZZZ"}, "-676"},
+		{"course-admin", `courses/integrity/status`, map[string]any{"case-id": integrityCase.ID, "status": "resolved"}, "-678"},
+		{"course-admin", `courses/integrity/status`, map[string]any{"case-id": integrityCase.ID, "status": "open"}, "-678"},
+		{"course-admin", `courses/integrity/note`, map[string]any{"case-id": integrityCase.ID, "text": " "}, "-679"},
+		{"course-admin", `courses/integrity/attachment`, map[string]any{"case-id": integrityCase.ID, "filename": "ZZZ"}, "-682"},
+		{"course-admin", `courses/integrity/penalty`, map[string]any{"case-id": integrityCase.ID, "penalty": map[string]any{"type": "ZZZ"}}, "-674"},
+		{"course-admin", `courses/integrity/note`, map[string]any{"case-id": "ZZZ", "text": "Note."}, "-698"},
+		{"course-admin", `courses/integrity/create`, map[string]any{"title": "Test", "users": []string{"zzz@test.edulinq.org"}}, "-694"},
+		{"course-admin", `courses/integrity/update`, map[string]any{"case-id": integrityCase.ID, "users": []string{"zzz@test.edulinq.org"}}, "-694"},
+		{"course-admin", `courses/integrity/update`, map[string]any{"case-id": integrityCase.ID, "assignment-id": "hw0", "clear-assignment": true}, "-695"},
+
+		// Bad permissions.
+		{"course-grader", `courses/integrity/list`, nil, "-020"},
+		{"course-student", `courses/integrity/get`, map[string]any{"case-id": integrityCase.ID}, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, testCase.endpoint, testCase.fields, nil, testCase.email)
+		if response.Success {
+			test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response)
+			continue
+		}
+
+		if testCase.locator != response.Locator {
+			test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			continue
+		}
+	}
+}
+
+func TestIntegrityUpdateAssignmentAndUsers(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+
+	// A user that has since left the course.
+	integrityCase := model.NewIntegrityCase(course.GetID(), "hw0", "Test", "course-admin@test.edulinq.org")
+	integrityCase.Users = []string{"zzz@test.edulinq.org"}
+
+	err := db.UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to create case: '%v'.", err)
+	}
+
+	// Cases with users that are no longer enrolled can still be changed.
+	fields := map[string]any{
+		"case-id": integrityCase.ID,
+		"text":    "Student dropped.",
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/note`, fields, nil)
+
+	// Clear the assignment.
+	fields = map[string]any{
+		"case-id":          integrityCase.ID,
+		"assignment-id":    "",
+		"clear-assignment": true,
+	}
+
+	integrityCase = sendCaseRequest(test, `courses/integrity/update`, fields, nil)
+
+	if integrityCase.AssignmentID != "" {
+		test.Fatalf("Assignment was not cleared: '%s'.", integrityCase.AssignmentID)
+	}
+
+	if !reflect.DeepEqual([]string{"zzz@test.edulinq.org"}, integrityCase.Users) {
+		test.Fatalf("Unexpected users: '%v'.", integrityCase.Users)
+	}
+
+	stored, err := db.GetIntegrityCase(course, integrityCase.ID)
+	if err != nil {
+		test.Fatalf("Failed to get case: '%v'.", err)
+	}
+
+	if util.MustToJSONIndent(integrityCase) != util.MustToJSONIndent(stored) {
+		test.Fatalf("Unexpected stored case. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(integrityCase), util.MustToJSONIndent(stored))
+	}
+}
+
+func sendCaseRequest(test *testing.T, endpoint string, fields map[string]any, paths []string) *model.IntegrityCase {
+	response := core.SendTestAPIRequestFull(test, endpoint, fields, paths, "course-admin")
+	if !response.Success {
+		test.Fatalf("Response for '%s' is not a success when it should be: '%v'.", endpoint, response)
+	}
+
+	var content GetResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &content)
+
+	if content.Case == nil {
+		test.Fatalf("Response for '%s' has no case.", endpoint)
+	}
+
+	return content.Case
+}
@@ -0,0 +1,60 @@
+package integrity
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	// Only list cases with this status (all cases if empty).
+	Status model.IntegrityCaseStatus `json:"status"`
+
+	// Only list cases involving this user (all cases if empty).
+	TargetEmail string `json:"target-email"`
+}
+
+type ListResponse struct {
+	Cases []*model.IntegrityCase `json:"cases"`
+}
+
+// List the course's academic integrity cases (oldest first).
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	cases, err := db.GetIntegrityCases(request.Course)
+	if err != nil {
+		return nil, core.NewInternalError("-677", &request.APIRequestCourseUserContext, "Failed to get integrity cases.").
+			Err(err)
+	}
+
+	response := ListResponse{
+		Cases: make([]*model.IntegrityCase, 0, len(cases)),
+	}
+
+	for _, integrityCase := range cases {
+		if (request.Status != "") && (integrityCase.Status != request.Status) {
+			continue
+		}
+
+		if (request.TargetEmail != "") && !slices.Contains(integrityCase.Users, request.TargetEmail) {
+			continue
+		}
+
+		response.Cases = append(response.Cases, integrityCase)
+	}
+
+	slices.SortFunc(response.Cases, func(a *model.IntegrityCase, b *model.IntegrityCase) int {
+		if a.CreatedTime != b.CreatedTime {
+			return cmp.Compare(a.CreatedTime, b.CreatedTime)
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return &response, nil
+}
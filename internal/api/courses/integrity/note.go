@@ -0,0 +1,48 @@
+package integrity
+
+import (
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type NoteRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	CaseID string `json:"case-id"`
+	Text   string `json:"text"`
+}
+
+type NoteResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Add a staff note to an academic integrity case.
+func HandleNote(request *NoteRequest) (*NoteResponse, *core.APIError) {
+	text := strings.TrimSpace(request.Text)
+	if text == "" {
+		return nil, core.NewBadCourseRequestError("-679", &request.APIRequestCourseUserContext, "Notes cannot be empty.").
+			Add("case-id", request.CaseID)
+	}
+
+	note := &model.IntegrityCaseNote{
+		Author:    request.User.Email,
+		Timestamp: timestamp.Now(),
+		Text:      text,
+	}
+
+	integrityCase, apiErr := updateCase(&request.APIRequestCourseUserContext, request.CaseID, func(integrityCase *model.IntegrityCase) *core.APIError {
+		integrityCase.Notes = append(integrityCase.Notes, note)
+		integrityCase.AddEvent(request.User.Email, model.IntegrityCaseActionNote, "Added a note.")
+
+		return nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &NoteResponse{integrityCase}, nil
+}
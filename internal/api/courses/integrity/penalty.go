@@ -0,0 +1,41 @@
+package integrity
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type PenaltyRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	CaseID string `json:"case-id"`
+
+	// A missing (null) penalty clears the case's penalty.
+	Penalty *model.IntegrityPenalty `json:"penalty"`
+}
+
+type PenaltyResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Set (or clear) the penalty for an academic integrity case.
+// Penalties marked to apply to scores will be applied when the case's assignment is scored,
+// as long as the case is resolved with a violation outcome.
+func HandlePenalty(request *PenaltyRequest) (*PenaltyResponse, *core.APIError) {
+	integrityCase, apiErr := updateCase(&request.APIRequestCourseUserContext, request.CaseID, func(integrityCase *model.IntegrityCase) *core.APIError {
+		integrityCase.AddEvent(request.User.Email, model.IntegrityCaseActionPenalty,
+			fmt.Sprintf("Changed penalty from %s to %s.", integrityCase.Penalty.String(), request.Penalty.String()))
+
+		integrityCase.Penalty = request.Penalty
+
+		return nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &PenaltyResponse{integrityCase}, nil
+}
@@ -0,0 +1,23 @@
+package integrity
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/integrity/attach`, HandleAttach),
+	core.MustNewAPIRoute(`courses/integrity/attachment`, HandleAttachment),
+	core.MustNewAPIRoute(`courses/integrity/create`, HandleCreate),
+	core.MustNewAPIRoute(`courses/integrity/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/integrity/list`, HandleList),
+	core.MustNewAPIRoute(`courses/integrity/note`, HandleNote),
+	core.MustNewAPIRoute(`courses/integrity/penalty`, HandlePenalty),
+	core.MustNewAPIRoute(`courses/integrity/status`, HandleStatus),
+	core.MustNewAPIRoute(`courses/integrity/update`, HandleUpdate),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
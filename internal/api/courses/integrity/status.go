@@ -0,0 +1,40 @@
+package integrity
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type StatusRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	CaseID string `json:"case-id"`
+
+	Status model.IntegrityCaseStatus `json:"status"`
+
+	// Required when (and only when) resolving a case.
+	Outcome model.IntegrityCaseOutcome `json:"outcome"`
+}
+
+type StatusResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Move an academic integrity case through its workflow (open, under review, resolved).
+func HandleStatus(request *StatusRequest) (*StatusResponse, *core.APIError) {
+	integrityCase, apiErr := updateCase(&request.APIRequestCourseUserContext, request.CaseID, func(integrityCase *model.IntegrityCase) *core.APIError {
+		err := integrityCase.SetStatus(request.Status, request.Outcome, request.User.Email)
+		if err != nil {
+			return core.NewBadCourseRequestError("-678", &request.APIRequestCourseUserContext, "Invalid status change.").
+				Err(err).Add("case-id", request.CaseID).Add("status", request.Status).Add("outcome", request.Outcome)
+		}
+
+		return nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &StatusResponse{integrityCase}, nil
+}
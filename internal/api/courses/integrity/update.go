@@ -0,0 +1,81 @@
+package integrity
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type UpdateRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	CaseID string `json:"case-id"`
+
+	// Empty values leave the title/assignment unchanged.
+	Title        string `json:"title"`
+	AssignmentID string `json:"assignment-id"`
+
+	// Remove the case's assignment (cannot be used with an assignment ID).
+	ClearAssignment bool `json:"clear-assignment"`
+
+	// Missing (null) values leave the links unchanged, other values replace them.
+	Users         []string            `json:"users"`
+	SubmissionIDs []string            `json:"submission-ids"`
+	PairwiseKeys  []model.PairwiseKey `json:"pairwise-keys"`
+}
+
+type UpdateResponse struct {
+	Case *model.IntegrityCase `json:"case"`
+}
+
+// Update the title, assignment, or links (users, submissions, and pairwise results) of an academic integrity case.
+func HandleUpdate(request *UpdateRequest) (*UpdateResponse, *core.APIError) {
+	if request.ClearAssignment && (request.AssignmentID != "") {
+		return nil, core.NewBadCourseRequestError("-695", &request.APIRequestCourseUserContext, "Cannot both set and clear a case's assignment.").
+			Add("case-id", request.CaseID).Add("assignment-id", request.AssignmentID)
+	}
+
+	assignmentID, apiErr := validateAssignmentID(&request.APIRequestCourseUserContext, request.AssignmentID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	integrityCase, apiErr := updateCase(&request.APIRequestCourseUserContext, request.CaseID, func(integrityCase *model.IntegrityCase) *core.APIError {
+		if request.Title != "" {
+			integrityCase.Title = request.Title
+		}
+
+		if assignmentID != "" {
+			integrityCase.AssignmentID = assignmentID
+		}
+
+		if request.ClearAssignment {
+			integrityCase.AssignmentID = ""
+		}
+
+		if request.Users != nil {
+			integrityCase.Users = request.Users
+		}
+
+		if request.SubmissionIDs != nil {
+			integrityCase.SubmissionIDs = request.SubmissionIDs
+		}
+
+		if request.PairwiseKeys != nil {
+			integrityCase.PairwiseKeys = request.PairwiseKeys
+		}
+
+		integrityCase.AddEvent(request.User.Email, model.IntegrityCaseActionUpdate,
+			fmt.Sprintf("Updated case (title: '%s', assignment: '%s', users: %d, submissions: %d, pairs: %d).",
+				integrityCase.Title, integrityCase.AssignmentID, len(integrityCase.Users), len(integrityCase.SubmissionIDs), len(integrityCase.PairwiseKeys)))
+
+		return nil
+	})
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &UpdateResponse{integrityCase}, nil
+}
@@ -0,0 +1,79 @@
+package latedays
+
+import (
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type AdjustRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	TargetUser core.TargetCourseUser `json:"target-email"`
+
+	Days         int    `json:"days"`
+	AssignmentID string `json:"assignment-id"`
+	Comment      string `json:"comment"`
+}
+
+type AdjustResponse struct {
+	FoundUser bool `json:"found-user"`
+	Balance   int  `json:"balance"`
+}
+
+// Manually add (positive days) or remove (negative days) late days from a user's balance.
+func HandleAdjust(request *AdjustRequest) (*AdjustResponse, *core.APIError) {
+	response := AdjustResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	assignmentID := ""
+	if request.AssignmentID != "" {
+		assignment := request.Course.GetAssignment(request.AssignmentID)
+		if assignment == nil {
+			return nil, core.NewBadRequestError("-641", &request.APIRequest, "Unknown assignment.").
+				Course(request.Course.GetID()).Add("assignment-id", request.AssignmentID)
+		}
+
+		assignmentID = assignment.GetID()
+	}
+
+	entry := &model.LateDayEntry{
+		Email:        request.TargetUser.Email,
+		Type:         model.LateDayEntryAdjustment,
+		Days:         request.Days,
+		AssignmentID: assignmentID,
+		Timestamp:    timestamp.Now(),
+		Author:       request.User.Email,
+		Comment:      request.Comment,
+	}
+
+	err := entry.Validate()
+	if err != nil {
+		return nil, core.NewBadRequestError("-642", &request.APIRequest, "Invalid late day adjustment.").
+			Course(request.Course.GetID()).Err(err)
+	}
+
+	// Add the entry and compute the new balance as one ledger operation,
+	// so the balance cannot be changed by another update in between.
+	err = db.UpdateLateDayEntries(request.Course, func(allEntries map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+		entries := append(slices.Clone(allEntries[request.TargetUser.Email]), entry)
+		response.Balance = model.ComputeLateDayBalance(request.Course.GetTotalLateDays(), entries)
+
+		return []*model.LateDayEntry{entry}, nil
+	})
+	if err != nil {
+		return nil, core.NewInternalError("-643", &request.APIRequestCourseUserContext, "Failed to add late day adjustment.").
+			Err(err).Add("target-user", request.TargetUser.Email)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,88 @@
+package latedays
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestAdjust(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	// Cases build on each other's balances.
+	testCases := []struct {
+		email           string
+		target          string
+		days            int
+		assignmentID    string
+		locator         string
+		expectedFound   bool
+		expectedBalance int
+	}{
+		{"course-admin", "course-student@test.edulinq.org", 3, "", "", true, 3},
+		{"course-owner", "course-student@test.edulinq.org", -1, "hw0", "", true, 2},
+		{"server-admin", "course-student@test.edulinq.org", 2, "", "", true, 4},
+
+		// Missing user.
+		{"course-admin", "ZZZ@test.edulinq.org", 1, "", "", false, 0},
+
+		// Errors.
+		{"course-admin", "course-student@test.edulinq.org", 1, "ZZZ", "-641", false, 0},
+		{"course-admin", "course-student@test.edulinq.org", 0, "", "-642", false, 0},
+
+		// Bad permissions.
+		{"course-grader", "course-student@test.edulinq.org", 1, "", "-020", false, 0},
+		{"course-student", "course-student@test.edulinq.org", 1, "", "-020", false, 0},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email":  testCase.target,
+			"days":          testCase.days,
+			"assignment-id": testCase.assignmentID,
+			"comment":       "test",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/latedays/adjust`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent AdjustResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedFound != responseContent.FoundUser {
+			test.Errorf("Case %d: Found user does not match. Expected: '%v', actual: '%v'.", i, testCase.expectedFound, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.expectedBalance != responseContent.Balance {
+			test.Errorf("Case %d: Unexpected balance. Expected: %d, actual: %d.", i, testCase.expectedBalance, responseContent.Balance)
+		}
+	}
+
+	entries, err := db.GetUserLateDayEntries(db.MustGetTestCourse(), "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get late day entries: '%v'.", err)
+	}
+
+	if len(entries) != 3 {
+		test.Fatalf("Unexpected number of entries. Expected: 3, Actual: %d.", len(entries))
+	}
+
+	if entries[1].Author != "course-owner@test.edulinq.org" {
+		test.Fatalf("Unexpected author. Expected: 'course-owner@test.edulinq.org', Actual: '%s'.", entries[1].Author)
+	}
+}
@@ -0,0 +1,43 @@
+package latedays
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type GetRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleStudent
+
+	TargetUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+}
+
+type GetResponse struct {
+	FoundUser     bool                  `json:"found-user"`
+	TotalLateDays int                   `json:"total-late-days"`
+	Balance       int                   `json:"balance"`
+	Entries       []*model.LateDayEntry `json:"entries"`
+}
+
+// Get a user's late day balance and ledger.
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	response := GetResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	entries, err := db.GetUserLateDayEntries(request.Course, request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-639", &request.APIRequestCourseUserContext, "Failed to get late day entries.").
+			Err(err).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.FoundUser = true
+	response.TotalLateDays = request.Course.GetTotalLateDays()
+	response.Balance = model.ComputeLateDayBalance(response.TotalLateDays, entries)
+	response.Entries = entries
+
+	return &response, nil
+}
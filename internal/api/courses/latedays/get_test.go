@@ -0,0 +1,96 @@
+package latedays
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGet(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+	course.LatePolicy = &model.LateGradingPolicy{Type: model.LateDays, Penalty: 0.1, MaxLateDays: 2, TotalLateDays: 5}
+
+	err := db.SaveCourse(course)
+	if err != nil {
+		test.Fatalf("Failed to save course: '%v'.", err)
+	}
+
+	err = db.AddLateDayEntries(course, []*model.LateDayEntry{
+		&model.LateDayEntry{Email: "course-student@test.edulinq.org", Type: model.LateDayEntryCharge, Days: -2, AssignmentID: "hw0"},
+		&model.LateDayEntry{Email: "course-student@test.edulinq.org", Type: model.LateDayEntryAdjustment, Days: 1},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add late day entries: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email           string
+		target          string
+		locator         string
+		expectedFound   bool
+		expectedBalance int
+		expectedEntries int
+	}{
+		// Self.
+		{"course-student", "", "", true, 4, 2},
+		{"course-grader", "", "", true, 5, 0},
+
+		// Other.
+		{"course-grader", "course-student@test.edulinq.org", "", true, 4, 2},
+		{"course-admin", "course-student@test.edulinq.org", "", true, 4, 2},
+		{"course-student", "course-grader@test.edulinq.org", "-033", false, 0, 0},
+
+		// Missing.
+		{"course-admin", "ZZZ@test.edulinq.org", "", false, 0, 0},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email": testCase.target,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/latedays/get`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedFound != responseContent.FoundUser {
+			test.Errorf("Case %d: Found user does not match. Expected: '%v', actual: '%v'.", i, testCase.expectedFound, responseContent.FoundUser)
+			continue
+		}
+
+		if !testCase.expectedFound {
+			continue
+		}
+
+		if responseContent.TotalLateDays != 5 {
+			test.Errorf("Case %d: Unexpected total late days. Expected: 5, actual: %d.", i, responseContent.TotalLateDays)
+		}
+
+		if testCase.expectedBalance != responseContent.Balance {
+			test.Errorf("Case %d: Unexpected balance. Expected: %d, actual: %d.", i, testCase.expectedBalance, responseContent.Balance)
+		}
+
+		if testCase.expectedEntries != len(responseContent.Entries) {
+			test.Errorf("Case %d: Unexpected number of entries. Expected: %d, actual: %d.", i, testCase.expectedEntries, len(responseContent.Entries))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package latedays
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleGrader
+
+	Users core.CourseUsers `json:"-"`
+}
+
+type ListResponse struct {
+	TotalLateDays int               `json:"total-late-days"`
+	Balances      []*LateDayBalance `json:"balances"`
+}
+
+type LateDayBalance struct {
+	Email   string `json:"email"`
+	Balance int    `json:"balance"`
+	Used    int    `json:"used"`
+}
+
+// List the late day balance of every student in the course.
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	allEntries, err := db.GetLateDayEntries(request.Course)
+	if err != nil {
+		return nil, core.NewInternalError("-640", &request.APIRequestCourseUserContext, "Failed to get late day entries.").
+			Err(err)
+	}
+
+	response := ListResponse{
+		TotalLateDays: request.Course.GetTotalLateDays(),
+		Balances:      make([]*LateDayBalance, 0, len(request.Users)),
+	}
+
+	for email, user := range request.Users {
+		if user.Role != model.CourseRoleStudent {
+			continue
+		}
+
+		response.Balances = append(response.Balances, &LateDayBalance{
+			Email:   email,
+			Balance: model.ComputeLateDayBalance(response.TotalLateDays, allEntries[email]),
+			Used:    model.ComputeUsedLateDays(allEntries[email]),
+		})
+	}
+
+	slices.SortFunc(response.Balances, func(a *LateDayBalance, b *LateDayBalance) int {
+		return strings.Compare(a.Email, b.Email)
+	})
+
+	return &response, nil
+}
@@ -0,0 +1,60 @@
+package latedays
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	err := db.AddLateDayEntries(db.MustGetTestCourse(), []*model.LateDayEntry{
+		&model.LateDayEntry{Email: "course-student@test.edulinq.org", Type: model.LateDayEntryAdjustment, Days: 3},
+		&model.LateDayEntry{Email: "course-student@test.edulinq.org", Type: model.LateDayEntryCharge, Days: -2, AssignmentID: "hw0"},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add late day entries: '%v'.", err)
+	}
+
+	expected := []*LateDayBalance{
+		&LateDayBalance{Email: "course-student@test.edulinq.org", Balance: 1, Used: 2},
+	}
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-grader", ""},
+		{"course-admin", ""},
+		{"course-student", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/latedays/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if util.MustToJSONIndent(expected) != util.MustToJSONIndent(responseContent.Balances) {
+			test.Errorf("Case %d: Unexpected balances. Expected: '%s', actual: '%s'.",
+				i, util.MustToJSONIndent(expected), util.MustToJSONIndent(responseContent.Balances))
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package latedays
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/latedays/adjust`, HandleAdjust),
+	core.MustNewAPIRoute(`courses/latedays/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/latedays/list`, HandleList),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/model"
-	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/procedures/grades"
 )
 
 type UploadRequest struct {
@@ -23,7 +23,7 @@ type UploadResponse struct {
 
 // Perform a full scoring and upload scores to the course's LMS.
 func HandleUpload(request *UploadRequest) (*UploadResponse, *core.APIError) {
-	scores, err := scoring.FullCourseScoringAndUpload(request.Course, request.DryRun)
+	scores, err := grades.FullCourseScoringAndUpload(request.Course, request.DryRun)
 	if err != nil {
 		return nil, core.NewInternalError("-617", &request.APIRequestCourseUserContext,
 			"Failed to perform a full course scoring.").Err(err)
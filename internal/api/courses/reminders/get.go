@@ -0,0 +1,25 @@
+package reminders
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+type GetRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleStudent
+}
+
+type GetResponse struct {
+	OptOut bool `json:"opt-out"`
+}
+
+// Get whether the context user has opted out of reminder emails for this course.
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	optOut, err := db.IsReminderOptOut(request.Course, request.User.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-685", &request.APIRequestCourseUserContext, "Failed to get reminder opt-out.").Err(err)
+	}
+
+	return &GetResponse{optOut}, nil
+}
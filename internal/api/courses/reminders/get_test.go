@@ -0,0 +1,53 @@
+package reminders
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGet(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	err := db.SetReminderOptOut(db.MustGetTestCourse(), "course-student@test.edulinq.org", true)
+	if err != nil {
+		test.Fatalf("Failed to set opt-out: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email          string
+		locator        string
+		expectedOptOut bool
+	}{
+		{"course-student", "", true},
+		{"course-grader", "", false},
+		{"server-user", "-040", false},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/reminders/get`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedOptOut != responseContent.OptOut {
+			test.Errorf("Case %d: Unexpected opt-out. Expected: '%v', actual: '%v'.", i, testCase.expectedOptOut, responseContent.OptOut)
+			continue
+		}
+	}
+}
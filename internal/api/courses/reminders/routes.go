@@ -0,0 +1,16 @@
+package reminders
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/reminders/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/reminders/set`, HandleSet),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,28 @@
+package reminders
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+type SetRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleStudent
+
+	OptOut bool `json:"opt-out"`
+}
+
+type SetResponse struct {
+	OptOut bool `json:"opt-out"`
+}
+
+// Opt the context user out of (or back into) reminder emails for this course.
+func HandleSet(request *SetRequest) (*SetResponse, *core.APIError) {
+	err := db.SetReminderOptOut(request.Course, request.User.Email, request.OptOut)
+	if err != nil {
+		return nil, core.NewInternalError("-686", &request.APIRequestCourseUserContext, "Failed to set reminder opt-out.").
+			Err(err).Add("opt-out", request.OptOut)
+	}
+
+	return &SetResponse{request.OptOut}, nil
+}
@@ -0,0 +1,64 @@
+package reminders
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSet(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	testCases := []struct {
+		email   string
+		optOut  bool
+		locator string
+	}{
+		{"course-student", true, ""},
+		{"course-student", false, ""},
+		{"course-other", true, "-020"},
+		{"server-user", true, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"opt-out": testCase.optOut,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/reminders/set`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent SetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.optOut != responseContent.OptOut {
+			test.Errorf("Case %d: Unexpected opt-out in response. Expected: '%v', actual: '%v'.", i, testCase.optOut, responseContent.OptOut)
+			continue
+		}
+
+		optOut, err := db.IsReminderOptOut(db.MustGetTestCourse(), testCase.email+"@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to check opt-out: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.optOut != optOut {
+			test.Errorf("Case %d: Unexpected stored opt-out. Expected: '%v', actual: '%v'.", i, testCase.optOut, optOut)
+			continue
+		}
+	}
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/api/courses/admin"
 	"github.com/edulinq/autograder/internal/api/courses/assignments"
+	"github.com/edulinq/autograder/internal/api/courses/grades"
+	"github.com/edulinq/autograder/internal/api/courses/integrity"
+	"github.com/edulinq/autograder/internal/api/courses/latedays"
 	"github.com/edulinq/autograder/internal/api/courses/lms"
+	"github.com/edulinq/autograder/internal/api/courses/reminders"
 	"github.com/edulinq/autograder/internal/api/courses/stats"
+	"github.com/edulinq/autograder/internal/api/courses/tasks"
 	"github.com/edulinq/autograder/internal/api/courses/upsert"
 	"github.com/edulinq/autograder/internal/api/courses/users"
 )
@@ -17,8 +22,13 @@ func GetRoutes() *[]core.Route {
 
 	routes = append(routes, *(admin.GetRoutes())...)
 	routes = append(routes, *(assignments.GetRoutes())...)
+	routes = append(routes, *(grades.GetRoutes())...)
+	routes = append(routes, *(integrity.GetRoutes())...)
+	routes = append(routes, *(latedays.GetRoutes())...)
 	routes = append(routes, *(lms.GetRoutes())...)
+	routes = append(routes, *(reminders.GetRoutes())...)
 	routes = append(routes, *(stats.GetRoutes())...)
+	routes = append(routes, *(tasks.GetRoutes())...)
 	routes = append(routes, *(upsert.GetRoutes())...)
 	routes = append(routes, *(users.GetRoutes())...)
 
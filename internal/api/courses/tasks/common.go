@@ -0,0 +1,24 @@
+package tasks
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get an active task from the request's course.
+// A missing task (or a task from another course) is a bad request.
+func getTask(request *core.APIRequestCourseUserContext, taskHash string) (*model.FullScheduledTask, *core.APIError) {
+	task, err := db.GetActiveTask(taskHash)
+	if err != nil {
+		return nil, core.NewInternalError("-687", request, "Failed to get task.").
+			Err(err).Add("task-hash", taskHash)
+	}
+
+	if (task == nil) || (task.CourseID != request.Course.GetID()) {
+		return nil, core.NewBadCourseRequestError("-688", request, "Unknown task.").
+			Add("task-hash", taskHash)
+	}
+
+	return task, nil
+}
@@ -0,0 +1,38 @@
+package tasks
+
+import (
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type HistoryRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	TaskHash string `json:"task-hash"`
+}
+
+type HistoryResponse struct {
+	Runs []*model.TaskRun `json:"runs"`
+}
+
+// Get the recorded runs of a course task (most recent first).
+func HandleHistory(request *HistoryRequest) (*HistoryResponse, *core.APIError) {
+	task, apiErr := getTask(&request.APIRequestCourseUserContext, request.TaskHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	runs, err := db.GetTaskRuns(task.Hash)
+	if err != nil {
+		return nil, core.NewInternalError("-690", &request.APIRequestCourseUserContext, "Failed to get task runs.").
+			Err(err).Add("task-hash", request.TaskHash)
+	}
+
+	slices.Reverse(runs)
+
+	return &HistoryResponse{runs}, nil
+}
@@ -0,0 +1,83 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestHistory(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+	addTestTasks()
+
+	for i := 0; i < 3; i++ {
+		run := &model.TaskRun{
+			TaskHash:  "A",
+			Type:      model.TaskTypeTest,
+			CourseID:  db.TEST_COURSE_ID,
+			StartTime: timestamp.FromMSecs(int64(i)),
+			Success:   true,
+		}
+
+		err := db.AddTaskRun(run)
+		if err != nil {
+			test.Fatalf("Failed to add task run: '%v'.", err)
+		}
+	}
+
+	testCases := []struct {
+		email         string
+		taskHash      string
+		locator       string
+		expectedTimes []timestamp.Timestamp
+	}{
+		{"course-admin", "A", "", []timestamp.Timestamp{2, 1, 0}},
+		{"course-admin", "B", "", []timestamp.Timestamp{}},
+
+		// Other course.
+		{"course-admin", "C", "-688", nil},
+
+		{"course-admin", "ZZZ", "-688", nil},
+		{"course-student", "A", "-020", nil},
+		{"server-user", "A", "-040", nil},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"task-hash": testCase.taskHash,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/tasks/history`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent HistoryResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actualTimes := make([]timestamp.Timestamp, 0, len(responseContent.Runs))
+		for _, run := range responseContent.Runs {
+			actualTimes = append(actualTimes, run.StartTime)
+		}
+
+		if !reflect.DeepEqual(testCase.expectedTimes, actualTimes) {
+			test.Errorf("Case %d: Unexpected runs. Expected: '%v', Actual: '%v'.", i, testCase.expectedTimes, actualTimes)
+			continue
+		}
+	}
+}
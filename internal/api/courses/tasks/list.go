@@ -0,0 +1,46 @@
+package tasks
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+}
+
+type ListResponse struct {
+	Tasks []*model.FullScheduledTask `json:"tasks"`
+}
+
+// List the course's active tasks (soonest next run first).
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	tasks, err := db.GetActiveCourseTasks(request.Course)
+	if err != nil {
+		return nil, core.NewInternalError("-689", &request.APIRequestCourseUserContext, "Failed to get tasks.").
+			Err(err)
+	}
+
+	response := ListResponse{
+		Tasks: make([]*model.FullScheduledTask, 0, len(tasks)),
+	}
+
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, task)
+	}
+
+	slices.SortFunc(response.Tasks, func(a *model.FullScheduledTask, b *model.FullScheduledTask) int {
+		if a.NextRunTime != b.NextRunTime {
+			return cmp.Compare(a.NextRunTime, b.NextRunTime)
+		}
+
+		return cmp.Compare(a.Hash, b.Hash)
+	})
+
+	return &response, nil
+}
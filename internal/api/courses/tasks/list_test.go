@@ -0,0 +1,92 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestList(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email         string
+		locator       string
+		expectedTasks []string
+	}{
+		{"course-admin", "", []string{"B", "A"}},
+		{"course-grader", "-020", nil},
+		{"course-student", "-020", nil},
+		{"server-user", "-040", nil},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		addTestTasks()
+
+		response := core.SendTestAPIRequestFull(test, `courses/tasks/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actualTasks := make([]string, 0, len(responseContent.Tasks))
+		for _, task := range responseContent.Tasks {
+			actualTasks = append(actualTasks, task.Hash)
+		}
+
+		if !reflect.DeepEqual(testCase.expectedTasks, actualTasks) {
+			test.Errorf("Case %d: Unexpected tasks. Expected: '%v', Actual: '%v'.", i, testCase.expectedTasks, actualTasks)
+			continue
+		}
+	}
+}
+
+// Add two tasks to the test course and one to another course.
+// All tasks are scheduled days in the future, so a running task engine will not run them.
+// Note that the hashes are not real and only work since we don't validate them.
+func addTestTasks() {
+	tasks := map[string]*model.FullScheduledTask{
+		"A": newTestTask("A", db.TEST_COURSE_ID, 2),
+		"B": newTestTask("B", db.TEST_COURSE_ID, 1),
+		"C": newTestTask("C", "course-languages", 0),
+	}
+
+	err := db.UpsertActiveTasks(tasks)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newTestTask(hash string, courseID string, daysFromNow int64) *model.FullScheduledTask {
+	return &model.FullScheduledTask{
+		UserTaskInfo: model.UserTaskInfo{
+			Type: model.TaskTypeTest,
+			When: &util.ScheduledTime{
+				Daily: "0:00",
+			},
+		},
+		SystemTaskInfo: model.SystemTaskInfo{
+			Source:      model.TaskSourceCourse,
+			CourseID:    courseID,
+			NextRunTime: timestamp.Now() + timestamp.FromMSecs((daysFromNow+1)*24*60*60*1000),
+			Hash:        hash,
+		},
+	}
+}
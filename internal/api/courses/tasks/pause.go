@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type PauseRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	TaskHash string `json:"task-hash"`
+	Paused   bool   `json:"paused"`
+}
+
+type PauseResponse struct {
+	Task *model.FullScheduledTask `json:"task"`
+}
+
+// Pause (or resume) the scheduled runs of a course task.
+func HandlePause(request *PauseRequest) (*PauseResponse, *core.APIError) {
+	_, apiErr := getTask(&request.APIRequestCourseUserContext, request.TaskHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	// Update in place, so a concurrent run's schedule update is not overwritten (and vice versa).
+	task, err := db.UpdateActiveTask(request.TaskHash, func(task *model.FullScheduledTask) error {
+		task.Paused = request.Paused
+		return nil
+	})
+	if err != nil {
+		return nil, core.NewInternalError("-691", &request.APIRequestCourseUserContext, "Failed to save task.").
+			Err(err).Add("task-hash", request.TaskHash).Add("paused", request.Paused)
+	}
+
+	// The task was removed after it was fetched.
+	if task == nil {
+		return nil, core.NewBadCourseRequestError("-696", &request.APIRequestCourseUserContext, "Unknown task.").
+			Add("task-hash", request.TaskHash)
+	}
+
+	return &PauseResponse{task}, nil
+}
@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestPause(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+	addTestTasks()
+
+	testCases := []struct {
+		email    string
+		taskHash string
+		paused   bool
+		locator  string
+	}{
+		{"course-admin", "A", true, ""},
+		{"course-admin", "A", false, ""},
+		{"course-admin", "B", true, ""},
+		{"course-admin", "C", true, "-688"},
+		{"course-admin", "ZZZ", true, "-688"},
+		{"course-student", "A", true, "-020"},
+		{"server-user", "A", true, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"task-hash": testCase.taskHash,
+			"paused":    testCase.paused,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/tasks/pause`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent PauseResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.paused != responseContent.Task.Paused {
+			test.Errorf("Case %d: Unexpected paused in response. Expected: '%v', actual: '%v'.", i, testCase.paused, responseContent.Task.Paused)
+			continue
+		}
+
+		task, err := db.GetActiveTask(testCase.taskHash)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get task: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.paused != task.Paused {
+			test.Errorf("Case %d: Unexpected stored paused. Expected: '%v', actual: '%v'.", i, testCase.paused, task.Paused)
+			continue
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package tasks
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/tasks/history`, HandleHistory),
+	core.MustNewAPIRoute(`courses/tasks/list`, HandleList),
+	core.MustNewAPIRoute(`courses/tasks/pause`, HandlePause),
+	core.MustNewAPIRoute(`courses/tasks/trigger`, HandleTrigger),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
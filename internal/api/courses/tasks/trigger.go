@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/tasks"
+)
+
+type TriggerRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	TaskHash          string `json:"task-hash"`
+	WaitForCompletion bool   `json:"wait-for-completion"`
+}
+
+type TriggerResponse struct {
+	// Only set when waiting for completion.
+	Run *model.TaskRun `json:"run"`
+}
+
+// Run a course task right away.
+// The task's schedule is not affected, and paused tasks can still be triggered.
+// Tasks cannot be triggered when the task engine is not running (e.g., tasks are disabled).
+// The task is run in the background, and its run can be found with courses/tasks/history once it completes.
+func HandleTrigger(request *TriggerRequest) (*TriggerResponse, *core.APIError) {
+	task, apiErr := getTask(&request.APIRequestCourseUserContext, request.TaskHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	run, err := tasks.RunTaskNow(task, request.User.Email, request.WaitForCompletion)
+	if err != nil {
+		return nil, core.NewBadCourseRequestError("-692", &request.APIRequestCourseUserContext, "Unable to run task.").
+			Err(err).Add("task-hash", request.TaskHash)
+	}
+
+	return &TriggerResponse{run}, nil
+}
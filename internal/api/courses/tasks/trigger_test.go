@@ -0,0 +1,87 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/tasks"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestTrigger(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+	addTestTasks()
+
+	// Tasks cannot be triggered when the engine is not running.
+	fields := map[string]any{
+		"task-hash":           "A",
+		"wait-for-completion": true,
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/tasks/trigger`, fields, nil, "course-admin")
+	if response.Success || (response.Locator != "-692") {
+		test.Fatalf("Did not get the expected error when the task engine is stopped. Expected: '%s', Actual: '%s'.", "-692", response.Locator)
+	}
+
+	config.NO_TASKS.Set(false)
+	defer config.NO_TASKS.Set(true)
+
+	tasks.Start()
+	defer tasks.Stop()
+
+	testCases := []struct {
+		email    string
+		taskHash string
+		locator  string
+	}{
+		{"course-admin", "A", ""},
+		{"course-admin", "C", "-688"},
+		{"course-admin", "ZZZ", "-688"},
+		{"course-student", "A", "-020"},
+		{"server-user", "A", "-040"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"task-hash":           testCase.taskHash,
+			"wait-for-completion": true,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/tasks/trigger`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent TriggerResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		run := responseContent.Run
+		if (run == nil) || !run.Success || (run.TaskHash != testCase.taskHash) || (run.TriggeredBy != (testCase.email + "@test.edulinq.org")) {
+			test.Errorf("Case %d: Unexpected run: '%s'.", i, util.MustToJSONIndent(run))
+			continue
+		}
+
+		runs, err := db.GetTaskRuns(testCase.taskHash)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get task runs: '%v'.", i, err)
+			continue
+		}
+
+		if len(runs) != 1 {
+			test.Errorf("Case %d: Unexpected number of task runs. Expected: %d, Actual: %d.", i, 1, len(runs))
+			continue
+		}
+	}
+}
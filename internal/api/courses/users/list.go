@@ -9,6 +9,9 @@ type ListRequest struct {
 	core.APIRequestCourseUserContext
 	core.MinCourseRoleGrader
 	Users core.CourseUsers `json:"-"`
+
+	// Only list users in this section/group.
+	model.CourseUserFilter
 }
 
 type ListResponse struct {
@@ -19,6 +22,10 @@ type ListResponse struct {
 func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
 	users := make([]*model.CourseUser, 0, len(request.Users))
 	for _, user := range request.Users {
+		if !request.CourseUserFilter.Matches(user) {
+			continue
+		}
+
 		users = append(users, user)
 	}
 
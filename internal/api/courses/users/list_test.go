@@ -115,3 +115,56 @@ func TestListEmptyCourse(test *testing.T) {
 			util.MustToJSONIndent(expectedInfos), util.MustToJSONIndent(responseContent.Users))
 	}
 }
+
+func TestListFilter(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+
+	student := db.MustGetServerUser("course-student@test.edulinq.org")
+	student.CourseInfo[course.GetID()].Sections = []string{"Lecture 01", "Lab 01"}
+	student.CourseInfo[course.GetID()].Groups = map[string]string{"Projects": "Team 1"}
+	db.MustUpsertUser(student)
+
+	grader := db.MustGetServerUser("course-grader@test.edulinq.org")
+	grader.CourseInfo[course.GetID()].Sections = []string{"Lab 01"}
+	db.MustUpsertUser(grader)
+
+	testCases := []struct {
+		filter         model.CourseUserFilter
+		expectedEmails []string
+	}{
+		{model.CourseUserFilter{FilterSection: "Lab 01"}, []string{"course-grader@test.edulinq.org", "course-student@test.edulinq.org"}},
+		{model.CourseUserFilter{FilterSection: "lecture 01"}, []string{"course-student@test.edulinq.org"}},
+		{model.CourseUserFilter{FilterGroup: "Projects/Team 1"}, []string{"course-student@test.edulinq.org"}},
+		{model.CourseUserFilter{FilterSection: "Lab 01", FilterGroup: "Team 1"}, []string{"course-student@test.edulinq.org"}},
+		{model.CourseUserFilter{FilterSection: "ZZZ"}, []string{}},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"filter-section": testCase.filter.FilterSection,
+			"filter-group":   testCase.filter.FilterGroup,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/users/list`, fields, nil, "course-admin")
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actualEmails := make([]string, 0, len(responseContent.Users))
+		for _, user := range responseContent.Users {
+			actualEmails = append(actualEmails, user.Email)
+		}
+
+		if !reflect.DeepEqual(testCase.expectedEmails, actualEmails) {
+			test.Errorf("Case %d: Unexpected users. Expected: '%v', actual: '%v'.", i, testCase.expectedEmails, actualEmails)
+			continue
+		}
+	}
+}
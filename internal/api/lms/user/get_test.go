@@ -26,6 +26,8 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleOther,
 				"lms-course-other@test.edulinq.org",
+				nil,
+				nil,
 			},
 		},
 		{
@@ -38,6 +40,8 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleStudent,
 				"lms-course-student@test.edulinq.org",
+				nil,
+				nil,
 			},
 		},
 		{
@@ -50,6 +54,8 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleGrader,
 				"lms-course-grader@test.edulinq.org",
+				nil,
+				nil,
 			},
 		},
 		{
@@ -62,6 +68,8 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleAdmin,
 				"lms-course-admin@test.edulinq.org",
+				nil,
+				nil,
 			},
 		},
 		{
@@ -74,6 +82,8 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleOwner,
 				"lms-course-owner@test.edulinq.org",
+				nil,
+				nil,
 			},
 		},
 
@@ -0,0 +1,140 @@
+package lti
+
+import (
+	"net/http"
+	neturl "net/url"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/lti"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Where users are sent after a successful launch.
+// Launch information (including a token to authenticate with) is passed in the URL fragment.
+const LAUNCH_REDIRECT_PATH = "/static/index.html"
+
+// The cookie that ties a login's state to the browser that started it.
+// Launches are cross-site POSTs from the platform, so the cookie must be SameSite=None (and therefore Secure).
+const LOGIN_STATE_COOKIE = "autograder-lti-state"
+const LOGIN_STATE_COOKIE_PATH = "/lti/launch"
+
+// OIDC login initiation (the platform may use either GET or POST).
+func HandleLogin(response http.ResponseWriter, request *http.Request) error {
+	err := request.ParseForm()
+	if err != nil {
+		http.Error(response, "Failed to parse LTI login request.", http.StatusBadRequest)
+		return nil
+	}
+
+	loginRequest := &lti.LoginRequest{
+		Issuer:         request.Form.Get("iss"),
+		LoginHint:      request.Form.Get("login_hint"),
+		TargetLinkURI:  request.Form.Get("target_link_uri"),
+		LTIMessageHint: request.Form.Get("lti_message_hint"),
+		ClientID:       request.Form.Get("client_id"),
+		DeploymentID:   request.Form.Get("lti_deployment_id"),
+	}
+
+	redirectURL, state, err := lti.InitiateLogin(loginRequest)
+	if err != nil {
+		log.Warn("Failed to initiate LTI login.", err, log.NewAttr("issuer", loginRequest.Issuer))
+		http.Error(response, "Failed to initiate LTI login.", http.StatusBadRequest)
+		return nil
+	}
+
+	http.SetCookie(response, &http.Cookie{
+		Name:     LOGIN_STATE_COOKIE,
+		Value:    state,
+		Path:     LOGIN_STATE_COOKIE_PATH,
+		MaxAge:   int(lti.LOGIN_STATE_TTL.ToMSecs() / 1000),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	http.Redirect(response, request, redirectURL, http.StatusFound)
+	return nil
+}
+
+// The platform posts the id token here (via the user's browser) to complete a launch.
+func HandleLaunch(response http.ResponseWriter, request *http.Request) error {
+	err := request.ParseForm()
+	if err != nil {
+		http.Error(response, "Failed to parse LTI launch request.", http.StatusBadRequest)
+		return nil
+	}
+
+	browserState := ""
+	cookie, err := request.Cookie(LOGIN_STATE_COOKIE)
+	if err == nil {
+		browserState = cookie.Value
+	}
+
+	// States can only be used once, so always clear the cookie.
+	http.SetCookie(response, &http.Cookie{
+		Name:     LOGIN_STATE_COOKIE,
+		Path:     LOGIN_STATE_COOKIE_PATH,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+
+	launch, err := lti.ValidateLaunch(request.PostForm.Get("id_token"), request.PostForm.Get("state"), browserState)
+	if err != nil {
+		log.Warn("Invalid LTI launch.", err)
+		http.Error(response, "Invalid LTI launch.", http.StatusUnauthorized)
+		return nil
+	}
+
+	result, err := lti.ApplyLaunch(launch)
+	if err != nil {
+		log.Error("Failed to apply LTI launch.", err, log.NewCourseAttr(launch.CourseID), log.NewUserAttr(launch.Email))
+		http.Error(response, "Failed to apply LTI launch.", http.StatusInternalServerError)
+		return nil
+	}
+
+	email := launch.Email
+	if result.User != nil {
+		email = result.User.Email
+	}
+
+	log.Info("LTI launch.", log.NewCourseAttr(launch.CourseID), log.NewUserAttr(email),
+		log.NewAttr("assignment", launch.AssignmentID), log.NewAttr("role", launch.Role.String()),
+		log.NewAttr("bind", (result.BindTicket != "")))
+
+	// Fragments are not sent to servers (or logged by them).
+	// Without a token, the user must log in normally (and redeem any bind ticket with the `users/lti/bind` endpoint).
+	fragment := neturl.Values{}
+	fragment.Set("course", launch.CourseID)
+	fragment.Set("email", email)
+
+	if result.Token != "" {
+		fragment.Set("token", result.Token)
+	}
+
+	if result.BindTicket != "" {
+		fragment.Set("bind-ticket", result.BindTicket)
+	}
+
+	if launch.AssignmentID != "" {
+		fragment.Set("assignment", launch.AssignmentID)
+	}
+
+	http.Redirect(response, request, LAUNCH_REDIRECT_PATH+"#"+fragment.Encode(), http.StatusSeeOther)
+	return nil
+}
+
+// The tool's public keys, which platforms use to verify service requests.
+func HandleKeySet(response http.ResponseWriter, request *http.Request) error {
+	body, err := util.ToJSON(lti.GetToolKeySet())
+	if err != nil {
+		return err
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(http.StatusOK)
+
+	_, err = response.Write([]byte(body))
+	return err
+}
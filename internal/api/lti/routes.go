@@ -0,0 +1,19 @@
+package lti
+
+// Routes for acting as an LTI 1.3 tool.
+// These are not API endpoints (they are called by browsers and LTI platforms), so they are base routes.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.NewBaseRoute("GET", `/lti/login`, HandleLogin),
+	core.NewBaseRoute("POST", `/lti/login`, HandleLogin),
+	core.NewBaseRoute("POST", `/lti/launch`, HandleLaunch),
+	core.NewBaseRoute("GET", `/lti/jwks`, HandleKeySet),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,29 @@
+package lti
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/lti"
+)
+
+type BindRequest struct {
+	core.APIRequestUserContext
+
+	BindTicket string `json:"bind-ticket"`
+}
+
+type BindResponse struct {
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id,omitempty"`
+}
+
+// Bind an LTI identity (from a launch for an existing account) to the context user.
+// Future launches with the same identity will authenticate as the context user.
+func HandleBind(request *BindRequest) (*BindResponse, *core.APIError) {
+	launch, err := lti.BindLaunch(request.BindTicket, request.ServerUser)
+	if err != nil {
+		return nil, core.NewBadUserRequestError("-814", &request.APIRequestUserContext, "Failed to bind LTI identity.").
+			Err(err)
+	}
+
+	return &BindResponse{launch.CourseID, launch.AssignmentID}, nil
+}
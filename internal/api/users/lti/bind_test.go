@@ -0,0 +1,87 @@
+package lti
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lti"
+	ltitest "github.com/edulinq/autograder/internal/lti/test"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestBind(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email   string
+		ticket  bool
+		locator string
+	}{
+		{"course-student", true, ""},
+		{"course-other", true, "-814"},
+		{"course-student", false, "-814"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		launch := &lti.Launch{
+			Issuer:       ltitest.TEST_ISSUER,
+			CourseID:     "course101",
+			AssignmentID: "hw0",
+			Email:        "course-student@test.edulinq.org",
+			Role:         model.CourseRoleGrader,
+			Subject:      "subject-1",
+		}
+
+		result, err := lti.ApplyLaunch(launch)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to apply launch: '%v'.", i, err)
+		}
+
+		ticket := "zzz"
+		if testCase.ticket {
+			ticket = result.BindTicket
+		}
+
+		fields := map[string]any{
+			"bind-ticket": ticket,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `users/lti/bind`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent BindResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		expected := BindResponse{"course101", "hw0"}
+		if expected != responseContent {
+			test.Errorf("Case %d: Unexpected response. Expected: '%v', Actual: '%v'.", i, expected, responseContent)
+			continue
+		}
+
+		email, err := db.GetLTIIdentity(launch.Issuer, launch.Subject)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get identity: '%v'.", i, err)
+			continue
+		}
+
+		if email != launch.Email {
+			test.Errorf("Case %d: Identity was not bound. Expected: '%s', Actual: '%s'.", i, launch.Email, email)
+			continue
+		}
+	}
+}
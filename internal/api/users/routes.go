@@ -4,6 +4,7 @@ package users
 
 import (
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/api/users/lti"
 	"github.com/edulinq/autograder/internal/api/users/password"
 	"github.com/edulinq/autograder/internal/api/users/tokens"
 )
@@ -20,6 +21,7 @@ func GetRoutes() *[]core.Route {
 	routes := make([]core.Route, 0)
 
 	routes = append(routes, baseRoutes...)
+	routes = append(routes, *(lti.GetRoutes())...)
 	routes = append(routes, *(password.GetRoutes())...)
 	routes = append(routes, *(tokens.GetRoutes())...)
 
@@ -13,7 +13,9 @@ const (
 	BACKUP_DIRNAME    = "backup"
 	CACHE_DIRNAME     = "cache"
 	CONFIG_DIRNAME    = "config"
+	CORPORA_DIRNAME   = "corpora"
 	DATABASE_DIRNAME  = "database"
+	LMS_FILES_DIRNAME = "lms-files"
 	LOGS_DIRNAME      = "logs"
 	SOURCES_DIRNAME   = "sources"
 	TEMPLATES_DIRNAME = "templates"
@@ -59,6 +61,10 @@ func GetConfigDir() string {
 	return filepath.Join(GetWorkDir(), CONFIG_DIRNAME)
 }
 
+func GetCorporaDir() string {
+	return filepath.Join(GetWorkDir(), CORPORA_DIRNAME)
+}
+
 func GetTestdataDir() string {
 	return filepath.Join(GetWorkDir(), TESTDATA_DIRNAME)
 }
@@ -67,6 +73,10 @@ func GetDatabaseDir() string {
 	return filepath.Join(GetWorkDir(), DATABASE_DIRNAME)
 }
 
+func GetLMSFilesDir() string {
+	return filepath.Join(GetWorkDir(), LMS_FILES_DIRNAME)
+}
+
 func GetLogsDir() string {
 	return filepath.Join(GetWorkDir(), LOGS_DIRNAME)
 }
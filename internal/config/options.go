@@ -43,9 +43,10 @@ var (
 	GRADING_RUNTIME_MAX_SECS = MustNewIntOption("grading.runtime.max", 60*5, "The maximum number of seconds a Docker container can be running for.")
 
 	// Tasks
-	NO_TASKS             = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.")
-	TASK_MAX_WAIT_SECS   = MustNewIntOption("tasks.maxwait", 2*60, "The maximum wait between checking for the next task to run.")
-	TASK_MIN_PERIOD_SECS = MustNewIntOption("tasks.minperiod", 10*60, "The minimum period between the runs of the same task.")
+	NO_TASKS              = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.")
+	TASK_MAX_WAIT_SECS    = MustNewIntOption("tasks.maxwait", 2*60, "The maximum wait between checking for the next task to run.")
+	TASK_MIN_PERIOD_SECS  = MustNewIntOption("tasks.minperiod", 10*60, "The minimum period between the runs of the same task.")
+	TASK_HISTORY_MAX_RUNS = MustNewIntOption("tasks.history.max", 100, "The maximum number of run records to keep for each task.")
 
 	// Server
 	WEB_HTTP_PORT        = MustNewIntOption("web.http.port", 8080, "The port to serve HTTP traffic on. Standard is 80 (but requires root to use).")
@@ -63,8 +64,19 @@ var (
 	DB_PG_URI = MustNewStringOption("db.pg.uri", "", "Connection string to connect to a Postgres Database. Empty if not using Postgres.")
 
 	// Code Analysis
+	ANALYSIS_ANOMALY_CODE_DROP           = MustNewFloatOption("analysis.anomaly.codedrop", 0.5, "The minimum fraction of a submission's previous code that must be removed for it to be flagged as a code drop.")
+	ANALYSIS_ANOMALY_NEAR_ZERO           = MustNewFloatOption("analysis.anomaly.nearzero", 0.1, "The fraction of the max score that counts as near zero (and near full) when flagging score jumps.")
+	ANALYSIS_ANOMALY_OUTLIER_FACTOR      = MustNewFloatOption("analysis.anomaly.outlierfactor", 3.0, "How many interquartile ranges outside of the class's 25th/75th percentiles a value must be to be flagged as an outlier.")
+	ANALYSIS_CLUSTER_THRESHOLD           = MustNewFloatOption("analysis.cluster.threshold", 0.5, "The minimum (total mean) similarity for a pair of submissions to be linked when clustering pairwise analysis results.")
+	ANALYSIS_CLUSTER_MIN_SIZE            = MustNewIntOption("analysis.cluster.minsize", 2, "The minimum number of members for a cluster of similar submissions to be reported.")
 	ANALYSIS_INDIVIDUAL_COURSE_POOL_SIZE = MustNewIntOption("analysis.individual.poolsize", 1, "The number of parallel workers per course when computing individual analysis.")
 	ANALYSIS_PAIRWISE_COURSE_POOL_SIZE   = MustNewIntOption("analysis.pairwise.poolsize", 1, "The number of parallel workers per course when computing pairwise analysis.")
+	ANALYSIS_PAIRWISE_ENGINES            = MustNewStringOption("analysis.pairwise.engines", "", "A comma-separated list of the similarity engines to use for pairwise analysis (e.g., 'native'). Empty means all available engines.")
+
+	// LTI
+	LTI_PLATFORMS_PATH = MustNewStringOption("lti.platforms", "", "Path to a JSON file with the LTI 1.3 platforms (e.g., LMS instances) that may launch the autograder.")
+	LTI_KEY_PATH       = MustNewStringOption("lti.key", "", "Path to a PEM encoded RSA private key used to sign LTI service requests. Required for LTI score passback.")
+	LTI_KEY_ID         = MustNewStringOption("lti.key.id", "autograder", "The ID of the LTI key (as advertised in the LTI key set).")
 
 	STALELOCK_DURATION_SECS = MustNewIntOption("lockmanager.staleduration", 2*60*60, "Number of seconds a lock can be unused before getting removed.")
 )
@@ -138,7 +138,7 @@ func upsertActiveCourseTasks(course *model.Course) error {
 
 	// Save.
 	if len(newTasks) > 0 {
-		err = backend.UpsertActiveTasks(newTasks)
+		err = upsertActiveTasks(newTasks)
 	}
 
 	return err
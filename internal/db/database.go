@@ -15,6 +15,7 @@ import (
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 var backend Backend
@@ -150,6 +151,78 @@ type Backend interface {
 	// A nil map should only be returned on error.
 	GetRecentSubmissionContents(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingResult, error)
 
+	// Get the final submissions that users have selected for this assignment.
+	// The returned map is keyed by email and has short submission IDs as values.
+	// Users without a selection will not be in the map.
+	GetFinalSubmissionSelections(assignment *model.Assignment) (map[string]string, error)
+
+	// Set a user's final submission for this assignment.
+	// An empty short submission ID will clear the user's selection.
+	SetFinalSubmissionSelection(assignment *model.Assignment, email string, shortSubmissionID string) error
+
+	// Late Day Ledger Operations
+
+	// Get all the late day entries for a course, keyed by email.
+	// Entries for each user are in the order they were added.
+	GetLateDayEntries(course *model.Course) (map[string][]*model.LateDayEntry, error)
+
+	// Add entries to the course's late day ledger.
+	// Entries should already be validated.
+	AddLateDayEntries(course *model.Course, entries []*model.LateDayEntry) error
+
+	// Read the course's late day ledger and add the entries returned by updateFunc as one operation.
+	// No other ledger changes may happen between the read and the write.
+	// updateFunc must not modify the entries it is given, and if it returns an error nothing is written.
+	UpdateLateDayEntries(course *model.Course, updateFunc func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error)) error
+
+	// Integrity Case Operations
+
+	// Get all the integrity cases for a course, keyed by case ID.
+	GetIntegrityCases(course *model.Course) (map[string]*model.IntegrityCase, error)
+
+	// Insert or update (by ID) an integrity case.
+	// Cases should already be validated.
+	UpsertIntegrityCase(course *model.Course, integrityCase *model.IntegrityCase) error
+
+	// Apply updateFunc to an existing integrity case and save the result as one operation.
+	// No other case changes may happen between the read and the write.
+	// Returns the updated case, or nil if the case does not exist (updateFunc will not be called).
+	// If updateFunc returns an error, nothing is written.
+	UpdateIntegrityCase(course *model.Course, caseID string, updateFunc func(*model.IntegrityCase) error) (*model.IntegrityCase, error)
+
+	// Store the contents of an attachment for an integrity case.
+	// Existing contents with the same filename will be replaced.
+	StoreIntegrityCaseAttachment(course *model.Course, caseID string, filename string, contents []byte) error
+
+	// Get the contents of an integrity case's attachment.
+	// Nil contents (and no error) will be returned if the attachment does not exist.
+	GetIntegrityCaseAttachment(course *model.Course, caseID string, filename string) ([]byte, error)
+
+	// Reminder Operations
+
+	// Get the users (by email) that have opted out of reminder emails for a course.
+	GetReminderOptOuts(course *model.Course) (map[string]bool, error)
+
+	// Set whether a user has opted out of reminder emails for a course.
+	SetReminderOptOut(course *model.Course, email string, optOut bool) error
+
+	// Get the reminders already sent for an assignment.
+	// The result maps each user's email to the due date they were reminded about.
+	GetSentReminders(assignment *model.Assignment) (map[string]timestamp.Timestamp, error)
+
+	// Record that reminders were sent for an assignment (see GetSentReminders()).
+	AddSentReminders(assignment *model.Assignment, reminders map[string]timestamp.Timestamp) error
+
+	// LTI Identity Operations
+
+	// Get the email of the user bound to an LTI identity (a platform issuer and the user's subject on that platform).
+	// An empty email (and no error) will be returned if the identity is not bound.
+	GetLTIIdentity(issuer string, subject string) (string, error)
+
+	// Bind an LTI identity to a user (by email).
+	// An empty email removes the binding.
+	SetLTIIdentity(issuer string, subject string, email string) error
+
 	// Task Operations
 
 	// Get all the active tasks that come from the given course.
@@ -159,7 +232,7 @@ type Backend interface {
 	// Get all the active tasks keyed by hash.
 	GetActiveTasks() (map[string]*model.FullScheduledTask, error)
 
-	// Get the next active task that should be run.
+	// Get the next active (non-paused) task that should be run.
 	// Return nil if there are no active tasks.
 	GetNextActiveTask() (*model.FullScheduledTask, error)
 
@@ -168,6 +241,22 @@ type Backend interface {
 	// and a nil value indicates that the given task should be removed.
 	UpsertActiveTasks(tasks map[string]*model.FullScheduledTask) error
 
+	// Apply updateFunc to an existing active task (by hash) and save the result as one operation.
+	// No other task changes may happen between the read and the write.
+	// Returns the updated task, or nil if the task does not exist (updateFunc will not be called).
+	// If updateFunc returns an error, nothing is written.
+	UpdateActiveTask(hash string, updateFunc func(*model.FullScheduledTask) error) (*model.FullScheduledTask, error)
+
+	// Get the run records for a task (by hash), oldest first.
+	GetTaskRuns(taskHash string) ([]*model.TaskRun, error)
+
+	// Add a run record for a task.
+	// Only the most recent |maxRuns| records for the task will be kept.
+	AddTaskRun(run *model.TaskRun, maxRuns int) error
+
+	// Remove all the run records for the given tasks (by hash).
+	RemoveTaskRuns(taskHashes []string) error
+
 	// Logging Operations
 
 	// DB backends will also be used as logging storage backends.
@@ -0,0 +1,142 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DISK_DB_INTEGRITY_CASES_FILENAME = "integrity-cases.json"
+	DISK_DB_INTEGRITY_CASES_DIRNAME  = "integrity-cases"
+)
+
+func (this *backend) GetIntegrityCases(course *model.Course) (map[string]*model.IntegrityCase, error) {
+	path := this.getIntegrityCasesPath(course.GetID())
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	return this.getIntegrityCases(path)
+}
+
+func (this *backend) UpsertIntegrityCase(course *model.Course, integrityCase *model.IntegrityCase) error {
+	path := this.getIntegrityCasesPath(course.GetID())
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	cases, err := this.getIntegrityCases(path)
+	if err != nil {
+		return err
+	}
+
+	cases[integrityCase.ID] = integrityCase
+
+	return this.writeIntegrityCases(path, cases)
+}
+
+func (this *backend) UpdateIntegrityCase(course *model.Course, caseID string, updateFunc func(*model.IntegrityCase) error) (*model.IntegrityCase, error) {
+	path := this.getIntegrityCasesPath(course.GetID())
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	cases, err := this.getIntegrityCases(path)
+	if err != nil {
+		return nil, err
+	}
+
+	integrityCase := cases[caseID]
+	if integrityCase == nil {
+		return nil, nil
+	}
+
+	err = updateFunc(integrityCase)
+	if err != nil {
+		return nil, err
+	}
+
+	err = this.writeIntegrityCases(path, cases)
+	if err != nil {
+		return nil, err
+	}
+
+	return integrityCase, nil
+}
+
+func (this *backend) StoreIntegrityCaseAttachment(course *model.Course, caseID string, filename string, contents []byte) error {
+	path := this.getIntegrityCaseAttachmentPath(course.GetID(), caseID, filename)
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for integrity case attachment '%s': '%w'.", path, err)
+	}
+
+	err = util.WriteBinaryFile(contents, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write integrity case attachment '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetIntegrityCaseAttachment(course *model.Course, caseID string, filename string) ([]byte, error) {
+	path := this.getIntegrityCaseAttachmentPath(course.GetID(), caseID, filename)
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	if !util.PathExists(path) {
+		return nil, nil
+	}
+
+	contents, err := util.ReadBinaryFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read integrity case attachment '%s': '%w'.", path, err)
+	}
+
+	return contents, nil
+}
+
+func (this *backend) getIntegrityCases(path string) (map[string]*model.IntegrityCase, error) {
+	cases := make(map[string]*model.IntegrityCase)
+
+	if !util.PathExists(path) {
+		return cases, nil
+	}
+
+	err := util.JSONFromFile(path, &cases)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read integrity cases file '%s': '%w'.", path, err)
+	}
+
+	return cases, nil
+}
+
+func (this *backend) writeIntegrityCases(path string, cases map[string]*model.IntegrityCase) error {
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for integrity cases file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(cases, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write integrity cases file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getIntegrityCasesPath(courseID string) string {
+	return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_INTEGRITY_CASES_FILENAME)
+}
+
+func (this *backend) getIntegrityCaseAttachmentPath(courseID string, caseID string, filename string) string {
+	return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_INTEGRITY_CASES_DIRNAME, caseID, filename)
+}
@@ -0,0 +1,82 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_LATE_DAYS_FILENAME = "late-days.json"
+
+func (this *backend) GetLateDayEntries(course *model.Course) (map[string][]*model.LateDayEntry, error) {
+	path := this.getLateDaysPath(course.GetID())
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	return this.getLateDayEntries(path)
+}
+
+func (this *backend) AddLateDayEntries(course *model.Course, entries []*model.LateDayEntry) error {
+	return this.UpdateLateDayEntries(course, func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+		return entries, nil
+	})
+}
+
+func (this *backend) UpdateLateDayEntries(course *model.Course, updateFunc func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error)) error {
+	path := this.getLateDaysPath(course.GetID())
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	allEntries, err := this.getLateDayEntries(path)
+	if err != nil {
+		return err
+	}
+
+	entries, err := updateFunc(allEntries)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for _, entry := range entries {
+		allEntries[entry.Email] = append(allEntries[entry.Email], entry)
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for late days file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(allEntries, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write late days file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getLateDayEntries(path string) (map[string][]*model.LateDayEntry, error) {
+	entries := make(map[string][]*model.LateDayEntry)
+
+	if !util.PathExists(path) {
+		return entries, nil
+	}
+
+	err := util.JSONFromFile(path, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read late days file '%s': '%w'.", path, err)
+	}
+
+	return entries, nil
+}
+
+func (this *backend) getLateDaysPath(courseID string) string {
+	return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_LATE_DAYS_FILENAME)
+}
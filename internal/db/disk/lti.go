@@ -0,0 +1,81 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_LTI_IDENTITIES_FILENAME = "lti-identities.json"
+
+func (this *backend) GetLTIIdentity(issuer string, subject string) (string, error) {
+	path := this.getLTIIdentitiesPath()
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	identities, err := this.getLTIIdentities(path)
+	if err != nil {
+		return "", err
+	}
+
+	return identities[issuer][subject], nil
+}
+
+func (this *backend) SetLTIIdentity(issuer string, subject string, email string) error {
+	path := this.getLTIIdentitiesPath()
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	identities, err := this.getLTIIdentities(path)
+	if err != nil {
+		return err
+	}
+
+	if email == "" {
+		delete(identities[issuer], subject)
+		if len(identities[issuer]) == 0 {
+			delete(identities, issuer)
+		}
+	} else {
+		if identities[issuer] == nil {
+			identities[issuer] = make(map[string]string)
+		}
+
+		identities[issuer][subject] = email
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for LTI identities file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(identities, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write LTI identities file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+// {issuer: {subject: email}}.
+func (this *backend) getLTIIdentities(path string) (map[string]map[string]string, error) {
+	identities := make(map[string]map[string]string)
+
+	if !util.PathExists(path) {
+		return identities, nil
+	}
+
+	err := util.JSONFromFile(path, &identities)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read LTI identities file '%s': '%w'.", path, err)
+	}
+
+	return identities, nil
+}
+
+func (this *backend) getLTIIdentitiesPath() string {
+	return filepath.Join(this.baseDir, DISK_DB_LTI_IDENTITIES_FILENAME)
+}
@@ -0,0 +1,139 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_REMINDERS_FILENAME = "reminders.json"
+
+type reminderRecords struct {
+	OptOuts map[string]bool `json:"opt-outs"`
+
+	// {assignmentID: {email: due date}}.
+	Sent map[string]map[string]timestamp.Timestamp `json:"sent"`
+}
+
+func (this *backend) GetReminderOptOuts(course *model.Course) (map[string]bool, error) {
+	path := this.getRemindersPath(course.GetID())
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	records, err := this.getReminderRecords(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return records.OptOuts, nil
+}
+
+func (this *backend) SetReminderOptOut(course *model.Course, email string, optOut bool) error {
+	path := this.getRemindersPath(course.GetID())
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	records, err := this.getReminderRecords(path)
+	if err != nil {
+		return err
+	}
+
+	if optOut {
+		records.OptOuts[email] = true
+	} else {
+		delete(records.OptOuts, email)
+	}
+
+	return this.putReminderRecords(path, records)
+}
+
+func (this *backend) GetSentReminders(assignment *model.Assignment) (map[string]timestamp.Timestamp, error) {
+	path := this.getRemindersPath(assignment.GetCourse().GetID())
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	records, err := this.getReminderRecords(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := records.Sent[assignment.GetID()]
+	if sent == nil {
+		sent = make(map[string]timestamp.Timestamp)
+	}
+
+	return sent, nil
+}
+
+func (this *backend) AddSentReminders(assignment *model.Assignment, reminders map[string]timestamp.Timestamp) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	path := this.getRemindersPath(assignment.GetCourse().GetID())
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	records, err := this.getReminderRecords(path)
+	if err != nil {
+		return err
+	}
+
+	sent := records.Sent[assignment.GetID()]
+	if sent == nil {
+		sent = make(map[string]timestamp.Timestamp)
+		records.Sent[assignment.GetID()] = sent
+	}
+
+	for email, dueDate := range reminders {
+		sent[email] = dueDate
+	}
+
+	return this.putReminderRecords(path, records)
+}
+
+func (this *backend) getReminderRecords(path string) (*reminderRecords, error) {
+	records := &reminderRecords{}
+
+	if util.PathExists(path) {
+		err := util.JSONFromFile(path, records)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read reminders file '%s': '%w'.", path, err)
+		}
+	}
+
+	if records.OptOuts == nil {
+		records.OptOuts = make(map[string]bool)
+	}
+
+	if records.Sent == nil {
+		records.Sent = make(map[string]map[string]timestamp.Timestamp)
+	}
+
+	return records, nil
+}
+
+func (this *backend) putReminderRecords(path string, records *reminderRecords) error {
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for reminders file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(records, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write reminders file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getRemindersPath(courseID string) string {
+	return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_REMINDERS_FILENAME)
+}
@@ -0,0 +1,86 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_FINAL_SUBMISSIONS_FILENAME = "final-submissions.json"
+
+func (this *backend) GetFinalSubmissionSelections(assignment *model.Assignment) (map[string]string, error) {
+	path := this.getFinalSubmissionsPath(assignment.GetCourse().GetID())
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	allSelections, err := this.getFinalSubmissionSelections(path)
+	if err != nil {
+		return nil, err
+	}
+
+	selections, ok := allSelections[assignment.GetID()]
+	if !ok {
+		selections = make(map[string]string)
+	}
+
+	return selections, nil
+}
+
+func (this *backend) SetFinalSubmissionSelection(assignment *model.Assignment, email string, shortSubmissionID string) error {
+	path := this.getFinalSubmissionsPath(assignment.GetCourse().GetID())
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	allSelections, err := this.getFinalSubmissionSelections(path)
+	if err != nil {
+		return err
+	}
+
+	selections, ok := allSelections[assignment.GetID()]
+	if !ok {
+		selections = make(map[string]string)
+		allSelections[assignment.GetID()] = selections
+	}
+
+	if shortSubmissionID == "" {
+		delete(selections, email)
+	} else {
+		selections[email] = shortSubmissionID
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for final submissions file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(allSelections, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write final submissions file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+// Get all final submission selections for a course: {assignmentID: {email: shortSubmissionID, ...}, ...}.
+func (this *backend) getFinalSubmissionSelections(path string) (map[string]map[string]string, error) {
+	selections := make(map[string]map[string]string)
+
+	if !util.PathExists(path) {
+		return selections, nil
+	}
+
+	err := util.JSONFromFile(path, &selections)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read final submissions file '%s': '%w'.", path, err)
+	}
+
+	return selections, nil
+}
+
+func (this *backend) getFinalSubmissionsPath(courseID string) string {
+	return filepath.Join(this.getCourseDirFromID(courseID), DISK_DB_FINAL_SUBMISSIONS_FILENAME)
+}
@@ -52,6 +52,10 @@ func (this *backend) GetNextActiveTask() (*model.FullScheduledTask, error) {
 
 	var nextTask *model.FullScheduledTask = nil
 	for _, task := range allTasks {
+		if task.Paused {
+			continue
+		}
+
 		if (nextTask == nil) || (nextTask.NextRunTime > task.NextRunTime) {
 			nextTask = task
 		}
@@ -80,6 +84,33 @@ func (this *backend) UpsertActiveTasks(upsertTasks map[string]*model.FullSchedul
 	return this.writeTasks(allTasks)
 }
 
+func (this *backend) UpdateActiveTask(hash string, updateFunc func(*model.FullScheduledTask) error) (*model.FullScheduledTask, error) {
+	this.tasksLock.Lock()
+	defer this.tasksLock.Unlock()
+
+	allTasks, err := this.getTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	task := allTasks[hash]
+	if task == nil {
+		return nil, nil
+	}
+
+	err = updateFunc(task)
+	if err != nil {
+		return nil, err
+	}
+
+	err = this.writeTasks(allTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
 func (this *backend) getActiveTasksPath() string {
 	return filepath.Join(this.baseDir, DISK_DB_ACTIVE_TASKS_FILENAME)
 }
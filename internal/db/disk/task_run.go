@@ -0,0 +1,111 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_TASK_RUNS_FILENAME = "task-runs.json"
+
+func (this *backend) GetTaskRuns(taskHash string) ([]*model.TaskRun, error) {
+	path := this.getTaskRunsPath()
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	allRuns, err := this.getTaskRuns(path)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := allRuns[taskHash]
+	if runs == nil {
+		runs = make([]*model.TaskRun, 0)
+	}
+
+	return runs, nil
+}
+
+func (this *backend) AddTaskRun(run *model.TaskRun, maxRuns int) error {
+	path := this.getTaskRunsPath()
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	allRuns, err := this.getTaskRuns(path)
+	if err != nil {
+		return err
+	}
+
+	runs := append(allRuns[run.TaskHash], run)
+	if (maxRuns > 0) && (len(runs) > maxRuns) {
+		runs = runs[len(runs)-maxRuns:]
+	}
+
+	allRuns[run.TaskHash] = runs
+
+	return this.writeTaskRuns(allRuns, path)
+}
+
+func (this *backend) RemoveTaskRuns(taskHashes []string) error {
+	path := this.getTaskRunsPath()
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	allRuns, err := this.getTaskRuns(path)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, taskHash := range taskHashes {
+		_, ok := allRuns[taskHash]
+		if ok {
+			delete(allRuns, taskHash)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return this.writeTaskRuns(allRuns, path)
+}
+
+func (this *backend) getTaskRuns(path string) (map[string][]*model.TaskRun, error) {
+	runs := make(map[string][]*model.TaskRun)
+
+	if !util.PathExists(path) {
+		return runs, nil
+	}
+
+	err := util.JSONFromFile(path, &runs)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read task runs file '%s': '%w'.", path, err)
+	}
+
+	return runs, nil
+}
+
+func (this *backend) writeTaskRuns(allRuns map[string][]*model.TaskRun, path string) error {
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for task runs file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(allRuns, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write task runs file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getTaskRunsPath() string {
+	return filepath.Join(this.baseDir, DISK_DB_TASK_RUNS_FILENAME)
+}
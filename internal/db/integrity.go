@@ -0,0 +1,117 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get all the integrity cases for a course, keyed by case ID.
+func GetIntegrityCases(course *model.Course) (map[string]*model.IntegrityCase, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetIntegrityCases(course)
+}
+
+// Get a single integrity case (nil if it does not exist).
+func GetIntegrityCase(course *model.Course, caseID string) (*model.IntegrityCase, error) {
+	cases, err := GetIntegrityCases(course)
+	if err != nil {
+		return nil, err
+	}
+
+	return cases[caseID], nil
+}
+
+// Validate and insert/update an integrity case.
+func UpsertIntegrityCase(course *model.Course, integrityCase *model.IntegrityCase) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := integrityCase.Validate()
+	if err != nil {
+		return fmt.Errorf("Integrity case is invalid: '%w'.", err)
+	}
+
+	if integrityCase.CourseID != course.GetID() {
+		return fmt.Errorf("Integrity case course ('%s') does not match the given course ('%s').", integrityCase.CourseID, course.GetID())
+	}
+
+	return backend.UpsertIntegrityCase(course, integrityCase)
+}
+
+// Change an existing integrity case, where the read, change, and write happen as one operation
+// (so concurrent changes to the same case are not lost).
+// The changed case will be validated before it is saved.
+// Returns the updated case, or nil if the case does not exist.
+func UpdateIntegrityCase(course *model.Course, caseID string, updateFunc func(*model.IntegrityCase) error) (*model.IntegrityCase, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpdateIntegrityCase(course, caseID, func(integrityCase *model.IntegrityCase) error {
+		err := updateFunc(integrityCase)
+		if err != nil {
+			return err
+		}
+
+		err = integrityCase.Validate()
+		if err != nil {
+			return fmt.Errorf("Integrity case is invalid: '%w'.", err)
+		}
+
+		if (integrityCase.ID != caseID) || (integrityCase.CourseID != course.GetID()) {
+			return fmt.Errorf("Integrity case ID or course cannot be changed.")
+		}
+
+		return nil
+	})
+}
+
+func StoreIntegrityCaseAttachment(course *model.Course, caseID string, filename string, contents []byte) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := validateAttachmentPath(caseID, filename)
+	if err != nil {
+		return err
+	}
+
+	return backend.StoreIntegrityCaseAttachment(course, caseID, filename, contents)
+}
+
+// Get the contents of an integrity case's attachment (nil if it does not exist).
+func GetIntegrityCaseAttachment(course *model.Course, caseID string, filename string) ([]byte, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	err := validateAttachmentPath(caseID, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.GetIntegrityCaseAttachment(course, caseID, filename)
+}
+
+// Attachments are stored flat under their case, so case IDs and filenames cannot contain any path components.
+func validateAttachmentPath(caseID string, filename string) error {
+	if !isPlainFilename(caseID) {
+		return fmt.Errorf("Integrity case ID is not valid: '%s'.", caseID)
+	}
+
+	if !isPlainFilename(filename) {
+		return fmt.Errorf("Attachment filename is not valid: '%s'.", filename)
+	}
+
+	return nil
+}
+
+func isPlainFilename(name string) bool {
+	return (name != "") && (name != ".") && (name != "..") && (filepath.Base(name) == name)
+}
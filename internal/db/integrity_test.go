@@ -0,0 +1,220 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestIntegrityCases(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	course := MustGetTestCourse()
+
+	cases, err := GetIntegrityCases(course)
+	if err != nil {
+		test.Fatalf("Failed to get initial cases: '%v'.", err)
+	}
+
+	if len(cases) != 0 {
+		test.Fatalf("Unexpected initial cases: '%s'.", util.MustToJSONIndent(cases))
+	}
+
+	integrityCase := model.NewIntegrityCase(course.GetID(), "hw0", "Test Case", "course-admin@test.edulinq.org")
+	integrityCase.SubmissionIDs = []string{"course101::hw0::course-student@test.edulinq.org::1697406256"}
+
+	err = UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to insert case: '%v'.", err)
+	}
+
+	// Users should have been filled in from the linked submissions.
+	expectedUsers := []string{"course-student@test.edulinq.org"}
+	if util.MustToJSON(expectedUsers) != util.MustToJSON(integrityCase.Users) {
+		test.Fatalf("Unexpected users. Expected: '%v', Actual: '%v'.", expectedUsers, integrityCase.Users)
+	}
+
+	err = integrityCase.SetStatus(model.IntegrityCaseStatusUnderReview, model.IntegrityCaseOutcomeNone, "course-admin@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to set status: '%v'.", err)
+	}
+
+	err = UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to update case: '%v'.", err)
+	}
+
+	actual, err := GetIntegrityCase(course, integrityCase.ID)
+	if err != nil {
+		test.Fatalf("Failed to get case: '%v'.", err)
+	}
+
+	if util.MustToJSONIndent(integrityCase) != util.MustToJSONIndent(actual) {
+		test.Fatalf("Unexpected case. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(integrityCase), util.MustToJSONIndent(actual))
+	}
+
+	missing, err := GetIntegrityCase(course, "zzz")
+	if err != nil {
+		test.Fatalf("Failed to get missing case: '%v'.", err)
+	}
+
+	if missing != nil {
+		test.Fatalf("Found a case that should not exist: '%s'.", util.MustToJSONIndent(missing))
+	}
+
+	// Invalid cases should not be stored.
+	integrityCase.Status = model.IntegrityCaseStatusResolved
+	err = UpsertIntegrityCase(course, integrityCase)
+	if err == nil {
+		test.Fatalf("Did not get an error when storing an invalid case.")
+	}
+}
+
+func (this *DBTests) DBTestUpdateIntegrityCase(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	course := MustGetTestCourse()
+	author := "course-admin@test.edulinq.org"
+
+	integrityCase := model.NewIntegrityCase(course.GetID(), "hw0", "Test Case", author)
+
+	err := UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to insert case: '%v'.", err)
+	}
+
+	// Concurrent changes should all be kept.
+	numNotes := 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numNotes)
+
+	for i := 0; i < numNotes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := UpdateIntegrityCase(course, integrityCase.ID, func(integrityCase *model.IntegrityCase) error {
+				integrityCase.Notes = append(integrityCase.Notes, &model.IntegrityCaseNote{Author: author, Text: "Note."})
+				return nil
+			})
+
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			test.Fatalf("Failed to update case: '%v'.", err)
+		}
+	}
+
+	actual, err := GetIntegrityCase(course, integrityCase.ID)
+	if err != nil {
+		test.Fatalf("Failed to get case: '%v'.", err)
+	}
+
+	if len(actual.Notes) != numNotes {
+		test.Fatalf("Unexpected number of notes. Expected: %d, Actual: %d.", numNotes, len(actual.Notes))
+	}
+
+	// Failed and invalid changes are not stored.
+	testCases := []func(*model.IntegrityCase) error{
+		func(integrityCase *model.IntegrityCase) error {
+			integrityCase.Title = "New Title"
+			return fmt.Errorf("Test error.")
+		},
+		func(integrityCase *model.IntegrityCase) error {
+			integrityCase.Title = ""
+			return nil
+		},
+		func(integrityCase *model.IntegrityCase) error {
+			integrityCase.ID = "zzz"
+			return nil
+		},
+	}
+
+	for i, updateFunc := range testCases {
+		_, err = UpdateIntegrityCase(course, integrityCase.ID, updateFunc)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error.", i)
+			continue
+		}
+
+		stored, err := GetIntegrityCase(course, integrityCase.ID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get case: '%v'.", i, err)
+			continue
+		}
+
+		if util.MustToJSONIndent(actual) != util.MustToJSONIndent(stored) {
+			test.Errorf("Case %d: Case was changed. Expected: '%s', Actual: '%s'.", i, util.MustToJSONIndent(actual), util.MustToJSONIndent(stored))
+		}
+	}
+
+	missing, err := UpdateIntegrityCase(course, "zzz", func(*model.IntegrityCase) error {
+		test.Fatalf("Update function called for a missing case.")
+		return nil
+	})
+	if err != nil {
+		test.Fatalf("Failed to update missing case: '%v'.", err)
+	}
+
+	if missing != nil {
+		test.Fatalf("Got a case that should not exist: '%s'.", util.MustToJSONIndent(missing))
+	}
+}
+
+func (this *DBTests) DBTestIntegrityCaseAttachments(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	course := MustGetTestCourse()
+
+	contents, err := GetIntegrityCaseAttachment(course, "case", "notes.txt")
+	if err != nil {
+		test.Fatalf("Failed to get missing attachment: '%v'.", err)
+	}
+
+	if contents != nil {
+		test.Fatalf("Found contents for a missing attachment: '%s'.", string(contents))
+	}
+
+	expected := []byte("Some notes.")
+	err = StoreIntegrityCaseAttachment(course, "case", "notes.txt", expected)
+	if err != nil {
+		test.Fatalf("Failed to store attachment: '%v'.", err)
+	}
+
+	contents, err = GetIntegrityCaseAttachment(course, "case", "notes.txt")
+	if err != nil {
+		test.Fatalf("Failed to get attachment: '%v'.", err)
+	}
+
+	if string(expected) != string(contents) {
+		test.Fatalf("Unexpected contents. Expected: '%s', Actual: '%s'.", string(expected), string(contents))
+	}
+
+	invalidPaths := [][]string{
+		[]string{"case", ""},
+		[]string{"case", ".."},
+		[]string{"case", "../notes.txt"},
+		[]string{"..", "notes.txt"},
+		[]string{"", "notes.txt"},
+	}
+
+	for i, invalidPath := range invalidPaths {
+		err = StoreIntegrityCaseAttachment(course, invalidPath[0], invalidPath[1], expected)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on an invalid path: '%v'.", i, invalidPath)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get all the late day entries for a course, keyed by email.
+func GetLateDayEntries(course *model.Course) (map[string][]*model.LateDayEntry, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetLateDayEntries(course)
+}
+
+// Get the late day entries for a single user (an empty list if they have none).
+func GetUserLateDayEntries(course *model.Course, email string) ([]*model.LateDayEntry, error) {
+	allEntries, err := GetLateDayEntries(course)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := allEntries[email]
+	if entries == nil {
+		entries = make([]*model.LateDayEntry, 0)
+	}
+
+	return entries, nil
+}
+
+// Validate and add entries to the course's late day ledger.
+func AddLateDayEntries(course *model.Course, entries []*model.LateDayEntry) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := validateLateDayEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	return backend.AddLateDayEntries(course, entries)
+}
+
+func AddLateDayEntry(course *model.Course, entry *model.LateDayEntry) error {
+	return AddLateDayEntries(course, []*model.LateDayEntry{entry})
+}
+
+// Read the course's late day ledger (keyed by email) and add the (validated) entries that updateFunc returns,
+// without any other ledger changes happening in between.
+// Use this when the new entries depend on the current ledger, e.g., a user's balance.
+func UpdateLateDayEntries(course *model.Course, updateFunc func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error)) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpdateLateDayEntries(course, func(allEntries map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+		entries, err := updateFunc(allEntries)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validateLateDayEntries(entries)
+		if err != nil {
+			return nil, err
+		}
+
+		return entries, nil
+	})
+}
+
+func validateLateDayEntries(entries []*model.LateDayEntry) error {
+	var errs error = nil
+	for i, entry := range entries {
+		err := entry.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("Late day entry at index %d is invalid: '%w'.", i, err))
+		}
+	}
+
+	return errs
+}
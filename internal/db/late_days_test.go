@@ -0,0 +1,162 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestLateDayEntries(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	course := MustGetTestCourse()
+	email := "course-student@test.edulinq.org"
+
+	entries, err := GetUserLateDayEntries(course, email)
+	if err != nil {
+		test.Fatalf("Failed to get initial entries: '%v'.", err)
+	}
+
+	if len(entries) != 0 {
+		test.Fatalf("Unexpected initial entries: '%s'.", util.MustToJSONIndent(entries))
+	}
+
+	expected := []*model.LateDayEntry{
+		&model.LateDayEntry{Email: email, Type: model.LateDayEntryCharge, Days: -2, AssignmentID: "hw0", Timestamp: timestamp.FromMSecs(100)},
+		&model.LateDayEntry{Email: email, Type: model.LateDayEntryRefund, Days: 1, AssignmentID: "hw0", Timestamp: timestamp.FromMSecs(200)},
+		&model.LateDayEntry{Email: email, Type: model.LateDayEntryAdjustment, Days: 3, Timestamp: timestamp.FromMSecs(300), Author: "course-admin@test.edulinq.org"},
+	}
+
+	err = AddLateDayEntries(course, expected[0:2])
+	if err != nil {
+		test.Fatalf("Failed to add entries: '%v'.", err)
+	}
+
+	otherEntry := &model.LateDayEntry{Email: "course-other@test.edulinq.org", Type: model.LateDayEntryAdjustment, Days: 1}
+	err = AddLateDayEntries(course, []*model.LateDayEntry{expected[2], otherEntry})
+	if err != nil {
+		test.Fatalf("Failed to add more entries: '%v'.", err)
+	}
+
+	entries, err = GetUserLateDayEntries(course, email)
+	if err != nil {
+		test.Fatalf("Failed to get entries: '%v'.", err)
+	}
+
+	if util.MustToJSONIndent(expected) != util.MustToJSONIndent(entries) {
+		test.Fatalf("Unexpected entries. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(entries))
+	}
+
+	allEntries, err := GetLateDayEntries(course)
+	if err != nil {
+		test.Fatalf("Failed to get all entries: '%v'.", err)
+	}
+
+	if len(allEntries) != 2 {
+		test.Fatalf("Unexpected number of users with entries. Expected: 2, Actual: %d.", len(allEntries))
+	}
+
+	// Invalid entries are not added.
+	err = AddLateDayEntry(course, &model.LateDayEntry{Email: email, Type: model.LateDayEntryCharge, Days: 1, AssignmentID: "hw0"})
+	if err == nil {
+		test.Fatalf("Did not get an error when adding an invalid entry.")
+	}
+
+	entries, err = GetUserLateDayEntries(course, email)
+	if err != nil {
+		test.Fatalf("Failed to get final entries: '%v'.", err)
+	}
+
+	if len(entries) != len(expected) {
+		test.Fatalf("Unexpected number of final entries. Expected: %d, Actual: %d.", len(expected), len(entries))
+	}
+}
+
+// Concurrent updates that check the balance before charging must never overspend.
+func (this *DBTests) DBTestUpdateLateDayEntries(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	course := MustGetTestCourse()
+	email := "course-student@test.edulinq.org"
+
+	err := AddLateDayEntry(course, &model.LateDayEntry{Email: email, Type: model.LateDayEntryAdjustment, Days: 3})
+	if err != nil {
+		test.Fatalf("Failed to add initial entry: '%v'.", err)
+	}
+
+	chargeIfAvailable := func(allEntries map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+		if model.ComputeLateDayBalance(course.GetTotalLateDays(), allEntries[email]) <= 0 {
+			return nil, nil
+		}
+
+		return []*model.LateDayEntry{
+			&model.LateDayEntry{Email: email, Type: model.LateDayEntryCharge, Days: -1, AssignmentID: "hw0"},
+		}, nil
+	}
+
+	numUpdates := 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numUpdates)
+
+	for i := 0; i < numUpdates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- UpdateLateDayEntries(course, chargeIfAvailable)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			test.Fatalf("Failed to update entries: '%v'.", err)
+		}
+	}
+
+	entries, err := GetUserLateDayEntries(course, email)
+	if err != nil {
+		test.Fatalf("Failed to get entries: '%v'.", err)
+	}
+
+	balance := model.ComputeLateDayBalance(course.GetTotalLateDays(), entries)
+	if balance != 0 {
+		test.Fatalf("Unexpected balance. Expected: 0, Actual: %d.", balance)
+	}
+
+	// Nothing is written on an error or an invalid entry.
+	testCases := []func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error){
+		func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+			return []*model.LateDayEntry{&model.LateDayEntry{Email: email, Type: model.LateDayEntryAdjustment, Days: 1}}, fmt.Errorf("Test error.")
+		},
+		func(map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+			return []*model.LateDayEntry{&model.LateDayEntry{Email: email, Type: model.LateDayEntryCharge, Days: 1, AssignmentID: "hw0"}}, nil
+		},
+	}
+
+	for i, updateFunc := range testCases {
+		err = UpdateLateDayEntries(course, updateFunc)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error.", i)
+			continue
+		}
+
+		newEntries, err := GetUserLateDayEntries(course, email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get entries: '%v'.", i, err)
+			continue
+		}
+
+		if len(entries) != len(newEntries) {
+			test.Errorf("Case %d: Unexpected number of entries. Expected: %d, Actual: %d.", i, len(entries), len(newEntries))
+		}
+	}
+}
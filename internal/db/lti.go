@@ -0,0 +1,28 @@
+package db
+
+import (
+	"fmt"
+)
+
+// Get the email of the user bound to an LTI identity.
+// An empty email (and no error) will be returned if the identity is not bound.
+func GetLTIIdentity(issuer string, subject string) (string, error) {
+	if backend == nil {
+		return "", fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetLTIIdentity(issuer, subject)
+}
+
+// Bind an LTI identity to a user (or remove the binding with an empty email).
+func SetLTIIdentity(issuer string, subject string, email string) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	if (issuer == "") || (subject == "") {
+		return fmt.Errorf("Cannot set an LTI identity with an empty issuer ('%s') or subject ('%s').", issuer, subject)
+	}
+
+	return backend.SetLTIIdentity(issuer, subject, email)
+}
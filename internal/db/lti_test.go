@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+)
+
+func (this *DBTests) DBTestLTIIdentityBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	email, err := GetLTIIdentity("https://platform", "subject-1")
+	if err != nil {
+		test.Fatalf("Failed to get empty identity: '%v'.", err)
+	}
+
+	if email != "" {
+		test.Fatalf("Got an unexpected initial binding: '%s'.", email)
+	}
+
+	err = SetLTIIdentity("https://platform", "subject-1", "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to set identity: '%v'.", err)
+	}
+
+	// Subjects are only unique within a platform.
+	email, err = GetLTIIdentity("https://other", "subject-1")
+	if err != nil {
+		test.Fatalf("Failed to get identity from other platform: '%v'.", err)
+	}
+
+	if email != "" {
+		test.Fatalf("Got a binding from the wrong platform: '%s'.", email)
+	}
+
+	email, err = GetLTIIdentity("https://platform", "subject-1")
+	if err != nil {
+		test.Fatalf("Failed to get identity: '%v'.", err)
+	}
+
+	if email != "course-student@test.edulinq.org" {
+		test.Fatalf("Unexpected binding. Expected: '%s', Actual: '%s'.", "course-student@test.edulinq.org", email)
+	}
+
+	err = SetLTIIdentity("https://platform", "subject-1", "")
+	if err != nil {
+		test.Fatalf("Failed to remove identity: '%v'.", err)
+	}
+
+	email, err = GetLTIIdentity("https://platform", "subject-1")
+	if err != nil {
+		test.Fatalf("Failed to get removed identity: '%v'.", err)
+	}
+
+	if email != "" {
+		test.Fatalf("Binding was not removed: '%s'.", email)
+	}
+
+	err = SetLTIIdentity("", "subject-1", "course-student@test.edulinq.org")
+	if err == nil {
+		test.Fatalf("Did not get an error when setting an identity without an issuer.")
+	}
+}
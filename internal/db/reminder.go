@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Get the users (by email) that have opted out of reminder emails for a course.
+func GetReminderOptOuts(course *model.Course) (map[string]bool, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetReminderOptOuts(course)
+}
+
+func IsReminderOptOut(course *model.Course, email string) (bool, error) {
+	optOuts, err := GetReminderOptOuts(course)
+	if err != nil {
+		return false, err
+	}
+
+	return optOuts[email], nil
+}
+
+func SetReminderOptOut(course *model.Course, email string, optOut bool) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	if email == "" {
+		return fmt.Errorf("Cannot set a reminder opt-out for an empty email.")
+	}
+
+	return backend.SetReminderOptOut(course, email, optOut)
+}
+
+// Get the reminders already sent for an assignment, keyed by email.
+// Each value is the due date the user was reminded about.
+func GetSentReminders(assignment *model.Assignment) (map[string]timestamp.Timestamp, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetSentReminders(assignment)
+}
+
+func AddSentReminders(assignment *model.Assignment, reminders map[string]timestamp.Timestamp) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.AddSentReminders(assignment, reminders)
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestReminderOptOuts(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	course := MustGetTestCourse()
+
+	optOuts, err := GetReminderOptOuts(course)
+	if err != nil {
+		test.Fatalf("Failed to get initial opt-outs: '%v'.", err)
+	}
+
+	if len(optOuts) != 0 {
+		test.Fatalf("Unexpected initial opt-outs: '%s'.", util.MustToJSONIndent(optOuts))
+	}
+
+	err = SetReminderOptOut(course, "course-student@test.edulinq.org", true)
+	if err != nil {
+		test.Fatalf("Failed to opt-out student: '%v'.", err)
+	}
+
+	err = SetReminderOptOut(course, "course-other@test.edulinq.org", true)
+	if err != nil {
+		test.Fatalf("Failed to opt-out other: '%v'.", err)
+	}
+
+	err = SetReminderOptOut(course, "course-other@test.edulinq.org", false)
+	if err != nil {
+		test.Fatalf("Failed to opt-in other: '%v'.", err)
+	}
+
+	optOuts, err = GetReminderOptOuts(course)
+	if err != nil {
+		test.Fatalf("Failed to get opt-outs: '%v'.", err)
+	}
+
+	expected := map[string]bool{
+		"course-student@test.edulinq.org": true,
+	}
+
+	if !reflect.DeepEqual(expected, optOuts) {
+		test.Fatalf("Unexpected opt-outs. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(optOuts))
+	}
+
+	optOut, err := IsReminderOptOut(course, "course-other@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to check opt-out: '%v'.", err)
+	}
+
+	if optOut {
+		test.Fatalf("User should not be opted-out.")
+	}
+
+	err = SetReminderOptOut(course, "", true)
+	if err == nil {
+		test.Fatalf("Did not get an error when opting-out an empty email.")
+	}
+}
+
+func (this *DBTests) DBTestSentReminders(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	sent, err := GetSentReminders(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get initial sent reminders: '%v'.", err)
+	}
+
+	if len(sent) != 0 {
+		test.Fatalf("Unexpected initial sent reminders: '%s'.", util.MustToJSONIndent(sent))
+	}
+
+	err = AddSentReminders(assignment, map[string]timestamp.Timestamp{
+		"course-student@test.edulinq.org": timestamp.FromMSecs(100),
+		"course-other@test.edulinq.org":   timestamp.FromMSecs(100),
+	})
+	if err != nil {
+		test.Fatalf("Failed to add sent reminders: '%v'.", err)
+	}
+
+	err = AddSentReminders(assignment, map[string]timestamp.Timestamp{
+		"course-other@test.edulinq.org": timestamp.FromMSecs(200),
+	})
+	if err != nil {
+		test.Fatalf("Failed to update sent reminders: '%v'.", err)
+	}
+
+	sent, err = GetSentReminders(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get sent reminders: '%v'.", err)
+	}
+
+	expected := map[string]timestamp.Timestamp{
+		"course-student@test.edulinq.org": timestamp.FromMSecs(100),
+		"course-other@test.edulinq.org":   timestamp.FromMSecs(200),
+	}
+
+	if !reflect.DeepEqual(expected, sent) {
+		test.Fatalf("Unexpected sent reminders. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(sent))
+	}
+}
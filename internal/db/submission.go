@@ -5,6 +5,7 @@ import (
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 func SaveSubmissions(course *model.Course, submissions []*model.GradingResult) error {
@@ -48,6 +49,26 @@ func GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.
 	return backend.GetSubmissionHistory(assignment, email)
 }
 
+// Get the submission history for each of the given users (keyed by email).
+// Users without any submissions will have an empty history.
+func GetSubmissionHistories(assignment *model.Assignment, emails []string) (map[string][]*model.SubmissionHistoryItem, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	histories := make(map[string][]*model.SubmissionHistoryItem, len(emails))
+	for _, email := range emails {
+		history, err := backend.GetSubmissionHistory(assignment, email)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get submission history for user '%s': '%w'.", email, err)
+		}
+
+		histories[email] = history
+	}
+
+	return histories, nil
+}
+
 func GetSubmissionResult(assignment *model.Assignment, email string, submissionID string) (*model.GradingInfo, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
@@ -74,12 +95,127 @@ func GetExistingScoringInfos(assignment *model.Assignment, filterRole model.Cour
 	return info, nil
 }
 
+// Get the scoring infos for the selected submission (see GetSelectedSubmissions()) of each user.
 func GetScoringInfos(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.ScoringInfo, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetScoringInfos(assignment, filterRole)
+	if assignment.GetSubmissionSelection() == model.SubmissionSelectionMostRecent {
+		return backend.GetScoringInfos(assignment, filterRole)
+	}
+
+	submissionResults, err := GetSelectedSubmissions(assignment, filterRole)
+	if err != nil {
+		return nil, err
+	}
+
+	scoringInfos := make(map[string]*model.ScoringInfo, len(submissionResults))
+	for email, submissionResult := range submissionResults {
+		if submissionResult == nil {
+			scoringInfos[email] = nil
+		} else {
+			scoringInfos[email] = submissionResult.ToScoringInfo()
+		}
+	}
+
+	return scoringInfos, nil
+}
+
+// Get the submission result for each user of the given role,
+// as chosen by the assignment's submission selection policy.
+// Like GetRecentSubmissions(), users without a submission will be represented with a nil map value.
+func GetSelectedSubmissions(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingInfo, error) {
+	return GetSelectedSubmissionsWithDueDate(assignment, filterRole, assignment.DueDate)
+}
+
+// Same as GetSelectedSubmissions(), but the given due date (which may be nil) is used instead of the assignment's own due date
+// when selecting the highest score before the due date.
+// This allows callers to select against the same due date that their late policy uses (e.g., one from the LMS).
+// Extensions and grace periods are still applied on top of the given due date.
+func GetSelectedSubmissionsWithDueDate(assignment *model.Assignment, filterRole model.CourseUserRole, dueDate *timestamp.Timestamp) (map[string]*model.GradingInfo, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	policy := assignment.GetSubmissionSelection()
+	if policy == model.SubmissionSelectionMostRecent {
+		return backend.GetRecentSubmissions(assignment, filterRole)
+	}
+
+	users, err := backend.GetCourseUsers(assignment.GetCourse())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	emails := make([]string, 0, len(users))
+	for email, user := range users {
+		if (filterRole != model.CourseRoleUnknown) && (filterRole != user.Role) {
+			continue
+		}
+
+		emails = append(emails, email)
+	}
+
+	return GetUsersSelectedSubmissions(assignment, emails, dueDate)
+}
+
+// Same as GetSelectedSubmissionsWithDueDate(), but only for the given users (regardless of their role).
+func GetUsersSelectedSubmissions(assignment *model.Assignment, emails []string, dueDate *timestamp.Timestamp) (map[string]*model.GradingInfo, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	policy := assignment.GetSubmissionSelection()
+
+	var err error
+	finalSelections := make(map[string]string)
+	if policy == model.SubmissionSelectionStudentSelected {
+		finalSelections, err = backend.GetFinalSubmissionSelections(assignment)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get final submission selections: '%w'.", err)
+		}
+	}
+
+	results := make(map[string]*model.GradingInfo, len(emails))
+	for _, email := range emails {
+		history, err := backend.GetSubmissionHistory(assignment, email)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get submission history for user '%s': '%w'.", email, err)
+		}
+
+		item := policy.Select(history, assignment.ResolveEffectiveUserDueDate(dueDate, email), finalSelections[email])
+		if item == nil {
+			results[email] = nil
+			continue
+		}
+
+		result, err := backend.GetSubmissionResult(assignment, email, item.ShortID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get submission result '%s': '%w'.", item.ID, err)
+		}
+
+		results[email] = result
+	}
+
+	return results, nil
+}
+
+func GetFinalSubmissionSelections(assignment *model.Assignment) (map[string]string, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetFinalSubmissionSelections(assignment)
+}
+
+func SetFinalSubmissionSelection(assignment *model.Assignment, email string, submissionID string) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	shortSubmissionID := common.GetShortSubmissionID(submissionID)
+	return backend.SetFinalSubmissionSelection(assignment, email, shortSubmissionID)
 }
 
 func GetRecentSubmissions(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.GradingInfo, error) {
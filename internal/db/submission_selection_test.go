@@ -0,0 +1,146 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestGetSelectedSubmissions(test *testing.T) {
+	defer ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+	dueDate := timestamp.FromMSecs(1697406270000)
+	extension := timestamp.FromMSecs(1697406280000)
+
+	testCases := []struct {
+		policy          model.SubmissionSelectionPolicy
+		dueDate         *timestamp.Timestamp
+		extension       *timestamp.Timestamp
+		finalSubmission string
+		expectedShortID string
+	}{
+		{"", nil, nil, "", "1697406272"},
+		{model.SubmissionSelectionMostRecent, nil, nil, "", "1697406272"},
+		{model.SubmissionSelectionHighestScore, nil, nil, "", "1697406272"},
+		{model.SubmissionSelectionHighestScoreBeforeDue, &dueDate, nil, "", "1697406265"},
+
+		// The user's extension is used instead of the due date.
+		{model.SubmissionSelectionHighestScoreBeforeDue, &dueDate, &extension, "", "1697406272"},
+
+		// No due date is the same as highest score.
+		{model.SubmissionSelectionHighestScoreBeforeDue, nil, nil, "", "1697406272"},
+
+		// Nothing before the due date, fall back to most recent.
+		{model.SubmissionSelectionHighestScoreBeforeDue, timestamp.ZeroPointer(), nil, "", "1697406272"},
+
+		{model.SubmissionSelectionStudentSelected, nil, nil, "1697406256", "1697406256"},
+		{model.SubmissionSelectionStudentSelected, nil, nil, "course101::hw0::course-student@test.edulinq.org::1697406265", "1697406265"},
+
+		// No selection, fall back to most recent.
+		{model.SubmissionSelectionStudentSelected, nil, nil, "", "1697406272"},
+
+		// Selection is ignored for other policies.
+		{model.SubmissionSelectionMostRecent, nil, nil, "1697406256", "1697406272"},
+	}
+
+	for i, testCase := range testCases {
+		ResetForTesting()
+
+		assignment := MustGetTestAssignment()
+		assignment.SubmissionSelection = testCase.policy
+		assignment.DueDate = testCase.dueDate
+
+		if testCase.extension != nil {
+			assignment.Extensions = map[string]timestamp.Timestamp{email: *testCase.extension}
+		}
+
+		if testCase.finalSubmission != "" {
+			err := SetFinalSubmissionSelection(assignment, email, testCase.finalSubmission)
+			if err != nil {
+				test.Errorf("Case %d: Failed to set final submission: '%v'.", i, err)
+				continue
+			}
+		}
+
+		results, err := GetSelectedSubmissions(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get selected submissions: '%v'.", i, err)
+			continue
+		}
+
+		if len(results) != 1 {
+			test.Errorf("Case %d: Unexpected number of results. Expected: 1, Actual: %d.", i, len(results))
+			continue
+		}
+
+		result := results[email]
+		if result == nil {
+			test.Errorf("Case %d: Missing result for '%s'.", i, email)
+			continue
+		}
+
+		if testCase.expectedShortID != result.ShortID {
+			test.Errorf("Case %d: Unexpected submission. Expected: '%s', Actual: '%s'.", i, testCase.expectedShortID, result.ShortID)
+			continue
+		}
+
+		scoringInfos, err := GetScoringInfos(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get scoring infos: '%v'.", i, err)
+			continue
+		}
+
+		if scoringInfos[email].ID != result.ID {
+			test.Errorf("Case %d: Scoring info does not match selected submission. Expected: '%s', Actual: '%s'.",
+				i, result.ID, util.MustToJSONIndent(scoringInfos[email]))
+			continue
+		}
+	}
+}
+
+func (this *DBTests) DBTestFinalSubmissionSelections(test *testing.T) {
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	selections, err := GetFinalSubmissionSelections(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get initial selections: '%v'.", err)
+	}
+
+	if len(selections) != 0 {
+		test.Fatalf("Initial selections are not empty: '%s'.", util.MustToJSONIndent(selections))
+	}
+
+	err = SetFinalSubmissionSelection(assignment, "course-student@test.edulinq.org", "1697406256")
+	if err != nil {
+		test.Fatalf("Failed to set selection: '%v'.", err)
+	}
+
+	selections, err = GetFinalSubmissionSelections(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get selections: '%v'.", err)
+	}
+
+	if selections["course-student@test.edulinq.org"] != "1697406256" {
+		test.Fatalf("Unexpected selections: '%s'.", util.MustToJSONIndent(selections))
+	}
+
+	// Clear the selection.
+	err = SetFinalSubmissionSelection(assignment, "course-student@test.edulinq.org", "")
+	if err != nil {
+		test.Fatalf("Failed to clear selection: '%v'.", err)
+	}
+
+	selections, err = GetFinalSubmissionSelections(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get cleared selections: '%v'.", err)
+	}
+
+	if len(selections) != 0 {
+		test.Fatalf("Cleared selections are not empty: '%s'.", util.MustToJSONIndent(selections))
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 
+	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
 )
@@ -23,6 +24,17 @@ func GetActiveTasks() (map[string]*model.FullScheduledTask, error) {
 	return backend.GetActiveTasks()
 }
 
+// Get an active task by its hash.
+// Returns nil (with no error) if the task does not exist.
+func GetActiveTask(hash string) (*model.FullScheduledTask, error) {
+	tasks, err := GetActiveTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks[hash], nil
+}
+
 func GetNextActiveTask() (*model.FullScheduledTask, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
@@ -48,10 +60,81 @@ func MustUpsertActiveTask(task *model.FullScheduledTask) {
 	}
 }
 
+// Upsert the given tasks (keyed by hash), where a nil value removes the task.
+// Removing a task also removes its run records.
 func UpsertActiveTasks(tasks map[string]*model.FullScheduledTask) error {
 	if backend == nil {
 		return fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.UpsertActiveTasks(tasks)
+	return upsertActiveTasks(tasks)
+}
+
+func upsertActiveTasks(tasks map[string]*model.FullScheduledTask) error {
+	err := backend.UpsertActiveTasks(tasks)
+	if err != nil {
+		return err
+	}
+
+	removedHashes := make([]string, 0)
+	for hash, task := range tasks {
+		if task == nil {
+			removedHashes = append(removedHashes, hash)
+		}
+	}
+
+	if len(removedHashes) == 0 {
+		return nil
+	}
+
+	err = backend.RemoveTaskRuns(removedHashes)
+	if err != nil {
+		return fmt.Errorf("Failed to remove runs for removed tasks: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Apply updateFunc to an active task and save the result as one operation
+// (so changes made while the task was read, e.g., pausing a running task, are not lost).
+// Returns the updated task, or nil if the task does not exist (updateFunc will not be called).
+func UpdateActiveTask(hash string, updateFunc func(*model.FullScheduledTask) error) (*model.FullScheduledTask, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpdateActiveTask(hash, func(task *model.FullScheduledTask) error {
+		err := updateFunc(task)
+		if err != nil {
+			return err
+		}
+
+		if task.Hash != hash {
+			return fmt.Errorf("Task hash cannot be changed.")
+		}
+
+		return nil
+	})
+}
+
+// Get a task's run records, oldest first.
+func GetTaskRuns(taskHash string) ([]*model.TaskRun, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetTaskRuns(taskHash)
+}
+
+// Add a task's run record, removing the task's oldest records if there are too many.
+func AddTaskRun(run *model.TaskRun) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	if run.TaskHash == "" {
+		return fmt.Errorf("Task run has an empty task hash.")
+	}
+
+	return backend.AddTaskRun(run, config.TASK_HISTORY_MAX_RUNS.Get())
 }
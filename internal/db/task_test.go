@@ -1,10 +1,13 @@
 package db
 
 import (
+	"fmt"
 	"reflect"
 	"slices"
+	"sync"
 	"testing"
 
+	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
@@ -142,6 +145,247 @@ func (this *DBTests) DBTestGetNextActiveTaskBase(test *testing.T) {
 	}
 }
 
+func (this *DBTests) DBTestGetNextActiveTaskPaused(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	err := UpsertActiveTasks(testTasks)
+	if err != nil {
+		test.Fatalf("Failed to upsert initial tasks: '%v'.", err)
+	}
+
+	task, err := GetActiveTask("C")
+	if err != nil {
+		test.Fatalf("Failed to get task: '%v'.", err)
+	}
+
+	task.Paused = true
+
+	err = UpsertActiveTask(task)
+	if err != nil {
+		test.Fatalf("Failed to pause task: '%v'.", err)
+	}
+
+	task, err = GetNextActiveTask()
+	if err != nil {
+		test.Fatalf("Failed to fetch next task: '%v'.", err)
+	}
+
+	if task.Hash != "A" {
+		test.Fatalf("Did not get expected hash. Expected: '%s', Actual: '%s'.", "A", task.Hash)
+	}
+}
+
+func (this *DBTests) DBTestGetActiveTaskMissing(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	task, err := GetActiveTask("ZZZ")
+	if err != nil {
+		test.Fatalf("Failed to get task: '%v'.", err)
+	}
+
+	if task != nil {
+		test.Fatalf("Got a task that should not exist: '%s'.", util.MustToJSONIndent(task))
+	}
+}
+
+func (this *DBTests) DBTestTaskRunsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	oldMax := config.TASK_HISTORY_MAX_RUNS.Get()
+	config.TASK_HISTORY_MAX_RUNS.Set(2)
+	defer config.TASK_HISTORY_MAX_RUNS.Set(oldMax)
+
+	runs, err := GetTaskRuns("A")
+	if err != nil {
+		test.Fatalf("Failed to fetch empty runs: '%v'.", err)
+	}
+
+	if len(runs) != 0 {
+		test.Fatalf("Initial run fetch is not empty, found %d runs.", len(runs))
+	}
+
+	for i := 0; i < 3; i++ {
+		run := &model.TaskRun{
+			TaskHash:  "A",
+			StartTime: timestamp.FromMSecs(int64(i)),
+			Success:   true,
+		}
+
+		err = AddTaskRun(run)
+		if err != nil {
+			test.Fatalf("Failed to add run %d: '%v'.", i, err)
+		}
+	}
+
+	err = AddTaskRun(&model.TaskRun{TaskHash: "B"})
+	if err != nil {
+		test.Fatalf("Failed to add run for other task: '%v'.", err)
+	}
+
+	runs, err = GetTaskRuns("A")
+	if err != nil {
+		test.Fatalf("Failed to fetch runs: '%v'.", err)
+	}
+
+	expectedTimes := []timestamp.Timestamp{timestamp.FromMSecs(1), timestamp.FromMSecs(2)}
+
+	actualTimes := make([]timestamp.Timestamp, 0, len(runs))
+	for _, run := range runs {
+		actualTimes = append(actualTimes, run.StartTime)
+	}
+
+	if !reflect.DeepEqual(expectedTimes, actualTimes) {
+		test.Fatalf("Unexpected runs. Expected: '%v', Actual: '%v'.", expectedTimes, actualTimes)
+	}
+
+	err = AddTaskRun(&model.TaskRun{})
+	if err == nil {
+		test.Fatalf("Did not get an error when adding a run without a task hash.")
+	}
+}
+
+func (this *DBTests) DBTestTaskRunsRemovedWithTask(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	for _, hash := range []string{"A", "B"} {
+		err := UpsertActiveTask(testTasks[hash])
+		if err != nil {
+			test.Fatalf("Failed to upsert task '%s': '%v'.", hash, err)
+		}
+
+		err = AddTaskRun(&model.TaskRun{TaskHash: hash, Success: true})
+		if err != nil {
+			test.Fatalf("Failed to add run for task '%s': '%v'.", hash, err)
+		}
+	}
+
+	err := UpsertActiveTasks(map[string]*model.FullScheduledTask{"A": nil})
+	if err != nil {
+		test.Fatalf("Failed to remove task: '%v'.", err)
+	}
+
+	runs, err := GetTaskRuns("A")
+	if err != nil {
+		test.Fatalf("Failed to fetch runs for removed task: '%v'.", err)
+	}
+
+	if len(runs) != 0 {
+		test.Fatalf("Found runs for a removed task: '%s'.", util.MustToJSONIndent(runs))
+	}
+
+	runs, err = GetTaskRuns("B")
+	if err != nil {
+		test.Fatalf("Failed to fetch runs for kept task: '%v'.", err)
+	}
+
+	if len(runs) != 1 {
+		test.Fatalf("Unexpected number of runs for kept task. Expected: %d, Actual: %d.", 1, len(runs))
+	}
+}
+
+func (this *DBTests) DBTestUpdateActiveTask(test *testing.T) {
+	defer ResetForTesting()
+	ResetForTesting()
+
+	for _, task := range testTasks {
+		err := UpsertActiveTask(task)
+		if err != nil {
+			test.Fatalf("Failed to upsert task '%s': '%v'.", task.Hash, err)
+		}
+	}
+
+	// Changes to different fields made at the same time should both be kept.
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	updateFuncs := []func(*model.FullScheduledTask) error{
+		func(task *model.FullScheduledTask) error {
+			task.Paused = true
+			return nil
+		},
+		func(task *model.FullScheduledTask) error {
+			task.NextRunTime = timestamp.FromMSecs(200)
+			return nil
+		},
+	}
+
+	for _, updateFunc := range updateFuncs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := UpdateActiveTask("A", updateFunc)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			test.Fatalf("Failed to update task: '%v'.", err)
+		}
+	}
+
+	task, err := GetActiveTask("A")
+	if err != nil {
+		test.Fatalf("Failed to get task: '%v'.", err)
+	}
+
+	if !task.Paused || (task.NextRunTime != timestamp.FromMSecs(200)) {
+		test.Fatalf("Task is missing updates: '%s'.", util.MustToJSONIndent(task))
+	}
+
+	// Failed changes are not stored.
+	testCases := []func(*model.FullScheduledTask) error{
+		func(task *model.FullScheduledTask) error {
+			task.Paused = false
+			return fmt.Errorf("Test error.")
+		},
+		func(task *model.FullScheduledTask) error {
+			task.Paused = false
+			task.Hash = "zzz"
+			return nil
+		},
+	}
+
+	for i, updateFunc := range testCases {
+		_, err = UpdateActiveTask("A", updateFunc)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error.", i)
+			continue
+		}
+
+		task, err := GetActiveTask("A")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get task: '%v'.", i, err)
+			continue
+		}
+
+		if !task.Paused {
+			test.Errorf("Case %d: Failed change was stored.", i)
+		}
+	}
+
+	// Missing tasks are not updated.
+	task, err = UpdateActiveTask("zzz", func(task *model.FullScheduledTask) error {
+		test.Fatalf("Update function called for a missing task.")
+		return nil
+	})
+	if err != nil {
+		test.Fatalf("Failed to update missing task: '%v'.", err)
+	}
+
+	if task != nil {
+		test.Fatalf("Got a missing task: '%s'.", util.MustToJSONIndent(task))
+	}
+}
+
 // Note that the hashes and tasks are not real and only work if we don't validate them.
 var testTasks map[string]*model.FullScheduledTask = map[string]*model.FullScheduledTask{
 	"A": &model.FullScheduledTask{
@@ -89,6 +89,21 @@ func GetCourseUser(course *model.Course, email string) (*model.CourseUser, error
 	return serverUser.ToCourseUser(course.ID, false)
 }
 
+// Remove any entries in |values| (keyed by email) that do not belong to a course user matching the filter.
+// An empty filter will return |values| untouched (without hitting the database).
+func FilterByCourseUsers[T any](course *model.Course, filter *model.CourseUserFilter, values map[string]T) (map[string]T, error) {
+	if filter.IsEmpty() {
+		return values, nil
+	}
+
+	users, err := GetCourseUsers(course)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	return model.FilterByCourseUsers(filter, users, values), nil
+}
+
 // Convenience function for UpsertUsers() with a single user.
 func UpsertUser(user *model.ServerUser) error {
 	users := map[string]*model.ServerUser{user.Email: user}
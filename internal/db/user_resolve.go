@@ -9,12 +9,19 @@ import (
 	"github.com/edulinq/autograder/internal/model"
 )
 
+const (
+	USER_SPEC_PREFIX_SECTION = "section:"
+	USER_SPEC_PREFIX_GROUP   = "group:"
+)
+
 // Resolve course email addresses.
 // Take a course and a list of strings (containing emails specs) and returns a sorted slice of lowercase emails without duplicates.
 // An email spec can be:
 // an email address,
 // a course role (which will include all course users with that role),
 // a literal "*" (which includes all users enrolled in the course),
+// a section prefixed with "section:" (which will include all course users in that LMS section),
+// a group prefixed with "group:" (which will include all course users in that LMS group, see model.CourseUser.InGroup()),
 // or an email address preceded by a dash ("-") (which indicates that this email address should NOT be included in the final results).
 func ResolveCourseUsers(course *model.Course, emails []string) ([]string, error) {
 	if backend == nil {
@@ -24,6 +31,7 @@ func ResolveCourseUsers(course *model.Course, emails []string) ([]string, error)
 	emailSet := map[string]any{}
 	removeEmailSet := map[string]any{}
 	roleSet := map[string]any{}
+	filters := make([]*model.CourseUserFilter, 0)
 
 	// Iterate over all strings, checking for emails, roles, and * (which denotes all users).
 	for _, email := range emails {
@@ -32,7 +40,11 @@ func ResolveCourseUsers(course *model.Course, emails []string) ([]string, error)
 			continue
 		}
 
-		if strings.HasPrefix(email, "-") {
+		if strings.HasPrefix(email, USER_SPEC_PREFIX_SECTION) {
+			filters = append(filters, &model.CourseUserFilter{FilterSection: strings.TrimPrefix(email, USER_SPEC_PREFIX_SECTION)})
+		} else if strings.HasPrefix(email, USER_SPEC_PREFIX_GROUP) {
+			filters = append(filters, &model.CourseUserFilter{FilterGroup: strings.TrimPrefix(email, USER_SPEC_PREFIX_GROUP)})
+		} else if strings.HasPrefix(email, "-") {
 			email = strings.TrimSpace(strings.TrimPrefix(email, "-"))
 			removeEmailSet[email] = nil
 		} else if strings.Contains(email, "@") {
@@ -54,8 +66,8 @@ func ResolveCourseUsers(course *model.Course, emails []string) ([]string, error)
 		}
 	}
 
-	// Add users from roles.
-	if len(roleSet) > 0 {
+	// Add users from roles, sections, and groups.
+	if (len(roleSet) > 0) || (len(filters) > 0) {
 		users, err := GetCourseUsers(course)
 		if err != nil {
 			return nil, err
@@ -66,6 +78,14 @@ func ResolveCourseUsers(course *model.Course, emails []string) ([]string, error)
 			_, ok := roleSet[user.Role.String()]
 			if ok {
 				emailSet[strings.ToLower(user.Email)] = nil
+				continue
+			}
+
+			for _, filter := range filters {
+				if !filter.IsEmpty() && filter.Matches(user) {
+					emailSet[strings.ToLower(user.Email)] = nil
+					break
+				}
 			}
 		}
 	}
@@ -145,6 +145,36 @@ func (this *DBTests) DBTestResolveCourseUsers(test *testing.T) {
 			0,
 		},
 
+		// Sections and groups.
+		{
+			[]string{"section:Lecture 01"},
+			[]string{"a_student@test.edulinq.org", "b_student@test.edulinq.org"},
+			sectionTestUsers,
+			[]string{},
+			0,
+		},
+		{
+			[]string{"SECTION: lab 02", "group:Projects/Team 1", "-a_student@test.edulinq.org"},
+			[]string{"b_student@test.edulinq.org", "course-grader@test.edulinq.org"},
+			sectionTestUsers,
+			[]string{},
+			0,
+		},
+		{
+			[]string{"group:team 1", "course-admin@test.edulinq.org"},
+			[]string{"a_student@test.edulinq.org", "course-admin@test.edulinq.org"},
+			sectionTestUsers,
+			[]string{},
+			0,
+		},
+		{
+			[]string{"group:Other/Team 1", "section:ZZZ"},
+			[]string{},
+			sectionTestUsers,
+			[]string{},
+			0,
+		},
+
 		// This is a test case to see if we properly trim whitespace.
 		{
 			[]string{"\t\n student    ", "\n \t testing@test.edulinq.org", "\t\n     \t    \n"},
@@ -246,3 +276,38 @@ func (this *DBTests) DBTestResolveCourseUsers(test *testing.T) {
 		}
 	}
 }
+
+var sectionTestUsers map[string]*model.ServerUser = map[string]*model.ServerUser{
+	"a_student@test.edulinq.org": &model.ServerUser{
+		Email: "a_student@test.edulinq.org",
+		Role:  model.ServerRoleUser,
+		CourseInfo: map[string]*model.UserCourseInfo{
+			TEST_COURSE_ID: &model.UserCourseInfo{
+				Role:     model.CourseRoleStudent,
+				Sections: []string{"Lecture 01", "Lab 01"},
+				Groups:   map[string]string{"Projects": "Team 1"},
+			},
+		},
+	},
+	"b_student@test.edulinq.org": &model.ServerUser{
+		Email: "b_student@test.edulinq.org",
+		Role:  model.ServerRoleUser,
+		CourseInfo: map[string]*model.UserCourseInfo{
+			TEST_COURSE_ID: &model.UserCourseInfo{
+				Role:     model.CourseRoleStudent,
+				Sections: []string{"Lecture 01", "Lab 02"},
+				Groups:   map[string]string{"Projects": "Team 2"},
+			},
+		},
+	},
+	"course-grader@test.edulinq.org": &model.ServerUser{
+		Email: "course-grader@test.edulinq.org",
+		Role:  model.ServerRoleUser,
+		CourseInfo: map[string]*model.UserCourseInfo{
+			TEST_COURSE_ID: &model.UserCourseInfo{
+				Role:     model.CourseRoleGrader,
+				Sections: []string{"Lab 02"},
+			},
+		},
+	},
+}
@@ -76,7 +76,7 @@ func checkForRejection(assignment *model.Assignment, submissionPath string, emai
 		return nil, nil
 	}
 
-	reason := checkLateSubmission(assignment, allowLate)
+	reason := checkLateSubmission(assignment, email, allowLate)
 	if reason != nil {
 		return reason, nil
 	}
@@ -84,15 +84,16 @@ func checkForRejection(assignment *model.Assignment, submissionPath string, emai
 	return checkSubmissionLimit(assignment, email)
 }
 
-func checkLateSubmission(assignment *model.Assignment, allowLate bool) RejectReason {
-	if assignment.DueDate == nil {
+func checkLateSubmission(assignment *model.Assignment, email string, allowLate bool) RejectReason {
+	dueDate := assignment.GetEffectiveUserDueDate(email)
+	if dueDate == nil {
 		return nil
 	}
 
 	now := timestamp.Now()
 
-	if (now > *assignment.DueDate) && !allowLate {
-		return &RejectLate{assignment.Name, *assignment.DueDate}
+	if (now > *dueDate) && !allowLate {
+		return &RejectLate{assignment.Name, *assignment.GetUserDueDate(email)}
 	}
 
 	return nil
@@ -136,6 +136,40 @@ func TestRejectLateSubmissionWithAllow(test *testing.T) {
 	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, nil)
 }
 
+// Check the late rejection directly, since an accepted submission would need to be graded.
+func TestRejectLateSubmissionWithExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+
+	// Set the due date to be the Unix epoch, but give some users extensions.
+	dueDate := timestamp.Zero()
+	assignment.DueDate = &dueDate
+	assignment.Extensions = map[string]timestamp.Timestamp{
+		"course-other@test.edulinq.org":   timestamp.Now() + timestamp.FromMSecs(60*60*1000),
+		"course-student@test.edulinq.org": timestamp.FromMSecs(1000),
+	}
+
+	testCases := []struct {
+		email     string
+		allowLate bool
+		expected  RejectReason
+	}{
+		{"course-other@test.edulinq.org", false, nil},
+		{"course-student@test.edulinq.org", false, &RejectLate{assignment.Name, timestamp.FromMSecs(1000)}},
+		{"course-student@test.edulinq.org", true, nil},
+		{"course-grader@test.edulinq.org", false, &RejectLate{assignment.Name, dueDate}},
+	}
+
+	for i, testCase := range testCases {
+		reason := checkLateSubmission(assignment, testCase.email, testCase.allowLate)
+		if !reflect.DeepEqual(testCase.expected, reason) {
+			test.Errorf("Case %d: Unexpected rejection. Expected: '%v', Actual: '%v'.", i, testCase.expected, reason)
+		}
+	}
+}
+
 func testMaxWindowAttempts(test *testing.T, user string, expectReject bool) {
 	db.ResetForTesting()
 	defer db.ResetForTesting()
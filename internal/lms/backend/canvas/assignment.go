@@ -2,7 +2,9 @@ package canvas
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -16,9 +18,7 @@ func (this *CanvasBackend) FetchAssignment(assignmentID string) (*lmstypes.Assig
 		this.CourseID, assignmentID)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-	body, _, err := util.GetWithHeaders(url, headers)
-
+	body, _, err := this.client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err)
 	}
@@ -45,41 +45,78 @@ func (this *CanvasBackend) fetchAssignments(rewriteLinks bool) ([]*lmstypes.Assi
 		this.CourseID, PAGE_SIZE)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
+	rawAssignments, err := lmshttp.FetchJSONPages[Assignment](this.client, url, this.getLinkRewriter(rewriteLinks))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err)
+	}
 
-	assignments := make([]*lmstypes.Assignment, 0)
+	assignments := make([]*lmstypes.Assignment, 0, len(rawAssignments))
+	for _, assignment := range rawAssignments {
+		assignments = append(assignments, assignment.ToLMSType())
+	}
 
-	for url != "" {
-		var err error
+	return assignments, nil
+}
+
+func (this *CanvasBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
 
-		if rewriteLinks {
-			url, err = this.rewriteLink(url)
-			if err != nil {
-				return nil, err
-			}
-		}
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/courses/%s/assignments",
+		this.CourseID)
+	url := this.BaseURL + apiEndpoint
 
-		body, responseHeaders, err := util.GetWithHeaders(url, headers)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
-		}
+	body, _, err := this.client.Post(url, assignmentForm(assignment))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create assignment: '%w'.", err)
+	}
 
-		var pageAssignments []*Assignment
-		err = util.JSONFromString(body, &pageAssignments)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal assignments page: '%w'.", err)
-		}
+	var createdAssignment Assignment
+	err = util.JSONFromString(body, &createdAssignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal created assignment: '%w'.", err)
+	}
 
-		for _, assignment := range pageAssignments {
-			if assignment == nil {
-				continue
-			}
+	return createdAssignment.ToLMSType(), nil
+}
 
-			assignments = append(assignments, assignment.ToLMSType())
-		}
+func (this *CanvasBackend) UpdateAssignment(assignment *lmstypes.Assignment) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
 
-		url = fetchNextCanvasLink(responseHeaders)
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/courses/%s/assignments/%s",
+		this.CourseID, assignment.ID)
+	url := this.BaseURL + apiEndpoint
+
+	_, _, err := this.client.Put(url, assignmentForm(assignment))
+	if err != nil {
+		return fmt.Errorf("Failed to update assignment '%s': '%w'.", assignment.ID, err)
 	}
 
-	return assignments, nil
+	return nil
+}
+
+// Only non-empty fields are included (so they will not be changed when updating).
+func assignmentForm(assignment *lmstypes.Assignment) map[string]string {
+	form := make(map[string]string)
+
+	if assignment.Name != "" {
+		form["assignment[name]"] = assignment.Name
+	}
+
+	if assignment.DueDate != nil {
+		form["assignment[due_at]"] = assignment.DueDate.ToGoTime().UTC().Format(time.RFC3339)
+	}
+
+	if !util.IsZero(assignment.MaxPoints) {
+		form["assignment[points_possible]"] = util.FloatToStr(assignment.MaxPoints)
+	}
+
+	if assignment.Description != "" {
+		form["assignment[description]"] = assignment.Description
+	}
+
+	return form
 }
@@ -16,6 +16,7 @@ var expectedAssignment lmstypes.Assignment = lmstypes.Assignment{
 	LMSCourseID: "12345",
 	DueDate:     &dueDate,
 	MaxPoints:   100.0,
+	Description: "desc",
 }
 
 func TestFetchAssignmentBase(test *testing.T) {
@@ -43,3 +44,77 @@ func TestFetchAssignmentsBase(test *testing.T) {
 			util.MustToJSONIndent(expected), util.MustToJSONIndent(assignments))
 	}
 }
+
+func TestCreateAssignmentBase(test *testing.T) {
+	assignmentDueDate := timestamp.MustGuessFromString("2023-10-13T06:59:59Z")
+	assignment := &lmstypes.Assignment{
+		Name:        "Assignment 1",
+		DueDate:     &assignmentDueDate,
+		MaxPoints:   50.0,
+		Description: "Pushed description.",
+	}
+
+	createdAssignment, err := testBackend.CreateAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to create assignment: '%v'.", err)
+	}
+
+	expected := *assignment
+	expected.ID = "98766"
+	expected.LMSCourseID = "12345"
+
+	if !reflect.DeepEqual(&expected, createdAssignment) {
+		test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(createdAssignment))
+	}
+}
+
+func TestUpdateAssignmentBase(test *testing.T) {
+	assignment := &lmstypes.Assignment{
+		ID:   TEST_ASSIGNMENT_ID,
+		Name: "Assignment 0 (Updated)",
+	}
+
+	err := testBackend.UpdateAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to update assignment: '%v'.", err)
+	}
+}
+
+func TestAssignmentForm(test *testing.T) {
+	assignmentDueDate := timestamp.MustGuessFromString("2023-10-13T06:59:59.123Z")
+
+	testCases := []struct {
+		assignment *lmstypes.Assignment
+		expected   map[string]string
+	}{
+		{
+			&lmstypes.Assignment{},
+			map[string]string{},
+		},
+		{
+			&lmstypes.Assignment{Name: "A", MaxPoints: 10},
+			map[string]string{
+				"assignment[name]":            "A",
+				"assignment[points_possible]": "10",
+			},
+		},
+		{
+			&lmstypes.Assignment{Name: "A", DueDate: &assignmentDueDate, MaxPoints: 12.5, Description: "D"},
+			map[string]string{
+				"assignment[name]":            "A",
+				"assignment[due_at]":          "2023-10-13T06:59:59Z",
+				"assignment[points_possible]": "12.5",
+				"assignment[description]":     "D",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		form := assignmentForm(testCase.assignment)
+		if !reflect.DeepEqual(testCase.expected, form) {
+			test.Errorf("Case %d: Form not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(form))
+		}
+	}
+}
@@ -3,12 +3,16 @@ package canvas
 import (
 	"fmt"
 	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
 )
 
 type CanvasBackend struct {
 	CourseID string
 	APIToken string
 	BaseURL  string
+
+	client *lmshttp.Client
 }
 
 func NewBackend(canvasCourseID string, apiToken string, baseURL string) (*CanvasBackend, error) {
@@ -32,5 +36,10 @@ func NewBackend(canvasCourseID string, apiToken string, baseURL string) (*Canvas
 		BaseURL:  baseURL,
 	}
 
+	backend.client = lmshttp.NewClient(backend.standardHeaders())
+	backend.client.RateLimitHeader = HEADER_RATE_LIMIT_REMAINING
+	backend.client.RateLimitThreshold = RATE_LIMIT_THRESHOLD
+	backend.client.RateLimitMaxDelay = RATE_LIMIT_MAX_DELAY
+
 	return &backend, nil
 }
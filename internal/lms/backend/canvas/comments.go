@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
-	"github.com/edulinq/autograder/internal/util"
 )
 
 func (this *CanvasBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
@@ -35,9 +34,7 @@ func (this *CanvasBackend) UpdateComment(assignmentID string, comment *lmstypes.
 	form := make(map[string]string, 1)
 	form["comment"] = comment.Text
 
-	headers := this.standardHeaders()
-	_, _, err := util.PutWithHeaders(url, form, headers)
-
+	_, _, err := this.client.Put(url, form)
 	if err != nil {
 		return fmt.Errorf("Failed to update comments: '%w'.", err)
 	}
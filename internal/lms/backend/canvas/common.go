@@ -3,17 +3,21 @@ package canvas
 import (
 	"fmt"
 	neturl "net/url"
-	"strings"
 	"time"
 
 	"github.com/edulinq/autograder/internal/lockmanager"
 )
 
 const (
-	PAGE_SIZE             int    = 75
-	POST_PAGE_SIZE        int    = 75
-	HEADER_LINK           string = "Link"
-	UPLOAD_SLEEP_TIME_SEC        = int64(0.5 * float64(time.Second))
+	PAGE_SIZE             int = 75
+	POST_PAGE_SIZE        int = 75
+	UPLOAD_SLEEP_TIME_SEC     = int64(0.5 * float64(time.Second))
+
+	// Canvas rate limits with a "leaky bucket" per token (usually 700 units), the header holds the units left in the bucket.
+	// Requests are slowed down as the bucket gets close to empty.
+	HEADER_RATE_LIMIT_REMAINING string  = "X-Rate-Limit-Remaining"
+	RATE_LIMIT_THRESHOLD        float64 = 200
+	RATE_LIMIT_MAX_DELAY                = 5 * time.Second
 )
 
 func (this *CanvasBackend) getAPILock() {
@@ -38,29 +42,13 @@ func (this *CanvasBackend) standardHeaders() map[string][]string {
 	}
 }
 
-// See if the response headers have a next link.
-// Returns the link or an empty string.
-func fetchNextCanvasLink(headers map[string][]string) string {
-	values, ok := headers[HEADER_LINK]
-	if !ok {
-		return ""
+// Get the function to rewrite page links with (if any).
+func (this *CanvasBackend) getLinkRewriter(rewriteLinks bool) func(string) (string, error) {
+	if !rewriteLinks {
+		return nil
 	}
 
-	for _, value := range values {
-		links := strings.Split(value, ",")
-		for _, link := range links {
-			parts := strings.Split(link, ";")
-			if len(parts) < 2 {
-				continue
-			}
-
-			if strings.TrimSpace(parts[1]) == `rel="next"` {
-				return strings.Trim(strings.TrimSpace(parts[0]), "<>")
-			}
-		}
-	}
-
-	return ""
+	return this.rewriteLink
 }
 
 // Rewrite a URL that appears in a LINK header for testing.
@@ -0,0 +1,144 @@
+package canvas
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const FEEDBACK_COMMENT_TEXT = "Autograder feedback is attached."
+
+// Fetch an assignment's rubric criteria (keyed by lower case description).
+// The rubric is only fetched if any of the scores have rubric points (otherwise nil is returned).
+func (this *CanvasBackend) fetchRubricCriteria(assignmentID string, scores []*lmstypes.SubmissionScore) (map[string]*RubricCriterion, error) {
+	hasRubric := false
+	for _, score := range scores {
+		if len(score.Rubric) > 0 {
+			hasRubric = true
+			break
+		}
+	}
+
+	if !hasRubric {
+		return nil, nil
+	}
+
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/courses/%s/assignments/%s",
+		this.CourseID, assignmentID)
+	url := this.BaseURL + apiEndpoint
+
+	body, _, err := this.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch assignment: '%w'.", err)
+	}
+
+	var assignment Assignment
+	err = util.JSONFromString(body, &assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal assignment: '%w'.", err)
+	}
+
+	criteria := make(map[string]*RubricCriterion, len(assignment.Rubric))
+	for _, criterion := range assignment.Rubric {
+		criteria[strings.ToLower(strings.TrimSpace(criterion.Description))] = criterion
+	}
+
+	return criteria, nil
+}
+
+// Upload the attachments for each score.
+// Each score is uploaded in its own batch, so a failed upload can be resumed.
+func (this *CanvasBackend) uploadAttachments(assignmentID string, scores []*lmstypes.SubmissionScore, rewriteLinks bool) error {
+	attachmentScores := make([]*lmstypes.SubmissionScore, 0)
+	for _, score := range scores {
+		if len(score.Attachments) > 0 {
+			attachmentScores = append(attachmentScores, score)
+		}
+	}
+
+	uploadKey := fmt.Sprintf("canvas::%s::%s::%s::attachments", this.BaseURL, this.CourseID, assignmentID)
+
+	return lmshttp.UploadBatches(uploadKey, attachmentScores, 1, time.Duration(UPLOAD_SLEEP_TIME_SEC),
+		func(batch []*lmstypes.SubmissionScore) error {
+			for _, attachment := range batch[0].Attachments {
+				err := this.uploadAttachment(assignmentID, batch[0].UserID, attachment, rewriteLinks)
+				if err != nil {
+					return fmt.Errorf("Failed to upload attachment '%s' for user '%s': '%w'.", attachment.Filename, batch[0].UserID, err)
+				}
+			}
+
+			return nil
+		})
+}
+
+// Canvas attaches files to submission comments in three steps:
+// tell Canvas about the file, upload the file to the location Canvas gives back, and then post a comment with the uploaded file.
+func (this *CanvasBackend) uploadAttachment(assignmentID string, userID string, attachment *lmstypes.SubmissionAttachment, rewriteLinks bool) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	submissionURL := this.BaseURL + fmt.Sprintf(
+		"/api/v1/courses/%s/assignments/%s/submissions/%s",
+		this.CourseID, assignmentID, userID)
+
+	form := map[string]string{
+		"name":         attachment.Filename,
+		"size":         fmt.Sprintf("%d", len(attachment.Content)),
+		"content_type": attachment.ContentType,
+	}
+
+	body, _, err := this.client.Post(submissionURL+"/comments/files", form)
+	if err != nil {
+		return fmt.Errorf("Failed to start file upload: '%w'.", err)
+	}
+
+	var target FileUploadTarget
+	err = util.JSONFromString(body, &target)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal file upload target: '%w'.", err)
+	}
+
+	uploadURL := target.UploadURL
+	if rewriteLinks {
+		uploadURL, err = this.rewriteLink(uploadURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The upload URL may not be a Canvas URL, so it should not get any auth headers.
+	body, _, err = lmshttp.NewClient(nil).PostFile(uploadURL, target.UploadParams, "file", attachment.Filename, attachment.Content)
+	if err != nil {
+		return fmt.Errorf("Failed to upload file: '%w'.", err)
+	}
+
+	var file File
+	err = util.JSONFromString(body, &file)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal uploaded file: '%w'.", err)
+	}
+
+	if file.ID == "" {
+		return fmt.Errorf("Uploaded file has no ID.")
+	}
+
+	form = map[string]string{
+		"comment[text_comment]": FEEDBACK_COMMENT_TEXT,
+		"comment[file_ids][]":   file.ID.String(),
+	}
+
+	_, _, err = this.client.Put(submissionURL, form)
+	if err != nil {
+		return fmt.Errorf("Failed to post comment with uploaded file: '%w'.", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package canvas
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
@@ -39,6 +40,27 @@ type Assignment struct {
 	CanvasCourseID string     `json:"course_id"`
 	DueDate        *time.Time `json:"due_at"`
 	MaxPoints      float64    `json:"points_possible"`
+	Description    string     `json:"description"`
+
+	// Only set when the assignment has a rubric.
+	Rubric []*RubricCriterion `json:"rubric"`
+}
+
+type RubricCriterion struct {
+	ID          string  `json:"id"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+// The response to the first step of a file upload,
+// the file should then be posted (along with the params) to the upload URL.
+type FileUploadTarget struct {
+	UploadURL    string            `json:"upload_url"`
+	UploadParams map[string]string `json:"upload_params"`
+}
+
+type File struct {
+	ID json.Number `json:"id"`
 }
 
 type Enrollment struct {
@@ -46,6 +68,28 @@ type Enrollment struct {
 	Type            string `json:"type"`
 	EnrollmentState string `json:"enrollment_state"`
 	Role            string `json:"role"`
+	CourseSectionID string `json:"course_section_id"`
+}
+
+type Section struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type GroupCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Group struct {
+	ID              string      `json:"id"`
+	Name            string      `json:"name"`
+	GroupCategoryID string      `json:"group_category_id"`
+	Users           []GroupUser `json:"users"`
+}
+
+type GroupUser struct {
+	ID string `json:"id"`
 }
 
 // Canvas enrollment to autograder role.
@@ -137,5 +181,6 @@ func (this *Assignment) ToLMSType() *lmstypes.Assignment {
 		LMSCourseID: this.CanvasCourseID,
 		DueDate:     timestamp.FromGoTimePointer(this.DueDate),
 		MaxPoints:   this.MaxPoints,
+		Description: this.Description,
 	}
 }
@@ -2,8 +2,10 @@ package canvas
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -17,10 +19,9 @@ func (this *CanvasBackend) FetchAssignmentScore(assignmentID string, userID stri
 		this.CourseID, assignmentID, userID)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-	body, _, err := util.GetWithHeaders(url, headers)
+	body, _, err := this.client.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to fetch score.")
+		return nil, fmt.Errorf("Failed to fetch score: '%w'.", err)
 	}
 
 	var rawScore SubmissionScore
@@ -45,65 +46,47 @@ func (this *CanvasBackend) fetchAssignmentScores(assignmentID string, rewriteLin
 		this.CourseID, assignmentID, PAGE_SIZE)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-
-	scores := make([]*lmstypes.SubmissionScore, 0)
-
-	for url != "" {
-		var err error
-
-		if rewriteLinks {
-			url, err = this.rewriteLink(url)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		body, responseHeaders, err := util.GetWithHeaders(url, headers)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to fetch scores.")
-		}
-
-		var pageScores []*SubmissionScore
-		err = util.JSONFromString(body, &pageScores)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal scores page: '%w'.", err)
-		}
-
-		for _, score := range pageScores {
-			if score == nil {
-				continue
-			}
-
-			scores = append(scores, score.ToLMSType())
-		}
+	rawScores, err := lmshttp.FetchJSONPages[SubmissionScore](this.client, url, this.getLinkRewriter(rewriteLinks))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err)
+	}
 
-		url = fetchNextCanvasLink(responseHeaders)
+	scores := make([]*lmstypes.SubmissionScore, 0, len(rawScores))
+	for _, score := range rawScores {
+		scores = append(scores, score.ToLMSType())
 	}
 
 	return scores, nil
 }
 
+// Scores are uploaded in pages.
+// If an upload fails part way through, then retrying the same upload will skip the pages that were already uploaded.
+// Rubric points are matched to the assignment's rubric criteria by name (case-insensitive),
+// and attachments are uploaded after all the scores (as additional submission comments).
 func (this *CanvasBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
-	for page := 0; (page * POST_PAGE_SIZE) < len(scores); page++ {
-		startIndex := page * POST_PAGE_SIZE
-		endIndex := min(len(scores), ((page + 1) * POST_PAGE_SIZE))
+	return this.uploadAssignmentScores(assignmentID, scores, false)
+}
 
-		if page != 0 {
-			time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC))
-		}
+func (this *CanvasBackend) uploadAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore, rewriteLinks bool) error {
+	criteria, err := this.fetchRubricCriteria(assignmentID, scores)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch rubric: '%w'.", err)
+	}
 
-		err := this.updateAssignmentScores(assignmentID, scores[startIndex:endIndex])
-		if err != nil {
-			return fmt.Errorf("Failed on page %d: '%w'.", page, err)
-		}
+	uploadKey := fmt.Sprintf("canvas::%s::%s::%s::scores", this.BaseURL, this.CourseID, assignmentID)
+
+	err = lmshttp.UploadBatches(uploadKey, scores, POST_PAGE_SIZE, time.Duration(UPLOAD_SLEEP_TIME_SEC),
+		func(batch []*lmstypes.SubmissionScore) error {
+			return this.updateAssignmentScores(assignmentID, batch, criteria)
+		})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return this.uploadAttachments(assignmentID, scores, rewriteLinks)
 }
 
-func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore, criteria map[string]*RubricCriterion) error {
 	this.getAPILock()
 	defer this.releaseAPILock()
 
@@ -116,26 +99,44 @@ func (this *CanvasBackend) updateAssignmentScores(assignmentID string, scores []
 		this.CourseID, assignmentID)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
+	form, err := scoresForm(assignmentID, scores, criteria)
+	if err != nil {
+		return err
+	}
 
+	_, _, err = this.client.Post(url, form)
+	if err != nil {
+		return fmt.Errorf("Failed to upload scores: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Get the form to upload scores with.
+// |criteria| is keyed by the lower case description of the criterion.
+func scoresForm(assignmentID string, scores []*lmstypes.SubmissionScore, criteria map[string]*RubricCriterion) (map[string]string, error) {
 	form := make(map[string]string)
 
 	for _, score := range scores {
 		form[fmt.Sprintf("grade_data[%s][posted_grade]", score.UserID)] = util.FloatToStr(score.Score)
 
 		if len(score.Comments) > 1 {
-			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+			return nil, fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
 		}
 
 		for _, comment := range score.Comments {
 			form[fmt.Sprintf("grade_data[%s][text_comment]", score.UserID)] = comment.Text
 		}
-	}
 
-	_, _, err := util.PostWithHeaders(url, form, headers)
-	if err != nil {
-		return fmt.Errorf("Failed to upload scores: '%w'.", err)
+		for name, points := range score.Rubric {
+			criterion := criteria[strings.ToLower(strings.TrimSpace(name))]
+			if criterion == nil {
+				continue
+			}
+
+			form[fmt.Sprintf("grade_data[%s][rubric_assessment][%s][points]", score.UserID, criterion.ID)] = util.FloatToStr(points)
+		}
 	}
 
-	return nil
+	return form, nil
 }
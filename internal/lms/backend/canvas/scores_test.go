@@ -51,3 +51,103 @@ func TestFetchAssignmentScoresBase(test *testing.T) {
 			util.MustToJSONIndent(expected), util.MustToJSONIndent(scores))
 	}
 }
+
+func TestUploadAssignmentScoresFeedback(test *testing.T) {
+	scores := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "00040",
+			Score:  100.0,
+			Rubric: map[string]float64{"Q1": 50, "q2": 50},
+			Attachments: []*lmstypes.SubmissionAttachment{
+				&lmstypes.SubmissionAttachment{
+					Filename:    "hw0-feedback.md",
+					ContentType: "text/markdown",
+					Content:     []byte("# Feedback"),
+				},
+			},
+		},
+	}
+
+	err := testBackend.uploadAssignmentScores(TEST_ASSIGNMENT_ID, scores, true)
+	if err != nil {
+		test.Fatalf("Failed to upload scores: '%v'.", err)
+	}
+}
+
+func TestScoresForm(test *testing.T) {
+	criteria := map[string]*RubricCriterion{
+		"q1": &RubricCriterion{ID: "_1001", Description: "Q1", Points: 50},
+		"q2": &RubricCriterion{ID: "_1002", Description: "Q2", Points: 50},
+	}
+
+	testCases := []struct {
+		scores   []*lmstypes.SubmissionScore
+		criteria map[string]*RubricCriterion
+		expected map[string]string
+	}{
+		{
+			[]*lmstypes.SubmissionScore{},
+			nil,
+			map[string]string{},
+		},
+		{
+			[]*lmstypes.SubmissionScore{
+				&lmstypes.SubmissionScore{UserID: "001", Score: 10, Comments: []*lmstypes.SubmissionComment{&lmstypes.SubmissionComment{Text: "C"}}},
+			},
+			nil,
+			map[string]string{
+				"grade_data[001][posted_grade]": "10",
+				"grade_data[001][text_comment]": "C",
+			},
+		},
+
+		// Rubric without criteria.
+		{
+			[]*lmstypes.SubmissionScore{
+				&lmstypes.SubmissionScore{UserID: "001", Score: 10, Rubric: map[string]float64{"Q1": 5}},
+			},
+			nil,
+			map[string]string{
+				"grade_data[001][posted_grade]": "10",
+			},
+		},
+
+		// Rubric with matching (case-insensitive) and unknown criteria.
+		{
+			[]*lmstypes.SubmissionScore{
+				&lmstypes.SubmissionScore{UserID: "001", Score: 10, Rubric: map[string]float64{" q1 ": 5, "Q2": 2.5, "Q3": 1}},
+				&lmstypes.SubmissionScore{UserID: "002", Score: 20, Rubric: map[string]float64{"Q1": 20}},
+			},
+			criteria,
+			map[string]string{
+				"grade_data[001][posted_grade]":                     "10",
+				"grade_data[001][rubric_assessment][_1001][points]": "5",
+				"grade_data[001][rubric_assessment][_1002][points]": "2.5",
+				"grade_data[002][posted_grade]":                     "20",
+				"grade_data[002][rubric_assessment][_1001][points]": "20",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		form, err := scoresForm(TEST_ASSIGNMENT_ID, testCase.scores, testCase.criteria)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get form: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, form) {
+			test.Errorf("Case %d: Form not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(form))
+		}
+	}
+
+	tooManyComments := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{UserID: "001", Comments: []*lmstypes.SubmissionComment{&lmstypes.SubmissionComment{}, &lmstypes.SubmissionComment{}}},
+	}
+
+	_, err := scoresForm(TEST_ASSIGNMENT_ID, tooManyComments, nil)
+	if err == nil {
+		test.Fatalf("Did not get an error on too many comments.")
+	}
+}
@@ -0,0 +1,163 @@
+package canvas
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Fill in the sections and groups for the given Canvas users.
+// Sections come from each user's enrollments (names are looked up from the course's sections),
+// and groups are fetched for the whole course.
+// The caller should already hold the API lock.
+func (this *CanvasBackend) addSectionsAndGroups(rawUsers []*User, users []*lmstypes.User, rewriteLinks bool) error {
+	sectionNames, err := this.fetchSectionNames(rewriteLinks)
+	if err != nil {
+		return err
+	}
+
+	userGroups, err := this.fetchUserGroups(rewriteLinks)
+	if err != nil {
+		return err
+	}
+
+	for i, rawUser := range rawUsers {
+		groups := userGroups[rawUser.ID]
+		if groups == nil {
+			groups = make(map[string]string)
+		}
+
+		users[i].Sections = getUserSections(rawUser, sectionNames)
+		users[i].Groups = groups
+	}
+
+	return nil
+}
+
+// Fill in the sections for a single Canvas user.
+// Only the user's own sections are fetched (instead of all the course's sections).
+// Canvas can only list the members of groups for the whole course, so groups are left unset.
+// The caller should already hold the API lock.
+func (this *CanvasBackend) addSections(rawUser *User, user *lmstypes.User) error {
+	sectionNames := make(map[string]string)
+	for _, enrollment := range rawUser.Enrollments {
+		if enrollment.CourseSectionID == "" {
+			continue
+		}
+
+		_, ok := sectionNames[enrollment.CourseSectionID]
+		if ok {
+			continue
+		}
+
+		apiEndpoint := fmt.Sprintf(
+			"/api/v1/courses/%s/sections/%s",
+			this.CourseID, enrollment.CourseSectionID)
+		url := this.BaseURL + apiEndpoint
+
+		body, _, err := this.client.Get(url)
+		if err != nil {
+			return fmt.Errorf("Failed to fetch section '%s': '%w'.", enrollment.CourseSectionID, err)
+		}
+
+		var section Section
+		err = util.JSONFromString(body, &section)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal section '%s': '%w'.", enrollment.CourseSectionID, err)
+		}
+
+		sectionNames[enrollment.CourseSectionID] = section.Name
+	}
+
+	user.Sections = getUserSections(rawUser, sectionNames)
+
+	return nil
+}
+
+// Get the names of the sections from a user's enrollments.
+// Sections without a known name use their ID.
+func getUserSections(rawUser *User, sectionNames map[string]string) []string {
+	sections := make([]string, 0)
+	for _, enrollment := range rawUser.Enrollments {
+		if enrollment.CourseSectionID == "" {
+			continue
+		}
+
+		name, ok := sectionNames[enrollment.CourseSectionID]
+		if !ok {
+			name = enrollment.CourseSectionID
+		}
+
+		sections = append(sections, name)
+	}
+
+	return sections
+}
+
+// Get a mapping of section ID to section name.
+func (this *CanvasBackend) fetchSectionNames(rewriteLinks bool) (map[string]string, error) {
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/courses/%s/sections?per_page=%d",
+		this.CourseID, PAGE_SIZE)
+	url := this.BaseURL + apiEndpoint
+
+	sections, err := lmshttp.FetchJSONPages[Section](this.client, url, this.getLinkRewriter(rewriteLinks))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch sections: '%w'.", err)
+	}
+
+	names := make(map[string]string, len(sections))
+	for _, section := range sections {
+		names[section.ID] = section.Name
+	}
+
+	return names, nil
+}
+
+// Get the groups for all users in the course: {user ID: {group category name: group name}}.
+func (this *CanvasBackend) fetchUserGroups(rewriteLinks bool) (map[string]map[string]string, error) {
+	apiEndpoint := fmt.Sprintf(
+		"/api/v1/courses/%s/group_categories?per_page=%d",
+		this.CourseID, PAGE_SIZE)
+	url := this.BaseURL + apiEndpoint
+
+	categories, err := lmshttp.FetchJSONPages[GroupCategory](this.client, url, this.getLinkRewriter(rewriteLinks))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch group categories: '%w'.", err)
+	}
+
+	categoryNames := make(map[string]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	apiEndpoint = fmt.Sprintf(
+		"/api/v1/courses/%s/groups?include[]=users&per_page=%d",
+		this.CourseID, PAGE_SIZE)
+	url = this.BaseURL + apiEndpoint
+
+	groups, err := lmshttp.FetchJSONPages[Group](this.client, url, this.getLinkRewriter(rewriteLinks))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch groups: '%w'.", err)
+	}
+
+	userGroups := make(map[string]map[string]string)
+	for _, group := range groups {
+		categoryName, ok := categoryNames[group.GroupCategoryID]
+		if !ok {
+			categoryName = group.GroupCategoryID
+		}
+
+		for _, user := range group.Users {
+			if userGroups[user.ID] == nil {
+				userGroups[user.ID] = make(map[string]string)
+			}
+
+			userGroups[user.ID][categoryName] = group.Name
+		}
+	}
+
+	return userGroups, nil
+}
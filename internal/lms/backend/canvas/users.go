@@ -4,6 +4,7 @@ import (
 	"fmt"
 	neturl "net/url"
 
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/util"
@@ -22,45 +23,25 @@ func (this *CanvasBackend) fetchUsers(rewriteLinks bool) ([]*lmstypes.User, erro
 		this.CourseID, PAGE_SIZE)
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-
-	users := make([]*lmstypes.User, 0)
-
-	for url != "" {
-		var err error
-
-		if rewriteLinks {
-			url, err = this.rewriteLink(url)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		body, responseHeaders, err := util.GetWithHeaders(url, headers)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
-		}
-
-		var pageUsers []*User
-		err = util.JSONFromString(body, &pageUsers)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal users page: '%w'.", err)
-		}
-
-		for _, user := range pageUsers {
-			if user == nil {
-				continue
-			}
+	rawUsers, err := lmshttp.FetchJSONPages[User](this.client, url, this.getLinkRewriter(rewriteLinks))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
+	}
 
-			users = append(users, user.ToLMSType())
-		}
+	users := make([]*lmstypes.User, 0, len(rawUsers))
+	for _, user := range rawUsers {
+		users = append(users, user.ToLMSType())
+	}
 
-		url = fetchNextCanvasLink(responseHeaders)
+	err = this.addSectionsAndGroups(rawUsers, users, rewriteLinks)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch user sections and groups: '%w'.", err)
 	}
 
 	return users, nil
 }
 
+// Only the user's sections are fetched, the user's groups are left unset (see addSections()).
 func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
 	this.getAPILock()
 	defer this.releaseAPILock()
@@ -70,8 +51,7 @@ func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
 		this.CourseID, neturl.QueryEscape(email))
 	url := this.BaseURL + apiEndpoint
 
-	headers := this.standardHeaders()
-	body, _, err := util.GetWithHeaders(url, headers)
+	body, _, err := this.client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err)
 	}
@@ -88,5 +68,12 @@ func (this *CanvasBackend) FetchUser(email string) (*lmstypes.User, error) {
 		return nil, nil
 	}
 
-	return pageUsers[0].ToLMSType(), nil
+	user := pageUsers[0].ToLMSType()
+
+	err = this.addSections(&pageUsers[0], user)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch sections for user '%s': '%w'.", email, err)
+	}
+
+	return user, nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/edulinq/autograder/internal/util"
 )
 
+// Groups are not fetched for a single user.
 func TestCanvasUserGetBase(test *testing.T) {
 	testCases := []struct {
 		email    string
@@ -21,6 +22,8 @@ func TestCanvasUserGetBase(test *testing.T) {
 				Name:  "course-owner",
 				Email: "course-owner@test.edulinq.org",
 				Role:  model.CourseRoleOwner,
+
+				Sections: []string{"CSE 40 - Staff"},
 			},
 		},
 		{
@@ -30,6 +33,8 @@ func TestCanvasUserGetBase(test *testing.T) {
 				Name:  "course-admin",
 				Email: "course-admin@test.edulinq.org",
 				Role:  model.CourseRoleAdmin,
+
+				Sections: []string{"CSE 40 - Lecture 01"},
 			},
 		},
 		{
@@ -39,6 +44,8 @@ func TestCanvasUserGetBase(test *testing.T) {
 				Name:  "course-student",
 				Email: "course-student@test.edulinq.org",
 				Role:  model.CourseRoleStudent,
+
+				Sections: []string{"CSE 40 - Lecture 01", "CSE 40 - Discussion 01B"},
 			},
 		},
 	}
@@ -50,7 +57,7 @@ func TestCanvasUserGetBase(test *testing.T) {
 			continue
 		}
 
-		if *testCase.expected != *user {
+		if !reflect.DeepEqual(testCase.expected, user) {
 			test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.",
 				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(user))
 			continue
@@ -65,18 +72,27 @@ func TestCanvasUsersGetBase(test *testing.T) {
 			Name:  "course-student",
 			Email: "course-student@test.edulinq.org",
 			Role:  model.CourseRoleStudent,
+
+			Sections: []string{"CSE 40 - Lecture 01", "CSE 40 - Discussion 01B"},
+			Groups:   map[string]string{"Project Teams": "Team 1"},
 		},
 		&lmstypes.User{
 			ID:    "00020",
 			Name:  "course-admin",
 			Email: "course-admin@test.edulinq.org",
 			Role:  model.CourseRoleAdmin,
+
+			Sections: []string{"CSE 40 - Lecture 01"},
+			Groups:   map[string]string{},
 		},
 		&lmstypes.User{
 			ID:    "00010",
 			Name:  "course-owner",
 			Email: "course-owner@test.edulinq.org",
 			Role:  model.CourseRoleOwner,
+
+			Sections: []string{"CSE 40 - Staff"},
+			Groups:   map[string]string{},
 		},
 	}
 
@@ -0,0 +1,79 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// There are no separate LMS assignments, the course's own assignments are used.
+// An assignment's LMS ID is its autograder ID (unless it already has an LMS ID).
+func (this *FileBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	course, err := this.getCourse()
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*lmstypes.Assignment, 0, len(course.Assignments))
+	for _, assignment := range course.GetSortedAssignments() {
+		assignments = append(assignments, toLMSAssignment(assignment))
+	}
+
+	return assignments, nil
+}
+
+// Returns (nil, nil) if the assignment does not exist.
+func (this *FileBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	course, err := this.getCourse()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range course.GetSortedAssignments() {
+		lmsAssignment := toLMSAssignment(assignment)
+		if lmsAssignment.ID == assignmentID {
+			return lmsAssignment, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (this *FileBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+	return nil, fmt.Errorf("File LMS backend does not support creating assignments (the course's assignments are used directly).")
+}
+
+func (this *FileBackend) UpdateAssignment(assignment *lmstypes.Assignment) error {
+	return fmt.Errorf("File LMS backend does not support updating assignments (the course's assignments are used directly).")
+}
+
+func (this *FileBackend) getCourse() (*model.Course, error) {
+	course, err := db.GetCourse(this.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course '%s': '%w'.", this.CourseID, err)
+	}
+
+	if course == nil {
+		return nil, fmt.Errorf("Unknown course '%s'.", this.CourseID)
+	}
+
+	return course, nil
+}
+
+func toLMSAssignment(assignment *model.Assignment) *lmstypes.Assignment {
+	id := assignment.GetLMSID()
+	if id == "" {
+		id = assignment.GetID()
+	}
+
+	return &lmstypes.Assignment{
+		ID:          id,
+		Name:        assignment.GetName(),
+		LMSCourseID: assignment.GetCourse().GetID(),
+		DueDate:     assignment.DueDate,
+		MaxPoints:   assignment.MaxPoints,
+		Description: assignment.Description,
+	}
+}
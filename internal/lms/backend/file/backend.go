@@ -0,0 +1,53 @@
+// An LMS backend for courses without an LMS.
+// Users are read from a roster CSV file and scores/comments are written to a gradebook CSV file.
+package file
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/lockmanager"
+)
+
+type FileBackend struct {
+	CourseID      string
+	RosterPath    string
+	GradebookPath string
+}
+
+func NewBackend(courseID string, rosterPath string, gradebookPath string) (*FileBackend, error) {
+	if courseID == "" {
+		return nil, fmt.Errorf("File LMS backend must have a non-empty course id.")
+	}
+
+	if rosterPath == "" {
+		return nil, fmt.Errorf("File LMS roster path (roster-path) cannot be empty.")
+	}
+
+	if gradebookPath == "" {
+		return nil, fmt.Errorf("File LMS gradebook path (gradebook-path) cannot be empty.")
+	}
+
+	backend := FileBackend{
+		CourseID:      courseID,
+		RosterPath:    filepath.Clean(rosterPath),
+		GradebookPath: filepath.Clean(gradebookPath),
+	}
+
+	return &backend, nil
+}
+
+func (this *FileBackend) getGradebookLock() {
+	lockmanager.Lock(this.getLockKey())
+}
+
+func (this *FileBackend) releaseGradebookLock() {
+	lockmanager.Unlock(this.getLockKey())
+}
+
+// Lock based on the gradebook path (since the file itself is what needs protecting).
+// Gradebooks are confined to their course's LMS files dir, so this is effectively a per-course lock
+// shared by all backend instances for that course.
+func (this *FileBackend) getLockKey() string {
+	return fmt.Sprintf("lms-file::%s", this.GradebookPath)
+}
@@ -0,0 +1,342 @@
+package file
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The author of all comments written to the gradebook.
+const COMMENT_AUTHOR = "autograder"
+
+// One row per assignment/user pair.
+// Comments are stored as a JSON list.
+var GRADEBOOK_COLUMNS []string = []string{"assignment-id", "user-id", "score", "time", "comments"}
+
+type gradebookEntry struct {
+	AssignmentID string
+	UserID       string
+	Score        float64
+	Time         *timestamp.Timestamp
+	Comments     []*lmstypes.SubmissionComment
+}
+
+func (this *FileBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+	this.getGradebookLock()
+	defer this.releaseGradebookLock()
+
+	entries, err := readGradebook(this.GradebookPath)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]*lmstypes.SubmissionScore, 0)
+	for _, entry := range entries {
+		if entry.AssignmentID == assignmentID {
+			scores = append(scores, entry.ToLMSType())
+		}
+	}
+
+	return scores, nil
+}
+
+// Returns (nil, nil) if the user does not have a score for this assignment.
+func (this *FileBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	this.getGradebookLock()
+	defer this.releaseGradebookLock()
+
+	entries, err := readGradebook(this.GradebookPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if (entry.AssignmentID == assignmentID) && (entry.UserID == userID) {
+			return entry.ToLMSType(), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Like Canvas, each score may have at most one comment, which is added to the user's existing comments.
+func (this *FileBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	this.getGradebookLock()
+	defer this.releaseGradebookLock()
+
+	entries, err := readGradebook(this.GradebookPath)
+	if err != nil {
+		return err
+	}
+
+	nextCommentID := getNextCommentID(entries)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	for _, score := range scores {
+		if len(score.Comments) > 1 {
+			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+		}
+
+		index := slices.IndexFunc(entries, func(entry *gradebookEntry) bool {
+			return (entry.AssignmentID == assignmentID) && (entry.UserID == score.UserID)
+		})
+
+		var entry *gradebookEntry
+		if index >= 0 {
+			entry = entries[index]
+		} else {
+			entry = &gradebookEntry{
+				AssignmentID: assignmentID,
+				UserID:       score.UserID,
+				Time:         score.Time,
+				Comments:     make([]*lmstypes.SubmissionComment, 0),
+			}
+
+			entries = append(entries, entry)
+		}
+
+		entry.Score = score.Score
+
+		// Scores without a time (e.g., manual score changes) keep the existing time.
+		if score.Time != nil {
+			entry.Time = score.Time
+		}
+
+		for _, comment := range score.Comments {
+			entry.Comments = append(entry.Comments, &lmstypes.SubmissionComment{
+				ID:     strconv.Itoa(nextCommentID),
+				Author: COMMENT_AUTHOR,
+				Text:   comment.Text,
+				Time:   now,
+			})
+
+			nextCommentID++
+		}
+	}
+
+	return writeGradebook(this.GradebookPath, entries)
+}
+
+func (this *FileBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+	this.getGradebookLock()
+	defer this.releaseGradebookLock()
+
+	entries, err := readGradebook(this.GradebookPath)
+	if err != nil {
+		return err
+	}
+
+	for i, comment := range comments {
+		err = updateComment(entries, assignmentID, comment)
+		if err != nil {
+			return fmt.Errorf("Failed on comment %d: '%w'.", i, err)
+		}
+	}
+
+	return writeGradebook(this.GradebookPath, entries)
+}
+
+func (this *FileBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+	return this.UpdateComments(assignmentID, []*lmstypes.SubmissionComment{comment})
+}
+
+// Comments are identified by their ID (which is unique within a gradebook).
+func updateComment(entries []*gradebookEntry, assignmentID string, comment *lmstypes.SubmissionComment) error {
+	for _, entry := range entries {
+		if entry.AssignmentID != assignmentID {
+			continue
+		}
+
+		for _, oldComment := range entry.Comments {
+			if oldComment.ID == comment.ID {
+				oldComment.Text = comment.Text
+				oldComment.Time = time.Now().UTC().Format(time.RFC3339)
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("Could not find comment '%s' for assignment '%s'.", comment.ID, assignmentID)
+}
+
+func getNextCommentID(entries []*gradebookEntry) int {
+	nextID := 1
+
+	for _, entry := range entries {
+		for _, comment := range entry.Comments {
+			id, err := strconv.Atoi(comment.ID)
+			if (err == nil) && (id >= nextID) {
+				nextID = id + 1
+			}
+		}
+	}
+
+	return nextID
+}
+
+func (this *gradebookEntry) ToLMSType() *lmstypes.SubmissionScore {
+	return &lmstypes.SubmissionScore{
+		UserID:   this.UserID,
+		Score:    this.Score,
+		Time:     this.Time,
+		Comments: this.Comments,
+	}
+}
+
+// Read a gradebook CSV file.
+// A missing gradebook is treated as an empty gradebook.
+func readGradebook(path string) ([]*gradebookEntry, error) {
+	if !util.PathExists(path) {
+		return make([]*gradebookEntry, 0), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open gradebook file '%s': '%w'.", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(GRADEBOOK_COLUMNS)
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read gradebook file '%s': '%w'.", path, err)
+	}
+
+	entries := make([]*gradebookEntry, 0, len(rows))
+	for i, row := range rows {
+		// Skip the header.
+		if i == 0 {
+			continue
+		}
+
+		entry, err := parseGradebookRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("Gradebook file '%s' has an invalid row (%d): '%w'.", path, (i + 1), err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseGradebookRow(row []string) (*gradebookEntry, error) {
+	entry := &gradebookEntry{
+		AssignmentID: strings.TrimSpace(row[0]),
+		UserID:       strings.TrimSpace(row[1]),
+		Comments:     make([]*lmstypes.SubmissionComment, 0),
+	}
+
+	if (entry.AssignmentID == "") || (entry.UserID == "") {
+		return nil, fmt.Errorf("Missing assignment or user ID.")
+	}
+
+	rawScore := strings.TrimSpace(row[2])
+	if rawScore != "" {
+		score, err := strconv.ParseFloat(rawScore, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse score '%s': '%w'.", rawScore, err)
+		}
+
+		entry.Score = score
+	}
+
+	rawTime := strings.TrimSpace(row[3])
+	if rawTime != "" {
+		submissionTime, err := timestamp.GuessFromString(rawTime)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse time '%s': '%w'.", rawTime, err)
+		}
+
+		entry.Time = &submissionTime
+	}
+
+	rawComments := strings.TrimSpace(row[4])
+	if rawComments != "" {
+		err := util.JSONFromString(rawComments, &entry.Comments)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse comments: '%w'.", err)
+		}
+	}
+
+	return entry, nil
+}
+
+// Write out the full gradebook (sorted by assignment and user).
+// The gradebook is first written to a temp file and then moved into place,
+// so a failed write will not leave a partial gradebook.
+func writeGradebook(path string, entries []*gradebookEntry) error {
+	slices.SortFunc(entries, func(a *gradebookEntry, b *gradebookEntry) int {
+		result := strings.Compare(a.AssignmentID, b.AssignmentID)
+		if result != 0 {
+			return result
+		}
+
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	err := writer.Write(GRADEBOOK_COLUMNS)
+	if err != nil {
+		return fmt.Errorf("Failed to write gradebook header: '%w'.", err)
+	}
+
+	for _, entry := range entries {
+		rawTime := ""
+		if entry.Time != nil {
+			rawTime = entry.Time.SafeString()
+		}
+
+		row := []string{
+			entry.AssignmentID,
+			entry.UserID,
+			util.FloatToStr(entry.Score),
+			rawTime,
+			util.MustToJSON(entry.Comments),
+		}
+
+		err = writer.Write(row)
+		if err != nil {
+			return fmt.Errorf("Failed to write gradebook row: '%w'.", err)
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return fmt.Errorf("Failed to flush gradebook: '%w'.", err)
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to make gradebook dir: '%w'.", err)
+	}
+
+	tempPath := path + ".tmp"
+
+	err = util.WriteBinaryFile(buffer.Bytes(), tempPath)
+	if err != nil {
+		return fmt.Errorf("Failed to write temp gradebook: '%w'.", err)
+	}
+
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("Failed to move gradebook into place '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package file
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGradebookScoresAndComments(test *testing.T) {
+	backend := makeTestBackend(test, TEST_ROSTER)
+
+	// A missing gradebook is empty.
+	scores, err := backend.FetchAssignmentScores("hw0")
+	if err != nil {
+		test.Fatalf("Failed to fetch scores from a missing gradebook: '%v'.", err)
+	}
+
+	if len(scores) != 0 {
+		test.Fatalf("Found scores in a missing gradebook: '%s'.", util.MustToJSONIndent(scores))
+	}
+
+	submissionTime := timestamp.MustGuessFromString("2023-10-06T06:59:59Z")
+
+	uploadScores := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "1001",
+			Score:  9.5,
+			Time:   &submissionTime,
+			Comments: []*lmstypes.SubmissionComment{
+				&lmstypes.SubmissionComment{Text: `{"a": "b, c"}`},
+			},
+		},
+		&lmstypes.SubmissionScore{
+			UserID: "course-grader@test.edulinq.org",
+			Score:  1,
+		},
+	}
+
+	err = backend.UpdateAssignmentScores("hw0", uploadScores)
+	if err != nil {
+		test.Fatalf("Failed to upload scores: '%v'.", err)
+	}
+
+	// Another assignment should not be affected.
+	err = backend.UpdateAssignmentScores("hw1", uploadScores[1:])
+	if err != nil {
+		test.Fatalf("Failed to upload other scores: '%v'.", err)
+	}
+
+	score, err := backend.FetchAssignmentScore("hw0", "1001")
+	if err != nil {
+		test.Fatalf("Failed to fetch score: '%v'.", err)
+	}
+
+	commentTime := score.Comments[0].Time
+	expected := &lmstypes.SubmissionScore{
+		UserID: "1001",
+		Score:  9.5,
+		Time:   &submissionTime,
+		Comments: []*lmstypes.SubmissionComment{
+			&lmstypes.SubmissionComment{ID: "1", Author: COMMENT_AUTHOR, Text: `{"a": "b, c"}`, Time: commentTime},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, score) {
+		test.Fatalf("Score not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(score))
+	}
+
+	// Update the score and existing comment.
+	err = backend.UpdateComment("hw0", &lmstypes.SubmissionComment{ID: "1", Text: "New Text"})
+	if err != nil {
+		test.Fatalf("Failed to update comment: '%v'.", err)
+	}
+
+	err = backend.UpdateAssignmentScores("hw0", []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID:   "1001",
+			Score:    10,
+			Comments: []*lmstypes.SubmissionComment{&lmstypes.SubmissionComment{Text: "Second"}},
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to upload updated scores: '%v'.", err)
+	}
+
+	scores, err = backend.FetchAssignmentScores("hw0")
+	if err != nil {
+		test.Fatalf("Failed to fetch scores: '%v'.", err)
+	}
+
+	if len(scores) != 2 {
+		test.Fatalf("Unexpected number of scores. Expected: 2, Actual: %d.", len(scores))
+	}
+
+	score = scores[0]
+	if (score.Score != 10) || (len(score.Comments) != 2) || (score.Comments[0].Text != "New Text") || (score.Comments[1].ID != "2") {
+		test.Fatalf("Updated score not as expected: '%s'.", util.MustToJSONIndent(score))
+	}
+
+	if *score.Time != submissionTime {
+		test.Fatalf("Submission time was changed. Expected: '%s', Actual: '%s'.", submissionTime.SafeString(), score.Time.SafeString())
+	}
+
+	// Re-upload with a newer submission.
+	newSubmissionTime := timestamp.MustGuessFromString("2023-10-07T06:59:59Z")
+
+	err = backend.UpdateAssignmentScores("hw0", []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "1001",
+			Score:  8,
+			Time:   &newSubmissionTime,
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to re-upload scores: '%v'.", err)
+	}
+
+	score, err = backend.FetchAssignmentScore("hw0", "1001")
+	if err != nil {
+		test.Fatalf("Failed to fetch re-uploaded score: '%v'.", err)
+	}
+
+	if (score.Score != 8) || (len(score.Comments) != 2) {
+		test.Fatalf("Re-uploaded score not as expected: '%s'.", util.MustToJSONIndent(score))
+	}
+
+	if (score.Time == nil) || (*score.Time != newSubmissionTime) {
+		test.Fatalf("Submission time was not updated. Expected: '%s', Actual: '%s'.", newSubmissionTime.SafeString(), score.Time.SafeString())
+	}
+
+	// Errors.
+	err = backend.UpdateComment("hw1", &lmstypes.SubmissionComment{ID: "1", Text: "ZZZ"})
+	if err == nil {
+		test.Fatalf("Did not get an error when updating a comment on the wrong assignment.")
+	}
+
+	err = backend.UpdateAssignmentScores("hw0", []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID:   "1001",
+			Comments: []*lmstypes.SubmissionComment{&lmstypes.SubmissionComment{}, &lmstypes.SubmissionComment{}},
+		},
+	})
+	if err == nil {
+		test.Fatalf("Did not get an error when uploading multiple comments.")
+	}
+
+	score, err = backend.FetchAssignmentScore("hw0", "zzz")
+	if err != nil {
+		test.Fatalf("Failed to fetch missing score: '%v'.", err)
+	}
+
+	if score != nil {
+		test.Fatalf("Got a score that should not exist: '%s'.", util.MustToJSONIndent(score))
+	}
+}
+
+func TestFetchAssignments(test *testing.T) {
+	backend := makeTestBackend(test, TEST_ROSTER)
+
+	assignments, err := backend.FetchAssignments()
+	if err != nil {
+		test.Fatalf("Failed to fetch assignments: '%v'.", err)
+	}
+
+	if (len(assignments) != 1) || (assignments[0].ID != "hw0") || (assignments[0].Name != "Homework 0") {
+		test.Fatalf("Assignments not as expected: '%s'.", util.MustToJSONIndent(assignments))
+	}
+
+	assignment, err := backend.FetchAssignment("hw0")
+	if err != nil {
+		test.Fatalf("Failed to fetch assignment: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(assignments[0], assignment) {
+		test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(assignments[0]), util.MustToJSONIndent(assignment))
+	}
+
+	assignment, err = backend.FetchAssignment("zzz")
+	if err != nil {
+		test.Fatalf("Failed to fetch missing assignment: '%v'.", err)
+	}
+
+	if assignment != nil {
+		test.Fatalf("Got an assignment that should not exist: '%s'.", util.MustToJSONIndent(assignment))
+	}
+}
@@ -0,0 +1,133 @@
+package file
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+const (
+	ROSTER_COLUMN_EMAIL    = "email"
+	ROSTER_COLUMN_NAME     = "name"
+	ROSTER_COLUMN_ROLE     = "role"
+	ROSTER_COLUMN_LMS_ID   = "lms-id"
+	ROSTER_COLUMN_SECTIONS = "sections"
+
+	// Columns with this prefix hold the user's group for the group set named after the prefix.
+	ROSTER_COLUMN_GROUP_PREFIX = "group:"
+
+	// Separates multiple sections in the sections column.
+	ROSTER_SECTIONS_SEPARATOR = ";"
+)
+
+func (this *FileBackend) FetchUsers() ([]*lmstypes.User, error) {
+	return readRoster(this.RosterPath)
+}
+
+func (this *FileBackend) FetchUser(email string) (*lmstypes.User, error) {
+	users, err := readRoster(this.RosterPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Read a roster CSV file.
+// The first row is a header, and only the email column is required.
+// Users without an explicit LMS ID will use their email as their LMS ID.
+func readRoster(path string) ([]*lmstypes.User, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open roster file '%s': '%w'.", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read roster file '%s': '%w'.", path, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("Roster file '%s' is empty, it must have at least a header.", path)
+	}
+
+	users := make([]*lmstypes.User, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		user, err := parseRosterRow(rows[0], row)
+		if err != nil {
+			return nil, fmt.Errorf("Roster file '%s' has an invalid row (%d): '%w'.", path, (i + 2), err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func parseRosterRow(header []string, row []string) (*lmstypes.User, error) {
+	user := &lmstypes.User{
+		Role:     model.CourseRoleStudent,
+		Sections: make([]string, 0),
+		Groups:   make(map[string]string),
+	}
+
+	for i, rawColumn := range header {
+		rawColumn = strings.TrimSpace(rawColumn)
+		column := strings.ToLower(rawColumn)
+		value := strings.TrimSpace(row[i])
+
+		switch column {
+		case ROSTER_COLUMN_EMAIL:
+			user.Email = value
+		case ROSTER_COLUMN_NAME:
+			user.Name = value
+		case ROSTER_COLUMN_ROLE:
+			if value == "" {
+				continue
+			}
+
+			user.Role = model.GetCourseUserRole(value)
+			if user.Role == model.CourseRoleUnknown {
+				return nil, fmt.Errorf("Unknown course role '%s'.", value)
+			}
+		case ROSTER_COLUMN_LMS_ID:
+			user.ID = value
+		case ROSTER_COLUMN_SECTIONS:
+			for _, section := range strings.Split(value, ROSTER_SECTIONS_SEPARATOR) {
+				section = strings.TrimSpace(section)
+				if section != "" {
+					user.Sections = append(user.Sections, section)
+				}
+			}
+		default:
+			if strings.HasPrefix(column, ROSTER_COLUMN_GROUP_PREFIX) && (value != "") {
+				// Keep the original case of the group set.
+				user.Groups[strings.TrimSpace(rawColumn[len(ROSTER_COLUMN_GROUP_PREFIX):])] = value
+			}
+		}
+	}
+
+	if user.Email == "" {
+		return nil, fmt.Errorf("Missing email.")
+	}
+
+	if user.ID == "" {
+		user.ID = user.Email
+	}
+
+	return user, nil
+}
@@ -0,0 +1,119 @@
+package file
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_ROSTER = `Email, Name, Role, LMS-ID, Sections, Group:Projects
+course-student@test.edulinq.org, Alice, student, 1001, Lecture 01; Lab 01, Team 1
+course-grader@test.edulinq.org, Bob, grader, , Lab 01,
+"course-other@test.edulinq.org", , , , ,
+`
+
+func TestFetchUsers(test *testing.T) {
+	backend := makeTestBackend(test, TEST_ROSTER)
+
+	expected := []*lmstypes.User{
+		&lmstypes.User{
+			ID:       "1001",
+			Name:     "Alice",
+			Email:    "course-student@test.edulinq.org",
+			Role:     model.CourseRoleStudent,
+			Sections: []string{"Lecture 01", "Lab 01"},
+			Groups:   map[string]string{"Projects": "Team 1"},
+		},
+		&lmstypes.User{
+			ID:       "course-grader@test.edulinq.org",
+			Name:     "Bob",
+			Email:    "course-grader@test.edulinq.org",
+			Role:     model.CourseRoleGrader,
+			Sections: []string{"Lab 01"},
+			Groups:   map[string]string{},
+		},
+		&lmstypes.User{
+			ID:       "course-other@test.edulinq.org",
+			Email:    "course-other@test.edulinq.org",
+			Role:     model.CourseRoleStudent,
+			Sections: []string{},
+			Groups:   map[string]string{},
+		},
+	}
+
+	users, err := backend.FetchUsers()
+	if err != nil {
+		test.Fatalf("Failed to fetch users: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, users) {
+		test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(users))
+	}
+
+	user, err := backend.FetchUser("COURSE-GRADER@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to fetch user: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected[1], user) {
+		test.Fatalf("User not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected[1]), util.MustToJSONIndent(user))
+	}
+
+	user, err = backend.FetchUser("zzz@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to fetch missing user: '%v'.", err)
+	}
+
+	if user != nil {
+		test.Fatalf("Got a user that should not exist: '%s'.", util.MustToJSONIndent(user))
+	}
+}
+
+func TestFetchUsersErrors(test *testing.T) {
+	testCases := []string{
+		"",
+		"name\nAlice\n",
+		"email, role\nalice@test.edulinq.org, zzz\n",
+		"email, name\nalice@test.edulinq.org\n",
+	}
+
+	for i, testCase := range testCases {
+		backend := makeTestBackend(test, testCase)
+
+		_, err := backend.FetchUsers()
+		if err == nil {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+		}
+	}
+
+	backend := makeTestBackend(test, TEST_ROSTER)
+	backend.RosterPath = filepath.Join(filepath.Dir(backend.RosterPath), "zzz.csv")
+
+	_, err := backend.FetchUsers()
+	if err == nil {
+		test.Errorf("Did not get an expected error on a missing roster.")
+	}
+}
+
+func makeTestBackend(test *testing.T, roster string) *FileBackend {
+	tempDir := test.TempDir()
+
+	rosterPath := filepath.Join(tempDir, "roster.csv")
+	err := util.WriteFile(roster, rosterPath)
+	if err != nil {
+		test.Fatalf("Failed to write roster: '%v'.", err)
+	}
+
+	backend, err := NewBackend("course101", rosterPath, filepath.Join(tempDir, "grades", "gradebook.csv"))
+	if err != nil {
+		test.Fatalf("Failed to create backend: '%v'.", err)
+	}
+
+	return backend
+}
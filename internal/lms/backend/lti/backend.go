@@ -0,0 +1,217 @@
+// An LMS backend that uses LTI Assignment and Grade Services (AGS).
+// Assignment LMS IDs are AGS line item URLs, and user LMS IDs are LTI subjects (both are set when users launch the autograder).
+// LTI does not provide a way to list users or assignments, so users are only added via launches.
+package lti
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/lti"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const UPLOAD_SLEEP_TIME_SEC = int64(0.1 * float64(time.Second))
+
+type LTIBackend struct {
+	CourseID string
+	Platform *lti.Platform
+}
+
+func NewBackend(courseID string) (*LTIBackend, error) {
+	platform, err := lti.GetPlatformForCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if platform == nil {
+		return nil, fmt.Errorf("Course '%s' is not linked to any LTI platform.", courseID)
+	}
+
+	backend := LTIBackend{
+		CourseID: courseID,
+		Platform: platform,
+	}
+
+	return &backend, nil
+}
+
+func (this *LTIBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	return nil, fmt.Errorf("The LTI LMS backend cannot list assignments, assignments are linked when they are launched.")
+}
+
+func (this *LTIBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	lineItem, err := this.Platform.FetchLineItem(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dueDate *timestamp.Timestamp = nil
+	if lineItem.EndDateTime != "" {
+		value, err := timestamp.GuessFromString(lineItem.EndDateTime)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse line item end time '%s': '%w'.", lineItem.EndDateTime, err)
+		}
+
+		dueDate = &value
+	}
+
+	return &lmstypes.Assignment{
+		ID:        assignmentID,
+		Name:      lineItem.Label,
+		DueDate:   dueDate,
+		MaxPoints: lineItem.ScoreMaximum,
+	}, nil
+}
+
+func (this *LTIBackend) FetchUsers() ([]*lmstypes.User, error) {
+	return nil, fmt.Errorf("The LTI LMS backend cannot list users, users are added when they launch the autograder.")
+}
+
+func (this *LTIBackend) FetchUser(email string) (*lmstypes.User, error) {
+	return nil, fmt.Errorf("The LTI LMS backend cannot fetch users, users are added when they launch the autograder.")
+}
+
+func (this *LTIBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+	return this.fetchAssignmentScores(assignmentID, "")
+}
+
+// Returns (nil, nil) if the user does not have a score.
+func (this *LTIBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	scores, err := this.fetchAssignmentScores(assignmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, score := range scores {
+		if score.UserID == userID {
+			return score, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// AGS results only have a single comment (identified by the user it belongs to).
+func (this *LTIBackend) fetchAssignmentScores(assignmentID string, userID string) ([]*lmstypes.SubmissionScore, error) {
+	results, err := this.Platform.FetchResults(assignmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]*lmstypes.SubmissionScore, 0, len(results))
+	for _, result := range results {
+		if (result == nil) || (result.ResultScore == nil) {
+			continue
+		}
+
+		comments := make([]*lmstypes.SubmissionComment, 0, 1)
+		if result.Comment != "" {
+			comments = append(comments, &lmstypes.SubmissionComment{
+				ID:     result.UserID,
+				Author: result.UserID,
+				Text:   result.Comment,
+			})
+		}
+
+		scores = append(scores, &lmstypes.SubmissionScore{
+			UserID:   result.UserID,
+			Score:    *result.ResultScore,
+			Comments: comments,
+		})
+	}
+
+	return scores, nil
+}
+
+func (this *LTIBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	lineItem, err := this.Platform.FetchLineItem(assignmentID)
+	if err != nil {
+		return err
+	}
+
+	for i, score := range scores {
+		if len(score.Comments) > 1 {
+			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+		}
+
+		if i != 0 {
+			time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC))
+		}
+
+		comment := ""
+		for _, scoreComment := range score.Comments {
+			comment = scoreComment.Text
+		}
+
+		ltiScore := lti.NewScore(score.UserID, score.Score, lineItem.ScoreMaximum, comment, score.Time)
+
+		err = this.Platform.PostScore(assignmentID, ltiScore)
+		if err != nil {
+			return fmt.Errorf("Failed on score %d: '%w'.", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *LTIBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+	for i, comment := range comments {
+		err := this.UpdateComment(assignmentID, comment)
+		if err != nil {
+			return fmt.Errorf("Failed on comment %d: '%w'.", i, err)
+		}
+	}
+
+	return nil
+}
+
+// AGS comments can only be changed by posting a new score,
+// so the user's current score is fetched and posted again with the new comment.
+func (this *LTIBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+	userID := comment.ID
+
+	score, err := this.FetchAssignmentScore(assignmentID, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch current score for comment update: '%w'.", err)
+	}
+
+	if score == nil {
+		return fmt.Errorf("Cannot update comment for user '%s' on assignment '%s', user does not have a score.", userID, assignmentID)
+	}
+
+	score.Comments = []*lmstypes.SubmissionComment{comment}
+
+	return this.UpdateAssignmentScores(assignmentID, []*lmstypes.SubmissionScore{score})
+}
+
+func (this *LTIBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+	return nil, fmt.Errorf("The LTI LMS backend cannot create assignments, assignments are linked when they are launched.")
+}
+
+// Line items do not have descriptions, so only the name, due date, and max points are updated.
+func (this *LTIBackend) UpdateAssignment(assignment *lmstypes.Assignment) error {
+	lineItem, err := this.Platform.FetchLineItem(assignment.ID)
+	if err != nil {
+		return err
+	}
+
+	if assignment.Name != "" {
+		lineItem.Label = assignment.Name
+	}
+
+	if assignment.DueDate != nil {
+		lineItem.EndDateTime = assignment.DueDate.ToGoTime().UTC().Format(time.RFC3339)
+	}
+
+	if !util.IsZero(assignment.MaxPoints) {
+		lineItem.ScoreMaximum = assignment.MaxPoints
+	}
+
+	// Update the line item at its linked URL.
+	lineItem.ID = assignment.ID
+
+	return this.Platform.UpdateLineItem(lineItem)
+}
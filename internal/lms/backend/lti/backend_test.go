@@ -0,0 +1,177 @@
+package lti
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/lti"
+	ltitest "github.com/edulinq/autograder/internal/lti/test"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestLTIBackendScores(test *testing.T) {
+	platform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer platform.Close()
+
+	backend, err := NewBackend(ltitest.TEST_COURSE_ID)
+	if err != nil {
+		test.Fatalf("Failed to create backend: '%v'.", err)
+	}
+
+	lineItemURL := platform.LineItemURL()
+
+	assignment, err := backend.FetchAssignment(lineItemURL)
+	if err != nil {
+		test.Fatalf("Failed to fetch assignment: '%v'.", err)
+	}
+
+	expectedAssignment := &lmstypes.Assignment{
+		ID:        lineItemURL,
+		Name:      "Homework 0",
+		MaxPoints: ltitest.TEST_MAX_POINTS,
+	}
+
+	if !reflect.DeepEqual(expectedAssignment, assignment) {
+		test.Fatalf("Assignment not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedAssignment), util.MustToJSONIndent(assignment))
+	}
+
+	scoreTime := timestamp.FromMSecs(1000)
+	scores := []*lmstypes.SubmissionScore{
+		&lmstypes.SubmissionScore{
+			UserID: "student-subject",
+			Score:  8,
+			Time:   &scoreTime,
+			Comments: []*lmstypes.SubmissionComment{
+				&lmstypes.SubmissionComment{
+					Text: "Nice work.",
+				},
+			},
+		},
+		&lmstypes.SubmissionScore{
+			UserID: "other-subject",
+			Score:  5,
+			Time:   &scoreTime,
+		},
+	}
+
+	err = backend.UpdateAssignmentScores(lineItemURL, scores)
+	if err != nil {
+		test.Fatalf("Failed to update scores: '%v'.", err)
+	}
+
+	postedScores := platform.GetScores()
+	if len(postedScores) != 2 {
+		test.Fatalf("Unexpected number of posted scores. Expected: 2, Actual: %d.", len(postedScores))
+	}
+
+	expectedScore := lti.NewScore("student-subject", 8, ltitest.TEST_MAX_POINTS, "Nice work.", &scoreTime)
+	if !reflect.DeepEqual(expectedScore, postedScores["student-subject"]) {
+		test.Fatalf("Posted score not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedScore), util.MustToJSONIndent(postedScores["student-subject"]))
+	}
+
+	score, err := backend.FetchAssignmentScore(lineItemURL, "student-subject")
+	if err != nil {
+		test.Fatalf("Failed to fetch score: '%v'.", err)
+	}
+
+	expectedSubmissionScore := &lmstypes.SubmissionScore{
+		UserID: "student-subject",
+		Score:  8,
+		Comments: []*lmstypes.SubmissionComment{
+			&lmstypes.SubmissionComment{
+				ID:     "student-subject",
+				Author: "student-subject",
+				Text:   "Nice work.",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedSubmissionScore, score) {
+		test.Fatalf("Fetched score not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedSubmissionScore), util.MustToJSONIndent(score))
+	}
+
+	missingScore, err := backend.FetchAssignmentScore(lineItemURL, "missing-subject")
+	if err != nil {
+		test.Fatalf("Failed to fetch missing score: '%v'.", err)
+	}
+
+	if missingScore != nil {
+		test.Fatalf("Found a score for a missing user: '%s'.", util.MustToJSONIndent(missingScore))
+	}
+
+	// Comment updates re-post the current score.
+	err = backend.UpdateComment(lineItemURL, &lmstypes.SubmissionComment{ID: "other-subject", Text: "Updated."})
+	if err != nil {
+		test.Fatalf("Failed to update comment: '%v'.", err)
+	}
+
+	otherScore := platform.GetScores()["other-subject"]
+	if (otherScore.Comment != "Updated.") || (otherScore.ScoreGiven != 5) {
+		test.Fatalf("Comment update not as expected: '%s'.", util.MustToJSONIndent(otherScore))
+	}
+}
+
+func TestLTIBackendUnlinkedCourse(test *testing.T) {
+	platform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer platform.Close()
+
+	_, err = NewBackend("course-languages")
+	if err == nil {
+		test.Fatalf("Did not get an error for a course that is not linked to a platform.")
+	}
+}
+
+func TestLTIBackendUpdateAssignment(test *testing.T) {
+	platform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer platform.Close()
+
+	backend, err := NewBackend(ltitest.TEST_COURSE_ID)
+	if err != nil {
+		test.Fatalf("Failed to create backend: '%v'.", err)
+	}
+
+	dueDate := timestamp.MustGuessFromString("2023-10-06T06:59:59Z")
+	assignment := &lmstypes.Assignment{
+		ID:          platform.LineItemURL(),
+		Name:        "Homework Zero",
+		DueDate:     &dueDate,
+		Description: "Line items do not have descriptions.",
+	}
+
+	err = backend.UpdateAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to update assignment: '%v'.", err)
+	}
+
+	expected := &lti.LineItem{
+		ID:           platform.LineItemURL(),
+		Label:        "Homework Zero",
+		ScoreMaximum: ltitest.TEST_MAX_POINTS,
+		EndDateTime:  "2023-10-06T06:59:59Z",
+	}
+
+	lineItem := platform.GetLineItem()
+	if !reflect.DeepEqual(expected, lineItem) {
+		test.Fatalf("Line item not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(lineItem))
+	}
+
+	_, err = backend.CreateAssignment(assignment)
+	if err == nil {
+		test.Fatalf("Did not get an error when creating an assignment.")
+	}
+}
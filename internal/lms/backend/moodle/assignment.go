@@ -0,0 +1,65 @@
+package moodle
+
+import (
+	"fmt"
+	neturl "net/url"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+// Moodle cannot fetch a single assignment, so all the course's assignments are fetched.
+// Returns (nil, nil) if the assignment does not exist.
+func (this *MoodleBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	assignments, err := this.FetchAssignments()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range assignments {
+		if assignment.ID == assignmentID {
+			return assignment, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (this *MoodleBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	params := neturl.Values{}
+	params.Set("courseids[0]", this.CourseID)
+
+	var response AssignmentsResponse
+	err := this.get("mod_assign_get_assignments", params, &response)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch assignments: '%w'.", err)
+	}
+
+	assignments := make([]*lmstypes.Assignment, 0)
+	for _, course := range response.Courses {
+		if course == nil {
+			continue
+		}
+
+		for _, assignment := range course.Assignments {
+			if assignment == nil {
+				continue
+			}
+
+			assignments = append(assignments, assignment.ToLMSType())
+		}
+	}
+
+	return assignments, nil
+}
+
+// The standard Moodle Web Services do not provide a way to create or edit assignments (course modules).
+func (this *MoodleBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+	return nil, fmt.Errorf("The Moodle LMS backend does not support creating assignments.")
+}
+
+func (this *MoodleBackend) UpdateAssignment(assignment *lmstypes.Assignment) error {
+	return fmt.Errorf("The Moodle LMS backend does not support updating assignments.")
+}
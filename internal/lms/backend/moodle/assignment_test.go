@@ -0,0 +1,66 @@
+package moodle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var dueDate timestamp.Timestamp = timestamp.MustGuessFromString("2023-10-06T06:59:59Z")
+var expectedAssignment lmstypes.Assignment = lmstypes.Assignment{
+	ID:          TEST_ASSIGNMENT_ID,
+	Name:        "Assignment 0",
+	LMSCourseID: TEST_COURSE_ID,
+	DueDate:     &dueDate,
+	MaxPoints:   100.0,
+}
+
+// No due date and a scale grade.
+var expectedScaleAssignment lmstypes.Assignment = lmstypes.Assignment{
+	ID:          "98766",
+	Name:        "Assignment 1",
+	LMSCourseID: TEST_COURSE_ID,
+	DueDate:     nil,
+	MaxPoints:   0.0,
+}
+
+func TestMoodleFetchAssignmentBase(test *testing.T) {
+	testCases := []struct {
+		id       string
+		expected *lmstypes.Assignment
+	}{
+		{TEST_ASSIGNMENT_ID, &expectedAssignment},
+		{"98766", &expectedScaleAssignment},
+		{"11111", nil},
+	}
+
+	for i, testCase := range testCases {
+		assignment, err := testBackend.FetchAssignment(testCase.id)
+		if err != nil {
+			test.Errorf("Case %d: Failed to fetch assignment: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, assignment) {
+			test.Errorf("Case %d: Assignment not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(assignment))
+		}
+	}
+}
+
+func TestMoodleFetchAssignmentsBase(test *testing.T) {
+	assignments, err := testBackend.FetchAssignments()
+	if err != nil {
+		test.Fatalf("Failed to fetch assignments: '%v'.", err)
+	}
+
+	expected := []*lmstypes.Assignment{&expectedAssignment, &expectedScaleAssignment}
+
+	if !reflect.DeepEqual(expected, assignments) {
+		test.Fatalf("Assignments not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(assignments))
+	}
+}
@@ -0,0 +1,63 @@
+package moodle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *MoodleBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
+	for i, comment := range comments {
+		if i != 0 {
+			time.Sleep(time.Duration(UPLOAD_SLEEP_TIME_SEC))
+		}
+
+		err := this.UpdateComment(assignmentID, comment)
+		if err != nil {
+			return fmt.Errorf("Failed on comment %d: '%w'.", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Moodle only has a single feedback comment per grade (identified by the graded user, see GradeItem.ToLMSType()),
+// and feedback can only be changed by saving the grade again.
+// So, the user's current grade is fetched and saved along with the new comment.
+func (this *MoodleBackend) UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	userID := comment.ID
+
+	score, err := this.fetchAssignmentScore(assignmentID, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch current score for comment update: '%w'.", err)
+	}
+
+	if score == nil {
+		return fmt.Errorf("Cannot update comment for user '%s' on assignment '%s', user has not been graded.", userID, assignmentID)
+	}
+
+	form := map[string]string{
+		"assignmentid":  assignmentID,
+		"userid":        userID,
+		"grade":         util.FloatToStr(score.Score),
+		"attemptnumber": "-1",
+		"addattempt":    "0",
+		"workflowstate": "",
+		"applytoall":    "0",
+
+		"plugindata[assignfeedbackcomments_editor][text]":   comment.Text,
+		"plugindata[assignfeedbackcomments_editor][format]": TEXT_FORMAT_PLAIN,
+	}
+
+	err = this.post("mod_assign_save_grade", form)
+	if err != nil {
+		return fmt.Errorf("Failed to update comments: '%w'.", err)
+	}
+
+	return nil
+}
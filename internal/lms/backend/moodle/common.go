@@ -0,0 +1,122 @@
+package moodle
+
+import (
+	"fmt"
+	"html"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	PAGE_SIZE             int    = 75
+	POST_PAGE_SIZE        int    = 75
+	REST_ENDPOINT         string = "/webservice/rest/server.php"
+	UPLOAD_SLEEP_TIME_SEC        = int64(0.5 * float64(time.Second))
+
+	// Moodle's FORMAT_PLAIN.
+	TEXT_FORMAT_PLAIN string = "2"
+)
+
+func (this *MoodleBackend) getAPILock() {
+	lockmanager.Lock(this.getLockKey())
+}
+
+func (this *MoodleBackend) releaseAPILock() {
+	lockmanager.Unlock(this.getLockKey())
+}
+
+// Lock based on the API token (like Canvas, a single token may be used for multiple courses).
+func (this *MoodleBackend) getLockKey() string {
+	return fmt.Sprintf("moodle::%s", this.APIToken)
+}
+
+// Get the URL for calling a Moodle Web Services function.
+// Moodle identifies the function via the query string.
+// The token is not included, since URLs may be logged (see tokenForm()).
+func (this *MoodleBackend) functionURL(function string, params neturl.Values) string {
+	query := neturl.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	query.Set("wsfunction", function)
+	query.Set("moodlewsrestformat", "json")
+
+	return fmt.Sprintf("%s%s?%s", this.BaseURL, REST_ENDPOINT, query.Encode())
+}
+
+// Get a form with the API token (and the given fields).
+// Moodle accepts the token from either the query string or a POST body,
+// so all calls are made as POSTs to keep the token out of URLs.
+func (this *MoodleBackend) tokenForm(fields map[string]string) map[string]string {
+	form := make(map[string]string, len(fields)+1)
+	for key, value := range fields {
+		form[key] = value
+	}
+
+	form["wstoken"] = this.APIToken
+
+	return form
+}
+
+// Call a read-only Web Services function and parse the result into the given pointer.
+// The caller should hold the API lock.
+func (this *MoodleBackend) get(function string, params neturl.Values, result any) error {
+	url := this.functionURL(function, params)
+
+	body, _, err := this.client.Post(url, this.tokenForm(nil))
+	if err != nil {
+		return fmt.Errorf("Failed to call Moodle function '%s': '%w'.", function, err)
+	}
+
+	return parseResponse(function, body, result)
+}
+
+// Call a Web Services function that modifies data.
+// The function's arguments are sent as a form.
+// The caller should hold the API lock.
+func (this *MoodleBackend) post(function string, form map[string]string) error {
+	url := this.functionURL(function, nil)
+
+	body, _, err := this.client.Post(url, this.tokenForm(form))
+	if err != nil {
+		return fmt.Errorf("Failed to call Moodle function '%s': '%w'.", function, err)
+	}
+
+	return parseResponse(function, body, nil)
+}
+
+// Moodle reports errors with an OK status and an exception object as the body.
+func parseResponse(function string, body string, result any) error {
+	body = strings.TrimSpace(body)
+
+	if strings.HasPrefix(body, "{") {
+		var exception Exception
+		err := util.JSONFromString(body, &exception)
+		if (err == nil) && (exception.Exception != "") {
+			return fmt.Errorf("Moodle function '%s' returned an error (%s): '%s'.", function, exception.ErrorCode, exception.Message)
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	err := util.JSONFromString(body, result)
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal response from Moodle function '%s': '%w'.", function, err)
+	}
+
+	return nil
+}
+
+// Moodle formats feedback text before returning it (escaping HTML and converting newlines).
+// Undo that formatting so that structured comments (e.g. JSON) can be parsed.
+func cleanFeedback(text string) string {
+	text = strings.ReplaceAll(text, "<br />", "")
+	return html.UnescapeString(text)
+}
@@ -0,0 +1,56 @@
+package moodle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseResponse(test *testing.T) {
+	testCases := []struct {
+		body           string
+		errorSubstring string
+	}{
+		{`[]`, ""},
+		{`{"courses": []}`, ""},
+		{`null`, ""},
+		{`{"exception": "moodle_exception", "errorcode": "invalidtoken", "message": "Invalid token - token not found"}`, "invalidtoken"},
+		{`{"exception": "invalid_parameter_exception", "errorcode": "invalidparameter", "message": "Invalid parameter value detected"}`, "Invalid parameter"},
+	}
+
+	for i, testCase := range testCases {
+		var result any
+		err := parseResponse("test_function", testCase.body, &result)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+		}
+	}
+}
+
+func TestCleanFeedback(test *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"{<br />\n&quot;a&quot;: 1<br />\n}", "{\n\"a\": 1\n}"},
+		{"a &lt; b &amp;&amp; c", "a < b && c"},
+	}
+
+	for i, testCase := range testCases {
+		actual := cleanFeedback(testCase.input)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+		}
+	}
+}
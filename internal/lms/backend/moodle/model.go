@@ -0,0 +1,188 @@
+package moodle
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type Exception struct {
+	Exception string `json:"exception"`
+	ErrorCode string `json:"errorcode"`
+	Message   string `json:"message"`
+}
+
+type User struct {
+	ID       int64   `json:"id"`
+	Username string  `json:"username"`
+	FullName string  `json:"fullname"`
+	Email    string  `json:"email"`
+	Roles    []*Role `json:"roles"`
+
+	// Moodle groups are the closest equivalent to sections.
+	// Nil if groups were not included in the response.
+	Groups []*Group `json:"groups"`
+}
+
+type Group struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type Role struct {
+	ID        int64  `json:"roleid"`
+	Name      string `json:"name"`
+	ShortName string `json:"shortname"`
+}
+
+type AssignmentsResponse struct {
+	Courses []*AssignmentCourse `json:"courses"`
+}
+
+type AssignmentCourse struct {
+	ID          int64         `json:"id"`
+	Assignments []*Assignment `json:"assignments"`
+}
+
+type Assignment struct {
+	ID       int64  `json:"id"`
+	CourseID int64  `json:"course"`
+	Name     string `json:"name"`
+
+	// Seconds since the epoch, zero if there is no due date.
+	DueDate int64 `json:"duedate"`
+
+	// The max points, negative values indicate a scale (which we do not support).
+	Grade float64 `json:"grade"`
+}
+
+type GradeItemsResponse struct {
+	UserGrades []*UserGrades `json:"usergrades"`
+}
+
+type UserGrades struct {
+	UserID     int64        `json:"userid"`
+	GradeItems []*GradeItem `json:"gradeitems"`
+}
+
+type GradeItem struct {
+	ItemType     string `json:"itemtype"`
+	ItemModule   string `json:"itemmodule"`
+	ItemInstance int64  `json:"iteminstance"`
+
+	// Null if the user has not been graded.
+	GradeRaw *float64 `json:"graderaw"`
+
+	// Seconds since the epoch.
+	DateSubmitted *int64 `json:"gradedatesubmitted"`
+
+	Feedback string `json:"feedback"`
+}
+
+// Moodle role (short name) to autograder role.
+var roleMapping map[string]model.CourseUserRole = map[string]model.CourseUserRole{
+	"guest":          model.CourseRoleOther,
+	"student":        model.CourseRoleStudent,
+	"teacher":        model.CourseRoleGrader,
+	"manager":        model.CourseRoleAdmin,
+	"editingteacher": model.CourseRoleOwner,
+}
+
+func (this *User) GetRole() model.CourseUserRole {
+	var maxRole model.CourseUserRole = model.CourseRoleOther
+	for _, role := range this.Roles {
+		if role == nil {
+			continue
+		}
+
+		maxRole = max(maxRole, roleMapping[role.ShortName])
+	}
+
+	return maxRole
+}
+
+func (this *User) ToLMSType() *lmstypes.User {
+	var sections []string = nil
+	if this.Groups != nil {
+		sections = make([]string, 0, len(this.Groups))
+		for _, group := range this.Groups {
+			sections = append(sections, group.Name)
+		}
+	}
+
+	return &lmstypes.User{
+		ID:       formatID(this.ID),
+		Name:     this.FullName,
+		Email:    this.Email,
+		Role:     this.GetRole(),
+		Sections: sections,
+	}
+}
+
+func (this *Assignment) ToLMSType() *lmstypes.Assignment {
+	var dueDate *timestamp.Timestamp = nil
+	if this.DueDate > 0 {
+		value := timestamp.FromMSecs(this.DueDate * 1000)
+		dueDate = &value
+	}
+
+	return &lmstypes.Assignment{
+		ID:          formatID(this.ID),
+		Name:        this.Name,
+		LMSCourseID: formatID(this.CourseID),
+		DueDate:     dueDate,
+		MaxPoints:   max(0.0, this.Grade),
+	}
+}
+
+// Check if this item is the grade for the given assignment (instance) ID.
+func (this *GradeItem) IsAssignment(assignmentID int64) bool {
+	return (this.ItemType == "mod") && (this.ItemModule == "assign") && (this.ItemInstance == assignmentID)
+}
+
+// Moodle only has a single feedback comment per grade,
+// so the comment is identified by the user it belongs to.
+func (this *GradeItem) ToLMSType(userID int64) *lmstypes.SubmissionScore {
+	var submissionTime *timestamp.Timestamp = nil
+	if (this.DateSubmitted != nil) && (*this.DateSubmitted > 0) {
+		value := timestamp.FromMSecs(*this.DateSubmitted * 1000)
+		submissionTime = &value
+	}
+
+	score := 0.0
+	if this.GradeRaw != nil {
+		score = *this.GradeRaw
+	}
+
+	comments := make([]*lmstypes.SubmissionComment, 0, 1)
+	if this.Feedback != "" {
+		comments = append(comments, &lmstypes.SubmissionComment{
+			ID:     formatID(userID),
+			Author: formatID(userID),
+			Text:   cleanFeedback(this.Feedback),
+		})
+	}
+
+	return &lmstypes.SubmissionScore{
+		UserID:   formatID(userID),
+		Score:    score,
+		Time:     submissionTime,
+		Comments: comments,
+	}
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func parseID(id string) (int64, error) {
+	value, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Moodle IDs must be integers, found '%s'.", id)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,133 @@
+package moodle
+
+import (
+	"fmt"
+	neturl "net/url"
+	"time"
+
+	"github.com/edulinq/autograder/internal/lms/lmshttp"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Returns (nil, nil) if the user does not have a grade for the assignment.
+func (this *MoodleBackend) FetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	return this.fetchAssignmentScore(assignmentID, userID)
+}
+
+func (this *MoodleBackend) fetchAssignmentScore(assignmentID string, userID string) (*lmstypes.SubmissionScore, error) {
+	scores, err := this.fetchAssignmentScores(assignmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, score := range scores {
+		if score.UserID == userID {
+			return score, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (this *MoodleBackend) FetchAssignmentScores(assignmentID string) ([]*lmstypes.SubmissionScore, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	return this.fetchAssignmentScores(assignmentID, "")
+}
+
+// Fetch scores (with feedback) from the grade report.
+// If userID is empty, then scores for all users are fetched.
+// Users that have not been graded are skipped.
+func (this *MoodleBackend) fetchAssignmentScores(assignmentID string, userID string) ([]*lmstypes.SubmissionScore, error) {
+	assignmentInstance, err := parseID(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := neturl.Values{}
+	params.Set("courseid", this.CourseID)
+	if userID != "" {
+		params.Set("userid", userID)
+	}
+
+	var response GradeItemsResponse
+	err = this.get("gradereport_user_get_grade_items", params, &response)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch scores: '%w'.", err)
+	}
+
+	scores := make([]*lmstypes.SubmissionScore, 0, len(response.UserGrades))
+	for _, userGrades := range response.UserGrades {
+		if userGrades == nil {
+			continue
+		}
+
+		for _, item := range userGrades.GradeItems {
+			if (item == nil) || !item.IsAssignment(assignmentInstance) {
+				continue
+			}
+
+			if item.GradeRaw == nil {
+				continue
+			}
+
+			scores = append(scores, item.ToLMSType(userGrades.UserID))
+		}
+	}
+
+	return scores, nil
+}
+
+// Scores are uploaded in pages.
+// If an upload fails part way through, then retrying the same upload will skip the pages that were already uploaded.
+func (this *MoodleBackend) UpdateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	uploadKey := fmt.Sprintf("moodle::%s::%s::%s::scores", this.BaseURL, this.CourseID, assignmentID)
+
+	return lmshttp.UploadBatches(uploadKey, scores, POST_PAGE_SIZE, time.Duration(UPLOAD_SLEEP_TIME_SEC),
+		func(batch []*lmstypes.SubmissionScore) error {
+			return this.updateAssignmentScores(assignmentID, batch)
+		})
+}
+
+func (this *MoodleBackend) updateAssignmentScores(assignmentID string, scores []*lmstypes.SubmissionScore) error {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	if len(scores) > POST_PAGE_SIZE {
+		return fmt.Errorf("Too many score upload requests at once. Found %d, max %d.", len(scores), POST_PAGE_SIZE)
+	}
+
+	form := make(map[string]string)
+	form["assignmentid"] = assignmentID
+	form["applytoall"] = "0"
+
+	for i, score := range scores {
+		if len(score.Comments) > 1 {
+			return fmt.Errorf("Scores to upload can have at most one comment. Student '%s' for assignment '%s' has %d.", score.UserID, assignmentID, len(score.Comments))
+		}
+
+		prefix := fmt.Sprintf("grades[%d]", i)
+		form[prefix+"[userid]"] = score.UserID
+		form[prefix+"[grade]"] = util.FloatToStr(score.Score)
+		form[prefix+"[attemptnumber]"] = "-1"
+		form[prefix+"[addattempt]"] = "0"
+		form[prefix+"[workflowstate]"] = ""
+
+		for _, comment := range score.Comments {
+			form[prefix+"[plugindata][assignfeedbackcomments_editor][text]"] = comment.Text
+			form[prefix+"[plugindata][assignfeedbackcomments_editor][format]"] = TEXT_FORMAT_PLAIN
+		}
+	}
+
+	err := this.post("mod_assign_save_grades", form)
+	if err != nil {
+		return fmt.Errorf("Failed to upload scores: '%w'.", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package moodle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var submissionTime timestamp.Timestamp = timestamp.FromMSecs(1696364768 * 1000)
+
+const testCommentText = "{\n\"id\": \"course101::hw0::course-student@test.edulinq.org::1696364768\",\n\"submission-time\":1234,\n\"upload-time\":1235,\n\"raw-score\": 100,\n\"score\": 100,\n\"lock\": false,\n\"late-date-usage\": 0,\n\"num-days-late\": 0,\n\"reject\": false,\n\"__autograder__v01__\": 0\n}"
+
+func makeTestScore(userID string, withComment bool) *lmstypes.SubmissionScore {
+	comments := []*lmstypes.SubmissionComment{}
+	if withComment {
+		comments = append(comments, &lmstypes.SubmissionComment{
+			ID:     userID,
+			Author: userID,
+			Text:   testCommentText,
+		})
+	}
+
+	return &lmstypes.SubmissionScore{
+		UserID:   userID,
+		Score:    100.0,
+		Time:     &submissionTime,
+		Comments: comments,
+	}
+}
+
+func TestMoodleFetchAssignmentScoreBase(test *testing.T) {
+	testCases := []struct {
+		userID   string
+		expected *lmstypes.SubmissionScore
+	}{
+		{"40", makeTestScore("40", true)},
+		// Not graded.
+		{"30", nil},
+	}
+
+	for i, testCase := range testCases {
+		score, err := testBackend.FetchAssignmentScore(TEST_ASSIGNMENT_ID, testCase.userID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to fetch assignment score: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, score) {
+			test.Errorf("Case %d: Score not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(score))
+		}
+	}
+}
+
+func TestMoodleFetchAssignmentScoresBase(test *testing.T) {
+	scores, err := testBackend.FetchAssignmentScores(TEST_ASSIGNMENT_ID)
+	if err != nil {
+		test.Fatalf("Failed to fetch assignment scores: '%v'.", err)
+	}
+
+	expected := []*lmstypes.SubmissionScore{
+		makeTestScore("40", true),
+		makeTestScore("20", true),
+		makeTestScore("10", false),
+	}
+
+	if !reflect.DeepEqual(expected, scores) {
+		test.Fatalf("Scores not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(scores))
+	}
+}
+
+func TestMoodleUpdateAssignmentScoresBase(test *testing.T) {
+	scores := []*lmstypes.SubmissionScore{
+		makeTestScore("40", true),
+		makeTestScore("10", false),
+	}
+
+	err := testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, scores)
+	if err != nil {
+		test.Fatalf("Failed to update assignment scores: '%v'.", err)
+	}
+
+	score := makeTestScore("40", true)
+	score.Comments = append(score.Comments, score.Comments[0])
+
+	err = testBackend.UpdateAssignmentScores(TEST_ASSIGNMENT_ID, []*lmstypes.SubmissionScore{score})
+	if err == nil {
+		test.Fatalf("Did not get an error when uploading multiple comments.")
+	}
+}
+
+func TestMoodleUpdateCommentBase(test *testing.T) {
+	testCases := []struct {
+		userID   string
+		hasError bool
+	}{
+		{"40", false},
+		// Not graded.
+		{"30", true},
+	}
+
+	for i, testCase := range testCases {
+		comment := &lmstypes.SubmissionComment{
+			ID:     testCase.userID,
+			Author: testCase.userID,
+			Text:   "new comment",
+		}
+
+		err := testBackend.UpdateComment(TEST_ASSIGNMENT_ID, comment)
+		if testCase.hasError && (err == nil) {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+		} else if !testCase.hasError && (err != nil) {
+			test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+		}
+	}
+}
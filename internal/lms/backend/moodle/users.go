@@ -0,0 +1,77 @@
+package moodle
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+)
+
+func (this *MoodleBackend) FetchUsers() ([]*lmstypes.User, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	return this.fetchUsers()
+}
+
+func (this *MoodleBackend) fetchUsers() ([]*lmstypes.User, error) {
+	users := make([]*lmstypes.User, 0)
+
+	for offset := 0; ; offset += PAGE_SIZE {
+		params := neturl.Values{}
+		params.Set("courseid", this.CourseID)
+		params.Set("options[0][name]", "limitfrom")
+		params.Set("options[0][value]", strconv.Itoa(offset))
+		params.Set("options[1][name]", "limitnumber")
+		params.Set("options[1][value]", strconv.Itoa(PAGE_SIZE))
+
+		var pageUsers []*User
+		err := this.get("core_enrol_get_enrolled_users", params, &pageUsers)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch users: '%w'.", err)
+		}
+
+		for _, user := range pageUsers {
+			if user == nil {
+				continue
+			}
+
+			users = append(users, user.ToLMSType())
+		}
+
+		if len(pageUsers) < PAGE_SIZE {
+			break
+		}
+	}
+
+	return users, nil
+}
+
+// Moodle cannot search enrolled users by email, so all users are fetched.
+func (this *MoodleBackend) FetchUser(email string) (*lmstypes.User, error) {
+	this.getAPILock()
+	defer this.releaseAPILock()
+
+	users, err := this.fetchUsers()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch user '%s': '%w'.", email, err)
+	}
+
+	matches := make([]*lmstypes.User, 0, 1)
+	for _, user := range users {
+		if strings.EqualFold(email, user.Email) {
+			matches = append(matches, user)
+		}
+	}
+
+	if len(matches) != 1 {
+		log.Warn("Did not find exactly one matching user in moodle.",
+			log.NewAttr("email", email), log.NewAttr("num-results", len(matches)))
+		return nil, nil
+	}
+
+	return matches[0], nil
+}
@@ -0,0 +1,83 @@
+package moodle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var expectedUsers []*lmstypes.User = []*lmstypes.User{
+	&lmstypes.User{
+		ID:    "40",
+		Name:  "course-student",
+		Email: "course-student@test.edulinq.org",
+		Role:  model.CourseRoleStudent,
+
+		Sections: []string{"Section A"},
+	},
+	&lmstypes.User{
+		ID:    "30",
+		Name:  "course-grader",
+		Email: "course-grader@test.edulinq.org",
+		Role:  model.CourseRoleGrader,
+
+		Sections: []string{},
+	},
+	&lmstypes.User{
+		ID:    "20",
+		Name:  "course-admin",
+		Email: "course-admin@test.edulinq.org",
+		Role:  model.CourseRoleAdmin,
+
+		Sections: []string{},
+	},
+	&lmstypes.User{
+		ID:    "10",
+		Name:  "course-owner",
+		Email: "course-owner@test.edulinq.org",
+		Role:  model.CourseRoleOwner,
+
+		Sections: []string{},
+	},
+}
+
+func TestMoodleUserGetBase(test *testing.T) {
+	testCases := []struct {
+		email    string
+		expected *lmstypes.User
+	}{
+		{"course-owner@test.edulinq.org", expectedUsers[3]},
+		{"course-admin@test.edulinq.org", expectedUsers[2]},
+		{"COURSE-STUDENT@test.edulinq.org", expectedUsers[0]},
+		{"zzz@test.edulinq.org", nil},
+	}
+
+	for i, testCase := range testCases {
+		user, err := testBackend.FetchUser(testCase.email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to fetch user: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, user) {
+			test.Errorf("Case %d: User not as expected. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(user))
+			continue
+		}
+	}
+}
+
+func TestMoodleUsersGetBase(test *testing.T) {
+	users, err := testBackend.FetchUsers()
+	if err != nil {
+		test.Fatalf("Failed to fetch users: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expectedUsers, users) {
+		test.Fatalf("Users not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedUsers), util.MustToJSONIndent(users))
+	}
+}
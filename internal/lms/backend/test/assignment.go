@@ -0,0 +1,105 @@
+package test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+)
+
+// Assignments that have been created (pushed) to the test LMS, keyed by LMS course ID and then assignment ID.
+var assignmentsLock sync.Mutex
+var assignments map[string]map[string]*lmstypes.Assignment = make(map[string]map[string]*lmstypes.Assignment)
+var nextAssignmentID int = 1
+
+func ClearAssignments() {
+	assignmentsLock.Lock()
+	defer assignmentsLock.Unlock()
+
+	assignments = make(map[string]map[string]*lmstypes.Assignment)
+	nextAssignmentID = 1
+}
+
+// Only assignments that were created in the test LMS are returned.
+func (this *TestLMSBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
+	assignmentsLock.Lock()
+	defer assignmentsLock.Unlock()
+
+	ids := make([]string, 0, len(assignments[this.CourseID]))
+	for id, _ := range assignments[this.CourseID] {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	results := make([]*lmstypes.Assignment, 0, len(ids))
+	for _, id := range ids {
+		result := *assignments[this.CourseID][id]
+		results = append(results, &result)
+	}
+
+	return results, nil
+}
+
+// Returns (nil, nil) for assignments that were not created in the test LMS.
+func (this *TestLMSBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
+	assignmentsLock.Lock()
+	defer assignmentsLock.Unlock()
+
+	assignment, ok := assignments[this.CourseID][assignmentID]
+	if !ok {
+		return nil, nil
+	}
+
+	result := *assignment
+	return &result, nil
+}
+
+func (this *TestLMSBackend) CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+	assignmentsLock.Lock()
+	defer assignmentsLock.Unlock()
+
+	createdAssignment := *assignment
+	createdAssignment.ID = fmt.Sprintf("test-lms-assignment-%03d", nextAssignmentID)
+	createdAssignment.LMSCourseID = this.CourseID
+	nextAssignmentID++
+
+	_, ok := assignments[this.CourseID]
+	if !ok {
+		assignments[this.CourseID] = make(map[string]*lmstypes.Assignment)
+	}
+
+	assignments[this.CourseID][createdAssignment.ID] = &createdAssignment
+
+	result := createdAssignment
+	return &result, nil
+}
+
+func (this *TestLMSBackend) UpdateAssignment(assignment *lmstypes.Assignment) error {
+	assignmentsLock.Lock()
+	defer assignmentsLock.Unlock()
+
+	oldAssignment, ok := assignments[this.CourseID][assignment.ID]
+	if !ok {
+		return fmt.Errorf("Unknown test LMS assignment: '%s'.", assignment.ID)
+	}
+
+	if assignment.Name != "" {
+		oldAssignment.Name = assignment.Name
+	}
+
+	if assignment.DueDate != nil {
+		oldAssignment.DueDate = assignment.DueDate
+	}
+
+	if assignment.MaxPoints != 0 {
+		oldAssignment.MaxPoints = assignment.MaxPoints
+	}
+
+	if assignment.Description != "" {
+		oldAssignment.Description = assignment.Description
+	}
+
+	return nil
+}
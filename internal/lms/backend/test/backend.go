@@ -42,14 +42,6 @@ func ClearUsersModifier() {
 	usersModifier = nil
 }
 
-func (this *TestLMSBackend) FetchAssignments() ([]*lmstypes.Assignment, error) {
-	return nil, nil
-}
-
-func (this *TestLMSBackend) FetchAssignment(assignmentID string) (*lmstypes.Assignment, error) {
-	return nil, nil
-}
-
 func (this *TestLMSBackend) UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error {
 	return nil
 }
@@ -62,5 +62,8 @@ func UserFromCourseUser(user *model.CourseUser) *lmstypes.User {
 		Name:  user.GetName(false),
 		Email: user.Email,
 		Role:  user.Role,
+
+		Sections: user.Sections,
+		Groups:   user.Groups,
 	}
 }
@@ -4,6 +4,9 @@ import (
 	"fmt"
 
 	"github.com/edulinq/autograder/internal/lms/backend/canvas"
+	"github.com/edulinq/autograder/internal/lms/backend/file"
+	"github.com/edulinq/autograder/internal/lms/backend/lti"
+	"github.com/edulinq/autograder/internal/lms/backend/moodle"
 	"github.com/edulinq/autograder/internal/lms/backend/test"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/model"
@@ -12,6 +15,8 @@ import (
 type lmsBackend interface {
 	FetchAssignments() ([]*lmstypes.Assignment, error)
 	FetchAssignment(assignmentID string) (*lmstypes.Assignment, error)
+	CreateAssignment(assignment *lmstypes.Assignment) (*lmstypes.Assignment, error)
+	UpdateAssignment(assignment *lmstypes.Assignment) error
 
 	UpdateComments(assignmentID string, comments []*lmstypes.SubmissionComment) error
 	UpdateComment(assignmentID string, comment *lmstypes.SubmissionComment) error
@@ -37,6 +42,32 @@ func getBackend(course *model.Course) (lmsBackend, error) {
 			return nil, err
 		}
 
+		return backend, nil
+	case model.LMS_TYPE_FILE:
+		rosterPath, gradebookPath, err := adapter.GetFilePaths(course)
+		if err != nil {
+			return nil, err
+		}
+
+		backend, err := file.NewBackend(course.GetID(), rosterPath, gradebookPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return backend, nil
+	case model.LMS_TYPE_LTI:
+		backend, err := lti.NewBackend(course.GetID())
+		if err != nil {
+			return nil, err
+		}
+
+		return backend, nil
+	case model.LMS_TYPE_MOODLE:
+		backend, err := moodle.NewBackend(adapter.LMSCourseID, adapter.APIToken, adapter.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+
 		return backend, nil
 	case model.LMS_TYPE_TEST:
 		backend, err := test.NewBackend(course.GetID())
@@ -68,6 +99,28 @@ func FetchAssignments(course *model.Course) ([]*lmstypes.Assignment, error) {
 	return backend.FetchAssignments()
 }
 
+// Create a new assignment in the LMS (the assignment's ID is ignored).
+// Returns the created assignment (which will have the LMS's ID).
+func CreateAssignment(course *model.Course, assignment *lmstypes.Assignment) (*lmstypes.Assignment, error) {
+	backend, err := getBackend(course)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.CreateAssignment(assignment)
+}
+
+// Update an existing LMS assignment (identified by the assignment's ID).
+// Empty fields (e.g., a nil due date) are not changed.
+func UpdateAssignment(course *model.Course, assignment *lmstypes.Assignment) error {
+	backend, err := getBackend(course)
+	if err != nil {
+		return err
+	}
+
+	return backend.UpdateAssignment(assignment)
+}
+
 func UpdateComments(course *model.Course, assignmentID string, comments []*lmstypes.SubmissionComment) error {
 	backend, err := getBackend(course)
 	if err != nil {
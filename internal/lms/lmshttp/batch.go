@@ -0,0 +1,98 @@
+package lmshttp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// How long a completed batch is remembered for after an upload fails.
+const COMPLETED_BATCH_TTL = timestamp.Timestamp(24 * time.Hour / time.Millisecond)
+
+// Batches that were uploaded as part of an upload that has not (yet) fully succeeded.
+// Keyed by a hash of the upload key and the batch contents, values are when the batch completed.
+var completedBatchesLock sync.Mutex
+var completedBatches map[string]timestamp.Timestamp = make(map[string]timestamp.Timestamp)
+
+// Upload items in batches (with a delay between batches).
+// Uploads are resumable: if an upload fails part way through,
+// then batches that were already uploaded are skipped when the same upload (key and items) is retried.
+// Only batches with the exact same contents are skipped, so changed items will always be (re-)uploaded.
+func UploadBatches[T any](uploadKey string, items []T, batchSize int, delay time.Duration, upload func(batch []T) error) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("Batch size must be positive, found %d.", batchSize)
+	}
+
+	numBatches := (len(items) + batchSize - 1) / batchSize
+	batchKeys := make([]string, 0, numBatches)
+
+	uploaded := false
+	for batchIndex := 0; batchIndex < numBatches; batchIndex++ {
+		startIndex := batchIndex * batchSize
+		endIndex := min(len(items), ((batchIndex + 1) * batchSize))
+		batch := items[startIndex:endIndex]
+
+		batchKey, err := util.Sha256HashFromJSONObject([]any{uploadKey, batch})
+		if err != nil {
+			return fmt.Errorf("Failed to compute key for batch %d: '%w'.", batchIndex, err)
+		}
+
+		batchKeys = append(batchKeys, batchKey)
+
+		if isBatchCompleted(batchKey) {
+			log.Debug("Skipping previously uploaded batch.", log.NewAttr("upload", uploadKey), log.NewAttr("batch", batchIndex))
+			continue
+		}
+
+		if uploaded {
+			time.Sleep(delay)
+		}
+
+		err = upload(batch)
+		if err != nil {
+			return fmt.Errorf("Failed on batch %d (of %d). Retrying the same upload will resume from this batch: '%w'.", batchIndex, numBatches, err)
+		}
+
+		uploaded = true
+		markBatchCompleted(batchKey)
+	}
+
+	clearBatches(batchKeys)
+	return nil
+}
+
+func isBatchCompleted(batchKey string) bool {
+	completedBatchesLock.Lock()
+	defer completedBatchesLock.Unlock()
+
+	_, ok := completedBatches[batchKey]
+	return ok
+}
+
+// Mark a batch as completed (and clear out any expired batches).
+func markBatchCompleted(batchKey string) {
+	completedBatchesLock.Lock()
+	defer completedBatchesLock.Unlock()
+
+	now := timestamp.Now()
+	for key, completedTime := range completedBatches {
+		if (completedTime + COMPLETED_BATCH_TTL) < now {
+			delete(completedBatches, key)
+		}
+	}
+
+	completedBatches[batchKey] = now
+}
+
+func clearBatches(batchKeys []string) {
+	completedBatchesLock.Lock()
+	defer completedBatchesLock.Unlock()
+
+	for _, batchKey := range batchKeys {
+		delete(completedBatches, batchKey)
+	}
+}
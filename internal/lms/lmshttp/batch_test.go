@@ -0,0 +1,96 @@
+package lmshttp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestUploadBatchesResume(test *testing.T) {
+	uploadKey := "test::resume"
+	items := []int{0, 1, 2, 3, 4, 5, 6}
+
+	uploaded := make([][]int, 0)
+	failOn := 2
+
+	upload := func(batch []int) error {
+		if (failOn >= 0) && (batch[0] == (failOn * 2)) {
+			return fmt.Errorf("Test failure.")
+		}
+
+		uploaded = append(uploaded, batch)
+		return nil
+	}
+
+	err := UploadBatches(uploadKey, items, 2, 0, upload)
+	if err == nil {
+		test.Fatalf("Did not get an expected error.")
+	}
+
+	expected := [][]int{{0, 1}, {2, 3}}
+	if !reflect.DeepEqual(expected, uploaded) {
+		test.Fatalf("Unexpected uploads after failure. Expected: '%v', Actual: '%v'.", expected, uploaded)
+	}
+
+	// Resume, only the remaining batches should be uploaded.
+	failOn = -1
+	uploaded = make([][]int, 0)
+
+	err = UploadBatches(uploadKey, items, 2, 0, upload)
+	if err != nil {
+		test.Fatalf("Failed to resume upload: '%v'.", err)
+	}
+
+	expected = [][]int{{4, 5}, {6}}
+	if !reflect.DeepEqual(expected, uploaded) {
+		test.Fatalf("Unexpected uploads after resume. Expected: '%v', Actual: '%v'.", expected, uploaded)
+	}
+
+	// After a full success, the same upload is sent in full again.
+	uploaded = make([][]int, 0)
+
+	err = UploadBatches(uploadKey, items, 2, 0, upload)
+	if err != nil {
+		test.Fatalf("Failed to repeat upload: '%v'.", err)
+	}
+
+	expected = [][]int{{0, 1}, {2, 3}, {4, 5}, {6}}
+	if !reflect.DeepEqual(expected, uploaded) {
+		test.Fatalf("Unexpected uploads after repeat. Expected: '%v', Actual: '%v'.", expected, uploaded)
+	}
+}
+
+func TestUploadBatchesChangedItems(test *testing.T) {
+	uploadKey := "test::changed"
+
+	uploaded := make([][]int, 0)
+	fail := true
+
+	upload := func(batch []int) error {
+		if fail && (batch[0] == 2) {
+			return fmt.Errorf("Test failure.")
+		}
+
+		uploaded = append(uploaded, batch)
+		return nil
+	}
+
+	err := UploadBatches(uploadKey, []int{0, 1, 2, 3}, 2, 0, upload)
+	if err == nil {
+		test.Fatalf("Did not get an expected error.")
+	}
+
+	// The first batch changed, so it must be uploaded again.
+	fail = false
+	uploaded = make([][]int, 0)
+
+	err = UploadBatches(uploadKey, []int{0, 9, 2, 3}, 2, 0, upload)
+	if err != nil {
+		test.Fatalf("Failed to resume upload: '%v'.", err)
+	}
+
+	expected := [][]int{{0, 9}, {2, 3}}
+	if !reflect.DeepEqual(expected, uploaded) {
+		test.Fatalf("Unexpected uploads. Expected: '%v', Actual: '%v'.", expected, uploaded)
+	}
+}
@@ -0,0 +1,197 @@
+// A shared HTTP layer for LMS backends.
+// Requests that are throttled (429) are retried with exponential backoff,
+// as are GET requests that hit a server error (5xx).
+// Other requests are not retried on server errors, since the LMS may have already applied them.
+// Requests are also slowed down when an LMS reports that the remaining rate limit is low.
+package lmshttp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DEFAULT_MAX_RETRIES     = 5
+	DEFAULT_INITIAL_BACKOFF = 1 * time.Second
+	DEFAULT_MAX_BACKOFF     = 60 * time.Second
+
+	HEADER_RETRY_AFTER = "Retry-After"
+)
+
+type Client struct {
+	// Headers sent with every request (e.g., authentication).
+	Headers map[string][]string
+
+	// The number of times a failed request will be retried (so a request may be attempted MaxRetries + 1 times).
+	MaxRetries int
+	// The backoff before the first retry, each following retry doubles the backoff (up to MaxBackoff).
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// If not empty, the response header that holds the remaining rate limit (e.g., Canvas's "X-Rate-Limit-Remaining").
+	RateLimitHeader string
+	// When the remaining rate limit drops below this threshold, requests will be delayed.
+	// The delay grows linearly (up to RateLimitMaxDelay) as the remaining limit approaches zero.
+	RateLimitThreshold float64
+	RateLimitMaxDelay  time.Duration
+}
+
+// Get a client with the default retry policy and no rate limit awareness.
+func NewClient(headers map[string][]string) *Client {
+	return &Client{
+		Headers:        headers,
+		MaxRetries:     DEFAULT_MAX_RETRIES,
+		InitialBackoff: DEFAULT_INITIAL_BACKOFF,
+		MaxBackoff:     DEFAULT_MAX_BACKOFF,
+	}
+}
+
+// Returns: (body, headers (response), error)
+func (this *Client) Get(url string) (string, map[string][]string, error) {
+	return this.do("GET", url, func() (string, map[string][]string, error) {
+		return util.GetWithHeaders(url, this.Headers)
+	})
+}
+
+// Returns: (body, headers (response), error)
+func (this *Client) Post(url string, form map[string]string) (string, map[string][]string, error) {
+	return this.do("POST", url, func() (string, map[string][]string, error) {
+		return util.PostWithHeaders(url, form, this.Headers)
+	})
+}
+
+// Returns: (body, headers (response), error)
+func (this *Client) Put(url string, form map[string]string) (string, map[string][]string, error) {
+	return this.do("PUT", url, func() (string, map[string][]string, error) {
+		return util.PutWithHeaders(url, form, this.Headers)
+	})
+}
+
+// Post a multipart form with a single file.
+// Returns: (body, headers (response), error)
+func (this *Client) PostFile(url string, form map[string]string, fieldName string, filename string, content []byte) (string, map[string][]string, error) {
+	return this.do("POST", url, func() (string, map[string][]string, error) {
+		return util.PostFileContentWithHeaders(url, form, fieldName, filename, content, this.Headers)
+	})
+}
+
+func (this *Client) do(verb string, url string, request func() (string, map[string][]string, error)) (string, map[string][]string, error) {
+	backoff := this.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		body, headers, err := request()
+		if err == nil {
+			this.paceRateLimit(headers)
+			return body, headers, nil
+		}
+
+		var statusErr *util.HTTPStatusError
+		if !errors.As(err, &statusErr) || !this.shouldRetry(verb, statusErr) || (attempt >= this.MaxRetries) {
+			return "", nil, err
+		}
+
+		delay := min(backoff, this.MaxBackoff)
+
+		retryAfter := getRetryAfter(statusErr.Headers)
+		if retryAfter > delay {
+			delay = min(retryAfter, this.MaxBackoff)
+		}
+
+		log.Warn("Retrying LMS request.",
+			log.NewAttr("method", verb), log.NewAttr("url", url), log.NewAttr("code", statusErr.Code),
+			log.NewAttr("attempt", attempt+1), log.NewAttr("delay-ms", delay.Milliseconds()))
+
+		time.Sleep(delay)
+		backoff *= 2
+	}
+}
+
+// If the remaining rate limit is getting low, then wait before allowing another request.
+func (this *Client) paceRateLimit(headers map[string][]string) {
+	delay := this.getRateLimitDelay(headers)
+	if delay <= 0 {
+		return
+	}
+
+	log.Debug("Slowing down LMS requests because of the rate limit.", log.NewAttr("delay-ms", delay.Milliseconds()))
+	time.Sleep(delay)
+}
+
+func (this *Client) getRateLimitDelay(headers map[string][]string) time.Duration {
+	if (this.RateLimitHeader == "") || (this.RateLimitThreshold <= 0) {
+		return 0
+	}
+
+	remaining, ok := this.getRateLimitRemaining(headers)
+	if !ok || (remaining >= this.RateLimitThreshold) {
+		return 0
+	}
+
+	remaining = max(0.0, remaining)
+	fraction := 1.0 - (remaining / this.RateLimitThreshold)
+
+	return time.Duration(fraction * float64(this.RateLimitMaxDelay))
+}
+
+// Returns: (remaining, found).
+func (this *Client) getRateLimitRemaining(headers map[string][]string) (float64, bool) {
+	value := http.Header(headers).Get(this.RateLimitHeader)
+	if value == "" {
+		return 0, false
+	}
+
+	remaining, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		log.Warn("Failed to parse rate limit header.", err, log.NewAttr("header", this.RateLimitHeader), log.NewAttr("value", value))
+		return 0, false
+	}
+
+	return remaining, true
+}
+
+// Some LMSs (e.g., Canvas) report throttling as a 403 with an exhausted rate limit.
+func (this *Client) shouldRetry(verb string, statusErr *util.HTTPStatusError) bool {
+	if IsRetryableStatus(verb, statusErr.Code) {
+		return true
+	}
+
+	if (statusErr.Code != http.StatusForbidden) || (this.RateLimitHeader == "") {
+		return false
+	}
+
+	remaining, ok := this.getRateLimitRemaining(statusErr.Headers)
+	return ok && (remaining < 1.0)
+}
+
+// Throttled requests (429) were not applied, so they can always be retried.
+// Server errors (5xx) are transient, but may happen after a request has been (partially) applied,
+// so only GETs are retried on them.
+func IsRetryableStatus(verb string, code int) bool {
+	if code == http.StatusTooManyRequests {
+		return true
+	}
+
+	return (verb == "GET") && (code >= 500)
+}
+
+// Get the delay requested by a Retry-After header (only the delay-seconds form is supported).
+// Returns zero if there is no (valid) header.
+func getRetryAfter(headers map[string][]string) time.Duration {
+	value := http.Header(headers).Get(HEADER_RETRY_AFTER)
+	if value == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if (err != nil) || (seconds < 0) {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,216 @@
+package lmshttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const TEST_RATE_LIMIT_HEADER = "X-Rate-Limit-Remaining"
+
+// A fake server that responds with a fixed sequence of status codes (then 200s).
+type testServer struct {
+	Server *httptest.Server
+
+	lock     sync.Mutex
+	codes    []int
+	headers  map[string]string
+	requests int
+}
+
+func newTestServer(codes []int, headers map[string]string) *testServer {
+	server := &testServer{
+		codes:   codes,
+		headers: headers,
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		server.lock.Lock()
+		defer server.lock.Unlock()
+
+		code := http.StatusOK
+		if server.requests < len(server.codes) {
+			code = server.codes[server.requests]
+		}
+
+		server.requests++
+
+		for key, value := range server.headers {
+			response.Header().Set(key, value)
+		}
+
+		response.WriteHeader(code)
+		fmt.Fprintf(response, "%d", server.requests)
+	}))
+
+	return server
+}
+
+func (this *testServer) getRequests() int {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return this.requests
+}
+
+func newTestClient() *Client {
+	client := NewClient(nil)
+	client.MaxRetries = 2
+	client.InitialBackoff = time.Millisecond
+	client.MaxBackoff = 5 * time.Millisecond
+
+	return client
+}
+
+func TestClientRetries(test *testing.T) {
+	testCases := []struct {
+		codes             []int
+		headers           map[string]string
+		rateLimitHeader   string
+		expectedRequests  int
+		expectedSucceeded bool
+	}{
+		{nil, nil, "", 1, true},
+		{[]int{http.StatusTooManyRequests}, nil, "", 2, true},
+		{[]int{http.StatusInternalServerError, http.StatusServiceUnavailable}, nil, "", 3, true},
+		{[]int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, nil, "", 3, false},
+
+		// Retry-After is capped at the max backoff.
+		{[]int{http.StatusTooManyRequests}, map[string]string{HEADER_RETRY_AFTER: "100"}, "", 2, true},
+
+		// Client errors are not retried.
+		{[]int{http.StatusNotFound}, nil, "", 1, false},
+		{[]int{http.StatusForbidden}, nil, "", 1, false},
+
+		// Canvas-style throttling.
+		{[]int{http.StatusForbidden}, map[string]string{TEST_RATE_LIMIT_HEADER: "0.0"}, TEST_RATE_LIMIT_HEADER, 2, true},
+		{[]int{http.StatusForbidden}, map[string]string{TEST_RATE_LIMIT_HEADER: "0.0"}, "", 1, false},
+		{[]int{http.StatusForbidden}, map[string]string{TEST_RATE_LIMIT_HEADER: "500.0"}, TEST_RATE_LIMIT_HEADER, 1, false},
+	}
+
+	for i, testCase := range testCases {
+		server := newTestServer(testCase.codes, testCase.headers)
+
+		client := newTestClient()
+		client.RateLimitHeader = testCase.rateLimitHeader
+
+		body, _, err := client.Get(server.Server.URL)
+		server.Server.Close()
+
+		if testCase.expectedSucceeded && (err != nil) {
+			test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			continue
+		}
+
+		if !testCase.expectedSucceeded && (err == nil) {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if server.getRequests() != testCase.expectedRequests {
+			test.Errorf("Case %d: Unexpected number of requests. Expected: %d, Actual: %d.", i, testCase.expectedRequests, server.getRequests())
+			continue
+		}
+
+		if testCase.expectedSucceeded && (body != fmt.Sprintf("%d", testCase.expectedRequests)) {
+			test.Errorf("Case %d: Unexpected body: '%s'.", i, body)
+			continue
+		}
+	}
+}
+
+// Only throttled requests are retried for non-GET requests,
+// since a request that got a server error may have already been applied.
+func TestClientNonGetRetries(test *testing.T) {
+	testCases := []struct {
+		verb              string
+		codes             []int
+		headers           map[string]string
+		rateLimitHeader   string
+		expectedRequests  int
+		expectedSucceeded bool
+	}{
+		{"POST", nil, nil, "", 1, true},
+		{"POST", []int{http.StatusTooManyRequests}, nil, "", 2, true},
+		{"POST", []int{http.StatusForbidden}, map[string]string{TEST_RATE_LIMIT_HEADER: "0.0"}, TEST_RATE_LIMIT_HEADER, 2, true},
+		{"PUT", []int{http.StatusTooManyRequests}, nil, "", 2, true},
+
+		{"POST", []int{http.StatusInternalServerError}, nil, "", 1, false},
+		{"POST", []int{http.StatusTooManyRequests, http.StatusInternalServerError}, nil, "", 2, false},
+		{"POST", []int{http.StatusServiceUnavailable}, nil, "", 1, false},
+		{"PUT", []int{http.StatusBadGateway}, nil, "", 1, false},
+		{"FILE", []int{http.StatusInternalServerError}, nil, "", 1, false},
+	}
+
+	for i, testCase := range testCases {
+		server := newTestServer(testCase.codes, testCase.headers)
+
+		client := newTestClient()
+		client.RateLimitHeader = testCase.rateLimitHeader
+
+		var err error
+		switch testCase.verb {
+		case "POST":
+			_, _, err = client.Post(server.Server.URL, map[string]string{"a": "b"})
+		case "PUT":
+			_, _, err = client.Put(server.Server.URL, map[string]string{"a": "b"})
+		case "FILE":
+			_, _, err = client.PostFile(server.Server.URL, map[string]string{"a": "b"}, "file", "a.txt", []byte("abc"))
+		default:
+			test.Fatalf("Case %d: Unknown verb '%s'.", i, testCase.verb)
+		}
+
+		server.Server.Close()
+
+		if testCase.expectedSucceeded && (err != nil) {
+			test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			continue
+		}
+
+		if !testCase.expectedSucceeded && (err == nil) {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if server.getRequests() != testCase.expectedRequests {
+			test.Errorf("Case %d: Unexpected number of requests. Expected: %d, Actual: %d.", i, testCase.expectedRequests, server.getRequests())
+			continue
+		}
+	}
+}
+
+func TestGetRateLimitDelay(test *testing.T) {
+	client := newTestClient()
+	client.RateLimitHeader = TEST_RATE_LIMIT_HEADER
+	client.RateLimitThreshold = 100
+	client.RateLimitMaxDelay = 10 * time.Second
+
+	testCases := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"ZZZ", 0},
+		{"700.0", 0},
+		{"100", 0},
+		{"50", 5 * time.Second},
+		{"75.0", 2500 * time.Millisecond},
+		{"0", 10 * time.Second},
+		{"-5", 10 * time.Second},
+	}
+
+	for i, testCase := range testCases {
+		headers := map[string][]string{}
+		if testCase.value != "" {
+			headers[TEST_RATE_LIMIT_HEADER] = []string{testCase.value}
+		}
+
+		delay := client.getRateLimitDelay(headers)
+		if delay != testCase.expected {
+			test.Errorf("Case %d: Unexpected delay. Expected: '%v', Actual: '%v'.", i, testCase.expected, delay)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package lmshttp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const HEADER_LINK = "Link"
+
+// Fetch every page of a paginated resource, starting at the given URL.
+// Following pages are found using the "next" link in each response's Link header.
+// If rewriteLink is not nil, every page URL is passed through it before being fetched (usually for testing).
+func (this *Client) FetchPages(url string, rewriteLink func(string) (string, error), handlePage func(body string) error) error {
+	for url != "" {
+		var err error
+
+		if rewriteLink != nil {
+			url, err = rewriteLink(url)
+			if err != nil {
+				return err
+			}
+		}
+
+		body, headers, err := this.Get(url)
+		if err != nil {
+			return err
+		}
+
+		err = handlePage(body)
+		if err != nil {
+			return err
+		}
+
+		url = NextLink(headers)
+	}
+
+	return nil
+}
+
+// Fetch every page of a paginated resource where each page is a JSON list.
+// Nil entries are skipped.
+func FetchJSONPages[T any](client *Client, url string, rewriteLink func(string) (string, error)) ([]*T, error) {
+	results := make([]*T, 0)
+
+	err := client.FetchPages(url, rewriteLink, func(body string) error {
+		var page []*T
+		err := util.JSONFromString(body, &page)
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal page: '%w'.", err)
+		}
+
+		for _, item := range page {
+			if item != nil {
+				results = append(results, item)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// See if the response headers have a next link (RFC 8288).
+// Returns the link or an empty string.
+func NextLink(headers map[string][]string) string {
+	values, ok := headers[HEADER_LINK]
+	if !ok {
+		return ""
+	}
+
+	for _, value := range values {
+		links := strings.Split(value, ",")
+		for _, link := range links {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				if strings.TrimSpace(param) == `rel="next"` {
+					return strings.Trim(strings.TrimSpace(parts[0]), "<>")
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,68 @@
+package lmshttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type testItem struct {
+	ID int `json:"id"`
+}
+
+func TestFetchJSONPages(test *testing.T) {
+	attempts := make(map[string]int)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		page, _ := strconv.Atoi(request.URL.Query().Get("page"))
+
+		// Throttle the first attempt at every page.
+		attempts[request.URL.RawQuery]++
+		if attempts[request.URL.RawQuery] == 1 {
+			response.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		if page < 2 {
+			response.Header().Set(HEADER_LINK, fmt.Sprintf(`<%s/items?page=%d>; rel="next", <%s/items?page=0>; rel="first"`, server.URL, page+1, server.URL))
+		}
+
+		fmt.Fprintf(response, `[{"id": %d}, null, {"id": %d}]`, (page * 2), (page*2)+1)
+	}))
+	defer server.Close()
+
+	items, err := FetchJSONPages[testItem](newTestClient(), server.URL+"/items?page=0", nil)
+	if err != nil {
+		test.Fatalf("Failed to fetch pages: '%v'.", err)
+	}
+
+	expected := []*testItem{{0}, {1}, {2}, {3}, {4}, {5}}
+	if !reflect.DeepEqual(expected, items) {
+		test.Fatalf("Unexpected items. Expected: '%v', Actual: '%v'.", expected, items)
+	}
+}
+
+func TestNextLink(test *testing.T) {
+	testCases := []struct {
+		headers  map[string][]string
+		expected string
+	}{
+		{map[string][]string{}, ""},
+		{map[string][]string{HEADER_LINK: []string{`<http://a.com/1>; rel="first"`}}, ""},
+		{map[string][]string{HEADER_LINK: []string{`<http://a.com/2>; rel="next"`}}, "http://a.com/2"},
+		{map[string][]string{HEADER_LINK: []string{`<http://a.com/1>; rel="current",<http://a.com/2>; rel="next"`}}, "http://a.com/2"},
+		{map[string][]string{HEADER_LINK: []string{`<http://a.com/1>; rel="current"`, `<http://a.com/2>; rel="next"`}}, "http://a.com/2"},
+		{map[string][]string{HEADER_LINK: []string{`<http://a.com/2>; type="json"; rel="next"`}}, "http://a.com/2"},
+	}
+
+	for i, testCase := range testCases {
+		actual := NextLink(testCase.headers)
+		if actual != testCase.expected {
+			test.Errorf("Case %d: Unexpected link. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -15,7 +15,15 @@ func syncAssignments(course *model.Course, dryRun bool) (*model.AssignmentSyncRe
 	result := model.NewAssignmentSyncResult()
 
 	adapter := course.GetLMSAdapter()
-	if (adapter == nil) || (!adapter.SyncAssignments) {
+	if adapter == nil {
+		return result, nil
+	}
+
+	if adapter.PushAssignments {
+		return pushAssignments(course, dryRun)
+	}
+
+	if !adapter.SyncAssignments {
 		return result, nil
 	}
 
@@ -0,0 +1,143 @@
+package lmssync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSyncFileLMSUsers(test *testing.T) {
+	reset()
+	defer reset()
+
+	course := db.MustGetTestCourse()
+
+	rosterPath := filepath.Join(course.GetBaseSourceDir(), "test-roster.csv")
+	roster := "email,role,lms-id,sections,group:Projects\ncourse-student@test.edulinq.org,student,1001,Lecture 01;Lab 01,Team 1\n"
+
+	err := util.WriteFile(roster, rosterPath)
+	if err != nil {
+		test.Fatalf("Failed to write roster: '%v'.", err)
+	}
+
+	adapter := course.GetLMSAdapter()
+	adapter.Type = model.LMS_TYPE_FILE
+	adapter.RosterPath = "test-roster.csv"
+	adapter.SyncUserAttributes = true
+
+	_, err = SyncLMSUserEmail(course, "course-student@test.edulinq.org", false, false)
+	if err != nil {
+		test.Fatalf("Failed to sync user: '%v'.", err)
+	}
+
+	user, err := db.GetCourseUser(course, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get synced user: '%v'.", err)
+	}
+
+	expected := &model.CourseUser{
+		Email:    "course-student@test.edulinq.org",
+		Name:     user.Name,
+		Role:     model.CourseRoleStudent,
+		LMSID:    util.StringPointer("1001"),
+		Sections: []string{"Lab 01", "Lecture 01"},
+		Groups:   map[string]string{"Projects": "Team 1"},
+	}
+
+	if !reflect.DeepEqual(expected, user) {
+		test.Fatalf("Synced user not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(user))
+	}
+}
+
+func TestFileLMSPathContainment(test *testing.T) {
+	reset()
+	defer reset()
+
+	course := db.MustGetTestCourse()
+
+	outsidePath := filepath.Join(test.TempDir(), "outside.csv")
+	err := util.WriteFile("email\n", outsidePath)
+	if err != nil {
+		test.Fatalf("Failed to write outside file: '%v'.", err)
+	}
+
+	err = os.Symlink(outsidePath, filepath.Join(course.GetBaseSourceDir(), "link.csv"))
+	if err != nil {
+		test.Fatalf("Failed to create link: '%v'.", err)
+	}
+
+	err = os.Symlink(filepath.Dir(outsidePath), filepath.Join(course.GetBaseSourceDir(), "link-dir"))
+	if err != nil {
+		test.Fatalf("Failed to create dir link: '%v'.", err)
+	}
+
+	testCases := []struct {
+		rosterPath     string
+		gradebookPath  string
+		errorSubstring string
+	}{
+		{"", "", ""},
+		{"roster.csv", "grades/gradebook.csv", ""},
+		{"a/../roster.csv", "", ""},
+
+		{outsidePath, "", "must be relative"},
+		{"../roster.csv", "", "must be relative"},
+		{"a/../../roster.csv", "", "must be relative"},
+		{"", "/tmp/gradebook.csv", "must be relative"},
+		{"", "../course-languages/gradebook.csv", "must be relative"},
+		{"link.csv", "", "resolves outside"},
+		{"link-dir/outside.csv", "", "resolves outside"},
+	}
+
+	for i, testCase := range testCases {
+		adapter := &model.LMSAdapter{
+			Type:          model.LMS_TYPE_FILE,
+			RosterPath:    testCase.rosterPath,
+			GradebookPath: testCase.gradebookPath,
+		}
+
+		rosterPath, gradebookPath, err := adapter.GetFilePaths(course)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if !util.PathHasParentOrSelf(rosterPath, course.GetBaseSourceDir()) {
+			test.Errorf("Case %d: Roster path is outside the source dir: '%s'.", i, rosterPath)
+		}
+
+		if !strings.HasPrefix(gradebookPath, course.GetLMSFilesDir()) {
+			test.Errorf("Case %d: Gradebook path is outside the LMS files dir: '%s'.", i, gradebookPath)
+		}
+	}
+
+	// Absolute and escaping paths are also rejected when validating.
+	adapter := &model.LMSAdapter{Type: model.LMS_TYPE_FILE, RosterPath: outsidePath}
+	err = adapter.Validate()
+	if err == nil {
+		test.Errorf("Did not get an error when validating an absolute roster path.")
+	}
+
+	adapter = &model.LMSAdapter{Type: model.LMS_TYPE_FILE, GradebookPath: "../gradebook.csv"}
+	err = adapter.Validate()
+	if err == nil {
+		test.Errorf("Did not get an error when validating an escaping gradebook path.")
+	}
+}
@@ -0,0 +1,210 @@
+package lmssync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Push the course's assignments to the LMS (the autograder is the source of truth).
+// Assignments without an LMS ID are first matched (by name) to existing LMS assignments that are not linked to another assignment.
+// This keeps pushes idempotent, since LMS IDs linked by a push are lost when a course is reloaded from its source.
+// Matched assignments are linked and updated, ambiguous matches are skipped,
+// and assignments without a match are created in the LMS (and linked to the new LMS assignment).
+// Assignments with an LMS ID have their LMS assignment updated (if anything changed).
+// Only fields set on the autograder assignment are pushed.
+func pushAssignments(course *model.Course, dryRun bool) (*model.AssignmentSyncResult, error) {
+	result := model.NewAssignmentSyncResult()
+
+	linkedAssignments := false
+
+	// Only fetched if there are unlinked assignments.
+	var lmsAssignments []*lmstypes.Assignment = nil
+
+	linkedIDs := make(map[string]bool)
+	for _, assignment := range course.GetAssignments() {
+		if assignment.GetLMSID() != "" {
+			linkedIDs[assignment.GetLMSID()] = true
+		}
+	}
+
+	for _, assignment := range course.GetSortedAssignments() {
+		info := model.AssignmentInfo{ID: assignment.GetID(), Name: assignment.GetName()}
+		lmsAssignment := toLMSAssignment(assignment)
+
+		var currentAssignment *lmstypes.Assignment = nil
+
+		if assignment.GetLMSID() == "" {
+			if lmsAssignments == nil {
+				var err error
+				lmsAssignments, err = lms.FetchAssignments(course)
+				if err != nil {
+					err = fmt.Errorf("Failed to fetch LMS assignments: '%w'.", err)
+					return nil, saveLinkedAssignments(course, linkedAssignments, err)
+				}
+
+				if lmsAssignments == nil {
+					lmsAssignments = make([]*lmstypes.Assignment, 0)
+				}
+			}
+
+			matches := findUnlinkedLMSAssignments(lmsAssignments, linkedIDs, assignment.GetName())
+			if len(matches) > 1 {
+				result.AmbiguousMatches = append(result.AmbiguousMatches, info)
+				continue
+			}
+
+			if len(matches) == 0 {
+				if !dryRun {
+					createdAssignment, err := lms.CreateAssignment(course, lmsAssignment)
+					if err != nil {
+						err = fmt.Errorf("Failed to create LMS assignment for assignment '%s': '%w'.", assignment.GetID(), err)
+						return nil, saveLinkedAssignments(course, linkedAssignments, err)
+					}
+
+					assignment.LMSID = createdAssignment.ID
+					linkedIDs[createdAssignment.ID] = true
+					linkedAssignments = true
+
+					log.Info("Created LMS assignment.", assignment, log.NewAttr("lms-id", createdAssignment.ID))
+				}
+
+				result.CreatedAssignments = append(result.CreatedAssignments, info)
+				continue
+			}
+
+			currentAssignment = matches[0]
+			lmsAssignment.ID = currentAssignment.ID
+			linkedIDs[currentAssignment.ID] = true
+
+			if !dryRun {
+				assignment.LMSID = currentAssignment.ID
+				linkedAssignments = true
+
+				log.Info("Linked assignment to an existing LMS assignment.", assignment, log.NewAttr("lms-id", currentAssignment.ID))
+			}
+		} else {
+			var err error
+			currentAssignment, err = lms.FetchAssignment(course, lmsAssignment.ID)
+			if err != nil {
+				err = fmt.Errorf("Failed to fetch LMS assignment for assignment '%s': '%w'.", assignment.GetID(), err)
+				return nil, saveLinkedAssignments(course, linkedAssignments, err)
+			}
+
+			if currentAssignment == nil {
+				// The linked LMS assignment does not exist.
+				result.NonMatchedAssignments = append(result.NonMatchedAssignments, info)
+				continue
+			}
+		}
+
+		if !assignmentNeedsPush(currentAssignment, lmsAssignment) {
+			result.UnchangedAssignments = append(result.UnchangedAssignments, info)
+			continue
+		}
+
+		if !dryRun {
+			err := lms.UpdateAssignment(course, lmsAssignment)
+			if err != nil {
+				err = fmt.Errorf("Failed to update LMS assignment for assignment '%s': '%w'.", assignment.GetID(), err)
+				return nil, saveLinkedAssignments(course, linkedAssignments, err)
+			}
+		}
+
+		result.SyncedAssignments = append(result.SyncedAssignments, info)
+	}
+
+	err := saveLinkedAssignments(course, linkedAssignments, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get the LMS assignments with the given name (ignoring case) that are not already linked to an assignment.
+func findUnlinkedLMSAssignments(lmsAssignments []*lmstypes.Assignment, linkedIDs map[string]bool, name string) []*lmstypes.Assignment {
+	matches := make([]*lmstypes.Assignment, 0)
+
+	if name == "" {
+		return matches
+	}
+
+	for _, lmsAssignment := range lmsAssignments {
+		if linkedIDs[lmsAssignment.ID] {
+			continue
+		}
+
+		if strings.EqualFold(name, lmsAssignment.Name) {
+			matches = append(matches, lmsAssignment)
+		}
+	}
+
+	return matches
+}
+
+// Save any newly linked LMS IDs.
+// This is done even if pushing failed part way through, so that a later push does not create duplicate LMS assignments.
+// Returns the original error (if not nil), or any error from saving.
+func saveLinkedAssignments(course *model.Course, linkedAssignments bool, pushErr error) error {
+	if !linkedAssignments {
+		return pushErr
+	}
+
+	err := db.SaveCourse(course)
+	if err != nil {
+		err = fmt.Errorf("Failed to save course: '%w'.", err)
+		if pushErr != nil {
+			log.Error("Failed to save linked LMS assignments after a failed push.", err, course)
+			return pushErr
+		}
+
+		return err
+	}
+
+	return pushErr
+}
+
+func toLMSAssignment(assignment *model.Assignment) *lmstypes.Assignment {
+	return &lmstypes.Assignment{
+		ID:          assignment.GetLMSID(),
+		Name:        assignment.GetName(),
+		DueDate:     assignment.DueDate,
+		MaxPoints:   assignment.MaxPoints,
+		Description: assignment.Description,
+	}
+}
+
+// Check if any set field of the local assignment differs from the LMS assignment.
+// Due dates are compared to the second (LMSs usually do not store milliseconds).
+func assignmentNeedsPush(lmsAssignment *lmstypes.Assignment, localAssignment *lmstypes.Assignment) bool {
+	if localAssignment.Name != lmsAssignment.Name {
+		return true
+	}
+
+	if localAssignment.DueDate != nil {
+		if lmsAssignment.DueDate == nil {
+			return true
+		}
+
+		if (localAssignment.DueDate.ToMSecs() / 1000) != (lmsAssignment.DueDate.ToMSecs() / 1000) {
+			return true
+		}
+	}
+
+	if !util.IsZero(localAssignment.MaxPoints) && !util.IsZero(localAssignment.MaxPoints-lmsAssignment.MaxPoints) {
+		return true
+	}
+
+	if (localAssignment.Description != "") && (localAssignment.Description != lmsAssignment.Description) {
+		return true
+	}
+
+	return false
+}
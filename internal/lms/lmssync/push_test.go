@@ -0,0 +1,221 @@
+package lmssync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_PUSHED_LMS_ID = "test-lms-assignment-001"
+
+func TestPushAssignments(test *testing.T) {
+	reset()
+	lmstest.ClearAssignments()
+	defer reset()
+	defer lmstest.ClearAssignments()
+
+	hw0 := []model.AssignmentInfo{model.AssignmentInfo{ID: "hw0", Name: "Homework 0"}}
+
+	course := db.MustGetTestCourse()
+	course.GetLMSAdapter().PushAssignments = true
+
+	// Dry run, nothing is created or linked.
+	result, err := syncAssignments(course, true)
+	if err != nil {
+		test.Fatalf("Failed to do a dry run push: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "dry run", result, hw0, nil, nil, nil)
+
+	if course.GetAssignment("hw0").GetLMSID() != "" {
+		test.Fatalf("Dry run linked an assignment.")
+	}
+
+	// Create.
+	dueDate := timestamp.MustGuessFromString("2023-10-06T06:59:59.123Z")
+	course.GetAssignment("hw0").DueDate = &dueDate
+	course.GetAssignment("hw0").MaxPoints = 10
+	course.GetAssignment("hw0").Description = "The first homework."
+
+	result, err = syncAssignments(course, false)
+	if err != nil {
+		test.Fatalf("Failed to push: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "create", result, hw0, nil, nil, nil)
+
+	savedCourse := db.MustGetTestCourse()
+	if savedCourse.GetAssignment("hw0").GetLMSID() != TEST_PUSHED_LMS_ID {
+		test.Fatalf("Created assignment was not linked. Expected: '%s', Actual: '%s'.",
+			TEST_PUSHED_LMS_ID, savedCourse.GetAssignment("hw0").GetLMSID())
+	}
+
+	expected := &lmstypes.Assignment{
+		ID:          TEST_PUSHED_LMS_ID,
+		Name:        "Homework 0",
+		LMSCourseID: "course101",
+		DueDate:     &dueDate,
+		MaxPoints:   10,
+		Description: "The first homework.",
+	}
+
+	checkTestLMSAssignment(test, "create", course, expected)
+
+	// No changes.
+	result, err = syncAssignments(course, false)
+	if err != nil {
+		test.Fatalf("Failed to push unchanged: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "unchanged", result, nil, nil, hw0, nil)
+
+	// Update.
+	course.GetAssignment("hw0").Name = "Homework Zero"
+	hw0[0].Name = "Homework Zero"
+
+	result, err = syncAssignments(course, true)
+	if err != nil {
+		test.Fatalf("Failed to dry run push update: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "update dry run", result, nil, hw0, nil, nil)
+	checkTestLMSAssignment(test, "update dry run", course, expected)
+
+	result, err = syncAssignments(course, false)
+	if err != nil {
+		test.Fatalf("Failed to push update: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "update", result, nil, hw0, nil, nil)
+
+	expected.Name = "Homework Zero"
+	checkTestLMSAssignment(test, "update", course, expected)
+
+	// Reloading the course from source loses the link, the existing LMS assignment is matched by name.
+	course.GetAssignment("hw0").LMSID = ""
+
+	result, err = syncAssignments(course, false)
+	if err != nil {
+		test.Fatalf("Failed to push reloaded: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "reloaded", result, nil, nil, hw0, nil)
+
+	if course.GetAssignment("hw0").GetLMSID() != TEST_PUSHED_LMS_ID {
+		test.Fatalf("Reloaded assignment was not linked. Expected: '%s', Actual: '%s'.",
+			TEST_PUSHED_LMS_ID, course.GetAssignment("hw0").GetLMSID())
+	}
+
+	// Multiple LMS assignments with the same name are ambiguous.
+	backend, err := lmstest.NewBackend(course.GetID())
+	if err != nil {
+		test.Fatalf("Failed to get test backend: '%v'.", err)
+	}
+
+	_, err = backend.CreateAssignment(&lmstypes.Assignment{Name: "homework zero"})
+	if err != nil {
+		test.Fatalf("Failed to create test LMS assignment: '%v'.", err)
+	}
+
+	course.GetAssignment("hw0").LMSID = ""
+
+	result, err = syncAssignments(course, false)
+	if err != nil {
+		test.Fatalf("Failed to push ambiguous: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(hw0, result.AmbiguousMatches) {
+		test.Fatalf("Unexpected ambiguous matches. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(hw0), util.MustToJSONIndent(result.AmbiguousMatches))
+	}
+
+	if course.GetAssignment("hw0").GetLMSID() != "" {
+		test.Fatalf("Ambiguous assignment was linked.")
+	}
+
+	// Missing LMS assignment.
+	course.GetAssignment("hw0").LMSID = "ZZZ"
+
+	result, err = syncAssignments(course, false)
+	if err != nil {
+		test.Fatalf("Failed to push missing: '%v'.", err)
+	}
+
+	checkAssignmentSyncResult(test, "missing", result, nil, nil, nil, hw0)
+}
+
+func TestAssignmentNeedsPush(test *testing.T) {
+	dueDate := timestamp.MustGuessFromString("2023-10-06T06:59:59Z")
+	dueDateMSecs := timestamp.MustGuessFromString("2023-10-06T06:59:59.999Z")
+	otherDueDate := timestamp.MustGuessFromString("2023-10-07T06:59:59Z")
+
+	lmsAssignment := &lmstypes.Assignment{
+		ID:          "1",
+		Name:        "A",
+		DueDate:     &dueDate,
+		MaxPoints:   10,
+		Description: "D",
+	}
+
+	testCases := []struct {
+		local    *lmstypes.Assignment
+		expected bool
+	}{
+		{&lmstypes.Assignment{ID: "1", Name: "A"}, false},
+		{&lmstypes.Assignment{ID: "1", Name: "A", DueDate: &dueDate, MaxPoints: 10, Description: "D"}, false},
+		{&lmstypes.Assignment{ID: "1", Name: "A", DueDate: &dueDateMSecs}, false},
+		{&lmstypes.Assignment{ID: "1", Name: "B"}, true},
+		{&lmstypes.Assignment{ID: "1", Name: "A", DueDate: &otherDueDate}, true},
+		{&lmstypes.Assignment{ID: "1", Name: "A", MaxPoints: 11}, true},
+		{&lmstypes.Assignment{ID: "1", Name: "A", Description: "E"}, true},
+	}
+
+	for i, testCase := range testCases {
+		actual := assignmentNeedsPush(lmsAssignment, testCase.local)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+
+	// A missing LMS due date is always pushed.
+	if !assignmentNeedsPush(&lmstypes.Assignment{Name: "A"}, &lmstypes.Assignment{Name: "A", DueDate: &dueDate}) {
+		test.Errorf("Missing LMS due date does not need a push.")
+	}
+}
+
+func checkAssignmentSyncResult(test *testing.T, label string, result *model.AssignmentSyncResult,
+	created []model.AssignmentInfo, synced []model.AssignmentInfo, unchanged []model.AssignmentInfo, nonMatched []model.AssignmentInfo) {
+	expected := model.NewAssignmentSyncResult()
+	expected.CreatedAssignments = append(expected.CreatedAssignments, created...)
+	expected.SyncedAssignments = append(expected.SyncedAssignments, synced...)
+	expected.UnchangedAssignments = append(expected.UnchangedAssignments, unchanged...)
+	expected.NonMatchedAssignments = append(expected.NonMatchedAssignments, nonMatched...)
+
+	if !reflect.DeepEqual(expected, result) {
+		test.Fatalf("Case '%s': Unexpected result. Expected: '%s', Actual: '%s'.",
+			label, util.MustToJSONIndent(expected), util.MustToJSONIndent(result))
+	}
+}
+
+func checkTestLMSAssignment(test *testing.T, label string, course *model.Course, expected *lmstypes.Assignment) {
+	backend, err := lmstest.NewBackend(course.GetID())
+	if err != nil {
+		test.Fatalf("Case '%s': Failed to get test backend: '%v'.", label, err)
+	}
+
+	actual, err := backend.FetchAssignment(expected.ID)
+	if err != nil {
+		test.Fatalf("Case '%s': Failed to fetch test LMS assignment: '%v'.", label, err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Case '%s': Unexpected LMS assignment. Expected: '%s', Actual: '%s'.",
+			label, util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+}
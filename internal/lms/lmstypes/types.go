@@ -10,6 +10,10 @@ type User struct {
 	Name  string
 	Email string
 	Role  model.CourseUserRole
+
+	// Nil when the LMS does not support sections/groups (or they were not fetched).
+	Sections []string
+	Groups   map[string]string
 }
 
 type SubmissionScore struct {
@@ -17,6 +21,11 @@ type SubmissionScore struct {
 	Score    float64
 	Time     *timestamp.Timestamp
 	Comments []*SubmissionComment
+
+	// Optional feedback, LMSs that do not support these will ignore them.
+	// Rubric points are keyed by the rubric criterion's name (e.g., a graded question's name).
+	Rubric      map[string]float64
+	Attachments []*SubmissionAttachment
 }
 
 type SubmissionComment struct {
@@ -26,12 +35,20 @@ type SubmissionComment struct {
 	Time   string
 }
 
+// A file attached to a submission (as part of a comment).
+type SubmissionAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
 type Assignment struct {
 	ID          string
 	Name        string
 	LMSCourseID string
 	DueDate     *timestamp.Timestamp
 	MaxPoints   float64
+	Description string
 }
 
 func (this *User) ToRawServerUserData(courseID string) *model.RawServerUserData {
@@ -41,6 +58,9 @@ func (this *User) ToRawServerUserData(courseID string) *model.RawServerUserData
 		Course:      courseID,
 		CourseRole:  this.Role.String(),
 		CourseLMSID: this.ID,
+
+		CourseSections: this.Sections,
+		CourseGroups:   this.Groups,
 	}
 
 	return data
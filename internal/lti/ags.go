@@ -0,0 +1,309 @@
+package lti
+
+// Assignment and Grade Services (AGS).
+// See: https://www.imsglobal.org/spec/lti-ags/v2p0
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	SCOPE_LINE_ITEM          = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem"
+	SCOPE_LINE_ITEM_READONLY = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem.readonly"
+	SCOPE_RESULT_READONLY    = "https://purl.imsglobal.org/spec/lti-ags/scope/result.readonly"
+	SCOPE_SCORE              = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+
+	CONTENT_TYPE_LINE_ITEM = "application/vnd.ims.lis.v2.lineitem+json"
+	CONTENT_TYPE_RESULTS   = "application/vnd.ims.lis.v2.resultcontainer+json"
+	CONTENT_TYPE_SCORE     = "application/vnd.ims.lis.v1.score+json"
+
+	CLIENT_ASSERTION_TYPE = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	CLIENT_ASSERTION_TTL_SECS = 5 * 60
+
+	// Refresh access tokens a little early to avoid using an expired token.
+	ACCESS_TOKEN_EARLY_REFRESH_SECS = 60
+)
+
+type LineItem struct {
+	ID             string  `json:"id"`
+	Label          string  `json:"label"`
+	ScoreMaximum   float64 `json:"scoreMaximum"`
+	ResourceID     string  `json:"resourceId,omitempty"`
+	ResourceLinkID string  `json:"resourceLinkId,omitempty"`
+	Tag            string  `json:"tag,omitempty"`
+	StartDateTime  string  `json:"startDateTime,omitempty"`
+	EndDateTime    string  `json:"endDateTime,omitempty"`
+}
+
+type Result struct {
+	ID            string   `json:"id"`
+	ScoreOf       string   `json:"scoreOf"`
+	UserID        string   `json:"userId"`
+	ResultScore   *float64 `json:"resultScore"`
+	ResultMaximum float64  `json:"resultMaximum"`
+	Comment       string   `json:"comment,omitempty"`
+}
+
+type Score struct {
+	UserID           string  `json:"userId"`
+	ScoreGiven       float64 `json:"scoreGiven"`
+	ScoreMaximum     float64 `json:"scoreMaximum"`
+	Comment          string  `json:"comment,omitempty"`
+	Timestamp        string  `json:"timestamp"`
+	ActivityProgress string  `json:"activityProgress"`
+	GradingProgress  string  `json:"gradingProgress"`
+}
+
+type clientAssertionClaims struct {
+	Issuer         string `json:"iss"`
+	Subject        string `json:"sub"`
+	Audience       string `json:"aud"`
+	IssuedAt       int64  `json:"iat"`
+	ExpirationTime int64  `json:"exp"`
+	JWTID          string `json:"jti"`
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+type cachedAccessToken struct {
+	Token   string
+	Expires timestamp.Timestamp
+}
+
+// Create a completed and fully graded score.
+func NewScore(userID string, score float64, maxPoints float64, comment string, scoreTime *timestamp.Timestamp) *Score {
+	if scoreTime == nil {
+		now := timestamp.Now()
+		scoreTime = &now
+	}
+
+	return &Score{
+		UserID:           userID,
+		ScoreGiven:       score,
+		ScoreMaximum:     maxPoints,
+		Comment:          comment,
+		Timestamp:        scoreTime.ToGoTime().UTC().Format(time.RFC3339Nano),
+		ActivityProgress: "Completed",
+		GradingProgress:  "FullyGraded",
+	}
+}
+
+func (this *Platform) FetchLineItem(lineItemURL string) (*LineItem, error) {
+	token, err := this.getAccessToken(SCOPE_LINE_ITEM_READONLY)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := serviceHeaders(token, CONTENT_TYPE_LINE_ITEM)
+	body, _, err := util.GetWithHeaders(lineItemURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch line item '%s': '%w'.", lineItemURL, err)
+	}
+
+	var lineItem LineItem
+	err = util.JSONFromString(body, &lineItem)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal line item '%s': '%w'.", lineItemURL, err)
+	}
+
+	return &lineItem, nil
+}
+
+// Update a line item (the line item's ID is its URL).
+func (this *Platform) UpdateLineItem(lineItem *LineItem) error {
+	token, err := this.getAccessToken(SCOPE_LINE_ITEM)
+	if err != nil {
+		return err
+	}
+
+	body, err := util.ToJSON(lineItem)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize line item: '%w'.", err)
+	}
+
+	headers := serviceHeaders(token, CONTENT_TYPE_LINE_ITEM)
+	headers["Content-Type"] = []string{CONTENT_TYPE_LINE_ITEM}
+
+	_, _, err = util.PutBodyWithHeaders(lineItem.ID, body, headers)
+	if err != nil {
+		return fmt.Errorf("Failed to update line item '%s': '%w'.", lineItem.ID, err)
+	}
+
+	return nil
+}
+
+// Fetch the results for a line item.
+// If userID is not empty, then only results for that user will be fetched.
+func (this *Platform) FetchResults(lineItemURL string, userID string) ([]*Result, error) {
+	token, err := this.getAccessToken(SCOPE_RESULT_READONLY)
+	if err != nil {
+		return nil, err
+	}
+
+	params := neturl.Values{}
+	if userID != "" {
+		params.Set("user_id", userID)
+	}
+
+	url, err := serviceURL(lineItemURL, "results", params)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := serviceHeaders(token, CONTENT_TYPE_RESULTS)
+	body, _, err := util.GetWithHeaders(url, headers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch results for line item '%s': '%w'.", lineItemURL, err)
+	}
+
+	var results []*Result
+	err = util.JSONFromString(body, &results)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal results for line item '%s': '%w'.", lineItemURL, err)
+	}
+
+	return results, nil
+}
+
+func (this *Platform) PostScore(lineItemURL string, score *Score) error {
+	token, err := this.getAccessToken(SCOPE_SCORE)
+	if err != nil {
+		return err
+	}
+
+	url, err := serviceURL(lineItemURL, "scores", nil)
+	if err != nil {
+		return err
+	}
+
+	body, err := util.ToJSON(score)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize score: '%w'.", err)
+	}
+
+	headers := serviceHeaders(token, "")
+	headers["Content-Type"] = []string{CONTENT_TYPE_SCORE}
+
+	_, _, err = util.PostBodyWithHeaders(url, body, headers)
+	if err != nil {
+		return fmt.Errorf("Failed to post score for user '%s' to line item '%s': '%w'.", score.UserID, lineItemURL, err)
+	}
+
+	return nil
+}
+
+// Get an access token for the platform's services using the OAuth2 client credentials grant
+// (authenticated with a JWT signed by the tool's key).
+func (this *Platform) getAccessToken(scope string) (string, error) {
+	if this.AuthTokenURL == "" {
+		return "", fmt.Errorf("LTI platform '%s' does not have an auth token URL.", this.Issuer)
+	}
+
+	this.tokensLock.Lock()
+	defer this.tokensLock.Unlock()
+
+	now := timestamp.Now()
+
+	cached := this.accessTokens[scope]
+	if (cached != nil) && (cached.Expires > now) {
+		return cached.Token, nil
+	}
+
+	key, keyID, err := GetToolKey()
+	if err != nil {
+		return "", err
+	}
+
+	nowSecs := now.ToMSecs() / 1000
+	claims := clientAssertionClaims{
+		Issuer:         this.ClientID,
+		Subject:        this.ClientID,
+		Audience:       this.AuthTokenURL,
+		IssuedAt:       nowSecs,
+		ExpirationTime: nowSecs + CLIENT_ASSERTION_TTL_SECS,
+		JWTID:          util.UUID(),
+	}
+
+	assertion, err := SignJWT(claims, key, keyID)
+	if err != nil {
+		return "", err
+	}
+
+	form := map[string]string{
+		"grant_type":            "client_credentials",
+		"client_assertion_type": CLIENT_ASSERTION_TYPE,
+		"client_assertion":      assertion,
+		"scope":                 scope,
+	}
+
+	body, _, err := util.PostWithHeaders(this.AuthTokenURL, form, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get access token from LTI platform '%s': '%w'.", this.Issuer, err)
+	}
+
+	var response accessTokenResponse
+	err = util.JSONFromString(body, &response)
+	if err != nil {
+		return "", fmt.Errorf("Failed to unmarshal access token from LTI platform '%s': '%w'.", this.Issuer, err)
+	}
+
+	if response.AccessToken == "" {
+		return "", fmt.Errorf("LTI platform '%s' did not return an access token.", this.Issuer)
+	}
+
+	if this.accessTokens == nil {
+		this.accessTokens = make(map[string]*cachedAccessToken)
+	}
+
+	expiresInSecs := max(0, response.ExpiresIn-ACCESS_TOKEN_EARLY_REFRESH_SECS)
+	this.accessTokens[scope] = &cachedAccessToken{
+		Token:   response.AccessToken,
+		Expires: now + timestamp.FromMSecs(expiresInSecs*1000),
+	}
+
+	return response.AccessToken, nil
+}
+
+func serviceHeaders(token string, accept string) map[string][]string {
+	headers := map[string][]string{
+		"Authorization": []string{fmt.Sprintf("Bearer %s", token)},
+	}
+
+	if accept != "" {
+		headers["Accept"] = []string{accept}
+	}
+
+	return headers
+}
+
+// Get a URL for a service under a line item (e.g. ".../lineitems/1/results").
+// Line item URLs may have a query string, so the path cannot just be appended.
+func serviceURL(lineItemURL string, service string, params neturl.Values) (string, error) {
+	url, err := neturl.Parse(lineItemURL)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse line item URL '%s': '%w'.", lineItemURL, err)
+	}
+
+	url.Path = strings.TrimSuffix(url.Path, "/") + "/" + service
+
+	query := url.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+
+	url.RawQuery = query.Encode()
+
+	return url.String(), nil
+}
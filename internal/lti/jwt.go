@@ -0,0 +1,223 @@
+package lti
+
+// A minimal implementation of the parts of JWS/JWT/JWK that LTI needs (RS256 only).
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const JWT_ALGORITHM = "RS256"
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+
+	// RSA modulus and exponent (base64url encoded).
+	N string `json:"n"`
+	E string `json:"e"`
+}
+
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// A JWT audience may be either a single string or a list.
+type Audience []string
+
+func (this *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	err := json.Unmarshal(data, &single)
+	if err == nil {
+		*this = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	err = json.Unmarshal(data, &multiple)
+	if err != nil {
+		return fmt.Errorf("JWT audience must be a string or list of strings: '%w'.", err)
+	}
+
+	*this = Audience(multiple)
+	return nil
+}
+
+func NewJWK(key *rsa.PublicKey, keyID string) *JWK {
+	return &JWK{
+		KeyType:   "RSA",
+		KeyID:     keyID,
+		Algorithm: JWT_ALGORITHM,
+		Use:       "sig",
+		N:         base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func (this *JWK) PublicKey() (*rsa.PublicKey, error) {
+	if this.KeyType != "RSA" {
+		return nil, fmt.Errorf("Unsupported JWK key type '%s'.", this.KeyType)
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(this.N)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode JWK modulus: '%w'.", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(this.E)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode JWK exponent: '%w'.", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || (exponent.Int64() <= 1) {
+		return nil, fmt.Errorf("Invalid JWK exponent.")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+// Get the key with the given ID.
+// If the ID is empty and there is exactly one key, then that key is returned.
+// Returns nil if no key matches.
+func (this *JWKSet) GetKey(keyID string) *JWK {
+	if this == nil {
+		return nil
+	}
+
+	if (keyID == "") && (len(this.Keys) == 1) {
+		return this.Keys[0]
+	}
+
+	for _, key := range this.Keys {
+		if (key != nil) && (key.KeyID == keyID) {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func SignJWT(claims any, key *rsa.PrivateKey, keyID string) (string, error) {
+	header := jwtHeader{
+		Algorithm: JWT_ALGORITHM,
+		KeyID:     keyID,
+		Type:      "JWT",
+	}
+
+	headerJSON, err := util.ToJSON(header)
+	if err != nil {
+		return "", fmt.Errorf("Failed to serialize JWT header: '%w'.", err)
+	}
+
+	claimsJSON, err := util.ToJSON(claims)
+	if err != nil {
+		return "", fmt.Errorf("Failed to serialize JWT claims: '%w'.", err)
+	}
+
+	signingInput := encodeSegment([]byte(headerJSON)) + "." + encodeSegment([]byte(claimsJSON))
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("Failed to sign JWT: '%w'.", err)
+	}
+
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// Get the key ID from a JWT's header without verifying it.
+func getJWTKeyID(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("JWT has %d parts, expected 3.", len(parts))
+	}
+
+	var header jwtHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return "", fmt.Errorf("Failed to decode JWT header: '%w'.", err)
+	}
+
+	return header.KeyID, nil
+}
+
+// Verify the signature of a JWT and parse its claims into the given pointer.
+// Only the signature is checked here, callers are responsible for checking claims (e.g. expiration).
+func VerifyJWT(token string, keySet *JWKSet, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("JWT has %d parts, expected 3.", len(parts))
+	}
+
+	var header jwtHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return fmt.Errorf("Failed to decode JWT header: '%w'.", err)
+	}
+
+	if header.Algorithm != JWT_ALGORITHM {
+		return fmt.Errorf("Unsupported JWT algorithm '%s'.", header.Algorithm)
+	}
+
+	jwk := keySet.GetKey(header.KeyID)
+	if jwk == nil {
+		return fmt.Errorf("Could not find key '%s' to verify JWT.", header.KeyID)
+	}
+
+	publicKey, err := jwk.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("Failed to decode JWT signature: '%w'.", err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return fmt.Errorf("Invalid JWT signature: '%w'.", err)
+	}
+
+	err = decodeSegment(parts[1], claims)
+	if err != nil {
+		return fmt.Errorf("Failed to decode JWT claims: '%w'.", err)
+	}
+
+	return nil
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string, target any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return util.JSONFromString(string(data), target)
+}
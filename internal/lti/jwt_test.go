@@ -0,0 +1,90 @@
+package lti
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+)
+
+const RSA_TEST_KEY_BITS = 2048
+
+func TestJWTSignVerify(test *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, RSA_TEST_KEY_BITS)
+	if err != nil {
+		test.Fatalf("Failed to generate key: '%v'.", err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, RSA_TEST_KEY_BITS)
+	if err != nil {
+		test.Fatalf("Failed to generate key: '%v'.", err)
+	}
+
+	claims := map[string]any{"sub": "abc", "aud": []string{"a", "b"}}
+
+	token, err := SignJWT(claims, key, "key-1")
+	if err != nil {
+		test.Fatalf("Failed to sign JWT: '%v'.", err)
+	}
+
+	parts := strings.Split(token, ".")
+
+	testCases := []struct {
+		token          string
+		keySet         *JWKSet
+		errorSubstring string
+	}{
+		{token, &JWKSet{Keys: []*JWK{NewJWK(&key.PublicKey, "key-1")}}, ""},
+		{token, &JWKSet{Keys: []*JWK{NewJWK(&otherKey.PublicKey, "key-2"), NewJWK(&key.PublicKey, "key-1")}}, ""},
+
+		{token, &JWKSet{Keys: []*JWK{NewJWK(&otherKey.PublicKey, "key-1")}}, "Invalid JWT signature"},
+		{token, &JWKSet{Keys: []*JWK{NewJWK(&key.PublicKey, "key-2"), NewJWK(&key.PublicKey, "key-3")}}, "Could not find key"},
+		{token, nil, "Could not find key"},
+		{parts[0] + "." + parts[1], &JWKSet{Keys: []*JWK{NewJWK(&key.PublicKey, "key-1")}}, "has 2 parts"},
+		{parts[0] + "." + encodeSegment([]byte(`{"sub":"zzz"}`)) + "." + parts[2], &JWKSet{Keys: []*JWK{NewJWK(&key.PublicKey, "key-1")}}, "Invalid JWT signature"},
+		{encodeSegment([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".", &JWKSet{Keys: []*JWK{NewJWK(&key.PublicKey, "key-1")}}, "Unsupported JWT algorithm"},
+	}
+
+	for i, testCase := range testCases {
+		var actual struct {
+			Subject  string   `json:"sub"`
+			Audience Audience `json:"aud"`
+		}
+
+		err := VerifyJWT(testCase.token, testCase.keySet, &actual)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to verify: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if (actual.Subject != "abc") || (len(actual.Audience) != 2) {
+			test.Errorf("Case %d: Unexpected claims: '%+v'.", i, actual)
+		}
+	}
+}
+
+func TestJWKRoundTrip(test *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, RSA_TEST_KEY_BITS)
+	if err != nil {
+		test.Fatalf("Failed to generate key: '%v'.", err)
+	}
+
+	publicKey, err := NewJWK(&key.PublicKey, "key").PublicKey()
+	if err != nil {
+		test.Fatalf("Failed to get public key: '%v'.", err)
+	}
+
+	if !key.PublicKey.Equal(publicKey) {
+		test.Fatalf("Public key does not match after round trip.")
+	}
+}
@@ -0,0 +1,97 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+)
+
+var toolKeyLock sync.Mutex
+var toolKey *rsa.PrivateKey = nil
+var toolKeyID string = ""
+
+// Get the tool's private key (used to sign service requests to platforms) and its ID.
+// Returns an error if no key is configured (see config.LTI_KEY_PATH).
+func GetToolKey() (*rsa.PrivateKey, string, error) {
+	toolKeyLock.Lock()
+	defer toolKeyLock.Unlock()
+
+	if toolKey != nil {
+		return toolKey, toolKeyID, nil
+	}
+
+	path := config.LTI_KEY_PATH.Get()
+	if path == "" {
+		return nil, "", fmt.Errorf("No LTI key has been configured.")
+	}
+
+	key, err := loadPrivateKey(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	toolKey = key
+	toolKeyID = config.LTI_KEY_ID.Get()
+
+	return toolKey, toolKeyID, nil
+}
+
+// Replace the tool's key (usually for testing).
+// Passing nil will cause the key to be reloaded from config on the next access.
+func SetToolKey(key *rsa.PrivateKey, keyID string) {
+	toolKeyLock.Lock()
+	defer toolKeyLock.Unlock()
+
+	toolKey = key
+	toolKeyID = keyID
+}
+
+// Get the public key set for the tool, this is what platforms use to verify our requests.
+// If no key is configured, then the set will be empty.
+func GetToolKeySet() *JWKSet {
+	keySet := &JWKSet{
+		Keys: make([]*JWK, 0, 1),
+	}
+
+	key, keyID, err := GetToolKey()
+	if err != nil {
+		return keySet
+	}
+
+	keySet.Keys = append(keySet.Keys, NewJWK(&key.PublicKey, keyID))
+	return keySet
+}
+
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read LTI key '%s': '%w'.", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("LTI key '%s' is not PEM encoded.", path)
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err == nil {
+		return key, nil
+	}
+
+	anyKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse LTI key '%s': '%w'.", path, err)
+	}
+
+	rsaKey, ok := anyKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("LTI key '%s' is not an RSA key.", path)
+	}
+
+	return rsaKey, nil
+}
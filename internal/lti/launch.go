@@ -0,0 +1,277 @@
+package lti
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	LTI_VERSION                = "1.3.0"
+	MESSAGE_TYPE_RESOURCE_LINK = "LtiResourceLinkRequest"
+
+	// The custom parameter (set on the platform's resource link) that identifies an autograder assignment.
+	CUSTOM_ASSIGNMENT_ID = "assignment-id"
+
+	// Allowed clock skew (in seconds) when checking token times.
+	CLOCK_SKEW_SECS = 60
+
+	CLAIM_PREFIX = "https://purl.imsglobal.org/spec/lti/claim/"
+	ROLE_PREFIX  = "http://purl.imsglobal.org/vocab/lis/v2/membership"
+)
+
+type LaunchClaims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        Audience `json:"aud"`
+	AuthorizedParty string   `json:"azp,omitempty"`
+	ExpirationTime  int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	Nonce           string   `json:"nonce"`
+
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+
+	MessageType  string            `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version      string            `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID string            `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	Roles        []string          `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	Context      *ContextClaim     `json:"https://purl.imsglobal.org/spec/lti/claim/context,omitempty"`
+	ResourceLink *ResourceClaim    `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link,omitempty"`
+	Custom       map[string]string `json:"https://purl.imsglobal.org/spec/lti/claim/custom,omitempty"`
+
+	// Assignment and Grade Services.
+	Endpoint *EndpointClaim `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint,omitempty"`
+}
+
+type ContextClaim struct {
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+type ResourceClaim struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+type EndpointClaim struct {
+	Scope     []string `json:"scope,omitempty"`
+	LineItems string   `json:"lineitems,omitempty"`
+	LineItem  string   `json:"lineitem,omitempty"`
+}
+
+// A validated launch, mapped to autograder concepts.
+type Launch struct {
+	Platform *Platform `json:"-"`
+
+	// Along with the subject, identifies the user (emails are not trusted for identifying users).
+	Issuer string `json:"issuer"`
+
+	CourseID string `json:"course-id"`
+	// Empty if the launch is not for a specific assignment.
+	AssignmentID string `json:"assignment-id,omitempty"`
+
+	Email string               `json:"email"`
+	Name  string               `json:"name,omitempty"`
+	Role  model.CourseUserRole `json:"role"`
+
+	// The user's ID within the platform (used as the user's LMS ID).
+	Subject string `json:"subject"`
+
+	// The AGS line item for the launched resource (empty if the platform did not provide one).
+	LineItemURL string `json:"line-item-url,omitempty"`
+}
+
+// LTI (context membership) roles to autograder roles.
+// LTI also allows for short ("simple") role names.
+var roleMapping map[string]model.CourseUserRole = map[string]model.CourseUserRole{
+	"Mentor":            model.CourseRoleOther,
+	"ContentDeveloper":  model.CourseRoleOther,
+	"Learner":           model.CourseRoleStudent,
+	"TeachingAssistant": model.CourseRoleGrader,
+	"Grader":            model.CourseRoleGrader,
+	"Administrator":     model.CourseRoleAdmin,
+	"Instructor":        model.CourseRoleOwner,
+}
+
+// Validate the id token (JWT) posted to the tool at the end of a login.
+// The browser state is the state that was stored in the user's browser when the login was initiated,
+// and must match the posted state (otherwise an attacker could complete their own login in someone else's browser).
+func ValidateLaunch(idToken string, state string, browserState string) (*Launch, error) {
+	return validateLaunch(idToken, state, browserState, timestamp.Now())
+}
+
+func validateLaunch(idToken string, state string, browserState string, now timestamp.Timestamp) (*Launch, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("LTI launch is missing an id token.")
+	}
+
+	if (state == "") || (subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1) {
+		return nil, fmt.Errorf("LTI launch state does not match the state of the browser that started the login.")
+	}
+
+	login := consumeLoginState(state, now)
+	if login == nil {
+		return nil, fmt.Errorf("LTI launch has an unknown or expired state.")
+	}
+
+	platform := login.Platform
+
+	var claims LaunchClaims
+	err := verifyPlatformJWT(platform, idToken, &claims)
+	if err != nil {
+		return nil, err
+	}
+
+	err = claims.validate(platform, login.Nonce, now)
+	if err != nil {
+		return nil, err
+	}
+
+	courseID := platform.Courses[claims.Context.ID]
+	if courseID == "" {
+		return nil, fmt.Errorf("LTI context '%s' from platform '%s' is not linked to an autograder course.", claims.Context.ID, platform.Issuer)
+	}
+
+	role := GetCourseRole(claims.Roles)
+	if role == model.CourseRoleUnknown {
+		return nil, fmt.Errorf("LTI launch does not have any recognized roles: '%s'.", strings.Join(claims.Roles, "', '"))
+	}
+
+	launch := &Launch{
+		Platform:     platform,
+		Issuer:       platform.Issuer,
+		CourseID:     courseID,
+		AssignmentID: claims.Custom[CUSTOM_ASSIGNMENT_ID],
+		Email:        strings.ToLower(claims.Email),
+		Name:         claims.Name,
+		Role:         role,
+		Subject:      claims.Subject,
+	}
+
+	if claims.Endpoint != nil {
+		launch.LineItemURL = claims.Endpoint.LineItem
+	}
+
+	return launch, nil
+}
+
+func verifyPlatformJWT(platform *Platform, token string, claims any) error {
+	keyID, err := getJWTKeyID(token)
+	if err != nil {
+		return err
+	}
+
+	key, err := platform.GetKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	return VerifyJWT(token, &JWKSet{Keys: []*JWK{key}}, claims)
+}
+
+func (this *LaunchClaims) validate(platform *Platform, nonce string, now timestamp.Timestamp) error {
+	if this.Issuer != platform.Issuer {
+		return fmt.Errorf("LTI launch issuer ('%s') does not match the platform ('%s').", this.Issuer, platform.Issuer)
+	}
+
+	foundAudience := false
+	for _, audience := range this.Audience {
+		if audience == platform.ClientID {
+			foundAudience = true
+			break
+		}
+	}
+
+	if !foundAudience {
+		return fmt.Errorf("LTI launch audience does not include the client ID '%s'.", platform.ClientID)
+	}
+
+	if (len(this.Audience) > 1) && (this.AuthorizedParty != platform.ClientID) {
+		return fmt.Errorf("LTI launch has multiple audiences, but the authorized party ('%s') is not the client ID.", this.AuthorizedParty)
+	}
+
+	nowSecs := now.ToMSecs() / 1000
+	if (this.ExpirationTime + CLOCK_SKEW_SECS) < nowSecs {
+		return fmt.Errorf("LTI launch has expired.")
+	}
+
+	if (this.IssuedAt - CLOCK_SKEW_SECS) > nowSecs {
+		return fmt.Errorf("LTI launch was issued in the future.")
+	}
+
+	if this.Nonce != nonce {
+		return fmt.Errorf("LTI launch has an invalid nonce.")
+	}
+
+	if this.Version != LTI_VERSION {
+		return fmt.Errorf("Unsupported LTI version: '%s'.", this.Version)
+	}
+
+	if this.MessageType != MESSAGE_TYPE_RESOURCE_LINK {
+		return fmt.Errorf("Unsupported LTI message type: '%s'.", this.MessageType)
+	}
+
+	if !platform.AllowsDeployment(this.DeploymentID) {
+		return fmt.Errorf("LTI platform '%s' does not allow deployment '%s'.", platform.Issuer, this.DeploymentID)
+	}
+
+	if this.Subject == "" {
+		return fmt.Errorf("LTI launch does not have a subject (user ID).")
+	}
+
+	if this.Email == "" {
+		return fmt.Errorf("LTI launch does not have an email. Ensure that the platform is configured to share user emails.")
+	}
+
+	if (this.Context == nil) || (this.Context.ID == "") {
+		return fmt.Errorf("LTI launch does not have a context.")
+	}
+
+	return nil
+}
+
+// Get the highest autograder course role from a list of LTI roles.
+// Non-membership roles (e.g. institution roles) are ignored.
+// Known sub-roles (e.g. "<prefix>/Instructor#TeachingAssistant") take precedence over their principal role ("<prefix>#Instructor"),
+// since platforms will often send both.
+func GetCourseRole(roles []string) model.CourseUserRole {
+	names := make([]string, 0, len(roles))
+	principalsWithSubRoles := make(map[string]bool)
+
+	for _, role := range roles {
+		if strings.HasPrefix(role, ROLE_PREFIX+"#") {
+			names = append(names, strings.TrimPrefix(role, ROLE_PREFIX+"#"))
+		} else if strings.HasPrefix(role, ROLE_PREFIX+"/") {
+			principal, subRole, found := strings.Cut(strings.TrimPrefix(role, ROLE_PREFIX+"/"), "#")
+			if !found {
+				continue
+			}
+
+			// Unknown sub-roles fall back to their principal role.
+			_, known := roleMapping[subRole]
+			if known {
+				names = append(names, subRole)
+				principalsWithSubRoles[principal] = true
+			}
+		} else if !strings.Contains(role, "/") {
+			// Simple role names.
+			names = append(names, role)
+		}
+	}
+
+	result := model.CourseRoleUnknown
+	for _, name := range names {
+		if principalsWithSubRoles[name] {
+			continue
+		}
+
+		result = max(result, roleMapping[name])
+	}
+
+	return result
+}
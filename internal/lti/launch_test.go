@@ -0,0 +1,299 @@
+package lti_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/lti"
+	ltitest "github.com/edulinq/autograder/internal/lti/test"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestInitiateLogin(test *testing.T) {
+	testPlatform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer testPlatform.Close()
+
+	testCases := []struct {
+		request        *lti.LoginRequest
+		errorSubstring string
+	}{
+		{&lti.LoginRequest{Issuer: ltitest.TEST_ISSUER, LoginHint: "a", TargetLinkURI: "https://b"}, ""},
+		{&lti.LoginRequest{Issuer: ltitest.TEST_ISSUER, LoginHint: "a", TargetLinkURI: "https://b", ClientID: ltitest.TEST_CLIENT_ID, DeploymentID: ltitest.TEST_DEPLOYMENT_ID, LTIMessageHint: "c"}, ""},
+
+		{&lti.LoginRequest{LoginHint: "a", TargetLinkURI: "https://b"}, "missing an issuer"},
+		{&lti.LoginRequest{Issuer: ltitest.TEST_ISSUER, TargetLinkURI: "https://b"}, "missing a login hint"},
+		{&lti.LoginRequest{Issuer: ltitest.TEST_ISSUER, LoginHint: "a"}, "missing a target link URI"},
+		{&lti.LoginRequest{Issuer: "https://zzz", LoginHint: "a", TargetLinkURI: "https://b"}, "Unknown LTI platform"},
+		{&lti.LoginRequest{Issuer: ltitest.TEST_ISSUER, LoginHint: "a", TargetLinkURI: "https://b", ClientID: "zzz"}, "Unknown LTI platform"},
+		{&lti.LoginRequest{Issuer: ltitest.TEST_ISSUER, LoginHint: "a", TargetLinkURI: "https://b", DeploymentID: "zzz"}, "does not allow deployment"},
+	}
+
+	for i, testCase := range testCases {
+		redirectURL, state, err := lti.InitiateLogin(testCase.request)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to initiate login: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		parsed, err := url.Parse(redirectURL)
+		if err != nil {
+			test.Errorf("Case %d: Failed to parse redirect URL '%s': '%v'.", i, redirectURL, err)
+			continue
+		}
+
+		if !strings.HasPrefix(redirectURL, testPlatform.Platform.AuthLoginURL+"?") {
+			test.Errorf("Case %d: Redirect is not to the platform: '%s'.", i, redirectURL)
+		}
+
+		query := parsed.Query()
+
+		expected := map[string]string{
+			"scope":            "openid",
+			"response_type":    "id_token",
+			"response_mode":    "form_post",
+			"prompt":           "none",
+			"client_id":        ltitest.TEST_CLIENT_ID,
+			"redirect_uri":     ltitest.TEST_LAUNCH_URL,
+			"login_hint":       testCase.request.LoginHint,
+			"lti_message_hint": testCase.request.LTIMessageHint,
+		}
+
+		for key, value := range expected {
+			if query.Get(key) != value {
+				test.Errorf("Case %d: Unexpected value for '%s'. Expected: '%s', Actual: '%s'.", i, key, value, query.Get(key))
+			}
+		}
+
+		if (query.Get("state") == "") || (query.Get("nonce") == "") {
+			test.Errorf("Case %d: Missing state or nonce: '%s'.", i, redirectURL)
+		}
+
+		if query.Get("state") != state {
+			test.Errorf("Case %d: Unexpected state. Expected: '%s', Actual: '%s'.", i, state, query.Get("state"))
+		}
+	}
+}
+
+func TestValidateLaunch(test *testing.T) {
+	testPlatform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer testPlatform.Close()
+
+	expected := &lti.Launch{
+		Issuer:       ltitest.TEST_ISSUER,
+		CourseID:     ltitest.TEST_COURSE_ID,
+		AssignmentID: "hw0",
+		Email:        "course-student@test.edulinq.org",
+		Name:         "course-student",
+		Role:         model.CourseRoleStudent,
+		Subject:      "student-subject",
+		LineItemURL:  testPlatform.LineItemURL(),
+	}
+
+	nowSecs := timestamp.Now().ToMSecs() / 1000
+
+	testCases := []struct {
+		modify         func(claims *lti.LaunchClaims)
+		badNonce       bool
+		badState       bool
+		errorSubstring string
+	}{
+		{nil, false, false, ""},
+		{func(claims *lti.LaunchClaims) { claims.Email = "COURSE-STUDENT@test.edulinq.org" }, false, false, ""},
+		{func(claims *lti.LaunchClaims) {
+			claims.Audience = lti.Audience{"other", ltitest.TEST_CLIENT_ID}
+			claims.AuthorizedParty = ltitest.TEST_CLIENT_ID
+		}, false, false, ""},
+
+		{nil, true, false, "invalid nonce"},
+		{nil, false, true, "unknown or expired state"},
+		{func(claims *lti.LaunchClaims) { claims.Issuer = "https://zzz" }, false, false, "issuer"},
+		{func(claims *lti.LaunchClaims) { claims.Audience = lti.Audience{"zzz"} }, false, false, "audience does not include"},
+		{func(claims *lti.LaunchClaims) { claims.Audience = lti.Audience{"other", ltitest.TEST_CLIENT_ID} }, false, false, "authorized party"},
+		{func(claims *lti.LaunchClaims) { claims.ExpirationTime = nowSecs - 600 }, false, false, "expired"},
+		{func(claims *lti.LaunchClaims) { claims.IssuedAt = nowSecs + 600 }, false, false, "in the future"},
+		{func(claims *lti.LaunchClaims) { claims.Version = "1.1" }, false, false, "Unsupported LTI version"},
+		{func(claims *lti.LaunchClaims) { claims.MessageType = "LtiDeepLinkingRequest" }, false, false, "Unsupported LTI message type"},
+		{func(claims *lti.LaunchClaims) { claims.DeploymentID = "zzz" }, false, false, "does not allow deployment"},
+		{func(claims *lti.LaunchClaims) { claims.Subject = "" }, false, false, "does not have a subject"},
+		{func(claims *lti.LaunchClaims) { claims.Email = "" }, false, false, "does not have an email"},
+		{func(claims *lti.LaunchClaims) { claims.Context = nil }, false, false, "does not have a context"},
+		{func(claims *lti.LaunchClaims) { claims.Context.ID = "zzz" }, false, false, "not linked to an autograder course"},
+		{func(claims *lti.LaunchClaims) {
+			claims.Roles = []string{"http://purl.imsglobal.org/vocab/lis/v2/institution/person#Student"}
+		}, false, false, "recognized roles"},
+	}
+
+	for i, testCase := range testCases {
+		state, nonce, err := testPlatform.Login()
+		if err != nil {
+			test.Errorf("Case %d: Failed to login: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.badNonce {
+			nonce = "zzz"
+		}
+
+		if testCase.badState {
+			state = "zzz"
+		}
+
+		browserState := state
+
+		claims := testPlatform.GetLaunchClaims(nonce)
+		if testCase.modify != nil {
+			testCase.modify(claims)
+		}
+
+		idToken, err := testPlatform.SignClaims(claims)
+		if err != nil {
+			test.Errorf("Case %d: Failed to sign claims: '%v'.", i, err)
+			continue
+		}
+
+		launch, err := lti.ValidateLaunch(idToken, state, browserState)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to validate launch: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if launch.Platform != testPlatform.Platform {
+			test.Errorf("Case %d: Unexpected platform.", i)
+		}
+
+		if util.MustToJSONIndent(expected) != util.MustToJSONIndent(launch) {
+			test.Errorf("Case %d: Unexpected launch. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(expected), util.MustToJSONIndent(launch))
+		}
+
+		// States can only be used once.
+		_, err = lti.ValidateLaunch(idToken, state, browserState)
+		if err == nil {
+			test.Errorf("Case %d: lti.Launch was replayed.", i)
+		}
+	}
+}
+
+func TestValidateLaunchWrongKey(test *testing.T) {
+	testPlatform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer testPlatform.Close()
+
+	state, nonce, err := testPlatform.Login()
+	if err != nil {
+		test.Fatalf("Failed to login: '%v'.", err)
+	}
+
+	// Sign with the tool's key instead of the platform's.
+	idToken, err := lti.SignJWT(testPlatform.GetLaunchClaims(nonce), testPlatform.ToolKey, ltitest.TEST_PLATFORM_KEY)
+	if err != nil {
+		test.Fatalf("Failed to sign claims: '%v'.", err)
+	}
+
+	_, err = lti.ValidateLaunch(idToken, state, state)
+	if (err == nil) || !strings.Contains(err.Error(), "Invalid JWT signature") {
+		test.Fatalf("Did not get a signature error: '%v'.", err)
+	}
+}
+
+// A launch must come from the browser that started the login (login CSRF).
+func TestValidateLaunchBrowserState(test *testing.T) {
+	testPlatform, err := ltitest.StartTestPlatform()
+	if err != nil {
+		test.Fatalf("Failed to start test platform: '%v'.", err)
+	}
+	defer testPlatform.Close()
+
+	state, nonce, err := testPlatform.Login()
+	if err != nil {
+		test.Fatalf("Failed to login: '%v'.", err)
+	}
+
+	otherState, _, err := testPlatform.Login()
+	if err != nil {
+		test.Fatalf("Failed to login again: '%v'.", err)
+	}
+
+	idToken, err := testPlatform.SignClaims(testPlatform.GetLaunchClaims(nonce))
+	if err != nil {
+		test.Fatalf("Failed to sign claims: '%v'.", err)
+	}
+
+	for i, browserState := range []string{"", otherState, "zzz"} {
+		_, err = lti.ValidateLaunch(idToken, state, browserState)
+		if (err == nil) || !strings.Contains(err.Error(), "does not match the state of the browser") {
+			test.Errorf("Case %d: Did not get a state mismatch error: '%v'.", i, err)
+		}
+	}
+
+	// Mismatched launches do not use up the state.
+	_, err = lti.ValidateLaunch(idToken, state, state)
+	if err != nil {
+		test.Fatalf("Failed to validate launch: '%v'.", err)
+	}
+}
+
+func TestGetCourseRole(test *testing.T) {
+	testCases := []struct {
+		roles    []string
+		expected model.CourseUserRole
+	}{
+		{nil, model.CourseRoleUnknown},
+		{[]string{}, model.CourseRoleUnknown},
+		{[]string{lti.ROLE_PREFIX + "#Learner"}, model.CourseRoleStudent},
+		{[]string{"Learner"}, model.CourseRoleStudent},
+		{[]string{lti.ROLE_PREFIX + "#Mentor"}, model.CourseRoleOther},
+		{[]string{lti.ROLE_PREFIX + "#Instructor"}, model.CourseRoleOwner},
+		{[]string{lti.ROLE_PREFIX + "#Administrator"}, model.CourseRoleAdmin},
+		{[]string{lti.ROLE_PREFIX + "#Learner", lti.ROLE_PREFIX + "#Instructor"}, model.CourseRoleOwner},
+
+		// Sub-roles.
+		{[]string{lti.ROLE_PREFIX + "/Instructor#TeachingAssistant"}, model.CourseRoleGrader},
+		{[]string{lti.ROLE_PREFIX + "#Instructor", lti.ROLE_PREFIX + "/Instructor#TeachingAssistant"}, model.CourseRoleGrader},
+		{[]string{lti.ROLE_PREFIX + "#Instructor", lti.ROLE_PREFIX + "/Instructor#Grader"}, model.CourseRoleGrader},
+		{[]string{lti.ROLE_PREFIX + "#Instructor", lti.ROLE_PREFIX + "/Instructor#Lecturer"}, model.CourseRoleOwner},
+
+		// Non-membership roles.
+		{[]string{"http://purl.imsglobal.org/vocab/lis/v2/institution/person#Administrator"}, model.CourseRoleUnknown},
+		{[]string{"http://purl.imsglobal.org/vocab/lis/v2/system/person#Administrator", lti.ROLE_PREFIX + "#Learner"}, model.CourseRoleStudent},
+		{[]string{"zzz"}, model.CourseRoleUnknown},
+	}
+
+	for i, testCase := range testCases {
+		actual := lti.GetCourseRole(testCase.roles)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected role. Expected: '%s', Actual: '%s'.", i, testCase.expected.String(), actual.String())
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package lti
+
+import (
+	"fmt"
+	neturl "net/url"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// How long a user has to complete a launch after login initiation.
+const LOGIN_STATE_TTL = timestamp.Timestamp(10 * time.Minute / time.Millisecond)
+
+// The maximum number of pending logins.
+// Logins can be initiated without authentication, so once this limit is reached the oldest pending logins are dropped.
+const MAX_LOGIN_STATES = 10000
+
+// The parameters a platform sends to initiate an OIDC login (third-party initiated login).
+type LoginRequest struct {
+	Issuer         string
+	LoginHint      string
+	TargetLinkURI  string
+	LTIMessageHint string
+	ClientID       string
+	DeploymentID   string
+}
+
+// Pending logins, keyed by state.
+type loginState struct {
+	Platform *Platform
+	Nonce    string
+	Expires  timestamp.Timestamp
+}
+
+var loginStatesLock sync.Mutex
+var loginStates map[string]*loginState = make(map[string]*loginState)
+
+// Start an OIDC login with a platform.
+// Returns the URL the user should be redirected to (the platform's auth endpoint) and the login's state.
+// The state should also be stored in the user's browser (e.g., a cookie) and passed to ValidateLaunch(),
+// so that a launch can only be completed by the browser that started the login.
+func InitiateLogin(request *LoginRequest) (string, string, error) {
+	return initiateLogin(request, timestamp.Now())
+}
+
+func initiateLogin(request *LoginRequest, now timestamp.Timestamp) (string, string, error) {
+	if request.Issuer == "" {
+		return "", "", fmt.Errorf("LTI login request is missing an issuer.")
+	}
+
+	if request.LoginHint == "" {
+		return "", "", fmt.Errorf("LTI login request is missing a login hint.")
+	}
+
+	if request.TargetLinkURI == "" {
+		return "", "", fmt.Errorf("LTI login request is missing a target link URI.")
+	}
+
+	platform, err := GetPlatform(request.Issuer, request.ClientID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if platform == nil {
+		return "", "", fmt.Errorf("Unknown LTI platform: '%s' (client ID: '%s').", request.Issuer, request.ClientID)
+	}
+
+	if (request.DeploymentID != "") && !platform.AllowsDeployment(request.DeploymentID) {
+		return "", "", fmt.Errorf("LTI platform '%s' does not allow deployment '%s'.", platform.Issuer, request.DeploymentID)
+	}
+
+	state := util.UUID()
+	nonce := util.UUID()
+
+	storeLoginState(state, &loginState{
+		Platform: platform,
+		Nonce:    nonce,
+		Expires:  now + LOGIN_STATE_TTL,
+	}, now)
+
+	params := neturl.Values{}
+	params.Set("scope", "openid")
+	params.Set("response_type", "id_token")
+	params.Set("response_mode", "form_post")
+	params.Set("prompt", "none")
+	params.Set("client_id", platform.ClientID)
+	// Never redirect to the (unverified) target link, only to the launch URL registered for the platform.
+	params.Set("redirect_uri", platform.LaunchURL)
+	params.Set("login_hint", request.LoginHint)
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+
+	if request.LTIMessageHint != "" {
+		params.Set("lti_message_hint", request.LTIMessageHint)
+	}
+
+	authURL, err := neturl.Parse(platform.AuthLoginURL)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse auth login URL for LTI platform '%s': '%w'.", platform.Issuer, err)
+	}
+
+	// Keep any existing query parameters.
+	query := authURL.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), state, nil
+}
+
+// Store a new login state (and clear out any expired ones).
+// If there are too many pending logins, the oldest ones are dropped (see MAX_LOGIN_STATES).
+func storeLoginState(state string, info *loginState, now timestamp.Timestamp) {
+	loginStatesLock.Lock()
+	defer loginStatesLock.Unlock()
+
+	for key, oldInfo := range loginStates {
+		if oldInfo.Expires < now {
+			delete(loginStates, key)
+		}
+	}
+
+	// All states have the same lifetime, so the oldest state expires first.
+	for len(loginStates) >= MAX_LOGIN_STATES {
+		oldestKey := ""
+		var oldestExpires timestamp.Timestamp
+		for key, oldInfo := range loginStates {
+			if (oldestKey == "") || (oldInfo.Expires < oldestExpires) {
+				oldestKey = key
+				oldestExpires = oldInfo.Expires
+			}
+		}
+
+		delete(loginStates, oldestKey)
+	}
+
+	loginStates[state] = info
+}
+
+// Get and remove a login state (each state/nonce can only be used once).
+// Returns nil if the state does not exist or has expired.
+func consumeLoginState(state string, now timestamp.Timestamp) *loginState {
+	loginStatesLock.Lock()
+	defer loginStatesLock.Unlock()
+
+	info, ok := loginStates[state]
+	if !ok {
+		return nil
+	}
+
+	delete(loginStates, state)
+
+	if info.Expires < now {
+		return nil
+	}
+
+	return info
+}
@@ -0,0 +1,32 @@
+package lti
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestLoginStateLimit(test *testing.T) {
+	loginStatesLock.Lock()
+	loginStates = make(map[string]*loginState)
+	loginStatesLock.Unlock()
+
+	now := timestamp.Now()
+
+	for i := 0; i <= MAX_LOGIN_STATES; i++ {
+		storeLoginState(fmt.Sprintf("state-%d", i), &loginState{Expires: now + LOGIN_STATE_TTL + timestamp.Timestamp(i)}, now)
+	}
+
+	if len(loginStates) != MAX_LOGIN_STATES {
+		test.Fatalf("Unexpected number of login states. Expected: %d, Actual: %d.", MAX_LOGIN_STATES, len(loginStates))
+	}
+
+	if consumeLoginState("state-0", now) != nil {
+		test.Fatalf("Oldest login state was not dropped.")
+	}
+
+	if consumeLoginState(fmt.Sprintf("state-%d", MAX_LOGIN_STATES), now) == nil {
+		test.Fatalf("Newest login state was dropped.")
+	}
+}
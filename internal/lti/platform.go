@@ -0,0 +1,231 @@
+package lti
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A platform (e.g., an LMS instance) that has registered the autograder as an LTI 1.3 tool.
+// Platforms are loaded from the file pointed to by config.LTI_PLATFORMS_PATH (a JSON list of platforms).
+type Platform struct {
+	Issuer   string `json:"issuer"`
+	ClientID string `json:"client-id"`
+
+	// If not empty, only launches from these deployments are allowed.
+	DeploymentIDs []string `json:"deployment-ids,omitempty"`
+
+	AuthLoginURL string `json:"auth-login-url"`
+	// The tool's launch URL as registered with the platform (where the platform posts id tokens).
+	// Always used as the OIDC redirect URI.
+	LaunchURL    string `json:"launch-url"`
+	AuthTokenURL string `json:"auth-token-url,omitempty"`
+
+	// Exactly one of the key set URL or key set must be provided.
+	KeySetURL string  `json:"key-set-url,omitempty"`
+	KeySet    *JWKSet `json:"key-set,omitempty"`
+
+	// LTI context IDs (the platform's course) to autograder course IDs.
+	Courses map[string]string `json:"courses"`
+
+	keySetLock sync.Mutex
+	// Key sets fetched from the key set URL.
+	fetchedKeySet *JWKSet
+
+	tokensLock sync.Mutex
+	// Service access tokens, keyed by scope.
+	accessTokens map[string]*cachedAccessToken
+}
+
+var platformsLock sync.Mutex
+var platforms []*Platform = nil
+
+func (this *Platform) Validate() error {
+	if this.Issuer == "" {
+		return fmt.Errorf("LTI platform is missing an issuer.")
+	}
+
+	if this.ClientID == "" {
+		return fmt.Errorf("LTI platform '%s' is missing a client ID.", this.Issuer)
+	}
+
+	if this.AuthLoginURL == "" {
+		return fmt.Errorf("LTI platform '%s' is missing an auth login URL.", this.Issuer)
+	}
+
+	if this.LaunchURL == "" {
+		return fmt.Errorf("LTI platform '%s' is missing a launch URL.", this.Issuer)
+	}
+
+	if (this.KeySetURL == "") && (this.KeySet == nil) {
+		return fmt.Errorf("LTI platform '%s' must have either a key set URL or key set.", this.Issuer)
+	}
+
+	if (this.KeySetURL != "") && (this.KeySet != nil) {
+		return fmt.Errorf("LTI platform '%s' cannot have both a key set URL and key set.", this.Issuer)
+	}
+
+	if this.Courses == nil {
+		this.Courses = make(map[string]string)
+	}
+
+	return nil
+}
+
+func (this *Platform) AllowsDeployment(deploymentID string) bool {
+	if len(this.DeploymentIDs) == 0 {
+		return true
+	}
+
+	for _, allowedID := range this.DeploymentIDs {
+		if allowedID == deploymentID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the key to verify platform messages with.
+// Keys from a key set URL are cached, and refetched if the requested key is not found (keys may be rotated).
+func (this *Platform) GetKey(keyID string) (*JWK, error) {
+	if this.KeySet != nil {
+		key := this.KeySet.GetKey(keyID)
+		if key == nil {
+			return nil, fmt.Errorf("LTI platform '%s' does not have key '%s'.", this.Issuer, keyID)
+		}
+
+		return key, nil
+	}
+
+	this.keySetLock.Lock()
+	defer this.keySetLock.Unlock()
+
+	key := this.fetchedKeySet.GetKey(keyID)
+	if key != nil {
+		return key, nil
+	}
+
+	body, err := util.Get(this.KeySetURL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch key set for LTI platform '%s': '%w'.", this.Issuer, err)
+	}
+
+	var keySet JWKSet
+	err = util.JSONFromString(body, &keySet)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse key set for LTI platform '%s': '%w'.", this.Issuer, err)
+	}
+
+	this.fetchedKeySet = &keySet
+
+	key = this.fetchedKeySet.GetKey(keyID)
+	if key == nil {
+		return nil, fmt.Errorf("LTI platform '%s' does not have key '%s'.", this.Issuer, keyID)
+	}
+
+	return key, nil
+}
+
+// Load the platforms from config.LTI_PLATFORMS_PATH (if they have not already been loaded).
+func GetPlatforms() ([]*Platform, error) {
+	platformsLock.Lock()
+	defer platformsLock.Unlock()
+
+	if platforms != nil {
+		return platforms, nil
+	}
+
+	path := config.LTI_PLATFORMS_PATH.Get()
+	if path == "" {
+		platforms = make([]*Platform, 0)
+		return platforms, nil
+	}
+
+	var loadedPlatforms []*Platform
+	err := util.JSONFromFile(path, &loadedPlatforms)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load LTI platforms from '%s': '%w'.", path, err)
+	}
+
+	err = setPlatforms(loadedPlatforms)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load LTI platforms from '%s': '%w'.", path, err)
+	}
+
+	return platforms, nil
+}
+
+// Replace the known platforms (usually for testing).
+// Passing nil will cause platforms to be reloaded from config on the next access.
+func SetPlatforms(newPlatforms []*Platform) error {
+	platformsLock.Lock()
+	defer platformsLock.Unlock()
+
+	if newPlatforms == nil {
+		platforms = nil
+		return nil
+	}
+
+	return setPlatforms(newPlatforms)
+}
+
+func setPlatforms(newPlatforms []*Platform) error {
+	for i, platform := range newPlatforms {
+		if platform == nil {
+			return fmt.Errorf("LTI platform at index %d is nil.", i)
+		}
+
+		err := platform.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	platforms = newPlatforms
+	return nil
+}
+
+// Get a platform by its issuer and (optionally) client ID.
+// Returns nil if there is no matching platform.
+func GetPlatform(issuer string, clientID string) (*Platform, error) {
+	allPlatforms, err := GetPlatforms()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, platform := range allPlatforms {
+		if platform.Issuer != issuer {
+			continue
+		}
+
+		if (clientID != "") && (platform.ClientID != clientID) {
+			continue
+		}
+
+		return platform, nil
+	}
+
+	return nil, nil
+}
+
+// Get the platform that is linked to an autograder course.
+// Returns nil if there is no matching platform.
+func GetPlatformForCourse(courseID string) (*Platform, error) {
+	allPlatforms, err := GetPlatforms()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, platform := range allPlatforms {
+		for _, platformCourseID := range platform.Courses {
+			if platformCourseID == courseID {
+				return platform, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
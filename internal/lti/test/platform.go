@@ -0,0 +1,376 @@
+// A local stand-in LTI platform for testing.
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/lti"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	TEST_ISSUER         = "https://platform.test.edulinq.org"
+	TEST_CLIENT_ID      = "test-client"
+	TEST_DEPLOYMENT_ID  = "test-deployment"
+	TEST_CONTEXT_ID     = "test-context"
+	TEST_COURSE_ID      = "course101"
+	TEST_PLATFORM_KEY   = "platform-key"
+	TEST_TOOL_KEY       = "tool-key"
+	TEST_ACCESS_TOKEN   = "test-access-token"
+	TEST_LINE_ITEM_PATH = "/lineitems/1"
+	TEST_MAX_POINTS     = 10.0
+	TEST_LAUNCH_URL     = "https://autograder.test.edulinq.org/lti/launch"
+	RSA_TEST_KEY_BITS   = 2048
+)
+
+// The parts of a client assertion (see lti.CLIENT_ASSERTION_TYPE) the platform checks.
+type clientAssertionClaims struct {
+	Issuer   string `json:"iss"`
+	Audience string `json:"aud"`
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+type TestPlatform struct {
+	Platform *lti.Platform
+	Key      *rsa.PrivateKey
+	ToolKey  *rsa.PrivateKey
+	Server   *httptest.Server
+
+	lock     sync.Mutex
+	scores   map[string]*lti.Score
+	lineItem *lti.LineItem
+}
+
+// Start a stand-in platform and register it (and a tool key) as the only LTI platform.
+// The platform links TEST_CONTEXT_ID to TEST_COURSE_ID.
+// Call Close() when done.
+func StartTestPlatform() (*TestPlatform, error) {
+	platformKey, err := rsa.GenerateKey(rand.Reader, RSA_TEST_KEY_BITS)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate platform key: '%w'.", err)
+	}
+
+	toolKey, err := rsa.GenerateKey(rand.Reader, RSA_TEST_KEY_BITS)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate tool key: '%w'.", err)
+	}
+
+	testPlatform := &TestPlatform{
+		Key:     platformKey,
+		ToolKey: toolKey,
+		scores:  make(map[string]*lti.Score),
+	}
+
+	testPlatform.Server = httptest.NewServer(http.HandlerFunc(testPlatform.serveHTTP))
+
+	testPlatform.lineItem = &lti.LineItem{
+		ID:           testPlatform.LineItemURL(),
+		Label:        "Homework 0",
+		ScoreMaximum: TEST_MAX_POINTS,
+	}
+
+	testPlatform.Platform = &lti.Platform{
+		Issuer:        TEST_ISSUER,
+		ClientID:      TEST_CLIENT_ID,
+		DeploymentIDs: []string{TEST_DEPLOYMENT_ID},
+		AuthLoginURL:  testPlatform.Server.URL + "/auth",
+		LaunchURL:     TEST_LAUNCH_URL,
+		AuthTokenURL:  testPlatform.Server.URL + "/token",
+		KeySetURL:     testPlatform.Server.URL + "/jwks",
+		Courses: map[string]string{
+			TEST_CONTEXT_ID: TEST_COURSE_ID,
+		},
+	}
+
+	err = lti.SetPlatforms([]*lti.Platform{testPlatform.Platform})
+	if err != nil {
+		testPlatform.Server.Close()
+		return nil, err
+	}
+
+	lti.SetToolKey(toolKey, TEST_TOOL_KEY)
+
+	return testPlatform, nil
+}
+
+func (this *TestPlatform) Close() {
+	this.Server.Close()
+	lti.SetPlatforms(nil)
+	lti.SetToolKey(nil, "")
+}
+
+func (this *TestPlatform) LineItemURL() string {
+	return this.Server.URL + TEST_LINE_ITEM_PATH
+}
+
+// Get the scores that have been posted to the platform, keyed by user ID.
+func (this *TestPlatform) GetScores() map[string]*lti.Score {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	scores := make(map[string]*lti.Score, len(this.scores))
+	for userID, score := range this.scores {
+		scores[userID] = score
+	}
+
+	return scores
+}
+
+func (this *TestPlatform) GetLineItem() *lti.LineItem {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	lineItem := *this.lineItem
+	return &lineItem
+}
+
+func (this *TestPlatform) SetScore(score *lti.Score) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.scores[score.UserID] = score
+}
+
+// Get the claims for a standard launch by course-student for hw0.
+func (this *TestPlatform) GetLaunchClaims(nonce string) *lti.LaunchClaims {
+	nowSecs := timestamp.Now().ToMSecs() / 1000
+
+	return &lti.LaunchClaims{
+		Issuer:         TEST_ISSUER,
+		Subject:        "student-subject",
+		Audience:       lti.Audience{TEST_CLIENT_ID},
+		ExpirationTime: nowSecs + 60,
+		IssuedAt:       nowSecs,
+		Nonce:          nonce,
+		Email:          "course-student@test.edulinq.org",
+		Name:           "course-student",
+		MessageType:    lti.MESSAGE_TYPE_RESOURCE_LINK,
+		Version:        lti.LTI_VERSION,
+		DeploymentID:   TEST_DEPLOYMENT_ID,
+		Roles:          []string{lti.ROLE_PREFIX + "#Learner"},
+		Context: &lti.ContextClaim{
+			ID: TEST_CONTEXT_ID,
+		},
+		ResourceLink: &lti.ResourceClaim{
+			ID: "resource-hw0",
+		},
+		Custom: map[string]string{
+			lti.CUSTOM_ASSIGNMENT_ID: "hw0",
+		},
+		Endpoint: &lti.EndpointClaim{
+			Scope:    []string{lti.SCOPE_LINE_ITEM_READONLY, lti.SCOPE_RESULT_READONLY, lti.SCOPE_SCORE},
+			LineItem: this.LineItemURL(),
+		},
+	}
+}
+
+// Sign claims as the platform (the id token a platform would send for a launch).
+func (this *TestPlatform) SignClaims(claims any) (string, error) {
+	return lti.SignJWT(claims, this.Key, TEST_PLATFORM_KEY)
+}
+
+// Do a full login and return the (state, nonce) that should be used for a launch.
+func (this *TestPlatform) Login() (string, string, error) {
+	request := &lti.LoginRequest{
+		Issuer:        TEST_ISSUER,
+		LoginHint:     "student-subject",
+		TargetLinkURI: TEST_LAUNCH_URL,
+		ClientID:      TEST_CLIENT_ID,
+	}
+
+	redirectURL, state, err := lti.InitiateLogin(request)
+	if err != nil {
+		return "", "", err
+	}
+
+	url, err := neturl.Parse(redirectURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return state, url.Query().Get("nonce"), nil
+}
+
+func (this *TestPlatform) serveHTTP(response http.ResponseWriter, request *http.Request) {
+	switch {
+	case request.URL.Path == "/jwks":
+		this.writeJSON(response, &lti.JWKSet{Keys: []*lti.JWK{lti.NewJWK(&this.Key.PublicKey, TEST_PLATFORM_KEY)}})
+	case request.URL.Path == "/token":
+		this.serveToken(response, request)
+	case request.URL.Path == TEST_LINE_ITEM_PATH:
+		if !this.checkAccessToken(response, request) {
+			return
+		}
+
+		this.serveLineItem(response, request)
+	case request.URL.Path == (TEST_LINE_ITEM_PATH + "/results"):
+		if !this.checkAccessToken(response, request) {
+			return
+		}
+
+		this.serveResults(response, request)
+	case request.URL.Path == (TEST_LINE_ITEM_PATH + "/scores"):
+		if !this.checkAccessToken(response, request) {
+			return
+		}
+
+		this.serveScores(response, request)
+	default:
+		http.NotFound(response, request)
+	}
+}
+
+// Check the client assertion (signed by the tool's key) and return an access token.
+func (this *TestPlatform) serveToken(response http.ResponseWriter, request *http.Request) {
+	err := request.ParseForm()
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.PostForm.Get("client_assertion_type") != lti.CLIENT_ASSERTION_TYPE {
+		http.Error(response, "Bad assertion type.", http.StatusBadRequest)
+		return
+	}
+
+	toolKeySet := &lti.JWKSet{Keys: []*lti.JWK{lti.NewJWK(&this.ToolKey.PublicKey, TEST_TOOL_KEY)}}
+
+	var claims clientAssertionClaims
+	err = lti.VerifyJWT(request.PostForm.Get("client_assertion"), toolKeySet, &claims)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if (claims.Issuer != TEST_CLIENT_ID) || (claims.Audience != this.Platform.AuthTokenURL) {
+		http.Error(response, "Bad assertion claims.", http.StatusUnauthorized)
+		return
+	}
+
+	this.writeJSON(response, &accessTokenResponse{
+		AccessToken: TEST_ACCESS_TOKEN,
+		TokenType:   "Bearer",
+		ExpiresIn:   3600,
+		Scope:       request.PostForm.Get("scope"),
+	})
+}
+
+func (this *TestPlatform) serveLineItem(response http.ResponseWriter, request *http.Request) {
+	if request.Method != "PUT" {
+		this.writeJSON(response, this.GetLineItem())
+		return
+	}
+
+	if request.Header.Get("Content-Type") != lti.CONTENT_TYPE_LINE_ITEM {
+		http.Error(response, "Bad content type.", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var lineItem lti.LineItem
+	err = util.JSONFromString(string(body), &lineItem)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lineItem.ID = this.LineItemURL()
+
+	this.lock.Lock()
+	this.lineItem = &lineItem
+	this.lock.Unlock()
+
+	this.writeJSON(response, &lineItem)
+}
+
+func (this *TestPlatform) serveResults(response http.ResponseWriter, request *http.Request) {
+	userID := request.URL.Query().Get("user_id")
+
+	results := make([]*lti.Result, 0)
+	for _, score := range this.GetScores() {
+		if (userID != "") && (userID != score.UserID) {
+			continue
+		}
+
+		scoreGiven := score.ScoreGiven
+		results = append(results, &lti.Result{
+			ID:            this.LineItemURL() + "/results/" + score.UserID,
+			ScoreOf:       this.LineItemURL(),
+			UserID:        score.UserID,
+			ResultScore:   &scoreGiven,
+			ResultMaximum: score.ScoreMaximum,
+			Comment:       score.Comment,
+		})
+	}
+
+	this.writeJSON(response, results)
+}
+
+func (this *TestPlatform) serveScores(response http.ResponseWriter, request *http.Request) {
+	if request.Method != "POST" {
+		http.Error(response, "Scores must be posted.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if request.Header.Get("Content-Type") != lti.CONTENT_TYPE_SCORE {
+		http.Error(response, "Bad content type.", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var score lti.Score
+	err = util.JSONFromString(string(body), &score)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	this.SetScore(&score)
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+func (this *TestPlatform) checkAccessToken(response http.ResponseWriter, request *http.Request) bool {
+	if request.Header.Get("Authorization") != ("Bearer " + TEST_ACCESS_TOKEN) {
+		http.Error(response, "Bad access token.", http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
+
+func (this *TestPlatform) writeJSON(response http.ResponseWriter, data any) {
+	body, err := util.ToJSON(data)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	_, _ = response.Write([]byte(strings.TrimSpace(body)))
+}
@@ -0,0 +1,365 @@
+package lti
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The name of tokens created for users when they launch the autograder.
+const LAUNCH_TOKEN_NAME = "lti-launch"
+
+// How long a launch token can be used to authenticate.
+// Users get a new token each time they launch the autograder from the platform.
+const LAUNCH_TOKEN_TTL = timestamp.Timestamp(12 * time.Hour / time.Millisecond)
+
+// How long a user has to bind an existing account after a launch.
+const BIND_TICKET_TTL = timestamp.Timestamp(30 * time.Minute / time.Millisecond)
+
+// The result of applying a launch.
+// Exactly one of the token or bind ticket will be set,
+// unless the user has an elevated server role (in which case neither will be set and the user must log in normally).
+type LaunchResult struct {
+	// The user the launch was applied to (nil if the launch needs to be bound to an account first).
+	User *model.ServerUser
+
+	// A token the user can authenticate with.
+	Token string
+
+	// Launches for an existing account that is not yet bound to the LTI identity cannot be trusted
+	// (the platform may let users claim any email).
+	// The account's user must authenticate and redeem this ticket (see BindLaunch()) to bind the identity.
+	BindTicket string
+}
+
+// Pending binds, keyed by ticket.
+type bindState struct {
+	Launch  *Launch
+	Expires timestamp.Timestamp
+}
+
+var bindStatesLock sync.Mutex
+var bindStates map[string]*bindState = make(map[string]*bindState)
+
+// Apply a validated launch to the autograder.
+// Users are identified by their LTI identity (issuer and subject), not their email.
+// For identities bound to an account (or new accounts created by the launch),
+// ensure the user is enrolled in the course (the platform is authoritative for the user's course role and LMS ID)
+// and create a fresh token (replacing any previous launch tokens) so the user can authenticate with the autograder.
+// For identities that are not bound but match an existing account's email, a bind ticket is created instead.
+// In all cases, the launched assignment is linked to the platform's line item.
+func ApplyLaunch(launch *Launch) (*LaunchResult, error) {
+	return applyLaunch(launch, timestamp.Now())
+}
+
+func applyLaunch(launch *Launch, now timestamp.Timestamp) (*LaunchResult, error) {
+	course, assignment, err := getLaunchTargets(launch)
+	if err != nil {
+		return nil, err
+	}
+
+	if (launch.Issuer == "") || (launch.Subject == "") {
+		return nil, fmt.Errorf("LTI launch does not have an identity (issuer: '%s', subject: '%s').", launch.Issuer, launch.Subject)
+	}
+
+	email, err := getBoundEmail(launch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LaunchResult{}
+
+	if email == "" {
+		oldUser, err := db.GetServerUser(launch.Email)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get user '%s': '%w'.", launch.Email, err)
+		}
+
+		if oldUser != nil {
+			log.Info("LTI launch for an existing account that is not bound to the LTI identity, creating bind ticket.",
+				log.NewUserAttr(launch.Email), log.NewCourseAttr(launch.CourseID), log.NewAttr("issuer", launch.Issuer))
+
+			result.BindTicket = util.UUID()
+			storeBindState(result.BindTicket, &bindState{launch, now + BIND_TICKET_TTL}, now)
+		} else {
+			email = launch.Email
+		}
+	}
+
+	if email != "" {
+		result.User, err = upsertLaunchUser(launch, email)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Token, err = createLaunchToken(result.User, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = linkLineItem(course, assignment, launch)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Bind the identity from a launch (see LaunchResult.BindTicket) to an authenticated user,
+// and apply the launch to the user (without creating a token).
+// The user's email must match the one claimed in the launch.
+func BindLaunch(ticket string, user *model.ServerUser) (*Launch, error) {
+	return bindLaunch(ticket, user, timestamp.Now())
+}
+
+func bindLaunch(ticket string, user *model.ServerUser, now timestamp.Timestamp) (*Launch, error) {
+	state := consumeBindState(ticket, now)
+	if state == nil {
+		return nil, fmt.Errorf("Unknown or expired LTI bind ticket.")
+	}
+
+	launch := state.Launch
+
+	if user.Email != launch.Email {
+		return nil, fmt.Errorf("LTI bind ticket is for a different user.")
+	}
+
+	_, _, err := getLaunchTargets(launch)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.SetLTIIdentity(launch.Issuer, launch.Subject, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to bind LTI identity to user '%s': '%w'.", user.Email, err)
+	}
+
+	_, err = upsertLaunchUser(launch, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Bound LTI identity to user.", log.NewUserAttr(user.Email), log.NewAttr("issuer", launch.Issuer))
+
+	return launch, nil
+}
+
+func getLaunchTargets(launch *Launch) (*model.Course, *model.Assignment, error) {
+	course, err := db.GetCourse(launch.CourseID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to get course '%s': '%w'.", launch.CourseID, err)
+	}
+
+	if course == nil {
+		return nil, nil, fmt.Errorf("LTI launch is for an unknown course: '%s'.", launch.CourseID)
+	}
+
+	var assignment *model.Assignment = nil
+	if launch.AssignmentID != "" {
+		assignment = course.GetAssignment(launch.AssignmentID)
+		if assignment == nil {
+			return nil, nil, fmt.Errorf("LTI launch is for an unknown assignment: '%s'.", launch.AssignmentID)
+		}
+	}
+
+	return course, assignment, nil
+}
+
+// Get the email of the existing user bound to the launch's identity.
+// Returns an empty string if the identity is not bound (or the bound user no longer exists).
+func getBoundEmail(launch *Launch) (string, error) {
+	email, err := db.GetLTIIdentity(launch.Issuer, launch.Subject)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get LTI identity: '%w'.", err)
+	}
+
+	if email == "" {
+		return "", nil
+	}
+
+	user, err := db.GetServerUser(email)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get user '%s': '%w'.", email, err)
+	}
+
+	if user == nil {
+		log.Warn("LTI identity is bound to a user that does not exist, removing binding.",
+			log.NewUserAttr(email), log.NewAttr("issuer", launch.Issuer))
+
+		err = db.SetLTIIdentity(launch.Issuer, launch.Subject, "")
+		if err != nil {
+			return "", fmt.Errorf("Failed to remove stale LTI identity: '%w'.", err)
+		}
+
+		return "", nil
+	}
+
+	if !strings.EqualFold(email, launch.Email) {
+		log.Info("LTI launch email does not match the bound user.", log.NewUserAttr(email), log.NewAttr("launch-email", launch.Email))
+	}
+
+	return email, nil
+}
+
+// Ensure the user (which may be new) exists and is enrolled in the launch's course.
+// New users are bound to the launch's identity.
+func upsertLaunchUser(launch *Launch, email string) (*model.ServerUser, error) {
+	rawUser := &model.RawServerUserData{
+		Email:       email,
+		Name:        launch.Name,
+		Course:      launch.CourseID,
+		CourseRole:  launch.Role.String(),
+		CourseLMSID: launch.Subject,
+	}
+
+	newUser, err := rawUser.ToServerUser()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid user in LTI launch: '%w'.", err)
+	}
+
+	oldUser, err := db.GetServerUser(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get user '%s': '%w'.", email, err)
+	}
+
+	if oldUser == nil {
+		// New users can only authenticate with tokens (until they set a password).
+		newUser.Role = model.ServerRoleUser
+		newUser.Salt = util.StringPointer(model.ShouldNewRandomSalt())
+
+		log.Info("Adding new user from LTI launch.", log.NewUserAttr(email), log.NewCourseAttr(launch.CourseID))
+	} else if oldUser.Name != nil {
+		// Do not override existing names.
+		newUser.Name = nil
+	}
+
+	err = db.UpsertUser(newUser)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to save user '%s': '%w'.", email, err)
+	}
+
+	if oldUser == nil {
+		err = db.SetLTIIdentity(launch.Issuer, launch.Subject, email)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to bind LTI identity to new user '%s': '%w'.", email, err)
+		}
+	}
+
+	user, err := db.GetServerUser(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get user '%s': '%w'.", email, err)
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("Could not find user '%s' after saving.", email)
+	}
+
+	return user, nil
+}
+
+// Create a launch token for the user (replacing any existing launch tokens).
+// The token expires after LAUNCH_TOKEN_TTL.
+// Users with an elevated server role never get launch tokens (an empty token will be returned).
+func createLaunchToken(user *model.ServerUser, now timestamp.Timestamp) (string, error) {
+	if user.Role > model.ServerRoleUser {
+		log.Warn("Not creating an LTI launch token for a user with an elevated server role.",
+			log.NewUserAttr(user.Email), log.NewAttr("role", user.Role.String()))
+		return "", nil
+	}
+
+	// Saving a user merges tokens, so old tokens need to be removed from both the database and the user.
+	tokens := make([]*model.Token, 0, len(user.Tokens))
+	for _, token := range user.Tokens {
+		if token.Name != LAUNCH_TOKEN_NAME {
+			tokens = append(tokens, token)
+			continue
+		}
+
+		_, err := db.DeleteUserToken(user.Email, token.ID)
+		if err != nil {
+			return "", fmt.Errorf("Failed to remove old launch token for user '%s': '%w'.", user.Email, err)
+		}
+	}
+
+	user.Tokens = tokens
+
+	token, cleartext, err := user.CreateRandomToken(LAUNCH_TOKEN_NAME, model.TokenSourceServer)
+	if err != nil {
+		return "", err
+	}
+
+	expirationTime := now + LAUNCH_TOKEN_TTL
+	token.ExpirationTime = &expirationTime
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		return "", fmt.Errorf("Failed to save launch token for user '%s': '%w'.", user.Email, err)
+	}
+
+	return cleartext, nil
+}
+
+// If the course uses LTI as its LMS, then link an assignment without an LMS ID to the launch's line item.
+func linkLineItem(course *model.Course, assignment *model.Assignment, launch *Launch) error {
+	if (assignment == nil) || (launch.LineItemURL == "") || (assignment.GetLMSID() != "") {
+		return nil
+	}
+
+	adapter := course.GetLMSAdapter()
+	if (adapter == nil) || (adapter.Type != model.LMS_TYPE_LTI) {
+		return nil
+	}
+
+	assignment.LMSID = launch.LineItemURL
+
+	err := db.SaveAssignment(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to link assignment '%s' to LTI line item '%s': '%w'.", assignment.GetID(), launch.LineItemURL, err)
+	}
+
+	log.Info("Linked assignment to LTI line item.", assignment, log.NewAttr("line-item", launch.LineItemURL))
+
+	return nil
+}
+
+// Store a new bind state (and clear out any expired ones).
+func storeBindState(ticket string, state *bindState, now timestamp.Timestamp) {
+	bindStatesLock.Lock()
+	defer bindStatesLock.Unlock()
+
+	for key, oldState := range bindStates {
+		if oldState.Expires < now {
+			delete(bindStates, key)
+		}
+	}
+
+	bindStates[ticket] = state
+}
+
+// Get and remove a bind state (each ticket can only be used once).
+// Returns nil if the ticket does not exist or has expired.
+func consumeBindState(ticket string, now timestamp.Timestamp) *bindState {
+	bindStatesLock.Lock()
+	defer bindStatesLock.Unlock()
+
+	state, ok := bindStates[ticket]
+	if !ok {
+		return nil
+	}
+
+	delete(bindStates, ticket)
+
+	if state.Expires < now {
+		return nil
+	}
+
+	return state
+}
@@ -0,0 +1,328 @@
+package lti
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_ISSUER = "https://platform.test.edulinq.org"
+
+func TestApplyLaunch(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		launch        *Launch
+		bindEmail     string
+		useLTI        bool
+		expectedEmail string
+		expectedName  string
+		expectedRole  model.CourseUserRole
+		expectedLMSID string
+		isNew         bool
+		errorExpected bool
+	}{
+		// Bound user, role changed by the platform.
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "course101", Email: "course-student@test.edulinq.org", Name: "zzz", Role: model.CourseRoleGrader, Subject: "subject-1"},
+			"course-student@test.edulinq.org", false, "course-student@test.edulinq.org", "course-student", model.CourseRoleGrader, "subject-1", false, false},
+		// New user.
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "course101", AssignmentID: "hw0", Email: "new@test.edulinq.org", Name: "New User", Role: model.CourseRoleStudent, Subject: "subject-2", LineItemURL: "https://platform/lineitems/1"},
+			"", false, "new@test.edulinq.org", "New User", model.CourseRoleStudent, "subject-2", true, false},
+		// Bound user in a new course.
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "course-languages", Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-3"},
+			"course-student@test.edulinq.org", false, "course-student@test.edulinq.org", "course-student", model.CourseRoleStudent, "subject-3", false, false},
+		// Line item linking.
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "course101", AssignmentID: "hw0", Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-1", LineItemURL: "https://platform/lineitems/1"},
+			"course-student@test.edulinq.org", true, "course-student@test.edulinq.org", "course-student", model.CourseRoleStudent, "subject-1", false, false},
+		// The bound user is used (not the claimed email).
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "course101", Email: "course-other@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-1"},
+			"course-student@test.edulinq.org", false, "course-student@test.edulinq.org", "course-student", model.CourseRoleStudent, "subject-1", false, false},
+
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "zzz", Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-1"},
+			"", false, "", "", model.CourseRoleUnknown, "", false, true},
+		{&Launch{Issuer: TEST_ISSUER, CourseID: "course101", AssignmentID: "zzz", Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-1"},
+			"", false, "", "", model.CourseRoleUnknown, "", false, true},
+		{&Launch{CourseID: "course101", Email: "new@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-1"},
+			"", false, "", "", model.CourseRoleUnknown, "", false, true},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		if testCase.useLTI {
+			course := db.MustGetCourse(testCase.launch.CourseID)
+			course.LMS = &model.LMSAdapter{Type: model.LMS_TYPE_LTI}
+			db.MustSaveCourse(course)
+		}
+
+		if testCase.bindEmail != "" {
+			err := db.SetLTIIdentity(testCase.launch.Issuer, testCase.launch.Subject, testCase.bindEmail)
+			if err != nil {
+				test.Fatalf("Case %d: Failed to bind identity: '%v'.", i, err)
+			}
+		}
+
+		result, err := ApplyLaunch(testCase.launch)
+		if err != nil {
+			if !testCase.errorExpected {
+				test.Errorf("Case %d: Failed to apply launch: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorExpected {
+			test.Errorf("Case %d: Did not get expected error.", i)
+			continue
+		}
+
+		if (result.User == nil) || (result.Token == "") || (result.BindTicket != "") {
+			test.Errorf("Case %d: Unexpected launch result: '%s'.", i, util.MustToJSONIndent(result))
+			continue
+		}
+
+		user := result.User
+
+		if user.Email != testCase.expectedEmail {
+			test.Errorf("Case %d: Unexpected user. Expected: '%s', Actual: '%s'.", i, testCase.expectedEmail, user.Email)
+			continue
+		}
+
+		if (user.Name == nil) || (*user.Name != testCase.expectedName) {
+			test.Errorf("Case %d: Unexpected name. Expected: '%s', Actual: '%v'.", i, testCase.expectedName, user.Name)
+		}
+
+		info := user.CourseInfo[testCase.launch.CourseID]
+		if info == nil {
+			test.Errorf("Case %d: User is not enrolled.", i)
+			continue
+		}
+
+		if info.Role != testCase.expectedRole {
+			test.Errorf("Case %d: Unexpected role. Expected: '%s', Actual: '%s'.", i, testCase.expectedRole.String(), info.Role.String())
+		}
+
+		if info.GetLMSID() != testCase.expectedLMSID {
+			test.Errorf("Case %d: Unexpected LMS ID. Expected: '%s', Actual: '%s'.", i, testCase.expectedLMSID, info.GetLMSID())
+		}
+
+		if testCase.isNew {
+			if user.Role != model.ServerRoleUser {
+				test.Errorf("Case %d: Unexpected server role: '%s'.", i, user.Role.String())
+			}
+
+			// New users are bound to the launch's identity.
+			email, err := db.GetLTIIdentity(testCase.launch.Issuer, testCase.launch.Subject)
+			if err != nil {
+				test.Errorf("Case %d: Failed to get identity: '%v'.", i, err)
+				continue
+			}
+
+			if email != testCase.expectedEmail {
+				test.Errorf("Case %d: New user was not bound. Expected: '%s', Actual: '%s'.", i, testCase.expectedEmail, email)
+			}
+		}
+
+		// Check that the token works (using a fresh copy of the user).
+		user = db.MustGetServerUser(testCase.expectedEmail)
+		ok, err := user.Auth(util.Sha256HexFromString(result.Token))
+		if err != nil {
+			test.Errorf("Case %d: Failed to auth: '%v'.", i, err)
+			continue
+		}
+
+		if !ok {
+			test.Errorf("Case %d: Launch token does not authenticate.", i)
+		}
+
+		// Launching again should replace the token.
+		newResult, err := ApplyLaunch(testCase.launch)
+		if err != nil {
+			test.Errorf("Case %d: Failed to apply second launch: '%v'.", i, err)
+			continue
+		}
+
+		user = db.MustGetServerUser(testCase.expectedEmail)
+
+		numLaunchTokens := 0
+		for _, userToken := range user.Tokens {
+			if userToken.Name == LAUNCH_TOKEN_NAME {
+				numLaunchTokens++
+			}
+		}
+
+		if numLaunchTokens != 1 {
+			test.Errorf("Case %d: Unexpected number of launch tokens: %d.", i, numLaunchTokens)
+		}
+
+		ok, _ = user.Auth(util.Sha256HexFromString(newResult.Token))
+		if !ok {
+			test.Errorf("Case %d: New launch token does not authenticate.", i)
+		}
+
+		ok, _ = user.Auth(util.Sha256HexFromString(result.Token))
+		if ok {
+			test.Errorf("Case %d: Old launch token still authenticates.", i)
+		}
+
+		if testCase.launch.AssignmentID != "" {
+			expectedLMSID := ""
+			if testCase.useLTI {
+				expectedLMSID = testCase.launch.LineItemURL
+			}
+
+			assignment := db.MustGetCourse(testCase.launch.CourseID).GetAssignment(testCase.launch.AssignmentID)
+			if assignment.GetLMSID() != expectedLMSID {
+				test.Errorf("Case %d: Unexpected assignment LMS ID. Expected: '%s', Actual: '%s'.", i, expectedLMSID, assignment.GetLMSID())
+			}
+		}
+	}
+}
+
+// A launch claiming the email of an existing (unbound) account must not be able to authenticate as that account.
+func TestApplyLaunchUnboundExistingUser(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	launch := &Launch{Issuer: TEST_ISSUER, CourseID: "course101", Email: "course-student@test.edulinq.org", Role: model.CourseRoleGrader, Subject: "subject-1"}
+
+	result, err := ApplyLaunch(launch)
+	if err != nil {
+		test.Fatalf("Failed to apply launch: '%v'.", err)
+	}
+
+	if (result.User != nil) || (result.Token != "") || (result.BindTicket == "") {
+		test.Fatalf("Unexpected launch result: '%s'.", util.MustToJSONIndent(result))
+	}
+
+	// The user should not have been changed.
+	user := db.MustGetServerUser(launch.Email)
+	if user.CourseInfo["course101"].Role != model.CourseRoleStudent {
+		test.Fatalf("Unbound launch changed the user's role: '%s'.", user.CourseInfo["course101"].Role.String())
+	}
+
+	for _, token := range user.Tokens {
+		if token.Name == LAUNCH_TOKEN_NAME {
+			test.Fatalf("Unbound launch created a token.")
+		}
+	}
+
+	// Another user cannot redeem the ticket.
+	_, err = BindLaunch(result.BindTicket, db.MustGetServerUser("course-other@test.edulinq.org"))
+	if err == nil {
+		test.Fatalf("Ticket was redeemed by a different user.")
+	}
+
+	// Tickets can only be used once (even if the redeem failed).
+	_, err = BindLaunch(result.BindTicket, user)
+	if err == nil {
+		test.Fatalf("Ticket was used twice.")
+	}
+
+	// Bind for real.
+	result, err = ApplyLaunch(launch)
+	if err != nil {
+		test.Fatalf("Failed to apply second launch: '%v'.", err)
+	}
+
+	boundLaunch, err := BindLaunch(result.BindTicket, user)
+	if err != nil {
+		test.Fatalf("Failed to bind launch: '%v'.", err)
+	}
+
+	if boundLaunch.CourseID != launch.CourseID {
+		test.Fatalf("Unexpected bound launch course. Expected: '%s', Actual: '%s'.", launch.CourseID, boundLaunch.CourseID)
+	}
+
+	user = db.MustGetServerUser(launch.Email)
+	if user.CourseInfo["course101"].Role != model.CourseRoleGrader {
+		test.Fatalf("Bound launch did not change the user's role: '%s'.", user.CourseInfo["course101"].Role.String())
+	}
+
+	// Now launches get a token.
+	result, err = ApplyLaunch(launch)
+	if err != nil {
+		test.Fatalf("Failed to apply bound launch: '%v'.", err)
+	}
+
+	if (result.User == nil) || (result.Token == "") || (result.BindTicket != "") {
+		test.Fatalf("Unexpected bound launch result: '%s'.", util.MustToJSONIndent(result))
+	}
+}
+
+func TestApplyLaunchElevatedServerRole(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	for _, email := range []string{"server-admin@test.edulinq.org", "server-owner@test.edulinq.org"} {
+		launch := &Launch{Issuer: TEST_ISSUER, CourseID: "course101", Email: email, Role: model.CourseRoleOwner, Subject: email}
+
+		err := db.SetLTIIdentity(launch.Issuer, launch.Subject, email)
+		if err != nil {
+			test.Fatalf("Failed to bind identity for '%s': '%v'.", email, err)
+		}
+
+		result, err := ApplyLaunch(launch)
+		if err != nil {
+			test.Fatalf("Failed to apply launch for '%s': '%v'.", email, err)
+		}
+
+		if (result.User == nil) || (result.Token != "") {
+			test.Fatalf("Unexpected launch result for '%s': '%s'.", email, util.MustToJSONIndent(result))
+		}
+
+		for _, token := range db.MustGetServerUser(email).Tokens {
+			if token.Name == LAUNCH_TOKEN_NAME {
+				test.Fatalf("Launch token was created for '%s'.", email)
+			}
+		}
+	}
+}
+
+func TestBindLaunchExpired(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	launch := &Launch{Issuer: TEST_ISSUER, CourseID: "course101", Email: "course-student@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-1"}
+
+	now := timestamp.Now()
+
+	result, err := applyLaunch(launch, now)
+	if err != nil {
+		test.Fatalf("Failed to apply launch: '%v'.", err)
+	}
+
+	_, err = bindLaunch(result.BindTicket, db.MustGetServerUser(launch.Email), now+BIND_TICKET_TTL+1)
+	if err == nil {
+		test.Fatalf("Expired ticket was redeemed.")
+	}
+}
+
+func TestLaunchTokenExpired(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	launch := &Launch{Issuer: TEST_ISSUER, CourseID: "course101", Email: "new@test.edulinq.org", Role: model.CourseRoleStudent, Subject: "subject-new"}
+
+	// Launch long enough ago that the token has already expired.
+	result, err := applyLaunch(launch, timestamp.Now()-LAUNCH_TOKEN_TTL-1)
+	if err != nil {
+		test.Fatalf("Failed to apply launch: '%v'.", err)
+	}
+
+	if result.Token == "" {
+		test.Fatalf("Did not get a launch token.")
+	}
+
+	ok, err := db.MustGetServerUser(launch.Email).Auth(util.Sha256HexFromString(result.Token))
+	if err != nil {
+		test.Fatalf("Failed to auth: '%v'.", err)
+	}
+
+	if ok {
+		test.Fatalf("Expired launch token still authenticates.")
+	}
+}
@@ -32,6 +32,13 @@ type AnalysisFileInfo struct {
 	Filename         string `json:"filename"`
 	OriginalFilename string `json:"original-filename,omitempty"`
 	LinesOfCode      int    `json:"lines-of-code"`
+
+	// Code quality metrics are only computed for known programming languages.
+	Language             string  `json:"language,omitempty"`
+	CyclomaticComplexity int     `json:"cyclomatic-complexity,omitempty"`
+	FunctionCount        int     `json:"function-count,omitempty"`
+	CommentRatio         float64 `json:"comment-ratio,omitempty"`
+	MaxNestingDepth      int     `json:"max-nesting-depth,omitempty"`
 }
 
 type FileSimilarity struct {
@@ -70,11 +77,19 @@ type IndividualAnalysis struct {
 
 	SubmissionStartTime timestamp.Timestamp `json:"submission-start-time,omitempty"`
 	Score               float64             `json:"score,omitempty"`
+	MaxScore            float64             `json:"max-score,omitempty"`
 
 	Files        []AnalysisFileInfo `json:"files,omitempty,omitzero"`
 	SkippedFiles []string           `json:"skipped-files,omitempty,omitzero"`
 	LinesOfCode  int                `json:"lines-of-code,omitempty"`
 
+	// Code quality metrics over all files (with a known programming language).
+	// Complexity and functions are summed, the comment ratio is weighted by lines of code, and nesting depth is the max.
+	CyclomaticComplexity int     `json:"cyclomatic-complexity,omitempty"`
+	FunctionCount        int     `json:"function-count,omitempty"`
+	CommentRatio         float64 `json:"comment-ratio,omitempty"`
+	MaxNestingDepth      int     `json:"max-nesting-depth,omitempty"`
+
 	SubmissionTimeDelta int64   `json:"submission-time-delta,omitempty"`
 	LinesOfCodeDelta    int     `json:"lines-of-code-delta,omitempty"`
 	ScoreDelta          float64 `json:"score-delta,omitempty"`
@@ -91,6 +106,11 @@ type IndividualAnalysisSummary struct {
 	AggregateLinesOfCode        util.AggregateValues            `json:"aggregate-lines-of-code"`
 	AggregateLinesOfCodePerFile map[string]util.AggregateValues `json:"aggregate-lines-of-code-per-file"`
 
+	AggregateCyclomaticComplexity util.AggregateValues `json:"aggregate-cyclomatic-complexity"`
+	AggregateFunctionCount        util.AggregateValues `json:"aggregate-function-count"`
+	AggregateCommentRatio         util.AggregateValues `json:"aggregate-comment-ratio"`
+	AggregateMaxNestingDepth      util.AggregateValues `json:"aggregate-max-nesting-depth"`
+
 	AggregateSubmissionTimeDelta util.AggregateValues `json:"aggregate-submission-time-delta"`
 	AggregateLinesOfCodeDelta    util.AggregateValues `json:"aggregate-lines-of-code-delta"`
 	AggregateScoreDelta          util.AggregateValues `json:"aggregate-score-delta"`
@@ -105,6 +125,9 @@ type PairwiseAnalysis struct {
 	AnalysisTimestamp timestamp.Timestamp `json:"analysis-timestamp"`
 	SubmissionIDs     PairwiseKey         `json:"submission-ids"`
 
+	// Set when this analysis compares a submission (LHS) against a corpus entry (RHS) instead of another submission.
+	Corpus string `json:"corpus,omitempty"`
+
 	Failure        bool   `json:"failure,omitempty"`
 	FailureMessage string `json:"failure-message,omitempty"`
 
@@ -198,6 +221,20 @@ func (this *PairwiseKey) String() string {
 	return this[0] + PAIRWISE_KEY_DELIM + this[1]
 }
 
+// Get the corpus that this key compares against (or an empty string if this key compares two submissions).
+func (this *PairwiseKey) Corpus() string {
+	if (this == nil) || !IsCorpusEntryID(this[1]) {
+		return ""
+	}
+
+	corpus, _, _, err := SplitCorpusEntryID(this[1])
+	if err != nil {
+		return ""
+	}
+
+	return corpus
+}
+
 // Get the representative course ID for this key.
 // Will return an empty string if there is no such course or the ID is malformed.
 func (this *PairwiseKey) Course() string {
@@ -244,6 +281,7 @@ func NewPairwiseAnalysis(pairwiseKey PairwiseKey, assignment *Assignment, simila
 		Options:             options,
 		AnalysisTimestamp:   timestamp.Now(),
 		SubmissionIDs:       pairwiseKey,
+		Corpus:              pairwiseKey.Corpus(),
 		Similarities:        similarities,
 		UnmatchedFiles:      unmatches,
 		SkippedFiles:        skipped,
@@ -262,6 +300,7 @@ func NewFailedPairwiseAnalysis(pairwiseKey PairwiseKey, assignment *Assignment,
 		Options:           options,
 		AnalysisTimestamp: timestamp.Now(),
 		SubmissionIDs:     pairwiseKey,
+		Corpus:            pairwiseKey.Corpus(),
 		Failure:           true,
 		FailureMessage:    message,
 	}
@@ -286,6 +325,10 @@ func NewIndividualAnalysisSummary(results []*IndividualAnalysis, pendingCount in
 
 	scores := make([]float64, 0, len(results))
 	locs := make([]float64, 0, len(results))
+	complexities := make([]float64, 0, len(results))
+	functionCounts := make([]float64, 0, len(results))
+	commentRatios := make([]float64, 0, len(results))
+	nestingDepths := make([]float64, 0, len(results))
 	timeDeltas := make([]float64, 0, len(results))
 	locDeltas := make([]float64, 0, len(results))
 	scoreDeltas := make([]float64, 0, len(results))
@@ -316,6 +359,10 @@ func NewIndividualAnalysisSummary(results []*IndividualAnalysis, pendingCount in
 
 		scores = append(scores, result.Score)
 		locs = append(locs, float64(result.LinesOfCode))
+		complexities = append(complexities, float64(result.CyclomaticComplexity))
+		functionCounts = append(functionCounts, float64(result.FunctionCount))
+		commentRatios = append(commentRatios, result.CommentRatio)
+		nestingDepths = append(nestingDepths, float64(result.MaxNestingDepth))
 		timeDeltas = append(timeDeltas, float64(result.SubmissionTimeDelta))
 		locDeltas = append(locDeltas, float64(result.LinesOfCodeDelta))
 		scoreDeltas = append(scoreDeltas, result.ScoreDelta)
@@ -337,9 +384,15 @@ func NewIndividualAnalysisSummary(results []*IndividualAnalysis, pendingCount in
 			FirstTimestamp: firstTimestamp,
 			LastTimestamp:  lastTimestamp,
 		},
-		AggregateScore:               util.ComputeAggregates(scores),
-		AggregateLinesOfCode:         util.ComputeAggregates(locs),
-		AggregateLinesOfCodePerFile:  aggregateLOCPerFile,
+		AggregateScore:              util.ComputeAggregates(scores),
+		AggregateLinesOfCode:        util.ComputeAggregates(locs),
+		AggregateLinesOfCodePerFile: aggregateLOCPerFile,
+
+		AggregateCyclomaticComplexity: util.ComputeAggregates(complexities),
+		AggregateFunctionCount:        util.ComputeAggregates(functionCounts),
+		AggregateCommentRatio:         util.ComputeAggregates(commentRatios),
+		AggregateMaxNestingDepth:      util.ComputeAggregates(nestingDepths),
+
 		AggregateSubmissionTimeDelta: util.ComputeAggregates(timeDeltas),
 		AggregateLinesOfCodeDelta:    util.ComputeAggregates(locDeltas),
 		AggregateScoreDelta:          util.ComputeAggregates(scoreDeltas),
@@ -416,9 +469,14 @@ func (this *IndividualAnalysis) RoundWithPrecision(precision uint) {
 	}
 
 	this.Score = util.RoundWithPrecision(this.Score, precision)
+	this.CommentRatio = util.RoundWithPrecision(this.CommentRatio, precision)
 	this.ScoreDelta = util.RoundWithPrecision(this.ScoreDelta, precision)
 	this.LinesOfCodeVelocity = util.RoundWithPrecision(this.LinesOfCodeVelocity, precision)
 	this.ScoreVelocity = util.RoundWithPrecision(this.ScoreVelocity, precision)
+
+	for i, _ := range this.Files {
+		this.Files[i].CommentRatio = util.RoundWithPrecision(this.Files[i].CommentRatio, precision)
+	}
 }
 
 func (this *PairwiseAnalysis) RoundWithPrecision(precision uint) {
@@ -446,6 +504,10 @@ func (this *IndividualAnalysisSummary) RoundWithPrecision(precision uint) {
 
 	this.AggregateScore = this.AggregateScore.RoundWithPrecision(precision)
 	this.AggregateLinesOfCode = this.AggregateLinesOfCode.RoundWithPrecision(precision)
+	this.AggregateCyclomaticComplexity = this.AggregateCyclomaticComplexity.RoundWithPrecision(precision)
+	this.AggregateFunctionCount = this.AggregateFunctionCount.RoundWithPrecision(precision)
+	this.AggregateCommentRatio = this.AggregateCommentRatio.RoundWithPrecision(precision)
+	this.AggregateMaxNestingDepth = this.AggregateMaxNestingDepth.RoundWithPrecision(precision)
 	this.AggregateSubmissionTimeDelta = this.AggregateSubmissionTimeDelta.RoundWithPrecision(precision)
 	this.AggregateLinesOfCodeDelta = this.AggregateLinesOfCodeDelta.RoundWithPrecision(precision)
 	this.AggregateScoreDelta = this.AggregateScoreDelta.RoundWithPrecision(precision)
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type AnomalyType string
+
+const (
+	// A submission that removed a large portion of the previous submission's code.
+	ANOMALY_TYPE_CODE_DROP AnomalyType = "code-drop"
+	// A submission that went from a near-zero score to a near-full score.
+	ANOMALY_TYPE_SCORE_JUMP AnomalyType = "score-jump"
+	// A submission with a value far outside of the class's distribution.
+	ANOMALY_TYPE_OUTLIER AnomalyType = "outlier"
+)
+
+// A single suspicious event in a student's submission timeline.
+// Flags are built from individual analysis results.
+type AnomalyFlag struct {
+	// The (1-indexed) rank of this flag, lower ranks are more suspicious.
+	Rank int `json:"rank"`
+
+	Type         AnomalyType `json:"type"`
+	SubmissionID string      `json:"submission-id"`
+	UserEmail    string      `json:"user-email"`
+
+	// The individual analysis value that was flagged (e.g., "lines-of-code-delta") and its value.
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"`
+
+	// The percentile rank (0 - 100) of the value within the class.
+	Percentile float64 `json:"percentile"`
+
+	// How anomalous this flag is (0 - 1), flags are ranked by severity.
+	Severity float64 `json:"severity"`
+
+	// A human-readable explanation of why this submission was flagged.
+	Explanation string `json:"explanation"`
+}
+
+func (this *AnomalyFlag) RoundWithPrecision(precision uint) {
+	if this == nil {
+		return
+	}
+
+	this.Value = util.RoundWithPrecision(this.Value, precision)
+	this.Percentile = util.RoundWithPrecision(this.Percentile, precision)
+	this.Severity = util.RoundWithPrecision(this.Severity, precision)
+}
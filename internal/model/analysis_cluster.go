@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A group of submissions (and corpus entries) that are all similar to each other.
+// Clusters are built from pairwise analysis results and are meant to be reviewed as a single case.
+type SimilarityCluster struct {
+	// The (1-indexed) rank of this cluster, lower ranks are more suspicious.
+	Rank int `json:"rank"`
+
+	Members []*SimilarityClusterMember `json:"members"`
+
+	// The pairs (edges) within this cluster, ordered by descending score.
+	Evidence []*SimilarityClusterEvidence `json:"evidence"`
+
+	MeanScore float64 `json:"mean-score"`
+	MaxScore  float64 `json:"max-score"`
+}
+
+type SimilarityClusterMember struct {
+	// A full submission ID or corpus entry ID.
+	ID string `json:"id"`
+
+	// Set for submissions.
+	UserEmail string `json:"user-email,omitempty"`
+	// Set for corpus entries.
+	Corpus string `json:"corpus,omitempty"`
+
+	// The highest score this member has with any other member of the cluster.
+	MaxScore float64 `json:"max-score"`
+}
+
+type SimilarityClusterEvidence struct {
+	SubmissionIDs PairwiseKey `json:"submission-ids"`
+	Score         float64     `json:"score"`
+}
+
+func (this *SimilarityCluster) RoundWithPrecision(precision uint) {
+	if this == nil {
+		return
+	}
+
+	this.MeanScore = util.RoundWithPrecision(this.MeanScore, precision)
+	this.MaxScore = util.RoundWithPrecision(this.MaxScore, precision)
+
+	for _, member := range this.Members {
+		member.MaxScore = util.RoundWithPrecision(member.MaxScore, precision)
+	}
+
+	for _, evidence := range this.Evidence {
+		evidence.Score = util.RoundWithPrecision(evidence.Score, precision)
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Corpus entry IDs look like: "corpus::<corpus>::<entry>::<hash>".
+	CORPUS_ID_PREFIX = "corpus"
+	CORPUS_ID_DELIM  = "::"
+
+	// Use all of an assignment's corpora.
+	CORPUS_ALL = "*"
+)
+
+var corpusNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_@\-][a-zA-Z0-9\._@\-]*$`)
+
+// A single entry in a named corpus for an assignment.
+// An entry is a set of files that submissions can be compared against,
+// e.g., a submission from a prior term or a known public solution.
+type CorpusEntry struct {
+	Corpus string `json:"corpus"`
+	Name   string `json:"name"`
+	// A hash of the entry's files (changes whenever the files change).
+	Hash  string   `json:"hash"`
+	Files []string `json:"files"`
+}
+
+// Corpus and entry names may only have letters, digits, periods, underscores, hyphens, and at signs (and may not start with a period).
+func ValidateCorpusName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return "", fmt.Errorf("Corpus names may not be empty.")
+	}
+
+	if !corpusNameRegex.MatchString(name) {
+		return "", fmt.Errorf("Corpus names must only have letters, digits, periods, underscores, hyphens, and at signs (and may not start with a period), found '%s'.", name)
+	}
+
+	return name, nil
+}
+
+// Get the ID that represents this entry in place of a submission ID (e.g., in a PairwiseKey).
+func (this *CorpusEntry) ID() string {
+	return strings.Join([]string{CORPUS_ID_PREFIX, this.Corpus, this.Name, this.Hash}, CORPUS_ID_DELIM)
+}
+
+func IsCorpusEntryID(id string) bool {
+	return strings.HasPrefix(id, CORPUS_ID_PREFIX+CORPUS_ID_DELIM)
+}
+
+// Returns: (corpus, entry, hash, error).
+func SplitCorpusEntryID(id string) (string, string, string, error) {
+	parts := strings.Split(id, CORPUS_ID_DELIM)
+	if (len(parts) != 4) || (parts[0] != CORPUS_ID_PREFIX) {
+		return "", "", "", fmt.Errorf("Malformed corpus entry ID '%s'.", id)
+	}
+
+	return parts[1], parts[2], parts[3], nil
+}
@@ -0,0 +1,36 @@
+package model
+
+// The matching regions between the files of two submissions (or a submission and a corpus entry).
+type PairwiseMatchReport struct {
+	SubmissionIDs PairwiseKey `json:"submission-ids"`
+
+	Files          []*FileMatches `json:"files"`
+	UnmatchedFiles [][2]string    `json:"unmatched-files,omitempty,omitzero"`
+	SkippedFiles   []string       `json:"skipped-files,omitempty,omitzero"`
+}
+
+// The matching regions between two versions of the same file.
+type FileMatches struct {
+	Filename         string `json:"filename"`
+	OriginalFilename string `json:"original-filename,omitempty"`
+
+	// The (prepared) contents of each version of the file.
+	Contents [2]string `json:"contents"`
+
+	// Matches are ordered by their position in the first file.
+	Matches []*MatchRegion `json:"matches"`
+}
+
+// A region of code that matches between two files.
+type MatchRegion struct {
+	// The lines (in each file) that the match covers.
+	Ranges [2]LineRange `json:"ranges"`
+	// The number of (normalized) tokens in the match.
+	Length int `json:"length"`
+}
+
+// An inclusive range of (1-indexed) lines.
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
@@ -292,13 +292,17 @@ func TestAssignmentAnalysisOptionsMatchRelpathBase(test *testing.T) {
 func TestNewIndividualAnalysisSummaryBase(test *testing.T) {
 	input := []*IndividualAnalysis{
 		&IndividualAnalysis{
-			Score:               10,
-			LinesOfCode:         10,
-			SubmissionTimeDelta: 0,
-			LinesOfCodeDelta:    0,
-			ScoreDelta:          0,
-			LinesOfCodeVelocity: 0,
-			ScoreVelocity:       0,
+			Score:                10,
+			LinesOfCode:          10,
+			CyclomaticComplexity: 3,
+			FunctionCount:        1,
+			CommentRatio:         0.10,
+			MaxNestingDepth:      1,
+			SubmissionTimeDelta:  0,
+			LinesOfCodeDelta:     0,
+			ScoreDelta:           0,
+			LinesOfCodeVelocity:  0,
+			ScoreVelocity:        0,
 			Files: []AnalysisFileInfo{
 				AnalysisFileInfo{
 					Filename:    "a.go",
@@ -307,13 +311,17 @@ func TestNewIndividualAnalysisSummaryBase(test *testing.T) {
 			},
 		},
 		&IndividualAnalysis{
-			Score:               20,
-			LinesOfCode:         40,
-			SubmissionTimeDelta: 12,
-			LinesOfCodeDelta:    15,
-			ScoreDelta:          20,
-			LinesOfCodeVelocity: 25,
-			ScoreVelocity:       30,
+			Score:                20,
+			LinesOfCode:          40,
+			CyclomaticComplexity: 8,
+			FunctionCount:        4,
+			CommentRatio:         0.25,
+			MaxNestingDepth:      3,
+			SubmissionTimeDelta:  12,
+			LinesOfCodeDelta:     15,
+			ScoreDelta:           20,
+			LinesOfCodeVelocity:  25,
+			ScoreVelocity:        30,
 			Files: []AnalysisFileInfo{
 				AnalysisFileInfo{
 					Filename:    "a.go",
@@ -326,13 +334,17 @@ func TestNewIndividualAnalysisSummaryBase(test *testing.T) {
 			},
 		},
 		&IndividualAnalysis{
-			Score:               30,
-			LinesOfCode:         20,
-			SubmissionTimeDelta: 32,
-			LinesOfCodeDelta:    35,
-			ScoreDelta:          40,
-			LinesOfCodeVelocity: 45,
-			ScoreVelocity:       50,
+			Score:                30,
+			LinesOfCode:          20,
+			CyclomaticComplexity: 5,
+			FunctionCount:        2,
+			CommentRatio:         0.40,
+			MaxNestingDepth:      2,
+			SubmissionTimeDelta:  32,
+			LinesOfCodeDelta:     35,
+			ScoreDelta:           40,
+			LinesOfCodeVelocity:  45,
+			ScoreVelocity:        50,
 			Files: []AnalysisFileInfo{
 				AnalysisFileInfo{
 					Filename:    "a.go",
@@ -368,6 +380,34 @@ func TestNewIndividualAnalysisSummaryBase(test *testing.T) {
 			Min:    10,
 			Max:    40,
 		},
+		AggregateCyclomaticComplexity: util.AggregateValues{
+			Count:  3,
+			Mean:   5.33,
+			Median: 5,
+			Min:    3,
+			Max:    8,
+		},
+		AggregateFunctionCount: util.AggregateValues{
+			Count:  3,
+			Mean:   2.33,
+			Median: 2,
+			Min:    1,
+			Max:    4,
+		},
+		AggregateCommentRatio: util.AggregateValues{
+			Count:  3,
+			Mean:   0.25,
+			Median: 0.25,
+			Min:    0.10,
+			Max:    0.40,
+		},
+		AggregateMaxNestingDepth: util.AggregateValues{
+			Count:  3,
+			Mean:   2,
+			Median: 2,
+			Min:    1,
+			Max:    3,
+		},
 		AggregateSubmissionTimeDelta: util.AggregateValues{
 			Count:  3,
 			Mean:   14.67,
@@ -22,14 +22,26 @@ type Assignment struct {
 	Name   string `json:"name"`
 	SortID string `json:"sort-id,omitempty"`
 
+	// Only used when pushing assignments to an LMS.
+	Description string `json:"description,omitempty"`
+
 	DueDate   *timestamp.Timestamp `json:"due-date,omitempty"`
 	MaxPoints float64              `json:"max-points,omitempty"`
 
+	// Per-user due dates (keyed by email) that replace the assignment's due date everywhere it is used
+	// (late rejection, late policies, submission selection, course grades, and reminders).
+	// Emails are normalized (trimmed and lowercased) on validation.
+	Extensions map[string]timestamp.Timestamp `json:"extensions,omitempty"`
+
 	LMSID      string             `json:"lms-id,omitempty"`
 	LatePolicy *LateGradingPolicy `json:"late-policy,omitempty"`
 
 	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+	SubmissionSelection SubmissionSelectionPolicy `json:"submission-selection,omitempty"`
+
+	ScoreRelease *ScoreReleasePolicy `json:"score-release,omitempty"`
+
 	docker.ImageInfo
 
 	AssignmentAnalysisOptions *AssignmentAnalysisOptions `json:"analysis-options,omitempty"`
@@ -69,6 +81,30 @@ func (this *Assignment) GetCourse() *Course {
 	return this.Course
 }
 
+// Get the assignment's submission selection policy, falling back to the most recent submission.
+func (this *Assignment) GetSubmissionSelection() SubmissionSelectionPolicy {
+	if this.SubmissionSelection == "" {
+		return SubmissionSelectionMostRecent
+	}
+
+	return this.SubmissionSelection
+}
+
+// Check if students can currently see the full results of their submissions.
+func (this *Assignment) IsScoreReleased() bool {
+	return this.ScoreRelease.IsReleased(timestamp.Now())
+}
+
+// Get the due date (including any grace period from the late policy), or nil if there is no due date.
+func (this *Assignment) GetEffectiveDueDate() *timestamp.Timestamp {
+	if this.DueDate == nil {
+		return nil
+	}
+
+	dueDate := this.LatePolicy.GetEffectiveDueDate(*this.DueDate)
+	return &dueDate
+}
+
 // Get the assignment's name, falling back to id if there is no name.
 func (this *Assignment) GetName() string {
 	if this.Name == "" {
@@ -106,6 +142,11 @@ func (this *Assignment) GetTemplatesDir() string {
 	return filepath.Join(this.Course.GetTemplatesDir(), this.ID)
 }
 
+// Get the directory that holds all of this assignment's analysis corpora.
+func (this *Assignment) GetCorporaDir() string {
+	return filepath.Join(config.GetCorporaDir(), this.Course.GetID(), this.ID)
+}
+
 // Ensure that the assignment is formatted correctly.
 // Missing optional components will be defaulted correctly.
 func (this *Assignment) Validate() error {
@@ -123,6 +164,11 @@ func (this *Assignment) Validate() error {
 		return fmt.Errorf("Max points cannot be negative: %f.", this.MaxPoints)
 	}
 
+	err = this.validateExtensions()
+	if err != nil {
+		return err
+	}
+
 	this.imageLock = &sync.Mutex{}
 
 	// Inherit submission limit from course or leave nil.
@@ -151,6 +197,24 @@ func (this *Assignment) Validate() error {
 		return fmt.Errorf("Failed to validate late policy: '%w'.", err)
 	}
 
+	// Ledger balances are course-wide, so total late days are only read from the course (see Course.GetTotalLateDays()).
+	if (this.LatePolicy != this.Course.LatePolicy) && (this.LatePolicy.TotalLateDays != 0) {
+		return fmt.Errorf("Total late days can only be set on the course's late policy, found %d on the assignment's late policy.",
+			this.LatePolicy.TotalLateDays)
+	}
+
+	err = this.SubmissionSelection.Validate()
+	if err != nil {
+		return fmt.Errorf("Failed to validate submission selection policy: '%w'.", err)
+	}
+
+	if this.ScoreRelease != nil {
+		err = this.ScoreRelease.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate score release policy: '%w'.", err)
+		}
+	}
+
 	if this.RelSourceDir == "" {
 		return fmt.Errorf("Relative source dir must not be empty.")
 	}
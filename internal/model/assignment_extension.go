@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Get the due date for a specific user (including any extension), or nil if there is no due date.
+func (this *Assignment) GetUserDueDate(email string) *timestamp.Timestamp {
+	extension, ok := this.Extensions[email]
+	if ok {
+		return &extension
+	}
+
+	return this.DueDate
+}
+
+// Get the due date for a specific user (including any extension and grace period), or nil if there is no due date.
+func (this *Assignment) GetEffectiveUserDueDate(email string) *timestamp.Timestamp {
+	return this.ResolveEffectiveUserDueDate(this.DueDate, email)
+}
+
+// Same as GetEffectiveUserDueDate(), but the given due date is used instead of the assignment's own due date
+// (e.g., when the due date comes from the LMS).
+// An extension always replaces the given due date.
+func (this *Assignment) ResolveEffectiveUserDueDate(dueDate *timestamp.Timestamp, email string) *timestamp.Timestamp {
+	extension, ok := this.Extensions[email]
+	if ok {
+		dueDate = &extension
+	}
+
+	if dueDate == nil {
+		return nil
+	}
+
+	effectiveDueDate := this.LatePolicy.GetEffectiveDueDate(*dueDate)
+	return &effectiveDueDate
+}
+
+// Normalize the emails of all extensions (trimmed and lowercased).
+func (this *Assignment) validateExtensions() error {
+	if this.Extensions == nil {
+		return nil
+	}
+
+	extensions := make(map[string]timestamp.Timestamp, len(this.Extensions))
+	for email, dueDate := range this.Extensions {
+		normalizedEmail := strings.ToLower(strings.TrimSpace(email))
+		if normalizedEmail == "" {
+			return fmt.Errorf("Extensions cannot have an empty email.")
+		}
+
+		_, exists := extensions[normalizedEmail]
+		if exists {
+			return fmt.Errorf("Found multiple extensions for the same email: '%s'.", normalizedEmail)
+		}
+
+		extensions[normalizedEmail] = dueDate
+	}
+
+	this.Extensions = extensions
+
+	return nil
+}
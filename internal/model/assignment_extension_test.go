@@ -0,0 +1,120 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestAssignmentValidateExtensions(test *testing.T) {
+	testCases := []struct {
+		extensions        map[string]timestamp.Timestamp
+		expected          map[string]timestamp.Timestamp
+		expectedErrorPart string
+	}{
+		{
+			nil,
+			nil,
+			"",
+		},
+		{
+			map[string]timestamp.Timestamp{"alice@test.edulinq.org": 1},
+			map[string]timestamp.Timestamp{"alice@test.edulinq.org": 1},
+			"",
+		},
+		{
+			map[string]timestamp.Timestamp{" Alice@Test.Edulinq.org ": 1, "BOB@test.edulinq.org": 2},
+			map[string]timestamp.Timestamp{"alice@test.edulinq.org": 1, "bob@test.edulinq.org": 2},
+			"",
+		},
+		{
+			map[string]timestamp.Timestamp{" ": 1},
+			nil,
+			"Extensions cannot have an empty email.",
+		},
+		{
+			map[string]timestamp.Timestamp{"alice@test.edulinq.org": 1, "ALICE@test.edulinq.org": 2},
+			nil,
+			"Found multiple extensions for the same email",
+		},
+	}
+
+	for i, testCase := range testCases {
+		assignment := &Assignment{
+			ID:           "hw0",
+			Course:       &Course{ID: "course101"},
+			RelSourceDir: "hw0",
+			ImageInfo: docker.ImageInfo{
+				Image: "alpine:latest",
+			},
+			Extensions: testCase.extensions,
+		}
+
+		err := assignment.Validate()
+		if err != nil {
+			if testCase.expectedErrorPart == "" {
+				test.Errorf("Case %d: Failed to validate assignment: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.expectedErrorPart) {
+				test.Errorf("Case %d: Did not get expected error. Expected Substring: '%s', Actual: '%v'.", i, testCase.expectedErrorPart, err)
+			}
+
+			continue
+		}
+
+		if testCase.expectedErrorPart != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.expectedErrorPart)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, assignment.Extensions) {
+			test.Errorf("Case %d: Unexpected extensions. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(assignment.Extensions))
+		}
+	}
+}
+
+func TestAssignmentValidateTotalLateDays(test *testing.T) {
+	testCases := []struct {
+		coursePolicy      *LateGradingPolicy
+		assignmentPolicy  *LateGradingPolicy
+		expectedErrorPart string
+	}{
+		{nil, nil, ""},
+		{&LateGradingPolicy{Type: LateDays, Penalty: 0.1, MaxLateDays: 1, TotalLateDays: 3}, nil, ""},
+		{&LateGradingPolicy{Type: LateDays, Penalty: 0.1, MaxLateDays: 1, TotalLateDays: 3}, &LateGradingPolicy{Type: LateDays, Penalty: 0.2, MaxLateDays: 2}, ""},
+		{&LateGradingPolicy{Type: LateDays, Penalty: 0.1, MaxLateDays: 1, TotalLateDays: 3}, &LateGradingPolicy{Type: LateDays, Penalty: 0.2, MaxLateDays: 2, TotalLateDays: 3}, "can only be set on the course's late policy"},
+		{&LateGradingPolicy{Type: LateDays, Penalty: 0.1, MaxLateDays: 1, TotalLateDays: 3}, &LateGradingPolicy{Type: LateDays, Penalty: 0.2, MaxLateDays: 2, TotalLateDays: 4}, "can only be set on the course's late policy"},
+		{nil, &LateGradingPolicy{Type: LateDays, Penalty: 0.2, MaxLateDays: 2, TotalLateDays: 4}, "can only be set on the course's late policy"},
+	}
+
+	for i, testCase := range testCases {
+		assignment := &Assignment{
+			ID:           "hw0",
+			Course:       &Course{ID: "course101", LatePolicy: testCase.coursePolicy},
+			RelSourceDir: "hw0",
+			ImageInfo: docker.ImageInfo{
+				Image: "alpine:latest",
+			},
+			LatePolicy: testCase.assignmentPolicy,
+		}
+
+		err := assignment.Validate()
+		if err != nil {
+			if testCase.expectedErrorPart == "" {
+				test.Errorf("Case %d: Failed to validate assignment: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.expectedErrorPart) {
+				test.Errorf("Case %d: Did not get expected error. Expected Substring: '%s', Actual: '%v'.", i, testCase.expectedErrorPart, err)
+			}
+
+			continue
+		}
+
+		if testCase.expectedErrorPart != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.expectedErrorPart)
+		}
+	}
+}
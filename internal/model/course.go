@@ -28,6 +28,9 @@ type Course struct {
 	// A common submission limit that assignments can inherit.
 	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
 
+	// How the final course grade is computed.
+	GradeScheme *GradeScheme `json:"grade-scheme,omitempty"`
+
 	Tasks []*UserTaskInfo `json:"tasks,omitempty"`
 
 	// Internal fields the autograder will set.
@@ -66,6 +69,20 @@ func (this *Course) HasLMSAdapter() bool {
 	return (this.LMS != nil)
 }
 
+// Get the number of late days each user starts with in the late day ledger (from the course's late policy).
+// This is the only source for the total, both ledger balances (API) and scoring use it.
+func (this *Course) GetTotalLateDays() int {
+	if this.LatePolicy == nil {
+		return 0
+	}
+
+	return this.LatePolicy.TotalLateDays
+}
+
+func (this *Course) GetGradeScheme() *GradeScheme {
+	return this.GradeScheme
+}
+
 func (this *Course) GetAssignmentLMSIDs() ([]string, []string) {
 	lmsIDs := make([]string, 0, len(this.Assignments))
 	assignmentIDs := make([]string, 0, len(this.Assignments))
@@ -111,6 +128,13 @@ func (this *Course) Validate() error {
 		}
 	}
 
+	if this.GradeScheme != nil {
+		err = this.GradeScheme.Validate(this.Assignments)
+		if err != nil {
+			return fmt.Errorf("Failed to validate grade scheme: '%w'.", err)
+		}
+	}
+
 	if this.Tasks == nil {
 		this.Tasks = make([]*UserTaskInfo, 0)
 	}
@@ -247,6 +271,11 @@ func (this *Course) GetTemplatesDir() string {
 	return filepath.Join(config.GetTemplatesDir(), this.GetID())
 }
 
+// Get the dir for files written by a file LMS (which lives outside the source dir, since the source dir is replaced on updates).
+func (this *Course) GetLMSFilesDir() string {
+	return filepath.Join(config.GetLMSFilesDir(), this.GetID())
+}
+
 func (this *Course) GetSourceConfigPath() string {
 	return filepath.Join(this.GetBaseSourceDir(), COURSE_CONFIG_FILENAME)
 }
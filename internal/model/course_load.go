@@ -36,6 +36,14 @@ func LoadCourseFromPath(path string, isSourceDir bool) (*Course, error) {
 		}
 	}
 
+	// Now that the assignments are loaded, the grade scheme can be checked against them.
+	if course.GradeScheme != nil {
+		err = course.GradeScheme.Validate(course.Assignments)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to validate grade scheme for course config '%s': '%w'.", path, err)
+		}
+	}
+
 	return course, nil
 }
 
@@ -65,12 +73,13 @@ func ReadCourseConfig(path string) (*Course, error) {
 		return nil, fmt.Errorf("Could not load course config (%s): '%w'.", path, err)
 	}
 
-	course.Assignments = make(map[string]*Assignment)
-
+	// Assignments are not loaded yet, so they will not be checked during validation.
 	err = course.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("Could not validate course config (%s): '%w'.", path, err)
 	}
 
+	course.Assignments = make(map[string]*Assignment)
+
 	return &course, nil
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/util"
 )
 
 func TestFullLoadCourseBase(test *testing.T) {
@@ -28,3 +29,51 @@ func TestFullLoadCourseBase(test *testing.T) {
 		test.Fatalf("Unexpected number of submissions. Expected %d, Actual: %d.", 3, len(submissions))
 	}
 }
+
+// A grade scheme that references an unknown assignment fails when the course is loaded.
+func TestLoadCourseGradeSchemeUnknownAssignment(test *testing.T) {
+	tempDir, err := util.MkDirTemp("course-load-grade-scheme-")
+	if err != nil {
+		test.Fatalf("Failed to create temp dir: '%v'.", err)
+	}
+	defer util.RemoveDirent(tempDir)
+
+	err = util.CopyDirContents(filepath.Join(config.GetTestdataDir(), "course101"), tempDir)
+	if err != nil {
+		test.Fatalf("Failed to copy test course: '%v'.", err)
+	}
+
+	testCases := []struct {
+		assignmentID string
+		expectError  bool
+	}{
+		{"hw0", false},
+		{"zzz", true},
+	}
+
+	for i, testCase := range testCases {
+		path := filepath.Join(tempDir, COURSE_CONFIG_FILENAME)
+
+		var rawCourse map[string]any
+		err = util.JSONFromFile(path, &rawCourse)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to read course config: '%v'.", i, err)
+		}
+
+		rawCourse["grade-scheme"] = &GradeScheme{
+			Categories: []*GradeCategory{&GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{testCase.assignmentID}}},
+		}
+
+		err = util.ToJSONFileIndent(rawCourse, path)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to write course config: '%v'.", i, err)
+		}
+
+		_, err = LoadCourseFromPath(path, true)
+		if testCase.expectError && (err == nil) {
+			test.Errorf("Case %d: Did not get an error on an unknown assignment.", i)
+		} else if !testCase.expectError && (err != nil) {
+			test.Errorf("Case %d: Failed to load course: '%v'.", i, err)
+		}
+	}
+}
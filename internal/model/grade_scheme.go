@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/common"
+)
+
+// How a course's final grade is computed from its assignments.
+type GradeScheme struct {
+	Categories []*GradeCategory `json:"categories"`
+
+	// Letter grades, checked in descending order of their minimum percent.
+	LetterGrades []*LetterGrade `json:"letter-grades,omitempty"`
+
+	// If set, the current course grade (as a percent) will be uploaded to this LMS assignment.
+	LMSID string `json:"lms-id,omitempty"`
+}
+
+type GradeCategory struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+
+	Assignments []string `json:"assignments"`
+
+	// The number of lowest scoring assignments to ignore in this category.
+	DropLowest int `json:"drop-lowest,omitempty"`
+
+	// Extra credit categories are added on top of the normal grade and do not count towards the total weight.
+	ExtraCredit bool `json:"extra-credit,omitempty"`
+}
+
+type LetterGrade struct {
+	Letter     string  `json:"letter"`
+	MinPercent float64 `json:"min-percent"`
+}
+
+// If assignments is not nil, then every assignment in the scheme must be one of the given assignments.
+// Assignments are nil when only the course config has been loaded (and assignments have not been loaded yet).
+func (this *GradeScheme) Validate(assignments map[string]*Assignment) error {
+	if len(this.Categories) == 0 {
+		return fmt.Errorf("Grade scheme has no categories.")
+	}
+
+	categoryNames := make(map[string]bool, len(this.Categories))
+	assignmentCategories := make(map[string]string)
+	totalWeight := 0.0
+
+	for i, category := range this.Categories {
+		if category == nil {
+			return fmt.Errorf("Grade category at index %d is nil.", i)
+		}
+
+		err := category.Validate()
+		if err != nil {
+			return fmt.Errorf("Grade category at index %d is invalid: '%w'.", i, err)
+		}
+
+		if categoryNames[category.Name] {
+			return fmt.Errorf("Found multiple grade categories with the same name: '%s'.", category.Name)
+		}
+
+		categoryNames[category.Name] = true
+
+		for _, assignmentID := range category.Assignments {
+			otherName, ok := assignmentCategories[assignmentID]
+			if ok {
+				return fmt.Errorf("Assignment '%s' appears in multiple grade categories: ['%s', '%s'].", assignmentID, otherName, category.Name)
+			}
+
+			assignmentCategories[assignmentID] = category.Name
+
+			if assignments == nil {
+				continue
+			}
+
+			_, ok = assignments[assignmentID]
+			if !ok {
+				return fmt.Errorf("Grade category '%s' references unknown assignment '%s'.", category.Name, assignmentID)
+			}
+		}
+
+		if !category.ExtraCredit {
+			totalWeight += category.Weight
+		}
+	}
+
+	if totalWeight <= 0.0 {
+		return fmt.Errorf("Total weight of non-extra credit grade categories must be positive, found %f.", totalWeight)
+	}
+
+	letters := make(map[string]bool, len(this.LetterGrades))
+	for i, letterGrade := range this.LetterGrades {
+		if letterGrade == nil {
+			return fmt.Errorf("Letter grade at index %d is nil.", i)
+		}
+
+		if letterGrade.Letter == "" {
+			return fmt.Errorf("Letter grade at index %d has an empty letter.", i)
+		}
+
+		if letters[letterGrade.Letter] {
+			return fmt.Errorf("Found multiple letter grades with the same letter: '%s'.", letterGrade.Letter)
+		}
+
+		letters[letterGrade.Letter] = true
+
+		if letterGrade.MinPercent < 0.0 {
+			return fmt.Errorf("Letter grade '%s' has a negative minimum percent: %f.", letterGrade.Letter, letterGrade.MinPercent)
+		}
+	}
+
+	slices.SortStableFunc(this.LetterGrades, func(a *LetterGrade, b *LetterGrade) int {
+		if a.MinPercent > b.MinPercent {
+			return -1
+		} else if a.MinPercent < b.MinPercent {
+			return 1
+		}
+
+		return 0
+	})
+
+	return nil
+}
+
+func (this *GradeCategory) Validate() error {
+	if this.Name == "" {
+		return fmt.Errorf("Grade category has no name.")
+	}
+
+	if this.Weight < 0.0 {
+		return fmt.Errorf("Grade category '%s' has a negative weight: %f.", this.Name, this.Weight)
+	}
+
+	if len(this.Assignments) == 0 {
+		return fmt.Errorf("Grade category '%s' has no assignments.", this.Name)
+	}
+
+	for i, assignmentID := range this.Assignments {
+		id, err := common.ValidateID(assignmentID)
+		if err != nil {
+			return fmt.Errorf("Grade category '%s' has an invalid assignment ID ('%s'): '%w'.", this.Name, assignmentID, err)
+		}
+
+		this.Assignments[i] = id
+	}
+
+	if this.DropLowest < 0 {
+		return fmt.Errorf("Grade category '%s' has a negative drop lowest: %d.", this.Name, this.DropLowest)
+	}
+
+	if this.DropLowest >= len(this.Assignments) {
+		return fmt.Errorf("Grade category '%s' drops %d assignments, but only has %d.", this.Name, this.DropLowest, len(this.Assignments))
+	}
+
+	return nil
+}
+
+// Get the total weight of all non-extra credit categories.
+func (this *GradeScheme) TotalWeight() float64 {
+	totalWeight := 0.0
+	for _, category := range this.Categories {
+		if !category.ExtraCredit {
+			totalWeight += category.Weight
+		}
+	}
+
+	return totalWeight
+}
+
+// Get the letter grade for a percent (0-100), or an empty string if there is no matching letter.
+func (this *GradeScheme) GetLetterGrade(percent float64) string {
+	for _, letterGrade := range this.LetterGrades {
+		if percent >= letterGrade.MinPercent {
+			return letterGrade.Letter
+		}
+	}
+
+	return ""
+}
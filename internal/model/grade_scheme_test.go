@@ -0,0 +1,177 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGradeSchemeValidate(test *testing.T) {
+	assignments := map[string]*Assignment{
+		"hw0": &Assignment{ID: "hw0"},
+	}
+
+	testCases := []struct {
+		scheme         *GradeScheme
+		assignments    map[string]*Assignment
+		errorSubstring string
+	}{
+		{
+			&GradeScheme{
+				Categories: []*GradeCategory{
+					&GradeCategory{Name: "Homework", Weight: 60, Assignments: []string{"HW0", "hw1"}, DropLowest: 1},
+					&GradeCategory{Name: "Exams", Weight: 40, Assignments: []string{"exam"}},
+					&GradeCategory{Name: "Bonus", Weight: 5, Assignments: []string{"bonus"}, ExtraCredit: true},
+				},
+				LetterGrades: []*LetterGrade{
+					&LetterGrade{Letter: "B", MinPercent: 80},
+					&LetterGrade{Letter: "A", MinPercent: 90},
+				},
+			},
+			nil,
+			"",
+		},
+
+		{&GradeScheme{}, nil, "no categories"},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Weight: 1, Assignments: []string{"hw0"}}}},
+			nil,
+			"has no name",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: -1, Assignments: []string{"hw0"}}}},
+			nil,
+			"negative weight",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1}}},
+			nil,
+			"has no assignments",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw 0"}}}},
+			nil,
+			"invalid assignment ID",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}, DropLowest: -1}}},
+			nil,
+			"negative drop lowest",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}, DropLowest: 1}}},
+			nil,
+			"drops 1 assignments",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{
+				&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}},
+				&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw1"}},
+			}},
+			nil,
+			"same name",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{
+				&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}},
+				&GradeCategory{Name: "B", Weight: 1, Assignments: []string{"HW0"}},
+			}},
+			nil,
+			"multiple grade categories",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}, ExtraCredit: true}}},
+			nil,
+			"must be positive",
+		},
+		{
+			&GradeScheme{
+				Categories:   []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}}},
+				LetterGrades: []*LetterGrade{&LetterGrade{Letter: "", MinPercent: 90}},
+			},
+			nil,
+			"empty letter",
+		},
+		{
+			&GradeScheme{
+				Categories:   []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}}},
+				LetterGrades: []*LetterGrade{&LetterGrade{Letter: "A", MinPercent: 90}, &LetterGrade{Letter: "A", MinPercent: 80}},
+			},
+			nil,
+			"same letter",
+		},
+		{
+			&GradeScheme{
+				Categories:   []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}}},
+				LetterGrades: []*LetterGrade{&LetterGrade{Letter: "F", MinPercent: -1}},
+			},
+			nil,
+			"negative minimum percent",
+		},
+
+		// Assignments are checked once they are loaded.
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"HW0"}}}},
+			assignments,
+			"",
+		},
+		{
+			&GradeScheme{Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0", "hw1"}}}},
+			assignments,
+			"unknown assignment 'hw1'",
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.scheme.Validate(testCase.assignments)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+	}
+}
+
+func TestGradeSchemeLetterGrade(test *testing.T) {
+	scheme := &GradeScheme{
+		Categories: []*GradeCategory{&GradeCategory{Name: "A", Weight: 1, Assignments: []string{"hw0"}}},
+		LetterGrades: []*LetterGrade{
+			&LetterGrade{Letter: "C", MinPercent: 70},
+			&LetterGrade{Letter: "A", MinPercent: 90},
+			&LetterGrade{Letter: "B", MinPercent: 80},
+		},
+	}
+
+	err := scheme.Validate(nil)
+	if err != nil {
+		test.Fatalf("Failed to validate scheme: '%v'.", err)
+	}
+
+	testCases := []struct {
+		percent  float64
+		expected string
+	}{
+		{105, "A"},
+		{90, "A"},
+		{89.99, "B"},
+		{80, "B"},
+		{70, "C"},
+		{69.99, ""},
+		{0, ""},
+	}
+
+	for i, testCase := range testCases {
+		actual := scheme.GetLetterGrade(testCase.percent)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected letter grade for %f. Expected: '%s', Actual: '%s'.", i, testCase.percent, testCase.expected, actual)
+		}
+	}
+}
@@ -37,6 +37,11 @@ type GradingInfo struct {
 
 	// Additional pass-through information that the grader can use.
 	AdditionalInfo map[string]any `json:"additional-info"`
+
+	// Set when this result is shown to a student before the assignment's scores are released.
+	ScoresWithheld  bool   `json:"scores-withheld,omitempty"`
+	WithheldMessage string `json:"withheld-message,omitempty"`
+	Passed          *bool  `json:"passed,omitempty"`
 }
 
 type GradedQuestion struct {
@@ -48,6 +53,23 @@ type GradedQuestion struct {
 	Message          string              `json:"message"`
 	GradingStartTime timestamp.Timestamp `json:"grading_start_time"`
 	GradingEndTime   timestamp.Timestamp `json:"grading_end_time"`
+	TestCases        []*GradedTestCase   `json:"test_cases,omitempty"`
+
+	// Set when this question is shown to a student before the assignment's scores are released.
+	ScoresWithheld bool `json:"scores-withheld,omitempty"`
+}
+
+// A structured result for a single test case within a question.
+// Hidden test cases only show their name and status to students.
+type GradedTestCase struct {
+	Name           string `json:"name"`
+	Passed         bool   `json:"passed"`
+	Hidden         bool   `json:"hidden,omitempty"`
+	Input          string `json:"input,omitempty"`
+	ExpectedOutput string `json:"expected_output,omitempty"`
+	ActualOutput   string `json:"actual_output,omitempty"`
+	Diff           string `json:"diff,omitempty"`
+	Message        string `json:"message,omitempty"`
 }
 
 func (this *GradingResult) HasTextOutput() bool {
@@ -119,6 +141,26 @@ func (this GradingInfo) Report() string {
 	return builder.String()
 }
 
+// Remove the details of any hidden test cases (leaving only the name and status).
+// This should be called before showing a result to a student.
+func (this *GradingInfo) RedactHiddenTestCases() {
+	if this == nil {
+		return
+	}
+
+	for _, question := range this.Questions {
+		if question == nil {
+			continue
+		}
+
+		for _, testCase := range question.TestCases {
+			if (testCase != nil) && testCase.Hidden {
+				testCase.Redact()
+			}
+		}
+	}
+}
+
 // Fill in the MaxPoints, Score, and (if empty) time fields.
 func (this *GradingInfo) ComputePoints() {
 	for _, question := range this.Questions {
@@ -146,6 +188,21 @@ func (this GradedQuestion) Report() string {
 		}
 	}
 
+	if len(this.TestCases) > 0 {
+		passedCount := 0
+		for _, testCase := range this.TestCases {
+			if testCase.Passed {
+				passedCount++
+			}
+		}
+
+		builder.WriteString(fmt.Sprintf("    Test Cases: %d / %d passed.\n", passedCount, len(this.TestCases)))
+
+		for _, testCase := range this.TestCases {
+			builder.WriteString(testCase.Report())
+		}
+	}
+
 	return builder.String()
 }
 
@@ -166,5 +223,88 @@ func (this *GradedQuestion) Equals(other *GradedQuestion, checkMessages bool) bo
 		return false
 	}
 
+	if len(this.TestCases) != len(other.TestCases) {
+		return false
+	}
+
+	for i := 0; i < len(this.TestCases); i++ {
+		if !this.TestCases[i].Equals(other.TestCases[i], checkMessages) {
+			return false
+		}
+	}
+
 	return true
 }
+
+func (this *GradedTestCase) Report() string {
+	var builder strings.Builder
+
+	status := "FAILED"
+	if this.Passed {
+		status = "PASSED"
+	}
+
+	name := this.Name
+	if this.Hidden {
+		name += " (hidden)"
+	}
+
+	builder.WriteString(fmt.Sprintf("    - %s: %s\n", name, status))
+
+	sections := []struct {
+		Label string
+		Text  string
+	}{
+		{"Message", this.Message},
+		{"Input", this.Input},
+		{"Expected Output", this.ExpectedOutput},
+		{"Actual Output", this.ActualOutput},
+		{"Diff", this.Diff},
+	}
+
+	for _, section := range sections {
+		if section.Text == "" {
+			continue
+		}
+
+		builder.WriteString(fmt.Sprintf("        %s:\n", section.Label))
+		for _, line := range strings.Split(strings.TrimRight(section.Text, "\n"), "\n") {
+			builder.WriteString(fmt.Sprintf("            %s\n", line))
+		}
+	}
+
+	return builder.String()
+}
+
+// Clear out all the details of this test case except for the name and status.
+func (this *GradedTestCase) Redact() {
+	this.Input = ""
+	this.ExpectedOutput = ""
+	this.ActualOutput = ""
+	this.Diff = ""
+	this.Message = ""
+}
+
+func (this *GradedTestCase) Equals(other *GradedTestCase, checkMessages bool) bool {
+	if this == other {
+		return true
+	}
+
+	if (this == nil) || (other == nil) {
+		return false
+	}
+
+	if (this.Name != other.Name) || (this.Passed != other.Passed) || (this.Hidden != other.Hidden) {
+		return false
+	}
+
+	if !checkMessages {
+		return true
+	}
+
+	return (this.Input == other.Input) &&
+		(this.ExpectedOutput == other.ExpectedOutput) &&
+		(this.ActualOutput == other.ActualOutput) &&
+		(this.Diff == other.Diff) &&
+		(this.Message == other.Message)
+}
@@ -99,3 +99,113 @@ func TestHardFailAndSkipped(test *testing.T) {
 		}
 	}
 }
+
+func TestGradedQuestionReportTestCases(test *testing.T) {
+	question := GradedQuestion{
+		Name:      "Q1",
+		MaxPoints: 2,
+		Score:     1,
+		TestCases: []*GradedTestCase{
+			&GradedTestCase{
+				Name:           "add",
+				Passed:         true,
+				Input:          "1 2",
+				ExpectedOutput: "3",
+				ActualOutput:   "3",
+			},
+			&GradedTestCase{
+				Name:           "sub",
+				Passed:         false,
+				Input:          "1 2",
+				ExpectedOutput: "-1",
+				ActualOutput:   "3",
+				Diff:           "- -1\n+ 3\n",
+			},
+			&GradedTestCase{
+				Name:   "secret",
+				Passed: false,
+				Hidden: true,
+			},
+		},
+	}
+
+	expected := `Q1: 1 / 2
+    Test Cases: 1 / 3 passed.
+    - add: PASSED
+        Input:
+            1 2
+        Expected Output:
+            3
+        Actual Output:
+            3
+    - sub: FAILED
+        Input:
+            1 2
+        Expected Output:
+            -1
+        Actual Output:
+            3
+        Diff:
+            - -1
+            + 3
+    - secret (hidden): FAILED
+`
+
+	actual := question.Report()
+	if expected != actual {
+		test.Fatalf("Unexpected report. Expected: '%s', Actual: '%s'.", expected, actual)
+	}
+}
+
+func TestRedactHiddenTestCases(test *testing.T) {
+	newInfo := func() *GradingInfo {
+		return &GradingInfo{
+			Questions: []*GradedQuestion{
+				&GradedQuestion{
+					Name: "Q1",
+					TestCases: []*GradedTestCase{
+						&GradedTestCase{
+							Name:           "visible",
+							Passed:         true,
+							Input:          "in",
+							ExpectedOutput: "out",
+							ActualOutput:   "out",
+						},
+						&GradedTestCase{
+							Name:           "hidden",
+							Passed:         false,
+							Hidden:         true,
+							Input:          "secret in",
+							ExpectedOutput: "secret out",
+							ActualOutput:   "wrong",
+							Diff:           "- secret out\n+ wrong",
+							Message:        "Secret message.",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	expected := newInfo()
+	expected.Questions[0].TestCases[1] = &GradedTestCase{
+		Name:   "hidden",
+		Passed: false,
+		Hidden: true,
+	}
+
+	actual := newInfo()
+	actual.RedactHiddenTestCases()
+
+	if !expected.Equals(*actual, true) {
+		test.Fatalf("Unexpected result. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+
+	// Make sure the JSON round trip keeps the test cases.
+	var roundTrip GradingInfo
+	util.MustJSONFromString(util.MustToJSON(actual), &roundTrip)
+
+	if !actual.Equals(roundTrip, true) {
+		test.Fatalf("Unexpected JSON round trip. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(actual), util.MustToJSONIndent(roundTrip))
+	}
+}
@@ -0,0 +1,395 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type IntegrityCaseStatus string
+
+const (
+	IntegrityCaseStatusOpen        IntegrityCaseStatus = "open"
+	IntegrityCaseStatusUnderReview IntegrityCaseStatus = "under-review"
+	IntegrityCaseStatusResolved    IntegrityCaseStatus = "resolved"
+)
+
+// The allowed status changes: {from: {to, ...}, ...}.
+// A resolved case may be reopened by putting it back under review.
+var integrityCaseTransitions = map[IntegrityCaseStatus][]IntegrityCaseStatus{
+	IntegrityCaseStatusOpen:        []IntegrityCaseStatus{IntegrityCaseStatusUnderReview, IntegrityCaseStatusResolved},
+	IntegrityCaseStatusUnderReview: []IntegrityCaseStatus{IntegrityCaseStatusOpen, IntegrityCaseStatusResolved},
+	IntegrityCaseStatusResolved:    []IntegrityCaseStatus{IntegrityCaseStatusUnderReview},
+}
+
+// Outcomes are only set on resolved cases.
+type IntegrityCaseOutcome string
+
+const (
+	IntegrityCaseOutcomeNone         IntegrityCaseOutcome = ""
+	IntegrityCaseOutcomeNoViolation  IntegrityCaseOutcome = "no-violation"
+	IntegrityCaseOutcomeViolation    IntegrityCaseOutcome = "violation"
+	IntegrityCaseOutcomeInconclusive IntegrityCaseOutcome = "inconclusive"
+)
+
+type IntegrityPenaltyType string
+
+const (
+	// Remove a fixed number of points.
+	IntegrityPenaltyPoints IntegrityPenaltyType = "points"
+	// Remove a fraction (0 - 1) of the score.
+	IntegrityPenaltyPercentage IntegrityPenaltyType = "percentage"
+	// Set the score to zero.
+	IntegrityPenaltyZero IntegrityPenaltyType = "zero"
+)
+
+// The kinds of changes recorded in a case's history.
+type IntegrityCaseAction string
+
+const (
+	IntegrityCaseActionCreate     IntegrityCaseAction = "create"
+	IntegrityCaseActionUpdate     IntegrityCaseAction = "update"
+	IntegrityCaseActionStatus     IntegrityCaseAction = "status"
+	IntegrityCaseActionNote       IntegrityCaseAction = "note"
+	IntegrityCaseActionAttachment IntegrityCaseAction = "attachment"
+	IntegrityCaseActionPenalty    IntegrityCaseAction = "penalty"
+)
+
+// A record of a possible academic integrity violation in a course.
+// A case links together the users, submissions, and pairwise analysis results involved,
+// and keeps a history (audit trail) of every change made to it.
+type IntegrityCase struct {
+	ID           string `json:"id"`
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id,omitempty"`
+	Title        string `json:"title"`
+
+	Status  IntegrityCaseStatus  `json:"status"`
+	Outcome IntegrityCaseOutcome `json:"outcome,omitempty"`
+
+	// Users are automatically added for any linked submissions.
+	Users         []string      `json:"users"`
+	SubmissionIDs []string      `json:"submission-ids"`
+	PairwiseKeys  []PairwiseKey `json:"pairwise-keys"`
+
+	Penalty *IntegrityPenalty `json:"penalty,omitempty"`
+
+	Notes       []*IntegrityCaseNote       `json:"notes"`
+	Attachments []*IntegrityCaseAttachment `json:"attachments"`
+	History     []*IntegrityCaseEvent      `json:"history"`
+
+	CreatedTime timestamp.Timestamp `json:"created-time"`
+	UpdatedTime timestamp.Timestamp `json:"updated-time"`
+}
+
+type IntegrityPenalty struct {
+	Type IntegrityPenaltyType `json:"type"`
+
+	// The number of points (for "points") or fraction of the score (for "percentage") to remove.
+	Value float64 `json:"value,omitempty"`
+
+	// Apply this penalty to the scores of the case's users for the case's assignment when scoring.
+	// Penalties are only applied for resolved cases with a violation outcome.
+	ApplyToScore bool `json:"apply-to-score"`
+}
+
+type IntegrityCaseNote struct {
+	Author    string              `json:"author"`
+	Timestamp timestamp.Timestamp `json:"timestamp"`
+	Text      string              `json:"text"`
+}
+
+// The contents of attachments are stored separately from the case.
+type IntegrityCaseAttachment struct {
+	Filename  string              `json:"filename"`
+	Size      int                 `json:"size"`
+	Author    string              `json:"author"`
+	Timestamp timestamp.Timestamp `json:"timestamp"`
+}
+
+type IntegrityCaseEvent struct {
+	Timestamp timestamp.Timestamp `json:"timestamp"`
+	Author    string              `json:"author"`
+	Action    IntegrityCaseAction `json:"action"`
+	Message   string              `json:"message"`
+}
+
+// Create a new open case (the creation will be the first entry in its history).
+func NewIntegrityCase(courseID string, assignmentID string, title string, author string) *IntegrityCase {
+	now := timestamp.Now()
+
+	integrityCase := &IntegrityCase{
+		ID:            util.UUID(),
+		CourseID:      courseID,
+		AssignmentID:  assignmentID,
+		Title:         title,
+		Status:        IntegrityCaseStatusOpen,
+		Users:         make([]string, 0),
+		SubmissionIDs: make([]string, 0),
+		PairwiseKeys:  make([]PairwiseKey, 0),
+		Notes:         make([]*IntegrityCaseNote, 0),
+		Attachments:   make([]*IntegrityCaseAttachment, 0),
+		History:       make([]*IntegrityCaseEvent, 0),
+		CreatedTime:   now,
+	}
+
+	integrityCase.AddEvent(author, IntegrityCaseActionCreate, fmt.Sprintf("Created case '%s'.", title))
+
+	return integrityCase
+}
+
+func (this *IntegrityCase) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Integrity case is nil.")
+	}
+
+	if this.ID == "" {
+		return fmt.Errorf("Integrity case has no ID.")
+	}
+
+	var err error
+
+	this.CourseID, err = common.ValidateID(this.CourseID)
+	if err != nil {
+		return fmt.Errorf("Course ID is not valid: '%w'.", err)
+	}
+
+	if this.AssignmentID != "" {
+		this.AssignmentID, err = common.ValidateID(this.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("Assignment ID is not valid: '%w'.", err)
+		}
+	}
+
+	this.Title = strings.TrimSpace(this.Title)
+	if this.Title == "" {
+		return fmt.Errorf("Integrity case has no title.")
+	}
+
+	_, ok := integrityCaseTransitions[this.Status]
+	if !ok {
+		return fmt.Errorf("Unknown integrity case status: '%s'.", this.Status)
+	}
+
+	switch this.Outcome {
+	case IntegrityCaseOutcomeNone, IntegrityCaseOutcomeNoViolation, IntegrityCaseOutcomeViolation, IntegrityCaseOutcomeInconclusive:
+	default:
+		return fmt.Errorf("Unknown integrity case outcome: '%s'.", this.Outcome)
+	}
+
+	if (this.Status == IntegrityCaseStatusResolved) && (this.Outcome == IntegrityCaseOutcomeNone) {
+		return fmt.Errorf("Resolved integrity cases must have an outcome.")
+	}
+
+	if (this.Status != IntegrityCaseStatusResolved) && (this.Outcome != IntegrityCaseOutcomeNone) {
+		return fmt.Errorf("Only resolved integrity cases may have an outcome, found status '%s' with outcome '%s'.", this.Status, this.Outcome)
+	}
+
+	err = this.validateLinks()
+	if err != nil {
+		return err
+	}
+
+	if this.Penalty != nil {
+		err = this.Penalty.Validate()
+		if err != nil {
+			return fmt.Errorf("Penalty is not valid: '%w'.", err)
+		}
+
+		if this.Penalty.ApplyToScore && (this.AssignmentID == "") {
+			return fmt.Errorf("Penalties can only be applied to scores for cases with an assignment.")
+		}
+	}
+
+	filenames := make(map[string]bool, len(this.Attachments))
+	for _, attachment := range this.Attachments {
+		filename := attachment.Filename
+		if (filename == "") || (filename == ".") || (filename == "..") || (filepath.Base(filename) != filename) {
+			return fmt.Errorf("Attachment filename is not valid: '%s'.", filename)
+		}
+
+		if filenames[filename] {
+			return fmt.Errorf("Found multiple attachments with the same filename: '%s'.", filename)
+		}
+
+		filenames[filename] = true
+	}
+
+	return nil
+}
+
+// Normalize the linked users, submissions, and pairs (adding users for linked submissions).
+func (this *IntegrityCase) validateLinks() error {
+	users := make(map[string]bool, len(this.Users))
+	for _, email := range this.Users {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			return fmt.Errorf("Linked users cannot be empty.")
+		}
+
+		users[email] = true
+	}
+
+	submissionIDs := make(map[string]bool, len(this.SubmissionIDs))
+	for _, id := range this.SubmissionIDs {
+		email, err := this.validateSubmissionID(id)
+		if err != nil {
+			return err
+		}
+
+		submissionIDs[id] = true
+		users[email] = true
+	}
+
+	pairwiseKeys := make(map[string]PairwiseKey, len(this.PairwiseKeys))
+	for _, key := range this.PairwiseKeys {
+		key = NewPairwiseKey(key[0], key[1])
+
+		for _, id := range key {
+			// Corpus entries do not belong to any user.
+			if IsCorpusEntryID(id) {
+				continue
+			}
+
+			email, err := this.validateSubmissionID(id)
+			if err != nil {
+				return err
+			}
+
+			users[email] = true
+		}
+
+		pairwiseKeys[key.String()] = key
+	}
+
+	this.Users = sortedKeys(users)
+	this.SubmissionIDs = sortedKeys(submissionIDs)
+
+	this.PairwiseKeys = make([]PairwiseKey, 0, len(pairwiseKeys))
+	for _, key := range sortedKeys(pairwiseKeys) {
+		this.PairwiseKeys = append(this.PairwiseKeys, pairwiseKeys[key])
+	}
+
+	return nil
+}
+
+// Validate that a full submission ID is from this case's course, and return the submission's user.
+func (this *IntegrityCase) validateSubmissionID(id string) (string, error) {
+	courseID, _, email, _, err := common.SplitFullSubmissionID(id)
+	if err != nil {
+		return "", fmt.Errorf("Linked submission '%s' is not valid: '%w'.", id, err)
+	}
+
+	if courseID != this.CourseID {
+		return "", fmt.Errorf("Linked submission '%s' is not from this case's course ('%s').", id, this.CourseID)
+	}
+
+	return email, nil
+}
+
+// Record a change in this case's history.
+func (this *IntegrityCase) AddEvent(author string, action IntegrityCaseAction, message string) {
+	now := timestamp.Now()
+
+	this.History = append(this.History, &IntegrityCaseEvent{
+		Timestamp: now,
+		Author:    author,
+		Action:    action,
+		Message:   message,
+	})
+
+	this.UpdatedTime = now
+}
+
+// Change this case's status (and outcome), recording the change in the case's history.
+// Outcomes must be given when resolving a case, and may not be given otherwise.
+func (this *IntegrityCase) SetStatus(status IntegrityCaseStatus, outcome IntegrityCaseOutcome, author string) error {
+	if !slices.Contains(integrityCaseTransitions[this.Status], status) {
+		return fmt.Errorf("Integrity case cannot change from status '%s' to status '%s'.", this.Status, status)
+	}
+
+	if (status == IntegrityCaseStatusResolved) && (outcome == IntegrityCaseOutcomeNone) {
+		return fmt.Errorf("An outcome is required to resolve an integrity case.")
+	}
+
+	if (status != IntegrityCaseStatusResolved) && (outcome != IntegrityCaseOutcomeNone) {
+		return fmt.Errorf("An outcome can only be given when resolving an integrity case.")
+	}
+
+	message := fmt.Sprintf("Changed status from '%s' to '%s'.", this.Status, status)
+	if outcome != IntegrityCaseOutcomeNone {
+		message = fmt.Sprintf("Changed status from '%s' to '%s' with outcome '%s'.", this.Status, status, outcome)
+	}
+
+	this.Status = status
+	this.Outcome = outcome
+	this.AddEvent(author, IntegrityCaseActionStatus, message)
+
+	return nil
+}
+
+// Check if this case's penalty should be applied to scores.
+func (this *IntegrityCase) IsPenaltyActive() bool {
+	return ((this.Status == IntegrityCaseStatusResolved) &&
+		(this.Outcome == IntegrityCaseOutcomeViolation) &&
+		(this.Penalty != nil) &&
+		this.Penalty.ApplyToScore)
+}
+
+func (this *IntegrityPenalty) Validate() error {
+	switch this.Type {
+	case IntegrityPenaltyPoints:
+		if this.Value < 0.0 {
+			return fmt.Errorf("Point penalties cannot be negative, found %f.", this.Value)
+		}
+	case IntegrityPenaltyPercentage:
+		if (this.Value < 0.0) || (this.Value > 1.0) {
+			return fmt.Errorf("Percentage penalties must be in [0.0, 1.0], found %f.", this.Value)
+		}
+	case IntegrityPenaltyZero:
+		this.Value = 0.0
+	default:
+		return fmt.Errorf("Unknown penalty type: '%s'.", this.Type)
+	}
+
+	return nil
+}
+
+// Get a score after this penalty has been applied (scores will never go below zero).
+func (this *IntegrityPenalty) Apply(score float64) float64 {
+	switch this.Type {
+	case IntegrityPenaltyPoints:
+		return math.Max(0.0, score-this.Value)
+	case IntegrityPenaltyPercentage:
+		return math.Max(0.0, score*(1.0-this.Value))
+	case IntegrityPenaltyZero:
+		return 0.0
+	default:
+		return score
+	}
+}
+
+func (this *IntegrityPenalty) String() string {
+	if this == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%s (value: %v, apply to score: %v)", this.Type, this.Value, this.ApplyToScore)
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key, _ := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,201 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIntegrityCaseValidate(test *testing.T) {
+	testCases := []struct {
+		integrityCase  *IntegrityCase
+		errorSubstring string
+		expectedUsers  []string
+	}{
+		{newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), "", []string{}},
+		{newTestIntegrityCase(IntegrityCaseStatusResolved, IntegrityCaseOutcomeViolation), "", []string{}},
+
+		// Users are normalized and pulled from linked submissions (but not corpus entries).
+		{
+			&IntegrityCase{
+				ID: "a", CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen,
+				Users:         []string{" B@test.edulinq.org", "b@test.edulinq.org"},
+				SubmissionIDs: []string{"course101::hw0::a@test.edulinq.org::1", "course101::hw0::a@test.edulinq.org::1"},
+				PairwiseKeys: []PairwiseKey{
+					NewPairwiseKey("course101::hw0::c@test.edulinq.org::1", "course101::hw0::a@test.edulinq.org::1"),
+					NewPairwiseKey("corpus::old::entry::1", "course101::hw0::d@test.edulinq.org::1"),
+				},
+			},
+			"",
+			[]string{"a@test.edulinq.org", "b@test.edulinq.org", "c@test.edulinq.org", "d@test.edulinq.org"},
+		},
+
+		// Penalties.
+		{withTestPenalty(newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), IntegrityPenaltyPoints, 5, true), "", []string{}},
+		{withTestPenalty(newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), IntegrityPenaltyPercentage, 0.5, true), "", []string{}},
+		{withTestPenalty(newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), IntegrityPenaltyZero, 0, false), "", []string{}},
+		{withTestPenalty(newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), IntegrityPenaltyPoints, -1, true), "cannot be negative", nil},
+		{withTestPenalty(newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), IntegrityPenaltyPercentage, 1.5, true), "must be in", nil},
+		{withTestPenalty(newTestIntegrityCase(IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone), "ZZZ", 0, true), "Unknown penalty type", nil},
+		{
+			withTestPenalty(&IntegrityCase{ID: "a", CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen}, IntegrityPenaltyZero, 0, true),
+			"cases with an assignment",
+			nil,
+		},
+
+		// Errors.
+		{nil, "is nil", nil},
+		{&IntegrityCase{CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen}, "no ID", nil},
+		{&IntegrityCase{ID: "a", CourseID: "course101", Title: " ", Status: IntegrityCaseStatusOpen}, "no title", nil},
+		{&IntegrityCase{ID: "a", CourseID: "course101", Title: "Test", Status: "ZZZ"}, "Unknown integrity case status", nil},
+		{newTestIntegrityCase(IntegrityCaseStatusOpen, "ZZZ"), "Unknown integrity case outcome", nil},
+		{newTestIntegrityCase(IntegrityCaseStatusResolved, IntegrityCaseOutcomeNone), "must have an outcome", nil},
+		{newTestIntegrityCase(IntegrityCaseStatusUnderReview, IntegrityCaseOutcomeViolation), "Only resolved", nil},
+		{
+			&IntegrityCase{ID: "a", CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen, SubmissionIDs: []string{"ZZZ"}},
+			"is not valid",
+			nil,
+		},
+		{
+			&IntegrityCase{ID: "a", CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen, SubmissionIDs: []string{"course-languages::bash::a@test.edulinq.org::1"}},
+			"not from this case's course",
+			nil,
+		},
+		{
+			&IntegrityCase{ID: "a", CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen, Attachments: []*IntegrityCaseAttachment{&IntegrityCaseAttachment{Filename: "../a.txt"}}},
+			"Attachment filename is not valid",
+			nil,
+		},
+		{
+			&IntegrityCase{ID: "a", CourseID: "course101", Title: "Test", Status: IntegrityCaseStatusOpen, Attachments: []*IntegrityCaseAttachment{&IntegrityCaseAttachment{Filename: "a.txt"}, &IntegrityCaseAttachment{Filename: "a.txt"}}},
+			"same filename",
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.integrityCase.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedUsers, testCase.integrityCase.Users) {
+			test.Errorf("Case %d: Unexpected users. Expected: '%v', Actual: '%v'.", i, testCase.expectedUsers, testCase.integrityCase.Users)
+		}
+	}
+}
+
+func TestIntegrityCaseSetStatus(test *testing.T) {
+	testCases := []struct {
+		from           IntegrityCaseStatus
+		to             IntegrityCaseStatus
+		outcome        IntegrityCaseOutcome
+		errorSubstring string
+	}{
+		{IntegrityCaseStatusOpen, IntegrityCaseStatusUnderReview, IntegrityCaseOutcomeNone, ""},
+		{IntegrityCaseStatusOpen, IntegrityCaseStatusResolved, IntegrityCaseOutcomeNoViolation, ""},
+		{IntegrityCaseStatusUnderReview, IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone, ""},
+		{IntegrityCaseStatusUnderReview, IntegrityCaseStatusResolved, IntegrityCaseOutcomeViolation, ""},
+		{IntegrityCaseStatusResolved, IntegrityCaseStatusUnderReview, IntegrityCaseOutcomeNone, ""},
+
+		{IntegrityCaseStatusOpen, IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone, "cannot change"},
+		{IntegrityCaseStatusResolved, IntegrityCaseStatusOpen, IntegrityCaseOutcomeNone, "cannot change"},
+		{IntegrityCaseStatusOpen, "ZZZ", IntegrityCaseOutcomeNone, "cannot change"},
+		{IntegrityCaseStatusOpen, IntegrityCaseStatusResolved, IntegrityCaseOutcomeNone, "outcome is required"},
+		{IntegrityCaseStatusOpen, IntegrityCaseStatusUnderReview, IntegrityCaseOutcomeViolation, "only be given when resolving"},
+	}
+
+	for i, testCase := range testCases {
+		integrityCase := NewIntegrityCase("course101", "hw0", "Test", "course-admin@test.edulinq.org")
+		integrityCase.Status = testCase.from
+		if testCase.from == IntegrityCaseStatusResolved {
+			integrityCase.Outcome = IntegrityCaseOutcomeInconclusive
+		}
+
+		err := integrityCase.SetStatus(testCase.to, testCase.outcome, "course-admin@test.edulinq.org")
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to set status: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			if len(integrityCase.History) != 1 {
+				test.Errorf("Case %d: Failed status change was recorded in the history.", i)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if (integrityCase.Status != testCase.to) || (integrityCase.Outcome != testCase.outcome) {
+			test.Errorf("Case %d: Unexpected status/outcome. Expected: '%s'/'%s', Actual: '%s'/'%s'.",
+				i, testCase.to, testCase.outcome, integrityCase.Status, integrityCase.Outcome)
+			continue
+		}
+
+		if (len(integrityCase.History) != 2) || (integrityCase.History[1].Action != IntegrityCaseActionStatus) {
+			test.Errorf("Case %d: Status change was not recorded in the history.", i)
+			continue
+		}
+
+		err = integrityCase.Validate()
+		if err != nil {
+			test.Errorf("Case %d: Case is not valid after status change: '%v'.", i, err)
+			continue
+		}
+	}
+}
+
+func TestIntegrityPenaltyApply(test *testing.T) {
+	testCases := []struct {
+		penalty  IntegrityPenalty
+		score    float64
+		expected float64
+	}{
+		{IntegrityPenalty{Type: IntegrityPenaltyPoints, Value: 5}, 10, 5},
+		{IntegrityPenalty{Type: IntegrityPenaltyPoints, Value: 15}, 10, 0},
+		{IntegrityPenalty{Type: IntegrityPenaltyPercentage, Value: 0.25}, 10, 7.5},
+		{IntegrityPenalty{Type: IntegrityPenaltyPercentage, Value: 1}, 10, 0},
+		{IntegrityPenalty{Type: IntegrityPenaltyZero}, 10, 0},
+		{IntegrityPenalty{Type: IntegrityPenaltyZero}, 0, 0},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.penalty.Apply(testCase.score)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func newTestIntegrityCase(status IntegrityCaseStatus, outcome IntegrityCaseOutcome) *IntegrityCase {
+	return &IntegrityCase{
+		ID:           "a",
+		CourseID:     "course101",
+		AssignmentID: "hw0",
+		Title:        "Test",
+		Status:       status,
+		Outcome:      outcome,
+	}
+}
+
+func withTestPenalty(integrityCase *IntegrityCase, penaltyType IntegrityPenaltyType, value float64, applyToScore bool) *IntegrityCase {
+	integrityCase.Penalty = &IntegrityPenalty{Type: penaltyType, Value: value, ApplyToScore: applyToScore}
+	return integrityCase
+}
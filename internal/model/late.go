@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -17,6 +18,12 @@ const (
 	ConstantPenalty   LateGradingPolicyType = "constant-penalty"
 	PercentagePenalty LateGradingPolicyType = "percentage-penalty"
 	LateDays          LateGradingPolicyType = "late-days"
+	// Multiply the score by (1 - penalty) for each day late.
+	ExponentialPenalty LateGradingPolicyType = "exponential-penalty"
+	// Deduct a percentage of the max points for each hour late.
+	HourlyPenalty LateGradingPolicyType = "hourly-penalty"
+	// Take the better of the best on-time score and the penalized (percentage per day) late score.
+	BestOfOnTime LateGradingPolicyType = "best-of-on-time"
 )
 
 type LateGradingPolicy struct {
@@ -24,8 +31,15 @@ type LateGradingPolicy struct {
 	Penalty         float64               `json:"penalty,omitempty"`
 	RejectAfterDays int                   `json:"reject-after-days,omitempty"`
 
+	// Submissions within this many minutes past the due date are not considered late.
+	GracePeriodMinutes int `json:"grace-period-minutes,omitempty"`
+
 	MaxLateDays   int    `json:"max-late-days,omitempty"`
 	LateDaysLMSID string `json:"late-days-lms-id,omitempty"`
+
+	// The number of late days each user starts with when late days are tracked in the autograder's ledger
+	// (when there is no LateDaysLMSID).
+	TotalLateDays int `json:"total-late-days,omitempty"`
 }
 
 func (this *LateGradingPolicy) Validate() error {
@@ -39,6 +53,10 @@ func (this *LateGradingPolicy) Validate() error {
 		return fmt.Errorf("Number of days for rejection is negative (%d), should be zero to be ignored or positive to be applied.", this.RejectAfterDays)
 	}
 
+	if this.GracePeriodMinutes < 0 {
+		return fmt.Errorf("Grace period is negative (%d), should be zero to be ignored or positive to be applied.", this.GracePeriodMinutes)
+	}
+
 	switch this.Type {
 	case EmptyPolicy, BaselinePolicy:
 		return nil
@@ -46,7 +64,7 @@ func (this *LateGradingPolicy) Validate() error {
 		if this.Penalty <= 0.0 {
 			return fmt.Errorf("Policy '%s': penalty must be larger than zero, found '%s'.", this.Type, util.FloatToStr(this.Penalty))
 		}
-	case PercentagePenalty:
+	case PercentagePenalty, ExponentialPenalty, HourlyPenalty, BestOfOnTime:
 		if (this.Penalty <= 0.0) || (this.Penalty > 1.0) {
 			return fmt.Errorf("Policy '%s': penalty must be in (0.0, 1.0], found '%s'.", this.Type, util.FloatToStr(this.Penalty))
 		}
@@ -63,8 +81,8 @@ func (this *LateGradingPolicy) Validate() error {
 			return fmt.Errorf("Policy '%s': max late days must be in [1, <reject days>(%d)], found '%d'.", this.Type, this.RejectAfterDays, this.MaxLateDays)
 		}
 
-		if this.LateDaysLMSID == "" {
-			return fmt.Errorf("Policy '%s': LMS ID for late days assignment cannot be empty.", this.Type)
+		if this.TotalLateDays < 0 {
+			return fmt.Errorf("Policy '%s': total late days cannot be negative, found '%d'.", this.Type, this.TotalLateDays)
 		}
 	default:
 		return fmt.Errorf("Unknown late policy type: '%s'.", this.Type)
@@ -72,3 +90,63 @@ func (this *LateGradingPolicy) Validate() error {
 
 	return nil
 }
+
+// Late days are tracked in the autograder's ledger instead of the LMS when there is no LMS ID.
+func (this *LateGradingPolicy) UsesLateDayLedger() bool {
+	return (this != nil) && (this.Type == LateDays) && (this.LateDaysLMSID == "")
+}
+
+// Get the due date that submissions will actually be checked against (the due date plus any grace period).
+func (this *LateGradingPolicy) GetEffectiveDueDate(dueDate timestamp.Timestamp) timestamp.Timestamp {
+	if (this == nil) || (this.GracePeriodMinutes <= 0) {
+		return dueDate
+	}
+
+	return dueDate + timestamp.FromMSecs(int64(this.GracePeriodMinutes)*60*1000)
+}
+
+// Get a human-readable description of this policy.
+func (this *LateGradingPolicy) Description() string {
+	if this == nil {
+		return "No late policy."
+	}
+
+	var description string
+
+	switch this.Type {
+	case EmptyPolicy:
+		return "No late policy."
+	case BaselinePolicy:
+		description = "Late submissions are not penalized."
+	case ConstantPenalty:
+		description = fmt.Sprintf("Late submissions lose %s points per day late.", util.FloatToStr(this.Penalty))
+	case PercentagePenalty:
+		description = fmt.Sprintf("Late submissions lose %s%% of the max points per day late.", this.penaltyPercentString())
+	case LateDays:
+		description = fmt.Sprintf("Up to %d late days may be used on this assignment, after which late submissions lose %s%% of the max points per day late.",
+			this.MaxLateDays, this.penaltyPercentString())
+	case ExponentialPenalty:
+		description = fmt.Sprintf("Late submissions have their score multiplied by %s for each day late.", util.FloatToStr(util.RoundWithPrecision(1.0-this.Penalty, 4)))
+	case HourlyPenalty:
+		description = fmt.Sprintf("Late submissions lose %s%% of the max points per hour late.", this.penaltyPercentString())
+	case BestOfOnTime:
+		description = fmt.Sprintf("The better of the best on-time score and the late score (losing %s%% of the max points per day late) is used.",
+			this.penaltyPercentString())
+	default:
+		return fmt.Sprintf("Unknown late policy '%s'.", this.Type)
+	}
+
+	if this.GracePeriodMinutes > 0 {
+		description += fmt.Sprintf(" Submissions within %d minutes of the due date are not considered late.", this.GracePeriodMinutes)
+	}
+
+	if this.RejectAfterDays > 0 {
+		description += fmt.Sprintf(" Submissions more than %d days late are rejected.", this.RejectAfterDays)
+	}
+
+	return description
+}
+
+func (this *LateGradingPolicy) penaltyPercentString() string {
+	return util.FloatToStr(util.RoundWithPrecision(this.Penalty*100.0, 2))
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type LateDayEntryType string
+
+const (
+	// Late days used by a late submission (negative days).
+	LateDayEntryCharge LateDayEntryType = "charge"
+	// Late days returned because a submission no longer needs them (positive days).
+	LateDayEntryRefund LateDayEntryType = "refund"
+	// A manual change made by course staff (positive or negative days).
+	LateDayEntryAdjustment LateDayEntryType = "adjustment"
+)
+
+// A single record in a user's late day ledger.
+// A user's balance is their initial late days plus the sum of all their entries.
+type LateDayEntry struct {
+	Email string           `json:"email"`
+	Type  LateDayEntryType `json:"type"`
+
+	// Positive values add to the user's balance, negative values take away from it.
+	Days int `json:"days"`
+
+	AssignmentID string              `json:"assignment-id,omitempty"`
+	Timestamp    timestamp.Timestamp `json:"timestamp"`
+
+	// The user that made this entry (empty for entries made by the autograder).
+	Author  string `json:"author,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func (this *LateDayEntry) Validate() error {
+	if this.Email == "" {
+		return fmt.Errorf("Late day entry has no email.")
+	}
+
+	this.Type = LateDayEntryType(strings.ToLower(string(this.Type)))
+
+	if this.Days == 0 {
+		return fmt.Errorf("Late day entry cannot have zero days.")
+	}
+
+	switch this.Type {
+	case LateDayEntryCharge:
+		if this.Days > 0 {
+			return fmt.Errorf("Late day charges must have negative days, found %d.", this.Days)
+		}
+
+		if this.AssignmentID == "" {
+			return fmt.Errorf("Late day charges must have an assignment.")
+		}
+	case LateDayEntryRefund:
+		if this.Days < 0 {
+			return fmt.Errorf("Late day refunds must have positive days, found %d.", this.Days)
+		}
+
+		if this.AssignmentID == "" {
+			return fmt.Errorf("Late day refunds must have an assignment.")
+		}
+	case LateDayEntryAdjustment:
+		// Adjustments may go either way.
+	default:
+		return fmt.Errorf("Unknown late day entry type: '%s'.", this.Type)
+	}
+
+	if this.Timestamp.IsZero() {
+		this.Timestamp = timestamp.Now()
+	}
+
+	return nil
+}
+
+// Get a user's remaining late days.
+func ComputeLateDayBalance(initialDays int, entries []*LateDayEntry) int {
+	balance := initialDays
+	for _, entry := range entries {
+		balance += entry.Days
+	}
+
+	return balance
+}
+
+// Get the number of late days currently used on an assignment (charges minus refunds).
+// Adjustments are not counted, even if they reference the assignment.
+func ComputeAllocatedLateDays(entries []*LateDayEntry, assignmentID string) int {
+	allocated := 0
+	for _, entry := range entries {
+		if entry.AssignmentID != assignmentID {
+			continue
+		}
+
+		if (entry.Type == LateDayEntryCharge) || (entry.Type == LateDayEntryRefund) {
+			allocated -= entry.Days
+		}
+	}
+
+	return allocated
+}
+
+// Get the total number of late days used across all assignments (charges minus refunds).
+func ComputeUsedLateDays(entries []*LateDayEntry) int {
+	used := 0
+	for _, entry := range entries {
+		if (entry.Type == LateDayEntryCharge) || (entry.Type == LateDayEntryRefund) {
+			used -= entry.Days
+		}
+	}
+
+	return used
+}
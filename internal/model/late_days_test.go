@@ -0,0 +1,77 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLateDayEntryValidate(test *testing.T) {
+	testCases := []struct {
+		entry          *LateDayEntry
+		errorSubstring string
+	}{
+		{&LateDayEntry{Email: "a", Type: LateDayEntryCharge, Days: -1, AssignmentID: "hw0"}, ""},
+		{&LateDayEntry{Email: "a", Type: "Refund", Days: 1, AssignmentID: "hw0"}, ""},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryAdjustment, Days: 2}, ""},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryAdjustment, Days: -2}, ""},
+
+		{&LateDayEntry{Type: LateDayEntryAdjustment, Days: 1}, "no email"},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryAdjustment}, "zero days"},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryCharge, Days: 1, AssignmentID: "hw0"}, "must have negative days"},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryCharge, Days: -1}, "must have an assignment"},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryRefund, Days: -1, AssignmentID: "hw0"}, "must have positive days"},
+		{&LateDayEntry{Email: "a", Type: LateDayEntryRefund, Days: 1}, "must have an assignment"},
+		{&LateDayEntry{Email: "a", Type: "ZZZ", Days: 1}, "Unknown late day entry type"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.entry.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if testCase.entry.Timestamp.IsZero() {
+			test.Errorf("Case %d: Timestamp was not set.", i)
+		}
+	}
+}
+
+func TestLateDayLedgerComputations(test *testing.T) {
+	entries := []*LateDayEntry{
+		&LateDayEntry{Type: LateDayEntryCharge, Days: -2, AssignmentID: "hw0"},
+		&LateDayEntry{Type: LateDayEntryCharge, Days: -1, AssignmentID: "hw1"},
+		&LateDayEntry{Type: LateDayEntryRefund, Days: 1, AssignmentID: "hw0"},
+		&LateDayEntry{Type: LateDayEntryAdjustment, Days: 3, AssignmentID: "hw0"},
+		&LateDayEntry{Type: LateDayEntryAdjustment, Days: -1},
+	}
+
+	testCases := []struct {
+		name     string
+		expected int
+		actual   int
+	}{
+		{"balance", 4, ComputeLateDayBalance(4, entries)},
+		{"balance (empty)", 4, ComputeLateDayBalance(4, nil)},
+		{"allocated hw0", 1, ComputeAllocatedLateDays(entries, "hw0")},
+		{"allocated hw1", 1, ComputeAllocatedLateDays(entries, "hw1")},
+		{"allocated hw2", 0, ComputeAllocatedLateDays(entries, "hw2")},
+		{"used", 2, ComputeUsedLateDays(entries)},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.expected != testCase.actual {
+			test.Errorf("Case %d (%s): Unexpected value. Expected: %d, Actual: %d.", i, testCase.name, testCase.expected, testCase.actual)
+		}
+	}
+}
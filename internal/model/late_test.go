@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -106,6 +107,52 @@ func TestLateGradingPolicyValidate(test *testing.T) {
 			},
 			"",
 		},
+		{
+			&LateGradingPolicy{
+				Type:    ExponentialPenalty,
+				Penalty: 0.10,
+			},
+			&LateGradingPolicy{
+				Type:    ExponentialPenalty,
+				Penalty: 0.10,
+			},
+			"",
+		},
+		{
+			&LateGradingPolicy{
+				Type:    HourlyPenalty,
+				Penalty: 0.01,
+			},
+			&LateGradingPolicy{
+				Type:    HourlyPenalty,
+				Penalty: 0.01,
+			},
+			"",
+		},
+		{
+			&LateGradingPolicy{
+				Type:    BestOfOnTime,
+				Penalty: 0.10,
+			},
+			&LateGradingPolicy{
+				Type:    BestOfOnTime,
+				Penalty: 0.10,
+			},
+			"",
+		},
+		{
+			&LateGradingPolicy{
+				Type:               "Constant-Penalty",
+				Penalty:            10,
+				GracePeriodMinutes: 15,
+			},
+			&LateGradingPolicy{
+				Type:               ConstantPenalty,
+				Penalty:            10,
+				GracePeriodMinutes: 15,
+			},
+			"",
+		},
 
 		// Errors
 
@@ -228,12 +275,66 @@ func TestLateGradingPolicyValidate(test *testing.T) {
 		},
 		{
 			&LateGradingPolicy{
-				Type:        LateDays,
-				Penalty:     0.1,
-				MaxLateDays: 1,
+				Type:          LateDays,
+				Penalty:       0.1,
+				MaxLateDays:   1,
+				TotalLateDays: 3,
+			},
+			&LateGradingPolicy{
+				Type:          LateDays,
+				Penalty:       0.1,
+				MaxLateDays:   1,
+				TotalLateDays: 3,
+			},
+			"",
+		},
+		{
+			&LateGradingPolicy{
+				Type:          LateDays,
+				Penalty:       0.1,
+				MaxLateDays:   1,
+				TotalLateDays: -1,
+			},
+			nil,
+			"total late days cannot be negative",
+		},
+		{
+			&LateGradingPolicy{
+				Type:               BaselinePolicy,
+				GracePeriodMinutes: -1,
+			},
+			nil,
+			"Grace period is negative",
+		},
+		{
+			&LateGradingPolicy{
+				Type: ExponentialPenalty,
 			},
 			nil,
-			"LMS ID for late days assignment cannot be empty",
+			"penalty must be in (0.0, 1.0]",
+		},
+		{
+			&LateGradingPolicy{
+				Type:    ExponentialPenalty,
+				Penalty: 1.5,
+			},
+			nil,
+			"penalty must be in (0.0, 1.0]",
+		},
+		{
+			&LateGradingPolicy{
+				Type: HourlyPenalty,
+			},
+			nil,
+			"penalty must be in (0.0, 1.0]",
+		},
+		{
+			&LateGradingPolicy{
+				Type:    BestOfOnTime,
+				Penalty: -0.1,
+			},
+			nil,
+			"penalty must be in (0.0, 1.0]",
 		},
 	}
 
@@ -263,3 +364,76 @@ func TestLateGradingPolicyValidate(test *testing.T) {
 		}
 	}
 }
+
+func TestLateGradingPolicyGetEffectiveDueDate(test *testing.T) {
+	dueDate := timestamp.FromMSecs(1000)
+
+	testCases := []struct {
+		policy   *LateGradingPolicy
+		expected timestamp.Timestamp
+	}{
+		{nil, dueDate},
+		{&LateGradingPolicy{}, dueDate},
+		{&LateGradingPolicy{Type: BaselinePolicy}, dueDate},
+		{&LateGradingPolicy{Type: BaselinePolicy, GracePeriodMinutes: 1}, timestamp.FromMSecs(61000)},
+		{&LateGradingPolicy{Type: ConstantPenalty, Penalty: 1, GracePeriodMinutes: 60}, timestamp.FromMSecs(3601000)},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.policy.GetEffectiveDueDate(dueDate)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected due date. Expected: '%d', Actual: '%d'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestLateGradingPolicyDescription(test *testing.T) {
+	testCases := []struct {
+		policy   *LateGradingPolicy
+		expected string
+	}{
+		{
+			nil,
+			"No late policy.",
+		},
+		{
+			&LateGradingPolicy{},
+			"No late policy.",
+		},
+		{
+			&LateGradingPolicy{Type: BaselinePolicy, RejectAfterDays: 3},
+			"Late submissions are not penalized. Submissions more than 3 days late are rejected.",
+		},
+		{
+			&LateGradingPolicy{Type: ConstantPenalty, Penalty: 5},
+			"Late submissions lose 5 points per day late.",
+		},
+		{
+			&LateGradingPolicy{Type: PercentagePenalty, Penalty: 0.07},
+			"Late submissions lose 7% of the max points per day late.",
+		},
+		{
+			&LateGradingPolicy{Type: LateDays, Penalty: 0.1, MaxLateDays: 2, LateDaysLMSID: "A"},
+			"Up to 2 late days may be used on this assignment, after which late submissions lose 10% of the max points per day late.",
+		},
+		{
+			&LateGradingPolicy{Type: ExponentialPenalty, Penalty: 0.3},
+			"Late submissions have their score multiplied by 0.7 for each day late.",
+		},
+		{
+			&LateGradingPolicy{Type: HourlyPenalty, Penalty: 0.01, GracePeriodMinutes: 10},
+			"Late submissions lose 1% of the max points per hour late. Submissions within 10 minutes of the due date are not considered late.",
+		},
+		{
+			&LateGradingPolicy{Type: BestOfOnTime, Penalty: 0.25},
+			"The better of the best on-time score and the late score (losing 25% of the max points per day late) is used.",
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.policy.Description()
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected description. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -2,12 +2,24 @@ package model
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
 )
 
 const (
 	LMS_TYPE_CANVAS = "canvas"
+	LMS_TYPE_FILE   = "file"
+	LMS_TYPE_LTI    = "lti"
+	LMS_TYPE_MOODLE = "moodle"
 	LMS_TYPE_TEST   = "test"
+
+	LMS_FILE_DEFAULT_ROSTER_PATH    = "roster.csv"
+	LMS_FILE_DEFAULT_GRADEBOOK_PATH = "gradebook.csv"
+
+	LMS_FEEDBACK_FORMAT_HTML     = "html"
+	LMS_FEEDBACK_FORMAT_MARKDOWN = "markdown"
 )
 
 type LMSAdapter struct {
@@ -18,6 +30,13 @@ type LMSAdapter struct {
 	APIToken    string `json:"api-token,omitempty"`
 	BaseURL     string `json:"base-url,omitempty"`
 
+	// File connection options.
+	// The roster path is relative to the course's source dir,
+	// and the gradebook path is relative to the course's LMS files dir.
+	// Neither may be absolute or leave its base dir.
+	RosterPath    string `json:"roster-path,omitempty"`
+	GradebookPath string `json:"gradebook-path,omitempty"`
+
 	// Behavior options.
 
 	SyncUserAttributes bool `json:"sync-user-attributes,omitempty"`
@@ -25,6 +44,14 @@ type LMSAdapter struct {
 	SyncUserRemoves    bool `json:"sync-user-removes,omitempty"`
 
 	SyncAssignments bool `json:"sync-assignments,omitempty"`
+	// Create/update LMS assignments from the autograder's assignments (the opposite direction of SyncAssignments).
+	PushAssignments bool `json:"push-assignments,omitempty"`
+
+	// When uploading scores, attach a rendered grading report in this format ("html" or "markdown").
+	// Empty means no feedback file is attached.
+	UploadFeedback string `json:"upload-feedback,omitempty"`
+	// When uploading scores, fill in LMS rubric criteria that match graded question names.
+	UploadRubric bool `json:"upload-rubric,omitempty"`
 }
 
 type LMSSyncResult struct {
@@ -38,6 +65,80 @@ func (this *LMSAdapter) Validate() error {
 	}
 	this.Type = strings.ToLower(this.Type)
 
+	if this.SyncAssignments && this.PushAssignments {
+		return fmt.Errorf("LMS cannot both sync (pull) and push assignments.")
+	}
+
+	if this.RosterPath != "" {
+		err := validateLMSFilePath(this.RosterPath, "roster")
+		if err != nil {
+			return err
+		}
+	}
+
+	if this.GradebookPath != "" {
+		err := validateLMSFilePath(this.GradebookPath, "gradebook")
+		if err != nil {
+			return err
+		}
+	}
+
+	this.UploadFeedback = strings.ToLower(strings.TrimSpace(this.UploadFeedback))
+	if (this.UploadFeedback != "") && (this.UploadFeedback != LMS_FEEDBACK_FORMAT_HTML) && (this.UploadFeedback != LMS_FEEDBACK_FORMAT_MARKDOWN) {
+		return fmt.Errorf("Unknown LMS feedback format '%s'. Expected '%s' or '%s'.", this.UploadFeedback, LMS_FEEDBACK_FORMAT_HTML, LMS_FEEDBACK_FORMAT_MARKDOWN)
+	}
+
+	return nil
+}
+
+// Get the (absolute) roster and gradebook paths for a file LMS.
+// Paths may not escape their base dir (the course's source dir for the roster and LMS files dir for the gradebook),
+// even through symbolic links.
+func (this *LMSAdapter) GetFilePaths(course *Course) (string, string, error) {
+	rosterPath := this.RosterPath
+	if rosterPath == "" {
+		rosterPath = LMS_FILE_DEFAULT_ROSTER_PATH
+	}
+
+	rosterPath, err := resolveLMSFilePath(course.GetBaseSourceDir(), rosterPath, "roster")
+	if err != nil {
+		return "", "", err
+	}
+
+	gradebookPath := this.GradebookPath
+	if gradebookPath == "" {
+		gradebookPath = LMS_FILE_DEFAULT_GRADEBOOK_PATH
+	}
+
+	gradebookPath, err = resolveLMSFilePath(course.GetLMSFilesDir(), gradebookPath, "gradebook")
+	if err != nil {
+		return "", "", err
+	}
+
+	return rosterPath, gradebookPath, nil
+}
+
+func resolveLMSFilePath(baseDir string, relpath string, name string) (string, error) {
+	err := validateLMSFilePath(relpath, name)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(baseDir, relpath)
+
+	// Nothing inside of a missing dir can be a link, so the lexical check above is enough.
+	if util.PathExists(baseDir) && !util.PathHasParentOrSelf(path, baseDir) {
+		return "", fmt.Errorf("LMS %s path '%s' resolves outside of its base dir.", name, relpath)
+	}
+
+	return path, nil
+}
+
+func validateLMSFilePath(relpath string, name string) error {
+	if !filepath.IsLocal(relpath) {
+		return fmt.Errorf("LMS %s path '%s' must be relative and cannot leave its base dir.", name, relpath)
+	}
+
 	return nil
 }
 
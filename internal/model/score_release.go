@@ -0,0 +1,181 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DEFAULT_PASS_THRESHOLD = 1.0
+
+// What students can see about their submissions before scores are released.
+type ScoreReleaseMode string
+
+const (
+	// Show full results immediately (the default).
+	ScoreReleaseFull ScoreReleaseMode = "full"
+	// Only show if the submission passed.
+	ScoreReleasePassFail ScoreReleaseMode = "pass-fail"
+	// Only show that the submission was received.
+	ScoreReleaseReceived ScoreReleaseMode = "received"
+)
+
+// Controls when students can see the full results of their submissions.
+// Course staff can always see full results.
+type ScoreReleasePolicy struct {
+	Mode ScoreReleaseMode `json:"mode"`
+
+	// When full results are released.
+	// If not set, results are withheld until the policy is changed.
+	ReleaseTime *timestamp.Timestamp `json:"release-time,omitempty"`
+
+	// The proportion of the max points required to pass (only used in pass-fail mode).
+	PassThreshold float64 `json:"pass-threshold,omitempty"`
+}
+
+func (this *ScoreReleasePolicy) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Score release policy is nil.")
+	}
+
+	this.Mode = ScoreReleaseMode(strings.ToLower(string(this.Mode)))
+
+	switch this.Mode {
+	case "", ScoreReleaseFull, ScoreReleaseReceived:
+	case ScoreReleasePassFail:
+		// An unset (zero) threshold uses the default.
+		if this.PassThreshold == 0.0 {
+			this.PassThreshold = DEFAULT_PASS_THRESHOLD
+		}
+
+		if (this.PassThreshold <= 0.0) || (this.PassThreshold > 1.0) {
+			return fmt.Errorf("Pass threshold must be in (0.0, 1.0], found '%s'.", util.FloatToStr(this.PassThreshold))
+		}
+	default:
+		return fmt.Errorf("Unknown score release mode: '%s'.", this.Mode)
+	}
+
+	return nil
+}
+
+// Check if full results are visible to students at the given time.
+func (this *ScoreReleasePolicy) IsReleased(now timestamp.Timestamp) bool {
+	if (this == nil) || (this.Mode == "") || (this.Mode == ScoreReleaseFull) {
+		return true
+	}
+
+	return (this.ReleaseTime != nil) && (*this.ReleaseTime <= now)
+}
+
+func (this *ScoreReleasePolicy) isPassing(score float64, maxPoints float64) bool {
+	return score >= (this.PassThreshold * maxPoints)
+}
+
+func (this *ScoreReleasePolicy) withheldMessage() string {
+	if this.ReleaseTime == nil {
+		return "Scores for this assignment have not been released yet."
+	}
+
+	return fmt.Sprintf("Scores for this assignment will be released at %s.", this.ReleaseTime.SafeString())
+}
+
+// Remove everything but the information allowed by the policy.
+// This should be called before showing an unreleased result to a student.
+func (this *GradingInfo) WithholdScores(policy *ScoreReleasePolicy) {
+	if (this == nil) || (policy == nil) {
+		return
+	}
+
+	if policy.Mode == ScoreReleasePassFail {
+		passed := policy.isPassing(this.Score, this.MaxPoints)
+		this.Passed = &passed
+	}
+
+	this.ScoresWithheld = true
+	this.WithheldMessage = policy.withheldMessage()
+	this.Score = 0.0
+	this.Prologue = ""
+	this.Epilogue = ""
+	this.AdditionalInfo = nil
+
+	for _, question := range this.Questions {
+		if question == nil {
+			continue
+		}
+
+		question.ScoresWithheld = true
+		question.Score = 0.0
+		question.HardFail = false
+		question.Message = ""
+		question.TestCases = nil
+	}
+}
+
+// Same as GradingInfo.WithholdScores(), but also removes any output.
+func (this *GradingResult) WithholdScores(policy *ScoreReleasePolicy) {
+	if (this == nil) || (policy == nil) {
+		return
+	}
+
+	this.Info.WithholdScores(policy)
+	this.OutputFilesGZip = nil
+	this.Stdout = ""
+	this.Stderr = ""
+}
+
+func (this *SubmissionHistoryItem) WithholdScores(policy *ScoreReleasePolicy) {
+	if (this == nil) || (policy == nil) {
+		return
+	}
+
+	if policy.Mode == ScoreReleasePassFail {
+		passed := policy.isPassing(this.Score, this.MaxPoints)
+		this.Passed = &passed
+	}
+
+	this.ScoresWithheld = true
+	this.Score = 0.0
+}
+
+// Withheld scores are left out of the JSON entirely (instead of showing up as zero).
+// The "scores-withheld" flag tells clients that the score is missing on purpose.
+
+type gradingInfoJSON GradingInfo
+type gradedQuestionJSON GradedQuestion
+type submissionHistoryItemJSON SubmissionHistoryItem
+
+func (this GradingInfo) MarshalJSON() ([]byte, error) {
+	if !this.ScoresWithheld {
+		return json.Marshal(gradingInfoJSON(this))
+	}
+
+	return json.Marshal(struct {
+		gradingInfoJSON
+		Score *float64 `json:"score,omitempty"`
+	}{gradingInfoJSON: gradingInfoJSON(this)})
+}
+
+func (this GradedQuestion) MarshalJSON() ([]byte, error) {
+	if !this.ScoresWithheld {
+		return json.Marshal(gradedQuestionJSON(this))
+	}
+
+	return json.Marshal(struct {
+		gradedQuestionJSON
+		Score *float64 `json:"score,omitempty"`
+	}{gradedQuestionJSON: gradedQuestionJSON(this)})
+}
+
+func (this SubmissionHistoryItem) MarshalJSON() ([]byte, error) {
+	if !this.ScoresWithheld {
+		return json.Marshal(submissionHistoryItemJSON(this))
+	}
+
+	return json.Marshal(struct {
+		submissionHistoryItemJSON
+		Score *float64 `json:"score,omitempty"`
+	}{submissionHistoryItemJSON: submissionHistoryItemJSON(this)})
+}
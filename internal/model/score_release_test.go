@@ -0,0 +1,176 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestScoreReleasePolicyValidate(test *testing.T) {
+	testCases := []struct {
+		policy            *ScoreReleasePolicy
+		expectedThreshold float64
+		errorSubstring    string
+	}{
+		{&ScoreReleasePolicy{}, 0.0, ""},
+		{&ScoreReleasePolicy{Mode: ScoreReleaseFull}, 0.0, ""},
+		{&ScoreReleasePolicy{Mode: "Received"}, 0.0, ""},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail}, DEFAULT_PASS_THRESHOLD, ""},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: 0.5}, 0.5, ""},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: 1.0}, 1.0, ""},
+
+		{nil, 0.0, "nil"},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: -0.5}, 0.0, "Pass threshold must be"},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: 1.5}, 0.0, "Pass threshold must be"},
+		{&ScoreReleasePolicy{Mode: "ZZZ"}, 0.0, "Unknown score release mode"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.policy.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if !util.IsClose(testCase.expectedThreshold, testCase.policy.PassThreshold) {
+			test.Errorf("Case %d: Unexpected threshold. Expected: '%f', Actual: '%f'.", i, testCase.expectedThreshold, testCase.policy.PassThreshold)
+		}
+	}
+}
+
+func TestScoreReleasePolicyIsReleased(test *testing.T) {
+	now := timestamp.FromMSecs(1000)
+	before := timestamp.FromMSecs(500)
+	after := timestamp.FromMSecs(1500)
+
+	testCases := []struct {
+		policy   *ScoreReleasePolicy
+		expected bool
+	}{
+		{nil, true},
+		{&ScoreReleasePolicy{}, true},
+		{&ScoreReleasePolicy{Mode: ScoreReleaseFull}, true},
+		{&ScoreReleasePolicy{Mode: ScoreReleaseFull, ReleaseTime: &after}, true},
+
+		{&ScoreReleasePolicy{Mode: ScoreReleaseReceived}, false},
+		{&ScoreReleasePolicy{Mode: ScoreReleaseReceived, ReleaseTime: &after}, false},
+		{&ScoreReleasePolicy{Mode: ScoreReleaseReceived, ReleaseTime: &before}, true},
+		{&ScoreReleasePolicy{Mode: ScoreReleaseReceived, ReleaseTime: &now}, true},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, ReleaseTime: &after}, false},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, ReleaseTime: &before}, true},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.policy.IsReleased(now)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestGradingInfoWithholdScores(test *testing.T) {
+	testCases := []struct {
+		policy         *ScoreReleasePolicy
+		score          float64
+		expectedPassed *bool
+	}{
+		{&ScoreReleasePolicy{Mode: ScoreReleaseReceived}, 2.0, nil},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: 1.0}, 2.0, util.BoolPointer(true)},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: 1.0}, 1.0, util.BoolPointer(false)},
+		{&ScoreReleasePolicy{Mode: ScoreReleasePassFail, PassThreshold: 0.5}, 1.0, util.BoolPointer(true)},
+	}
+
+	for i, testCase := range testCases {
+		info := &GradingInfo{
+			Score:     testCase.score,
+			MaxPoints: 2.0,
+			Prologue:  "prologue",
+			Epilogue:  "epilogue",
+			Questions: []*GradedQuestion{
+				&GradedQuestion{
+					Name:      "Q1",
+					MaxPoints: 2.0,
+					Score:     testCase.score,
+					Message:   "message",
+					TestCases: []*GradedTestCase{&GradedTestCase{Name: "T1"}},
+				},
+			},
+		}
+
+		info.WithholdScores(testCase.policy)
+
+		if !info.ScoresWithheld {
+			test.Errorf("Case %d: Scores not marked as withheld.", i)
+		}
+
+		if info.WithheldMessage == "" {
+			test.Errorf("Case %d: No withheld message.", i)
+		}
+
+		if (info.Score != 0.0) || (info.Prologue != "") || (info.Epilogue != "") {
+			test.Errorf("Case %d: Info not cleared: '%s'.", i, util.MustToJSONIndent(info))
+		}
+
+		question := info.Questions[0]
+		if (question.Score != 0.0) || (question.Message != "") || (question.TestCases != nil) {
+			test.Errorf("Case %d: Question not cleared: '%s'.", i, util.MustToJSONIndent(question))
+		}
+
+		if !util.IsClose(question.MaxPoints, 2.0) || !util.IsClose(info.MaxPoints, 2.0) {
+			test.Errorf("Case %d: Max points should not be cleared: '%s'.", i, util.MustToJSONIndent(info))
+		}
+
+		if util.MustToJSON(testCase.expectedPassed) != util.MustToJSON(info.Passed) {
+			test.Errorf("Case %d: Unexpected passed value. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSON(testCase.expectedPassed), util.MustToJSON(info.Passed))
+		}
+	}
+}
+
+func TestWithheldScoresJSON(test *testing.T) {
+	info := &GradingInfo{
+		Score:     0.0,
+		MaxPoints: 2.0,
+		Questions: []*GradedQuestion{
+			&GradedQuestion{Name: "Q1", MaxPoints: 2.0},
+		},
+	}
+
+	// Zero scores are still included when scores are not withheld.
+	if !strings.Contains(util.MustToJSON(info), `"score":0`) {
+		test.Fatalf("Score missing from unwithheld info: '%s'.", util.MustToJSON(info))
+	}
+
+	info.WithholdScores(&ScoreReleasePolicy{Mode: ScoreReleaseReceived})
+	item := info.ToHistoryItem()
+	item.WithholdScores(&ScoreReleasePolicy{Mode: ScoreReleaseReceived})
+
+	testCases := []any{info, info.Questions[0], item}
+	for i, testCase := range testCases {
+		text := util.MustToJSON(testCase)
+
+		if strings.Contains(text, `"score"`) {
+			test.Errorf("Case %d: Withheld score found in JSON: '%s'.", i, text)
+		}
+
+		if !strings.Contains(text, `"scores-withheld":true`) {
+			test.Errorf("Case %d: Withheld flag not found in JSON: '%s'.", i, text)
+		}
+
+		if !strings.Contains(text, `"max_points":2`) {
+			test.Errorf("Case %d: Max points not found in JSON: '%s'.", i, text)
+		}
+	}
+}
@@ -17,6 +17,9 @@ type ScoringInfo struct {
 	NumDaysLate    int                 `json:"num-days-late"`
 	Reject         bool                `json:"reject"`
 
+	// The points removed by academic integrity penalties.
+	IntegrityPenalty float64 `json:"integrity-penalty,omitempty"`
+
 	// A distinct key so we can recognize this as an autograder object.
 	AutograderStructVersion string `json:"__autograder__version__"`
 
@@ -49,6 +52,7 @@ func (this *ScoringInfo) Equal(other *ScoringInfo) bool {
 		this.LateDayUsage == other.LateDayUsage &&
 		this.NumDaysLate == other.NumDaysLate &&
 		this.Reject == other.Reject &&
+		this.IntegrityPenalty == other.IntegrityPenalty &&
 		this.AutograderStructVersion == other.AutograderStructVersion)
 }
 
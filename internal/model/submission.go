@@ -19,6 +19,10 @@ type SubmissionHistoryItem struct {
 	MaxPoints        float64             `json:"max_points"`
 	Score            float64             `json:"score"`
 	GradingStartTime timestamp.Timestamp `json:"grading_start_time"`
+
+	// Set when this item is shown to a student before the assignment's scores are released.
+	ScoresWithheld bool  `json:"scores-withheld,omitempty"`
+	Passed         *bool `json:"passed,omitempty"`
 }
 
 func (this GradingInfo) ToHistoryItem() *SubmissionHistoryItem {
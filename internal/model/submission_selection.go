@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// How to choose which of a user's submissions is used for scoring.
+type SubmissionSelectionPolicy string
+
+const (
+	// Use the most recent submission (the default).
+	SubmissionSelectionMostRecent SubmissionSelectionPolicy = "most-recent"
+	// Use the highest scoring submission.
+	SubmissionSelectionHighestScore SubmissionSelectionPolicy = "highest-score"
+	// Use the highest scoring submission made before the due date (falling back to the most recent submission).
+	SubmissionSelectionHighestScoreBeforeDue SubmissionSelectionPolicy = "highest-score-before-due"
+	// Use the submission the student marked as final (falling back to the most recent submission).
+	SubmissionSelectionStudentSelected SubmissionSelectionPolicy = "student-selected"
+)
+
+func (this *SubmissionSelectionPolicy) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Submission selection policy is nil.")
+	}
+
+	*this = SubmissionSelectionPolicy(strings.ToLower(string(*this)))
+
+	switch *this {
+	case "", SubmissionSelectionMostRecent, SubmissionSelectionHighestScore, SubmissionSelectionHighestScoreBeforeDue, SubmissionSelectionStudentSelected:
+		return nil
+	default:
+		return fmt.Errorf("Unknown submission selection policy: '%s'.", *this)
+	}
+}
+
+// Choose a submission from a user's history (which should be sorted by time).
+// The due date is only used by SubmissionSelectionHighestScoreBeforeDue and may be nil,
+// and the final submission ID (short) is only used by SubmissionSelectionStudentSelected and may be empty.
+// Ties in score will go to the most recent submission.
+// Returns nil if there are no submissions.
+func (this SubmissionSelectionPolicy) Select(history []*SubmissionHistoryItem, dueDate *timestamp.Timestamp, finalShortSubmissionID string) *SubmissionHistoryItem {
+	if len(history) == 0 {
+		return nil
+	}
+
+	mostRecent := history[len(history)-1]
+
+	switch this {
+	case SubmissionSelectionHighestScore:
+		return selectHighestScore(history, nil)
+	case SubmissionSelectionHighestScoreBeforeDue:
+		best := selectHighestScore(history, dueDate)
+		if best == nil {
+			return mostRecent
+		}
+
+		return best
+	case SubmissionSelectionStudentSelected:
+		for _, item := range history {
+			if (finalShortSubmissionID != "") && (item.ShortID == finalShortSubmissionID) {
+				return item
+			}
+		}
+
+		return mostRecent
+	default:
+		return mostRecent
+	}
+}
+
+func selectHighestScore(history []*SubmissionHistoryItem, dueDate *timestamp.Timestamp) *SubmissionHistoryItem {
+	var best *SubmissionHistoryItem = nil
+
+	for _, item := range history {
+		if (dueDate != nil) && (item.GradingStartTime > *dueDate) {
+			continue
+		}
+
+		if (best == nil) || (item.Score >= best.Score) {
+			best = item
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubmissionSelectionPolicyValidate(test *testing.T) {
+	testCases := []struct {
+		input          SubmissionSelectionPolicy
+		expected       SubmissionSelectionPolicy
+		errorSubstring string
+	}{
+		{"", "", ""},
+		{"most-recent", SubmissionSelectionMostRecent, ""},
+		{"Highest-Score", SubmissionSelectionHighestScore, ""},
+		{"highest-score-before-due", SubmissionSelectionHighestScoreBeforeDue, ""},
+		{"STUDENT-SELECTED", SubmissionSelectionStudentSelected, ""},
+		{"ZZZ", "", "Unknown submission selection policy"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.input.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Failed to validate '%s': '%v'.", i, testCase.input, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Did not get expected error outpout. Expected Substring '%s', Actual Error: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		if testCase.expected != testCase.input {
+			test.Errorf("Case %d: Result not as expected. Expected: '%s', Actual: '%s'.", i, testCase.expected, testCase.input)
+		}
+	}
+}
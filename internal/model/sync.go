@@ -1,6 +1,8 @@
 package model
 
 type AssignmentSyncResult struct {
+	// Only used when pushing assignments (assignments that were created in the LMS).
+	CreatedAssignments    []AssignmentInfo `json:"created-assignments"`
 	SyncedAssignments     []AssignmentInfo `json:"synced-assignments"`
 	AmbiguousMatches      []AssignmentInfo `json:"ambiguous-matches"`
 	NonMatchedAssignments []AssignmentInfo `json:"non-matched-assignments"`
@@ -14,6 +16,7 @@ type AssignmentInfo struct {
 
 func NewAssignmentSyncResult() *AssignmentSyncResult {
 	return &AssignmentSyncResult{
+		CreatedAssignments:    make([]AssignmentInfo, 0),
 		SyncedAssignments:     make([]AssignmentInfo, 0),
 		AmbiguousMatches:      make([]AssignmentInfo, 0),
 		NonMatchedAssignments: make([]AssignmentInfo, 0),
@@ -33,6 +33,9 @@ type SystemTaskInfo struct {
 	CourseID     string              `json:"course-id,omitempty"`
 	AssignmentID string              `json:"assignment-id,omitempty"`
 	UserEmail    string              `json:"user-email,omitempty"`
+
+	// Paused tasks stay active, but will not be run on their schedule.
+	Paused bool `json:"paused,omitempty"`
 }
 
 func (this *UserTaskInfo) String() string {
@@ -95,13 +98,13 @@ func (this *UserTaskInfo) ToFullCourseTask(courseID string) (*FullScheduledTask,
 		return nil, fmt.Errorf("Unable to make hash from task: '%w'.", err)
 	}
 
-	// Computing the next time depends on how the task is to be repeated: daily or periodically.
-	// Daily tasks will be computed starting at right now, so they will run the next chosen time period.
+	// Computing the next time depends on how the task is to be repeated: on the calendar (daily, weekly, cron) or periodically.
+	// Calendar (e.g., daily) tasks will be computed starting at right now, so they will run the next chosen time period.
 	// Periodic ("every") tasks will be computed starting at zero time, so they will get a run in right away.
 	// However, if a periodic task has an existing run in the DB, then it will be merged and this time overwritten (see MergeTimes()).
-	// Daily tasks will also get merged, but the two tasks should share the same next run time.
+	// Calendar tasks will also get merged, but the two tasks should share the same next run time.
 	baselineTime := timestamp.Zero()
-	if !this.When.Daily.IsEmpty() {
+	if this.When.IsCalendarBased() {
 		baselineTime = timestamp.Now()
 	}
 
@@ -162,20 +165,23 @@ func (this *FullScheduledTask) Validate() error {
 
 // Merge times according to task updating logic
 // (as if a new task (this) was just read in and it replacing the exiting task (oldTask)).
+// The paused state is also kept from the old task, since it is not part of the user's config.
 func (this *FullScheduledTask) MergeTimes(oldTask *FullScheduledTask) {
 	if (this == nil) || (oldTask == nil) {
 		return
 	}
 
+	this.Paused = oldTask.Paused
+
 	// Always take the last run time from the old task.
 	this.LastRunTime = oldTask.LastRunTime
 
-	// For daily tasks, take the earlier time.
+	// For calendar (e.g., daily) tasks, take the earlier time.
 	// For periodic ("every") tasks, take the latter time.
 	// Note that daily tasks should share the same next run time,
 	// but a loading a task (e.g., from a course) at the exact right time could cause a skip if we took the latter time.
 
-	if !this.When.Daily.IsEmpty() {
+	if this.When.IsCalendarBased() {
 		if this.NextRunTime > oldTask.NextRunTime {
 			this.NextRunTime = oldTask.NextRunTime
 		}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// A record of a single run of a task.
+type TaskRun struct {
+	TaskHash string   `json:"task-hash"`
+	Type     TaskType `json:"type"`
+	CourseID string   `json:"course-id,omitempty"`
+
+	// The user that manually triggered this run (empty for scheduled runs).
+	TriggeredBy string `json:"triggered-by,omitempty"`
+
+	StartTime timestamp.Timestamp `json:"start-time"`
+	EndTime   timestamp.Timestamp `json:"end-time"`
+
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+
+	// A short summary of what the task did.
+	Output string `json:"output,omitempty"`
+}
+
+func NewTaskRun(task *FullScheduledTask, triggeredBy string) *TaskRun {
+	return &TaskRun{
+		TaskHash:    task.Hash,
+		Type:        task.Type,
+		CourseID:    task.CourseID,
+		TriggeredBy: triggeredBy,
+		StartTime:   timestamp.Now(),
+	}
+}
+
+// Mark this run as complete.
+func (this *TaskRun) Finish(output string, err error) {
+	this.EndTime = timestamp.Now()
+	this.Output = output
+	this.Success = (err == nil)
+
+	if err != nil {
+		this.Error = err.Error()
+	}
+}
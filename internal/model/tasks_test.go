@@ -124,6 +124,42 @@ func TestTaskValidationBase(test *testing.T) {
                     ],
                     "send-empty": false
                 }
+            }`,
+			"",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseAnalysis,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"to": []string{
+						"course-admin@test.edulinq.org",
+					},
+					"assignments": []string{
+						"HW0",
+					},
+					"top-pairs": 5,
+				},
+			},
+			`{
+                "type": "analysis",
+                "when": {
+                    "daily": "3:00",
+                    "every": {}
+                },
+                "options": {
+                    "after-due": false,
+                    "assignments": [
+                        "hw0"
+                    ],
+                    "to": [
+                        "course-admin@test.edulinq.org"
+                    ],
+                    "top-flags": 10,
+                    "top-pairs": 5
+                }
             }`,
 			"",
 		},
@@ -138,6 +174,94 @@ func TestTaskValidationBase(test *testing.T) {
             }`,
 			"",
 		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseLMSSync,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+			},
+			`{
+                "type": "lms-sync",
+                "when": {
+                    "daily": "3:00",
+                    "every": {}
+                },
+                "options": {
+                    "send-empty": false,
+                    "skip-emails": false,
+                    "to": []
+                }
+            }`,
+			"",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseLMSSync,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"to": []string{
+						"admin",
+					},
+					"skip-emails": true,
+				},
+			},
+			`{
+                "type": "lms-sync",
+                "when": {
+                    "daily": "3:00",
+                    "every": {}
+                },
+                "options": {
+                    "send-empty": false,
+                    "skip-emails": true,
+                    "to": [
+                        "admin"
+                    ]
+                }
+            }`,
+			"",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseReminder,
+				When: &util.ScheduledTime{
+					Every: util.DurationSpec{Hours: 1},
+				},
+				Options: map[string]any{
+					"before": map[string]any{
+						"days": 2,
+					},
+					"assignments": []string{
+						"HW0",
+					},
+					"subject":  "{{.AssignmentName}} is due soon",
+					"template": "Hi {{.Name}}.",
+				},
+			},
+			`{
+                "type": "reminder",
+                "when": {
+                    "every": {
+                        "hours": 1
+                    }
+                },
+                "options": {
+                    "assignments": [
+                        "hw0"
+                    ],
+                    "before": {
+                        "days": 2
+                    },
+                    "score-threshold": 0,
+                    "subject": "{{.AssignmentName}} is due soon",
+                    "template": "Hi {{.Name}}."
+                }
+            }`,
+			"",
+		},
 
 		// Errors
 		{
@@ -202,6 +326,78 @@ func TestTaskValidationBase(test *testing.T) {
 			``,
 			"'to' value is not properly formatted",
 		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseAnalysis,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"to": []string{
+						"course-admin@test.edulinq.org",
+					},
+					"assignments": []string{
+						"!!",
+					},
+				},
+			},
+			``,
+			"Assignment ID at index 0 is not valid",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseAnalysis,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"to": []string{
+						"course-admin@test.edulinq.org",
+					},
+					"top-flags": -1,
+				},
+			},
+			``,
+			"'top-flags' value cannot be negative",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseReminder,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+			},
+			``,
+			"'before' value must be a positive duration",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseReminder,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"before":          util.DurationSpec{Days: 1},
+					"score-threshold": 101,
+				},
+			},
+			``,
+			"'score-threshold' value must be in [0, 100]",
+		},
+		{
+			&UserTaskInfo{
+				Type: TaskTypeCourseReminder,
+				When: &util.ScheduledTime{
+					Daily: "3:00",
+				},
+				Options: map[string]any{
+					"before":   util.DurationSpec{Days: 1},
+					"template": "{{.Name",
+				},
+			},
+			``,
+			"'template' value is not a valid template",
+		},
 	}
 
 	for i, testCase := range testCases {
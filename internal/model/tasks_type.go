@@ -2,19 +2,38 @@ package model
 
 import (
 	"fmt"
+	"text/template"
 
+	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/util"
 )
 
+// The default number of similar pairs and anomaly flags included in an analysis task's summary.
+const DEFAULT_ANALYSIS_TASK_TOP_COUNT = 10
+
+// The defaults used for the emails sent by a reminder task.
+// Templates are executed with a tasks.ReminderTemplateData.
+const (
+	DEFAULT_REMINDER_TASK_SUBJECT  = "Reminder: {{.AssignmentName}} is due {{.DueDate}}"
+	DEFAULT_REMINDER_TASK_TEMPLATE = `Hello {{.Name}},
+
+This is a reminder that {{.AssignmentName}} ({{.CourseName}}) is due {{.DueDate}}.
+{{if .HasSubmission}}Your current score is {{printf "%.2f" .Score}} / {{printf "%.2f" .MaxPoints}}.{{else}}You have not made a submission yet.{{end}}
+`
+)
+
 // The allowed types a task may have.
 type TaskType string
 
 const (
 	TaskTypeUnknown TaskType = ""
 
+	TaskTypeCourseAnalysis      TaskType = "analysis"
 	TaskTypeCourseBackup        TaskType = "backup"
 	TaskTypeCourseEmailLogs     TaskType = "email-logs"
+	TaskTypeCourseLMSSync       TaskType = "lms-sync"
+	TaskTypeCourseReminder      TaskType = "reminder"
 	TaskTypeCourseReport        TaskType = "report"
 	TaskTypeCourseScoringUpload TaskType = "scoring-upload"
 	TaskTypeCourseUpdate        TaskType = "update"
@@ -25,8 +44,11 @@ const (
 var taskTypeToString = map[TaskType]string{
 	TaskTypeUnknown: string(TaskTypeUnknown),
 
+	TaskTypeCourseAnalysis:      string(TaskTypeCourseAnalysis),
 	TaskTypeCourseBackup:        string(TaskTypeCourseBackup),
 	TaskTypeCourseEmailLogs:     string(TaskTypeCourseEmailLogs),
+	TaskTypeCourseLMSSync:       string(TaskTypeCourseLMSSync),
+	TaskTypeCourseReminder:      string(TaskTypeCourseReminder),
 	TaskTypeCourseReport:        string(TaskTypeCourseReport),
 	TaskTypeCourseScoringUpload: string(TaskTypeCourseScoringUpload),
 	TaskTypeCourseUpdate:        string(TaskTypeCourseUpdate),
@@ -37,8 +59,11 @@ var taskTypeToString = map[TaskType]string{
 var stringToTaskType = map[string]TaskType{
 	string(TaskTypeUnknown): TaskTypeUnknown,
 
+	string(TaskTypeCourseAnalysis):      TaskTypeCourseAnalysis,
 	string(TaskTypeCourseBackup):        TaskTypeCourseBackup,
 	string(TaskTypeCourseEmailLogs):     TaskTypeCourseEmailLogs,
+	string(TaskTypeCourseLMSSync):       TaskTypeCourseLMSSync,
+	string(TaskTypeCourseReminder):      TaskTypeCourseReminder,
 	string(TaskTypeCourseReport):        TaskTypeCourseReport,
 	string(TaskTypeCourseScoringUpload): TaskTypeCourseScoringUpload,
 	string(TaskTypeCourseUpdate):        TaskTypeCourseUpdate,
@@ -61,8 +86,12 @@ func (this *TaskType) UnmarshalJSON(data []byte) error {
 
 func validateTaskTypes(task *UserTaskInfo) error {
 	switch task.Type {
+	case TaskTypeCourseAnalysis:
+		return validateTaskTypeCourseAnalysis(task)
 	case TaskTypeCourseBackup:
 		return nil
+	case TaskTypeCourseReminder:
+		return validateTaskTypeCourseReminder(task)
 	case TaskTypeCourseReport:
 		return validateTaskTypeCourseReport(task)
 	case TaskTypeCourseScoringUpload:
@@ -71,6 +100,8 @@ func validateTaskTypes(task *UserTaskInfo) error {
 		return nil
 	case TaskTypeCourseEmailLogs:
 		return validateTaskTypeCourseEmailLogs(task)
+	case TaskTypeCourseLMSSync:
+		return validateTaskTypeCourseLMSSync(task)
 	case TaskTypeTest:
 		return nil
 	default:
@@ -78,6 +109,35 @@ func validateTaskTypes(task *UserTaskInfo) error {
 	}
 }
 
+func validateTaskTypeCourseAnalysis(task *UserTaskInfo) error {
+	err := validateEmailList(task)
+	if err != nil {
+		return err
+	}
+
+	err = validateAssignmentList(task)
+	if err != nil {
+		return err
+	}
+
+	task.Options["after-due"] = (task.Options["after-due"] == true)
+
+	for _, key := range []string{"top-pairs", "top-flags"} {
+		count, err := GetTaskOptionAsType(task, key, DEFAULT_ANALYSIS_TASK_TOP_COUNT)
+		if err != nil {
+			return fmt.Errorf("'%s' value is not properly formatted: '%w'.", key, err)
+		}
+
+		if count < 0 {
+			return fmt.Errorf("'%s' value cannot be negative, found %d.", key, count)
+		}
+
+		task.Options[key] = count
+	}
+
+	return nil
+}
+
 func validateTaskTypeCourseEmailLogs(task *UserTaskInfo) error {
 	err := validateEmailList(task)
 	if err != nil {
@@ -101,10 +161,102 @@ func validateTaskTypeCourseEmailLogs(task *UserTaskInfo) error {
 	return nil
 }
 
+func validateTaskTypeCourseLMSSync(task *UserTaskInfo) error {
+	// Recipients are optional, the sync will still run without sending a summary.
+	to, err := GetTaskOptionAsType(task, "to", make([]string, 0))
+	if err != nil {
+		return fmt.Errorf("'to' value is not properly formatted: '%w'.", err)
+	}
+
+	task.Options["to"] = to
+
+	task.Options["send-empty"] = (task.Options["send-empty"] == true)
+	task.Options["skip-emails"] = (task.Options["skip-emails"] == true)
+
+	return nil
+}
+
+func validateTaskTypeCourseReminder(task *UserTaskInfo) error {
+	before, err := GetTaskOptionAsType(task, "before", util.DurationSpec{})
+	if err != nil {
+		return fmt.Errorf("'before' value is not properly formatted: '%w'.", err)
+	}
+
+	err = before.Validate()
+	if err != nil {
+		return fmt.Errorf("'before' value is not valid: '%w'.", err)
+	}
+
+	if before.TotalMSecs() <= 0 {
+		return fmt.Errorf("'before' value must be a positive duration.")
+	}
+
+	task.Options["before"] = before
+
+	err = validateAssignmentList(task)
+	if err != nil {
+		return err
+	}
+
+	threshold, err := GetTaskOptionAsType(task, "score-threshold", 0.0)
+	if err != nil {
+		return fmt.Errorf("'score-threshold' value is not properly formatted: '%w'.", err)
+	}
+
+	if (threshold < 0.0) || (threshold > 100.0) {
+		return fmt.Errorf("'score-threshold' value must be in [0, 100], found %f.", threshold)
+	}
+
+	task.Options["score-threshold"] = threshold
+
+	defaults := map[string]string{
+		"subject":  DEFAULT_REMINDER_TASK_SUBJECT,
+		"template": DEFAULT_REMINDER_TASK_TEMPLATE,
+	}
+
+	for key, defaultValue := range defaults {
+		text, err := GetTaskOptionAsType(task, key, "")
+		if err != nil {
+			return fmt.Errorf("'%s' value is not properly formatted: '%w'.", key, err)
+		}
+
+		if text == "" {
+			text = defaultValue
+		}
+
+		_, err = template.New(key).Parse(text)
+		if err != nil {
+			return fmt.Errorf("'%s' value is not a valid template: '%w'.", key, err)
+		}
+
+		task.Options[key] = text
+	}
+
+	return nil
+}
+
 func validateTaskTypeCourseReport(task *UserTaskInfo) error {
 	return validateEmailList(task)
 }
 
+func validateAssignmentList(task *UserTaskInfo) error {
+	assignmentIDs, err := GetTaskOptionAsType(task, "assignments", make([]string, 0))
+	if err != nil {
+		return fmt.Errorf("'assignments' value is not properly formatted: '%w'.", err)
+	}
+
+	for i, assignmentID := range assignmentIDs {
+		assignmentIDs[i], err = common.ValidateID(assignmentID)
+		if err != nil {
+			return fmt.Errorf("Assignment ID at index %d is not valid: '%w'.", i, err)
+		}
+	}
+
+	task.Options["assignments"] = assignmentIDs
+
+	return nil
+}
+
 func validateEmailList(task *UserTaskInfo) error {
 	to, err := GetTaskOptionAsType(task, "to", make([]string, 0))
 	if err != nil {
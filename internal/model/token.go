@@ -37,6 +37,9 @@ type Token struct {
 	Name         string              `json:"name"`
 	CreationTime timestamp.Timestamp `json:"creation-time"`
 	AccessTime   timestamp.Timestamp `json:"access-time"`
+
+	// Tokens without an expiration time never expire.
+	ExpirationTime *timestamp.Timestamp `json:"expiration-time,omitempty"`
 }
 
 const (
@@ -145,7 +148,7 @@ func NewRandomSalt() (string, error) {
 // Check if some input matches this token.
 // As with NewToken(), the input is suggested (but not required) to the hex encoding of a Sha256 digest.
 // The salt must be a hex encoded string.
-// If the input matches, then true will be returned and the token's access time will be set,
+// If the input matches (and the token has not expired), then true will be returned and the token's access time will be set,
 // false will otherwise be returned.
 func (this *Token) Check(input string, salt string) (bool, error) {
 	now := timestamp.Now()
@@ -161,6 +164,7 @@ func (this *Token) Check(input string, salt string) (bool, error) {
 	}
 
 	match := (subtle.ConstantTimeCompare(thisDigestBytes, otherDigestBytes) == 1)
+	match = match && !this.IsExpired(now)
 	if match {
 		this.AccessTime = now
 	}
@@ -168,6 +172,10 @@ func (this *Token) Check(input string, salt string) (bool, error) {
 	return match, nil
 }
 
+func (this *Token) IsExpired(now timestamp.Timestamp) bool {
+	return (this.ExpirationTime != nil) && (*this.ExpirationTime < now)
+}
+
 func (this *Token) Validate() error {
 	if this == nil {
 		return fmt.Errorf("Token is nil.")
@@ -192,13 +200,20 @@ func (this *Token) Validate() error {
 }
 
 func (this *Token) Clone() *Token {
+	var expirationTime *timestamp.Timestamp = nil
+	if this.ExpirationTime != nil {
+		expirationTime = new(timestamp.Timestamp)
+		*expirationTime = *this.ExpirationTime
+	}
+
 	return &Token{
-		ID:           this.ID,
-		HexDigest:    this.HexDigest,
-		Source:       this.Source,
-		Name:         this.Name,
-		CreationTime: this.CreationTime,
-		AccessTime:   this.AccessTime,
+		ID:             this.ID,
+		HexDigest:      this.HexDigest,
+		Source:         this.Source,
+		Name:           this.Name,
+		CreationTime:   this.CreationTime,
+		AccessTime:     this.AccessTime,
+		ExpirationTime: expirationTime,
 	}
 }
 
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -71,3 +73,51 @@ func TestTokenRandom(test *testing.T) {
 		test.Fatalf("Token did match when it should not have.")
 	}
 }
+
+func TestTokenExpired(test *testing.T) {
+	pass := util.Sha256HexFromString("foo")
+
+	salt, err := NewRandomSalt()
+	if err != nil {
+		test.Fatalf("Failed to generate salt: '%v'.", err)
+	}
+
+	now := timestamp.Now()
+
+	testCases := []struct {
+		expirationTime *timestamp.Timestamp
+		expected       bool
+	}{
+		{nil, true},
+		{timestampPointer(now + 100000), true},
+		{timestampPointer(now - 100000), false},
+	}
+
+	for i, testCase := range testCases {
+		token, err := NewToken(pass, salt, TokenSourceServer, "")
+		if err != nil {
+			test.Fatalf("Case %d: Failed to generate token: '%v'.", i, err)
+		}
+
+		token.ExpirationTime = testCase.expirationTime
+
+		match, err := token.Check(pass, salt)
+		if err != nil {
+			test.Errorf("Case %d: Failed to check token: '%v'.", i, err)
+			continue
+		}
+
+		if match != testCase.expected {
+			test.Errorf("Case %d: Unexpected match. Expected: '%v', Actual: '%v'.", i, testCase.expected, match)
+		}
+
+		clone := token.Clone()
+		if !reflect.DeepEqual(token, clone) {
+			test.Errorf("Case %d: Clone is not equal. Expected: '%v', Actual: '%v'.", i, token, clone)
+		}
+	}
+}
+
+func timestampPointer(value timestamp.Timestamp) *timestamp.Timestamp {
+	return &value
+}
@@ -18,6 +18,9 @@ type CourseUser struct {
 	Name  *string        `json:"name"`
 	Role  CourseUserRole `json:"role"`
 	LMSID *string        `json:"lms-id"`
+
+	Sections []string          `json:"sections,omitempty"`
+	Groups   map[string]string `json:"groups,omitempty"`
 }
 
 func NewCourseUser(email string, name *string, role CourseUserRole, lmsID *string) (*CourseUser, error) {
@@ -51,6 +54,9 @@ func (this *CourseUser) Validate() error {
 		this.LMSID = &lmsID
 	}
 
+	this.Sections = NormalizeSections(this.Sections)
+	this.Groups = NormalizeGroups(this.Groups)
+
 	return nil
 }
 
@@ -85,6 +91,45 @@ func (this *CourseUser) GetLMSID() string {
 	return *this.LMSID
 }
 
+// Is this user in the given section (case-insensitive)?
+func (this *CourseUser) InSection(section string) bool {
+	section = strings.TrimSpace(section)
+
+	for _, userSection := range this.Sections {
+		if strings.EqualFold(userSection, section) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Is this user in the given group (case-insensitive)?
+// The group may be qualified with a group set, e.g., "Projects/Team 1",
+// otherwise the group will be matched against all of the user's group sets.
+func (this *CourseUser) InGroup(group string) bool {
+	groupSet, groupName, qualified := strings.Cut(strings.TrimSpace(group), GROUP_SET_SEPARATOR)
+	if !qualified {
+		groupName = groupSet
+		groupSet = ""
+	}
+
+	groupSet = strings.TrimSpace(groupSet)
+	groupName = strings.TrimSpace(groupName)
+
+	for userGroupSet, userGroup := range this.Groups {
+		if qualified && !strings.EqualFold(userGroupSet, groupSet) {
+			continue
+		}
+
+		if strings.EqualFold(userGroup, groupName) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Note that this function is potentially dangerous because
 // we are converting from a type with less information into
 // a type with more information.
@@ -92,7 +137,7 @@ func (this *CourseUser) ToServerUser(courseID string) (*ServerUser, error) {
 	serverUser := &ServerUser{
 		Email:      this.Email,
 		Name:       this.Name,
-		CourseInfo: map[string]*UserCourseInfo{courseID: &UserCourseInfo{Role: this.Role, LMSID: this.LMSID}},
+		CourseInfo: map[string]*UserCourseInfo{courseID: &UserCourseInfo{Role: this.Role, LMSID: this.LMSID, Sections: this.Sections, Groups: this.Groups}},
 	}
 
 	return serverUser, serverUser.validate(false)
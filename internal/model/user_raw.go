@@ -1,6 +1,9 @@
 package model
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -14,6 +17,9 @@ type RawServerUserData struct {
 	Course      string `json:"course,omitempty" help:"Optional ID of course to enroll user in."`
 	CourseRole  string `json:"course-role,omitempty" help:"Role for the new user in the specified course. Defaults to 'student'." default:"student"`
 	CourseLMSID string `json:"course-lms-id,omitempty" help:"LMS ID for the new user in the specified course."`
+
+	CourseSections []string          `json:"course-sections,omitempty" help:"LMS sections for the new user in the specified course."`
+	CourseGroups   map[string]string `json:"course-groups,omitempty" help:"LMS groups (keyed by group set) for the new user in the specified course."`
 }
 
 // Raw/dirty data for a course user.
@@ -79,6 +85,14 @@ func (this *RawServerUserData) ToServerUser() (*ServerUser, error) {
 		if this.CourseLMSID != "" {
 			user.CourseInfo[this.Course].LMSID = &this.CourseLMSID
 		}
+
+		if this.CourseSections != nil {
+			user.CourseInfo[this.Course].Sections = slices.Clone(this.CourseSections)
+		}
+
+		if this.CourseGroups != nil {
+			user.CourseInfo[this.Course].Groups = maps.Clone(this.CourseGroups)
+		}
 	}
 
 	return user, user.validate(false)
@@ -91,5 +105,5 @@ func (this *RawServerUserData) HasServerInfo() bool {
 
 // Does this data have course-level user information?
 func (this *RawServerUserData) HasCourseInfo() bool {
-	return (this.Course != "") || (this.CourseRole != "") || (this.CourseLMSID != "")
+	return (this.Course != "") || (this.CourseRole != "") || (this.CourseLMSID != "") || (this.CourseSections != nil) || (this.CourseGroups != nil)
 }
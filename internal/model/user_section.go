@@ -0,0 +1,100 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// Separates a group set from a group name when qualifying a group, e.g., "Projects/Team 1".
+const GROUP_SET_SEPARATOR = "/"
+
+// Filter course users by their LMS section and/or group.
+// Empty fields match all users.
+// This struct can be directly embedded in API requests and Kong arguments.
+type CourseUserFilter struct {
+	FilterSection string `json:"filter-section,omitempty" help:"Only include users in this LMS section."`
+	FilterGroup   string `json:"filter-group,omitempty" help:"Only include users in this LMS group (optionally qualified with a group set, e.g., 'Projects/Team 1')."`
+}
+
+func (this *CourseUserFilter) IsEmpty() bool {
+	return (this == nil) || ((strings.TrimSpace(this.FilterSection) == "") && (strings.TrimSpace(this.FilterGroup) == ""))
+}
+
+func (this *CourseUserFilter) Matches(user *CourseUser) bool {
+	if this.IsEmpty() {
+		return true
+	}
+
+	if user == nil {
+		return false
+	}
+
+	if (strings.TrimSpace(this.FilterSection) != "") && !user.InSection(this.FilterSection) {
+		return false
+	}
+
+	if (strings.TrimSpace(this.FilterGroup) != "") && !user.InGroup(this.FilterGroup) {
+		return false
+	}
+
+	return true
+}
+
+// Remove any entries in |values| (keyed by email) that belong to users that do not match this filter.
+// Entries for emails not in |users| are also removed (unless the filter is empty).
+func FilterByCourseUsers[T any](filter *CourseUserFilter, users map[string]*CourseUser, values map[string]T) map[string]T {
+	if filter.IsEmpty() {
+		return values
+	}
+
+	for email, _ := range values {
+		if !filter.Matches(users[email]) {
+			delete(values, email)
+		}
+	}
+
+	return values
+}
+
+// Trim, dedup, and sort sections (removing empty ones).
+// A nil input will return nil.
+func NormalizeSections(sections []string) []string {
+	if sections == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(sections))
+	for _, section := range sections {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+
+		result = append(result, section)
+	}
+
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// Trim group sets and names (removing empty ones).
+// A nil input will return nil.
+func NormalizeGroups(groups map[string]string) map[string]string {
+	if groups == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(groups))
+	for groupSet, group := range groups {
+		groupSet = strings.TrimSpace(groupSet)
+		group = strings.TrimSpace(group)
+
+		if (groupSet == "") || (group == "") {
+			continue
+		}
+
+		result[groupSet] = group
+	}
+
+	return result
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCourseUserFilterMatches(test *testing.T) {
+	user := &CourseUser{
+		Email:    "alice@test.edulinq.org",
+		Role:     CourseRoleStudent,
+		Sections: []string{"Lab 01", "Lecture 01"},
+		Groups:   map[string]string{"Projects": "Team 1", "Labs": "Pair 3"},
+	}
+
+	testCases := []struct {
+		filter   *CourseUserFilter
+		expected bool
+	}{
+		{nil, true},
+		{&CourseUserFilter{}, true},
+		{&CourseUserFilter{FilterSection: " \t "}, true},
+
+		{&CourseUserFilter{FilterSection: "Lab 01"}, true},
+		{&CourseUserFilter{FilterSection: " lecture 01 "}, true},
+		{&CourseUserFilter{FilterSection: "Lab 02"}, false},
+
+		{&CourseUserFilter{FilterGroup: "Team 1"}, true},
+		{&CourseUserFilter{FilterGroup: "pair 3"}, true},
+		{&CourseUserFilter{FilterGroup: "Projects/Team 1"}, true},
+		{&CourseUserFilter{FilterGroup: " projects / team 1 "}, true},
+		{&CourseUserFilter{FilterGroup: "Labs/Team 1"}, false},
+		{&CourseUserFilter{FilterGroup: "Team 2"}, false},
+
+		{&CourseUserFilter{FilterSection: "Lab 01", FilterGroup: "Team 1"}, true},
+		{&CourseUserFilter{FilterSection: "Lab 02", FilterGroup: "Team 1"}, false},
+		{&CourseUserFilter{FilterSection: "Lab 01", FilterGroup: "Team 2"}, false},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.filter.Matches(user)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+
+	if (&CourseUserFilter{FilterSection: "Lab 01"}).Matches(nil) {
+		test.Errorf("Nil user matches a non-empty filter.")
+	}
+}
+
+func TestFilterByCourseUsers(test *testing.T) {
+	users := map[string]*CourseUser{
+		"alice@test.edulinq.org": &CourseUser{Email: "alice@test.edulinq.org", Sections: []string{"A"}},
+		"bob@test.edulinq.org":   &CourseUser{Email: "bob@test.edulinq.org", Sections: []string{"B"}},
+	}
+
+	values := map[string]int{
+		"alice@test.edulinq.org":   1,
+		"bob@test.edulinq.org":     2,
+		"charlie@test.edulinq.org": 3,
+	}
+
+	actual := FilterByCourseUsers(nil, users, values)
+	if len(actual) != 3 {
+		test.Fatalf("Empty filter removed values: '%v'.", actual)
+	}
+
+	expected := map[string]int{"alice@test.edulinq.org": 1}
+
+	actual = FilterByCourseUsers(&CourseUserFilter{FilterSection: "a"}, users, values)
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Unexpected values. Expected: '%v', Actual: '%v'.", expected, actual)
+	}
+}
+
+func TestNormalizeSectionsAndGroups(test *testing.T) {
+	if NormalizeSections(nil) != nil {
+		test.Errorf("Nil sections did not normalize to nil.")
+	}
+
+	if NormalizeGroups(nil) != nil {
+		test.Errorf("Nil groups did not normalize to nil.")
+	}
+
+	expectedSections := []string{"A", "B"}
+	actualSections := NormalizeSections([]string{" B ", "", "A", "B", "\t"})
+	if !reflect.DeepEqual(expectedSections, actualSections) {
+		test.Errorf("Unexpected sections. Expected: '%v', Actual: '%v'.", expectedSections, actualSections)
+	}
+
+	expectedGroups := map[string]string{"Projects": "Team 1"}
+	actualGroups := NormalizeGroups(map[string]string{" Projects ": " Team 1 ", "": "Team 2", "Labs": " "})
+	if !reflect.DeepEqual(expectedGroups, actualGroups) {
+		test.Errorf("Unexpected groups. Expected: '%v', Actual: '%v'.", expectedGroups, actualGroups)
+	}
+}
+
+func TestUserCourseInfoMergeSectionsAndGroups(test *testing.T) {
+	testCases := []struct {
+		info     *UserCourseInfo
+		other    *UserCourseInfo
+		expected *UserCourseInfo
+		changed  bool
+	}{
+		// Nil values in other do not overwrite.
+		{
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}, Groups: map[string]string{"P": "1"}},
+			&UserCourseInfo{Role: CourseRoleStudent},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}, Groups: map[string]string{"P": "1"}},
+			false,
+		},
+
+		// Empty values in other clear.
+		{
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}, Groups: map[string]string{"P": "1"}},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{}, Groups: map[string]string{}},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{}, Groups: map[string]string{}},
+			true,
+		},
+
+		// Replace.
+		{
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"B"}, Groups: map[string]string{"P": "2"}},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"B"}, Groups: map[string]string{"P": "2"}},
+			true,
+		},
+
+		// Same.
+		{
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}, Groups: map[string]string{"P": "1"}},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}, Groups: map[string]string{"P": "1"}},
+			&UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"A"}, Groups: map[string]string{"P": "1"}},
+			false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		changed := testCase.info.Merge(testCase.other)
+		if testCase.changed != changed {
+			test.Errorf("Case %d: Unexpected changed. Expected: '%v', Actual: '%v'.", i, testCase.changed, changed)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, testCase.info) {
+			test.Errorf("Case %d: Unexpected info. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(testCase.info))
+			continue
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -46,6 +47,12 @@ var RootUserEmail = "root"
 type UserCourseInfo struct {
 	Role  CourseUserRole `json:"role"`
 	LMSID *string        `json:"lms-id"`
+
+	// Sections (as named in the LMS) this user is in.
+	Sections []string `json:"sections,omitempty"`
+
+	// Groups this user is in, keyed by the name of the group set.
+	Groups map[string]string `json:"groups,omitempty"`
 }
 
 func (this *ServerUser) Validate() error {
@@ -197,6 +204,8 @@ func (this *ServerUser) ToCourseUser(courseID string, escalateServerAdmin bool)
 	if enrolled {
 		courseUser.Role = info.Role
 		courseUser.LMSID = info.LMSID
+		courseUser.Sections = slices.Clone(info.Sections)
+		courseUser.Groups = maps.Clone(info.Groups)
 	}
 
 	if escalate {
@@ -453,6 +462,9 @@ func (this *UserCourseInfo) Validate() error {
 		}
 	}
 
+	this.Sections = NormalizeSections(this.Sections)
+	this.Groups = NormalizeGroups(this.Groups)
+
 	return nil
 }
 
@@ -476,12 +488,25 @@ func (this *UserCourseInfo) Merge(other *UserCourseInfo) bool {
 		changed = true
 	}
 
+	// Sections and groups are only ever set as a whole (e.g., from an LMS sync).
+	if (other.Sections != nil) && !slices.Equal(this.Sections, other.Sections) {
+		this.Sections = slices.Clone(other.Sections)
+		changed = true
+	}
+
+	if (other.Groups != nil) && !maps.Equal(this.Groups, other.Groups) {
+		this.Groups = maps.Clone(other.Groups)
+		changed = true
+	}
+
 	return changed
 }
 
 func (this *UserCourseInfo) Clone() *UserCourseInfo {
 	return &UserCourseInfo{
-		Role:  this.Role,
-		LMSID: this.LMSID,
+		Role:     this.Role,
+		LMSID:    this.LMSID,
+		Sections: slices.Clone(this.Sections),
+		Groups:   maps.Clone(this.Groups),
 	}
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -365,3 +366,80 @@ var standardTemplateFiles map[string][]string = map[string][]string{
 var standardDryRunBuildImages []string = []string{
 	"autograder.__autograder_dryrun__course101.hw0",
 }
+
+// LMS IDs linked by a push are not in the course's source,
+// so upserting a pushing course twice should not create the LMS assignments again.
+func TestUpsertPushAssignmentsTwice(test *testing.T) {
+	defer db.ResetForTesting()
+	defer lmstest.ClearAssignments()
+
+	lmstest.ClearAssignments()
+
+	tempDir := util.MustMkDirTemp("test-internal.procedures.courses.upsert-push-")
+	defer util.RemoveDirent(tempDir)
+
+	courseDir := filepath.Join(tempDir, "course101")
+	err := util.CopyDirent(filepath.Join(config.GetTestdataDir(), "course101"), courseDir)
+	if err != nil {
+		test.Fatalf("Failed to copy course: '%v'.", err)
+	}
+
+	configPath := filepath.Join(courseDir, model.COURSE_CONFIG_FILENAME)
+	err = util.WriteFile(`{"id": "course101", "name": "Course 101", "lms": {"type": "test", "push-assignments": true}}`, configPath)
+	if err != nil {
+		test.Fatalf("Failed to write course config: '%v'.", err)
+	}
+
+	// Images are not needed to push assignments (so this test does not need Docker).
+	options := CourseUpsertOptions{
+		ContextUser: db.MustGetServerUser("server-creator@test.edulinq.org"),
+		CourseUpsertPublicOptions: CourseUpsertPublicOptions{
+			SkipBuildImages: true,
+		},
+	}
+
+	hw0 := []model.AssignmentInfo{model.AssignmentInfo{ID: "hw0", Name: "Homework 0"}}
+
+	expectedResults := []*model.AssignmentSyncResult{
+		model.NewAssignmentSyncResult(),
+		model.NewAssignmentSyncResult(),
+	}
+	expectedResults[0].CreatedAssignments = hw0
+	expectedResults[1].UnchangedAssignments = hw0
+
+	for i, expected := range expectedResults {
+		result, _, err := UpsertFromConfigPath(configPath, options)
+		if err != nil {
+			test.Fatalf("Upsert %d: Failed to upsert: '%v'.", i, err)
+		}
+
+		if !result.Success {
+			test.Fatalf("Upsert %d: Upsert was not successful: '%s'.", i, util.MustToJSONIndent(result))
+		}
+
+		actual := result.LMSSyncResult.AssignmentSync
+		if !reflect.DeepEqual(expected, actual) {
+			test.Fatalf("Upsert %d: Unexpected assignment sync result. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+		}
+
+		course := db.MustGetCourse("course101")
+		if course.GetAssignment("hw0").GetLMSID() == "" {
+			test.Fatalf("Upsert %d: Assignment was not linked to the LMS.", i)
+		}
+	}
+
+	backend, err := lmstest.NewBackend("course101")
+	if err != nil {
+		test.Fatalf("Failed to get test backend: '%v'.", err)
+	}
+
+	lmsAssignments, err := backend.FetchAssignments()
+	if err != nil {
+		test.Fatalf("Failed to fetch test LMS assignments: '%v'.", err)
+	}
+
+	if len(lmsAssignments) != 1 {
+		test.Fatalf("Unexpected number of LMS assignments. Expected: 1, Actual: %d.", len(lmsAssignments))
+	}
+}
@@ -0,0 +1,291 @@
+package grades
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Compute the course grade for each user of the given role (or all users for model.CourseRoleUnknown).
+// Scores come from each user's selected submission (see the assignment's submission selection policy),
+// with late policies and integrity penalties applied (the same scores that are uploaded to the LMS).
+// Late days are never updated when computing grades, and only data stored in the autograder is used (see scoring.ApplyLocalPenalties()).
+// If includeUnreleased is false, then assignments with unreleased scores are treated as pending.
+func ComputeCourseGrades(course *model.Course, filterRole model.CourseUserRole, includeUnreleased bool) (map[string]*CourseGrade, error) {
+	users, err := db.GetCourseUsers(course)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course users: '%w'.", err)
+	}
+
+	gradedUsers := make(map[string]*model.CourseUser, len(users))
+	for email, user := range users {
+		if (filterRole != model.CourseRoleUnknown) && (filterRole != user.Role) {
+			continue
+		}
+
+		gradedUsers[email] = user
+	}
+
+	return computeCourseGrades(course, gradedUsers, includeUnreleased, timestamp.Now())
+}
+
+// Compute the course grade for a single user.
+// Only this user's submissions are scored.
+// Returns (nil, nil) if the user is not enrolled in the course.
+func ComputeCourseGrade(course *model.Course, email string, includeUnreleased bool) (*CourseGrade, error) {
+	user, err := db.GetCourseUser(course, email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get course user: '%w'.", err)
+	}
+
+	if user == nil {
+		return nil, nil
+	}
+
+	grades, err := computeCourseGrades(course, map[string]*model.CourseUser{email: user}, includeUnreleased, timestamp.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return grades[email], nil
+}
+
+// Compute the course grade for each of the given users (keyed by email).
+func computeCourseGrades(course *model.Course, users map[string]*model.CourseUser, includeUnreleased bool, now timestamp.Timestamp) (map[string]*CourseGrade, error) {
+	scheme := course.GetGradeScheme()
+	if scheme == nil {
+		return nil, fmt.Errorf("Course '%s' does not have a grade scheme.", course.GetID())
+	}
+
+	emails := make([]string, 0, len(users))
+
+	// {email: {assignmentID: grade, ...}, ...}.
+	userAssignmentGrades := make(map[string]map[string]*AssignmentGrade, len(users))
+	for email, _ := range users {
+		emails = append(emails, email)
+		userAssignmentGrades[email] = make(map[string]*AssignmentGrade)
+	}
+
+	for _, category := range scheme.Categories {
+		for _, assignmentID := range category.Assignments {
+			assignment := course.GetAssignment(assignmentID)
+			if assignment == nil {
+				return nil, fmt.Errorf("Grade category '%s' references unknown assignment '%s'.", category.Name, assignmentID)
+			}
+
+			if !includeUnreleased && !assignment.ScoreRelease.IsReleased(now) {
+				for _, assignmentGrades := range userAssignmentGrades {
+					assignmentGrades[assignmentID] = &AssignmentGrade{
+						AssignmentID: assignment.GetID(),
+						Status:       AssignmentGradeStatusPending,
+						MaxPoints:    assignment.MaxPoints,
+					}
+				}
+
+				continue
+			}
+
+			submissions, err := scoring.GetLocalSelectedSubmissions(assignment, emails)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get submissions for assignment '%s': '%w'.", assignmentID, err)
+			}
+
+			scoringInfos := make(map[string]*model.ScoringInfo, len(submissions))
+			for email, submission := range submissions {
+				if submission != nil {
+					scoringInfos[email] = submission.ToScoringInfo()
+				}
+			}
+
+			err = scoring.ApplyLocalPenalties(assignment, users, scoringInfos)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to apply penalties for assignment '%s': '%w'.", assignmentID, err)
+			}
+
+			for email, assignmentGrades := range userAssignmentGrades {
+				assignmentGrades[assignmentID] = newAssignmentGrade(assignment, email, submissions[email], scoringInfos[email], now)
+			}
+		}
+	}
+
+	results := make(map[string]*CourseGrade, len(userAssignmentGrades))
+	for email, assignmentGrades := range userAssignmentGrades {
+		results[email] = computeCourseGrade(scheme, email, assignmentGrades)
+	}
+
+	return results, nil
+}
+
+// The score comes from the (penalized) scoring info, the submission is only used for its max points.
+func newAssignmentGrade(assignment *model.Assignment, email string, submission *model.GradingInfo, scoringInfo *model.ScoringInfo, now timestamp.Timestamp) *AssignmentGrade {
+	grade := &AssignmentGrade{
+		AssignmentID: assignment.GetID(),
+		MaxPoints:    assignment.MaxPoints,
+	}
+
+	// Rejected submissions (e.g., too late) do not count.
+	if (submission == nil) || (scoringInfo == nil) || scoringInfo.Reject {
+		grade.Status = AssignmentGradeStatusPending
+
+		dueDate := assignment.GetEffectiveUserDueDate(email)
+		if (dueDate != nil) && (*dueDate < now) {
+			grade.Status = AssignmentGradeStatusMissing
+		}
+
+		return grade
+	}
+
+	grade.Status = AssignmentGradeStatusGraded
+	grade.Score = scoringInfo.Score
+
+	if grade.MaxPoints <= 0.0 {
+		grade.MaxPoints = submission.MaxPoints
+	}
+
+	if grade.MaxPoints > 0.0 {
+		grade.Percent = 100.0 * grade.Score / grade.MaxPoints
+	}
+
+	return grade
+}
+
+// Compute a user's course grade from their per-assignment grades.
+// All assignments in the scheme should be present in assignmentGrades.
+func computeCourseGrade(scheme *model.GradeScheme, email string, assignmentGrades map[string]*AssignmentGrade) *CourseGrade {
+	totalWeight := scheme.TotalWeight()
+
+	result := &CourseGrade{
+		Email:      email,
+		Categories: make([]*CategoryGrade, 0, len(scheme.Categories)),
+	}
+
+	// First, compute the current grade.
+	currentWeight := 0.0
+	currentWeightedSum := 0.0
+	extraCredit := 0.0
+
+	for _, category := range scheme.Categories {
+		categoryGrade := &CategoryGrade{
+			Name:        category.Name,
+			Weight:      category.Weight,
+			ExtraCredit: category.ExtraCredit,
+			Assignments: make([]*AssignmentGrade, 0, len(category.Assignments)),
+		}
+
+		completed := make([]*AssignmentGrade, 0, len(category.Assignments))
+		for _, assignmentID := range category.Assignments {
+			assignmentGrade := assignmentGrades[assignmentID]
+			if assignmentGrade == nil {
+				assignmentGrade = &AssignmentGrade{
+					AssignmentID: assignmentID,
+					Status:       AssignmentGradeStatusPending,
+				}
+			}
+
+			categoryGrade.Assignments = append(categoryGrade.Assignments, assignmentGrade)
+
+			if assignmentGrade.Status != AssignmentGradeStatusPending {
+				completed = append(completed, assignmentGrade)
+			}
+		}
+
+		categoryGrade.NumCompleted = len(completed)
+
+		if len(completed) > 0 {
+			percents := make([]float64, 0, len(completed))
+			for _, assignmentGrade := range completed {
+				percents = append(percents, assignmentGrade.Percent)
+			}
+
+			categoryGrade.CurrentPercent = meanAfterDrop(percents, category.DropLowest)
+
+			for _, dropped := range lowest(completed, category.DropLowest) {
+				dropped.Dropped = true
+			}
+
+			if category.ExtraCredit {
+				extraCredit += category.Weight * categoryGrade.CurrentPercent
+			} else {
+				currentWeight += category.Weight
+				currentWeightedSum += category.Weight * categoryGrade.CurrentPercent
+			}
+		}
+
+		result.Categories = append(result.Categories, categoryGrade)
+	}
+
+	if currentWeight > 0.0 {
+		result.CurrentPercent = currentWeightedSum / currentWeight
+	}
+
+	result.CurrentPercent += extraCredit / totalWeight
+
+	// Next, compute the projected grade (pending assignments count as zero).
+	projectedWeightedSum := 0.0
+
+	for i, category := range scheme.Categories {
+		categoryGrade := result.Categories[i]
+
+		percents := make([]float64, 0, len(categoryGrade.Assignments))
+		for _, assignmentGrade := range categoryGrade.Assignments {
+			percents = append(percents, assignmentGrade.Percent)
+		}
+
+		categoryGrade.ProjectedPercent = meanAfterDrop(percents, category.DropLowest)
+		projectedWeightedSum += category.Weight * categoryGrade.ProjectedPercent
+	}
+
+	result.ProjectedPercent = projectedWeightedSum / totalWeight
+
+	result.CurrentLetter = scheme.GetLetterGrade(result.CurrentPercent)
+	result.ProjectedLetter = scheme.GetLetterGrade(result.ProjectedPercent)
+
+	return result
+}
+
+// Get the mean of the values after dropping the lowest ones.
+// At least one value will always be kept.
+func meanAfterDrop(values []float64, dropLowest int) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+
+	values = slices.Clone(values)
+	slices.Sort(values)
+
+	dropCount := min(dropLowest, len(values)-1)
+	values = values[dropCount:]
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
+
+// Get the assignment grades that would be dropped (ties are broken by assignment ID).
+func lowest(grades []*AssignmentGrade, dropLowest int) []*AssignmentGrade {
+	if len(grades) == 0 {
+		return nil
+	}
+
+	grades = slices.Clone(grades)
+	slices.SortFunc(grades, func(a *AssignmentGrade, b *AssignmentGrade) int {
+		if a.Percent < b.Percent {
+			return -1
+		} else if a.Percent > b.Percent {
+			return 1
+		}
+
+		return strings.Compare(a.AssignmentID, b.AssignmentID)
+	})
+
+	dropCount := min(dropLowest, len(grades)-1)
+	return grades[:dropCount]
+}
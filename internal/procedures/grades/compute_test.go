@@ -0,0 +1,358 @@
+package grades
+
+import (
+	"math"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestComputeCourseGradeBase(test *testing.T) {
+	scheme := &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 60, Assignments: []string{"hw1", "hw2", "hw3"}, DropLowest: 1},
+			&model.GradeCategory{Name: "Exams", Weight: 40, Assignments: []string{"exam1", "exam2"}},
+			&model.GradeCategory{Name: "Bonus", Weight: 5, Assignments: []string{"bonus"}, ExtraCredit: true},
+		},
+		LetterGrades: []*model.LetterGrade{
+			&model.LetterGrade{Letter: "A", MinPercent: 90},
+			&model.LetterGrade{Letter: "B", MinPercent: 80},
+			&model.LetterGrade{Letter: "C", MinPercent: 70},
+		},
+	}
+
+	err := scheme.Validate(nil)
+	if err != nil {
+		test.Fatalf("Failed to validate scheme: '%v'.", err)
+	}
+
+	testCases := []struct {
+		percents          map[string]float64
+		missing           []string
+		expectedCurrent   float64
+		expectedProjected float64
+		expectedCurrentL  string
+		expectedProjL     string
+		expectedDropped   []string
+	}{
+		// Everything graded.
+		{
+			map[string]float64{"hw1": 100, "hw2": 50, "hw3": 80, "exam1": 70, "exam2": 90, "bonus": 100},
+			nil,
+			91, 91, "A", "A",
+			[]string{"hw2"},
+		},
+
+		// Nothing graded.
+		{
+			map[string]float64{},
+			nil,
+			0, 0, "", "",
+			[]string{},
+		},
+
+		// Some pending, the exams category is not considered for the current grade.
+		{
+			map[string]float64{"hw1": 100, "hw2": 50},
+			nil,
+			100, 45, "A", "",
+			[]string{"hw2"},
+		},
+
+		// Missing assignments count as zero.
+		{
+			map[string]float64{"hw1": 100, "exam1": 80},
+			[]string{"hw2", "hw3"},
+			62, 46, "", "",
+			[]string{"hw2"},
+		},
+
+		// Extra credit only.
+		{
+			map[string]float64{"bonus": 100},
+			nil,
+			5, 5, "", "",
+			[]string{},
+		},
+
+		// Only one completed assignment cannot be dropped.
+		{
+			map[string]float64{"hw1": 40},
+			nil,
+			40, 12, "", "",
+			[]string{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		assignmentGrades := make(map[string]*AssignmentGrade)
+		for assignmentID, percent := range testCase.percents {
+			assignmentGrades[assignmentID] = &AssignmentGrade{
+				AssignmentID: assignmentID,
+				Status:       AssignmentGradeStatusGraded,
+				Score:        percent,
+				MaxPoints:    100,
+				Percent:      percent,
+			}
+		}
+
+		for _, assignmentID := range testCase.missing {
+			assignmentGrades[assignmentID] = &AssignmentGrade{
+				AssignmentID: assignmentID,
+				Status:       AssignmentGradeStatusMissing,
+				MaxPoints:    100,
+			}
+		}
+
+		grade := computeCourseGrade(scheme, "alice@test.edulinq.org", assignmentGrades)
+
+		if !floatEquals(testCase.expectedCurrent, grade.CurrentPercent) {
+			test.Errorf("Case %d: Unexpected current percent. Expected: %f, Actual: %f.", i, testCase.expectedCurrent, grade.CurrentPercent)
+		}
+
+		if !floatEquals(testCase.expectedProjected, grade.ProjectedPercent) {
+			test.Errorf("Case %d: Unexpected projected percent. Expected: %f, Actual: %f.", i, testCase.expectedProjected, grade.ProjectedPercent)
+		}
+
+		if testCase.expectedCurrentL != grade.CurrentLetter {
+			test.Errorf("Case %d: Unexpected current letter. Expected: '%s', Actual: '%s'.", i, testCase.expectedCurrentL, grade.CurrentLetter)
+		}
+
+		if testCase.expectedProjL != grade.ProjectedLetter {
+			test.Errorf("Case %d: Unexpected projected letter. Expected: '%s', Actual: '%s'.", i, testCase.expectedProjL, grade.ProjectedLetter)
+		}
+
+		dropped := make([]string, 0)
+		for _, category := range grade.Categories {
+			for _, assignmentGrade := range category.Assignments {
+				if assignmentGrade.Dropped {
+					dropped = append(dropped, assignmentGrade.AssignmentID)
+				}
+			}
+		}
+
+		if util.MustToJSON(testCase.expectedDropped) != util.MustToJSON(dropped) {
+			test.Errorf("Case %d: Unexpected dropped assignments. Expected: '%v', Actual: '%v'.", i, testCase.expectedDropped, dropped)
+		}
+	}
+}
+
+func TestComputeCourseGradesDB(test *testing.T) {
+	defer db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+	course.GradeScheme = &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{"hw0"}},
+		},
+	}
+
+	grades, err := ComputeCourseGrades(course, model.CourseRoleStudent, true)
+	if err != nil {
+		test.Fatalf("Failed to compute grades: '%v'.", err)
+	}
+
+	expected := map[string]*CourseGrade{
+		"course-student@test.edulinq.org": &CourseGrade{
+			Email:            "course-student@test.edulinq.org",
+			CurrentPercent:   100,
+			ProjectedPercent: 100,
+			Categories: []*CategoryGrade{
+				&CategoryGrade{
+					Name:             "Homework",
+					Weight:           1,
+					NumCompleted:     1,
+					CurrentPercent:   100,
+					ProjectedPercent: 100,
+					Assignments: []*AssignmentGrade{
+						&AssignmentGrade{
+							AssignmentID: "hw0",
+							Status:       AssignmentGradeStatusGraded,
+							Score:        2,
+							MaxPoints:    2,
+							Percent:      100,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if util.MustToJSONIndent(expected) != util.MustToJSONIndent(grades) {
+		test.Fatalf("Unexpected grades. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(grades))
+	}
+
+	course.GradeScheme.Categories[0].Assignments = []string{"zzz"}
+
+	_, err = ComputeCourseGrades(course, model.CourseRoleStudent, true)
+	if err == nil {
+		test.Fatalf("Did not get an error on an unknown assignment.")
+	}
+}
+
+// Course grades use the same penalized scores that are uploaded to the LMS.
+func TestComputeCourseGradesPenalties(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+
+	course := db.MustGetTestCourse()
+	course.GradeScheme = &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{"hw0"}},
+		},
+	}
+
+	// The student's most recent submission is one day late.
+	dueDate := timestamp.FromMSecs(1697406270000)
+
+	assignment := course.GetAssignment("hw0")
+	assignment.DueDate = &dueDate
+	assignment.MaxPoints = 2
+	assignment.LatePolicy = &model.LateGradingPolicy{Type: model.ConstantPenalty, Penalty: 0.5}
+
+	integrityCase := model.NewIntegrityCase(course.GetID(), "hw0", "Test", "course-admin@test.edulinq.org")
+	integrityCase.Users = []string{email}
+	integrityCase.Penalty = &model.IntegrityPenalty{Type: model.IntegrityPenaltyPercentage, Value: 0.5, ApplyToScore: true}
+
+	err := integrityCase.SetStatus(model.IntegrityCaseStatusResolved, model.IntegrityCaseOutcomeViolation, "course-admin@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to resolve case: '%v'.", err)
+	}
+
+	err = db.UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to store case: '%v'.", err)
+	}
+
+	grades, err := ComputeCourseGrades(course, model.CourseRoleStudent, true)
+	if err != nil {
+		test.Fatalf("Failed to compute grades: '%v'.", err)
+	}
+
+	// (2 - 0.5 late penalty) * 0.5 integrity penalty.
+	expected := &AssignmentGrade{
+		AssignmentID: "hw0",
+		Status:       AssignmentGradeStatusGraded,
+		Score:        0.75,
+		MaxPoints:    2,
+		Percent:      37.5,
+	}
+
+	actual := grades[email].Categories[0].Assignments[0]
+	if util.MustToJSONIndent(expected) != util.MustToJSONIndent(actual) {
+		test.Fatalf("Unexpected assignment grade. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+
+	// Ledger late days are never charged when computing grades.
+	course.LatePolicy = &model.LateGradingPolicy{Type: model.LateDays, Penalty: 0.5, MaxLateDays: 1, TotalLateDays: 1}
+	assignment.LatePolicy = course.LatePolicy
+
+	_, err = ComputeCourseGrades(course, model.CourseRoleStudent, true)
+	if err != nil {
+		test.Fatalf("Failed to compute grades with late days: '%v'.", err)
+	}
+
+	entries, err := db.GetUserLateDayEntries(course, email)
+	if err != nil {
+		test.Fatalf("Failed to get late day entries: '%v'.", err)
+	}
+
+	if len(entries) != 0 {
+		test.Fatalf("Computing grades charged late days: '%s'.", util.MustToJSONIndent(entries))
+	}
+}
+
+// A single user's grade matches their grade when computing all grades.
+func TestComputeCourseGradeSingleUser(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+
+	course := db.MustGetTestCourse()
+	course.GradeScheme = &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{"hw0"}},
+		},
+	}
+
+	allGrades, err := ComputeCourseGrades(course, model.CourseRoleUnknown, true)
+	if err != nil {
+		test.Fatalf("Failed to compute all grades: '%v'.", err)
+	}
+
+	grade, err := ComputeCourseGrade(course, email, true)
+	if err != nil {
+		test.Fatalf("Failed to compute grade: '%v'.", err)
+	}
+
+	if util.MustToJSONIndent(allGrades[email]) != util.MustToJSONIndent(grade) {
+		test.Fatalf("Unexpected grade. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(allGrades[email]), util.MustToJSONIndent(grade))
+	}
+
+	grade, err = ComputeCourseGrade(course, "zzz@test.edulinq.org", true)
+	if err != nil {
+		test.Fatalf("Failed to compute grade for unknown user: '%v'.", err)
+	}
+
+	if grade != nil {
+		test.Fatalf("Got a grade for an unknown user: '%s'.", util.MustToJSONIndent(grade))
+	}
+}
+
+// A missing submission is still pending if the user has an extension that has not passed.
+func TestComputeCourseGradeExtension(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-other@test.edulinq.org"
+
+	course := db.MustGetTestCourse()
+	course.GradeScheme = &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{"hw0"}},
+		},
+	}
+
+	dueDate := timestamp.FromMSecs(1697406270000)
+	futureExtension := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+
+	assignment := course.GetAssignment("hw0")
+	assignment.DueDate = &dueDate
+
+	testCases := []struct {
+		extension *timestamp.Timestamp
+		expected  AssignmentGradeStatus
+	}{
+		{nil, AssignmentGradeStatusMissing},
+		{timestamp.ZeroPointer(), AssignmentGradeStatusMissing},
+		{&futureExtension, AssignmentGradeStatusPending},
+	}
+
+	for i, testCase := range testCases {
+		assignment.Extensions = nil
+		if testCase.extension != nil {
+			assignment.Extensions = map[string]timestamp.Timestamp{email: *testCase.extension}
+		}
+
+		grade, err := ComputeCourseGrade(course, email, true)
+		if err != nil {
+			test.Errorf("Case %d: Failed to compute grade: '%v'.", i, err)
+			continue
+		}
+
+		actual := grade.Categories[0].Assignments[0].Status
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected status. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func floatEquals(a float64, b float64) bool {
+	return math.Abs(a-b) < 0.0001
+}
@@ -0,0 +1,72 @@
+package grades
+
+import (
+	"strings"
+)
+
+type AssignmentGradeStatus string
+
+const (
+	// The user has a submission for this assignment.
+	AssignmentGradeStatusGraded AssignmentGradeStatus = "graded"
+	// The user has no submission and the assignment's (effective) due date has passed.
+	AssignmentGradeStatusMissing AssignmentGradeStatus = "missing"
+	// The user has no submission, but the assignment is not yet due (or has no due date).
+	AssignmentGradeStatusPending AssignmentGradeStatus = "pending"
+)
+
+type AssignmentGrade struct {
+	AssignmentID string                `json:"assignment-id"`
+	Status       AssignmentGradeStatus `json:"status"`
+	Score        float64               `json:"score"`
+	MaxPoints    float64               `json:"max-points"`
+	Percent      float64               `json:"percent"`
+
+	// True if this assignment was dropped when computing the current grade.
+	Dropped bool `json:"dropped"`
+}
+
+type CategoryGrade struct {
+	Name        string  `json:"name"`
+	Weight      float64 `json:"weight"`
+	ExtraCredit bool    `json:"extra-credit,omitempty"`
+
+	// The number of assignments that count towards the current grade (graded or missing).
+	NumCompleted int `json:"num-completed"`
+
+	CurrentPercent   float64 `json:"current-percent"`
+	ProjectedPercent float64 `json:"projected-percent"`
+
+	Assignments []*AssignmentGrade `json:"assignments"`
+}
+
+// All percents are on a 0-100 scale (but may exceed 100 with extra credit).
+// The current grade only considers completed (graded or missing) assignments,
+// and renormalizes the weights of categories that have at least one completed assignment.
+// The projected grade is the final grade the user would get if every pending assignment received a zero.
+type CourseGrade struct {
+	Email string `json:"email"`
+
+	CurrentPercent   float64 `json:"current-percent"`
+	CurrentLetter    string  `json:"current-letter,omitempty"`
+	ProjectedPercent float64 `json:"projected-percent"`
+	ProjectedLetter  string  `json:"projected-letter,omitempty"`
+
+	Categories []*CategoryGrade `json:"categories"`
+}
+
+func CompareCourseGrades(a *CourseGrade, b *CourseGrade) int {
+	if (a == nil) && (b == nil) {
+		return 0
+	}
+
+	if a == nil {
+		return 1
+	}
+
+	if b == nil {
+		return -1
+	}
+
+	return strings.Compare(a.Email, b.Email)
+}
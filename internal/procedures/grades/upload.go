@@ -0,0 +1,90 @@
+package grades
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Score and upload all of the course's assignments (see scoring.FullCourseScoringAndUpload()),
+// and then upload the course grades if the course's grade scheme has an LMS ID.
+// Returns: {assignmentID: {email: scoringInfo, ...}, ...}.
+func FullCourseScoringAndUpload(course *model.Course, dryRun bool) (map[string]map[string]*model.ScoringInfo, error) {
+	results, err := scoring.FullCourseScoringAndUpload(course, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := course.GetGradeScheme()
+	if (scheme != nil) && (scheme.LMSID != "") {
+		_, err = UploadCourseGrades(course, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to upload course grades for course '%s': '%w'.", course.GetID(), err)
+		}
+	}
+
+	return results, nil
+}
+
+// Upload each student's current course grade (as a percent) to the grade scheme's LMS assignment.
+// Assignments with unreleased scores are not included.
+// Returns the grades that were (or would be on a dry run) uploaded, keyed by email.
+func UploadCourseGrades(course *model.Course, dryRun bool) (map[string]*CourseGrade, error) {
+	scheme := course.GetGradeScheme()
+	if (scheme == nil) || (scheme.LMSID == "") {
+		return nil, fmt.Errorf("Course '%s' does not have an LMS ID for course grades.", course.GetID())
+	}
+
+	if course.GetLMSAdapter() == nil {
+		return nil, fmt.Errorf("Course '%s' has no LMS info associated with it.", course.GetID())
+	}
+
+	users, err := db.GetCourseUsers(course)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch autograder users: '%w'.", err)
+	}
+
+	grades, err := ComputeCourseGrades(course, model.CourseRoleStudent, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compute course grades: '%w'.", err)
+	}
+
+	now := timestamp.Now()
+
+	uploadedGrades := make(map[string]*CourseGrade, len(grades))
+	scores := make([]*lmstypes.SubmissionScore, 0, len(grades))
+
+	for email, grade := range grades {
+		lmsID := users[email].GetLMSID()
+		if lmsID == "" {
+			log.Warn("User does not have an LMS ID, skipping course grade upload.", course, log.NewUserAttr(email))
+			continue
+		}
+
+		scores = append(scores, &lmstypes.SubmissionScore{
+			UserID: lmsID,
+			Score:  grade.CurrentPercent,
+			Time:   &now,
+		})
+
+		uploadedGrades[email] = grade
+	}
+
+	if dryRun {
+		log.Debug("Dry Run: Skipping upload of course grades.", course, log.NewAttr("grades", scores))
+		return uploadedGrades, nil
+	}
+
+	err = lms.UpdateAssignmentScores(course, scheme.LMSID, scores)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to upload course grades: '%w'.", err)
+	}
+
+	return uploadedGrades, nil
+}
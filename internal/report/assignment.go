@@ -19,6 +19,7 @@ type AssignmentScoringReport struct {
 	AssignmentName      string                        `json:"assignment-name"`
 	NumberOfSubmissions int                           `json:"number-of-submissions"`
 	LatestSubmission    timestamp.Timestamp           `json:"latest-submission"`
+	LatePolicy          string                        `json:"late-policy"`
 	Questions           []*ScoringReportQuestionStats `json:"questions"`
 }
 
@@ -40,8 +41,8 @@ type ScoringReportQuestionStats struct {
 
 const DEFAULT_VALUE float64 = -1.0
 
-func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScoringReport, error) {
-	questionNames, scores, lastSubmissionTime, err := fetchScores(assignment)
+func GetAssignmentScoringReport(assignment *model.Assignment, filter *model.CourseUserFilter) (*AssignmentScoringReport, error) {
+	questionNames, scores, lastSubmissionTime, err := fetchScores(assignment, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -50,26 +51,7 @@ func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScorin
 	questions := make([]*ScoringReportQuestionStats, 0, len(questionNames))
 
 	for _, questionName := range questionNames {
-		min, max := util.MinMax(scores[questionName])
-		mean, stdDev := stat.MeanStdDev(scores[questionName], nil)
-		median := util.Median(scores[questionName])
-
-		stats := &ScoringReportQuestionStats{
-			QuestionName: questionName,
-			Min:          util.DefaultNaN(min, DEFAULT_VALUE),
-			Max:          util.DefaultNaN(max, DEFAULT_VALUE),
-			Median:       util.DefaultNaN(median, DEFAULT_VALUE),
-			Mean:         util.DefaultNaN(mean, DEFAULT_VALUE),
-			StdDev:       util.DefaultNaN(stdDev, DEFAULT_VALUE),
-
-			MinString:    fmt.Sprintf("%0.2f", min),
-			MaxString:    fmt.Sprintf("%0.2f", max),
-			MedianString: fmt.Sprintf("%0.2f", median),
-			MeanString:   fmt.Sprintf("%0.2f", mean),
-			StdDevString: fmt.Sprintf("%0.2f", stdDev),
-		}
-
-		questions = append(questions, stats)
+		questions = append(questions, newQuestionStats(questionName, scores[questionName]))
 		numSubmissions = len(scores[questionName])
 	}
 
@@ -77,16 +59,43 @@ func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScorin
 		AssignmentName:      assignment.GetName(),
 		NumberOfSubmissions: numSubmissions,
 		LatestSubmission:    lastSubmissionTime,
+		LatePolicy:          assignment.LatePolicy.Description(),
 		Questions:           questions,
 	}
 
 	return &report, nil
 }
 
-func fetchScores(assignment *model.Assignment) ([]string, map[string][]float64, timestamp.Timestamp, error) {
-	results, err := db.GetRecentSubmissions(assignment, model.CourseRoleStudent)
+func newQuestionStats(questionName string, values []float64) *ScoringReportQuestionStats {
+	min, max := util.MinMax(values)
+	mean, stdDev := stat.MeanStdDev(values, nil)
+	median := util.Median(values)
+
+	return &ScoringReportQuestionStats{
+		QuestionName: questionName,
+		Min:          util.DefaultNaN(min, DEFAULT_VALUE),
+		Max:          util.DefaultNaN(max, DEFAULT_VALUE),
+		Median:       util.DefaultNaN(median, DEFAULT_VALUE),
+		Mean:         util.DefaultNaN(mean, DEFAULT_VALUE),
+		StdDev:       util.DefaultNaN(stdDev, DEFAULT_VALUE),
+
+		MinString:    fmt.Sprintf("%0.2f", min),
+		MaxString:    fmt.Sprintf("%0.2f", max),
+		MedianString: fmt.Sprintf("%0.2f", median),
+		MeanString:   fmt.Sprintf("%0.2f", mean),
+		StdDevString: fmt.Sprintf("%0.2f", stdDev),
+	}
+}
+
+func fetchScores(assignment *model.Assignment, filter *model.CourseUserFilter) ([]string, map[string][]float64, timestamp.Timestamp, error) {
+	results, err := db.GetSelectedSubmissions(assignment, model.CourseRoleStudent)
+	if err != nil {
+		return nil, nil, timestamp.Zero(), fmt.Errorf("Failed to get selected submission results: '%w'.", err)
+	}
+
+	results, err = db.FilterByCourseUsers(assignment.GetCourse(), filter, results)
 	if err != nil {
-		return nil, nil, timestamp.Zero(), fmt.Errorf("Failed to get recent submission results: '%w'.", err)
+		return nil, nil, timestamp.Zero(), fmt.Errorf("Failed to filter submission results: '%w'.", err)
 	}
 
 	questionNames := make([]string, 0)
@@ -7,13 +7,18 @@ import (
 type CourseScoringReport struct {
 	CourseName  string                     `json:"course-name"`
 	Assignments []*AssignmentScoringReport `json:"assignments"`
+
+	// Only present if the course has a grade scheme.
+	CourseGrades *CourseGradesReport `json:"course-grades,omitempty"`
 }
 
-func GetCourseScoringReport(course *model.Course) (*CourseScoringReport, error) {
+// Get a scoring report for the course.
+// If a (non-empty) filter is provided, then only submissions from matching users will be included.
+func GetCourseScoringReport(course *model.Course, filter *model.CourseUserFilter) (*CourseScoringReport, error) {
 	assignmentReports := make([]*AssignmentScoringReport, 0)
 
 	for _, assignment := range course.GetSortedAssignments() {
-		assignmentReport, err := GetAssignmentScoringReport(assignment)
+		assignmentReport, err := GetAssignmentScoringReport(assignment, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -21,9 +26,15 @@ func GetCourseScoringReport(course *model.Course) (*CourseScoringReport, error)
 		assignmentReports = append(assignmentReports, assignmentReport)
 	}
 
+	courseGrades, err := GetCourseGradesReport(course, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	report := CourseScoringReport{
-		CourseName:  course.GetName(),
-		Assignments: assignmentReports,
+		CourseName:   course.GetName(),
+		Assignments:  assignmentReports,
+		CourseGrades: courseGrades,
 	}
 
 	return &report, nil
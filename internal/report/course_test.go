@@ -2,9 +2,11 @@ package report
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -12,7 +14,7 @@ import (
 func TestCourseReportBase(test *testing.T) {
 	course := db.MustGetTestCourse()
 
-	report, err := GetCourseScoringReport(course)
+	report, err := GetCourseScoringReport(course, nil)
 	if err != nil {
 		test.Fatalf("Failed to get course report: '%v'.", err)
 	}
@@ -26,7 +28,7 @@ func TestCourseReportBase(test *testing.T) {
 func TestCourseReportHTML(test *testing.T) {
 	course := db.MustGetTestCourse()
 
-	report, err := GetCourseScoringReport(course)
+	report, err := GetCourseScoringReport(course, nil)
 	if err != nil {
 		test.Fatalf("Failed to get course report: '%v'.", err)
 	}
@@ -54,6 +56,7 @@ var expected *CourseScoringReport = &CourseScoringReport{
 			AssignmentName:      "Homework 0",
 			NumberOfSubmissions: 1,
 			LatestSubmission:    timestamp.MustGuessFromString("2023-10-15T21:44:33Z"),
+			LatePolicy:          "No late policy.",
 			Questions: []*ScoringReportQuestionStats{
 				&ScoringReportQuestionStats{
 					QuestionName: "Q1",
@@ -111,3 +114,49 @@ var expected *CourseScoringReport = &CourseScoringReport{
 		},
 	},
 }
+
+func TestCourseReportCourseGrades(test *testing.T) {
+	defer db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+	course.GradeScheme = &model.GradeScheme{
+		Categories: []*model.GradeCategory{
+			&model.GradeCategory{Name: "Homework", Weight: 1, Assignments: []string{"hw0"}},
+		},
+		LetterGrades: []*model.LetterGrade{
+			&model.LetterGrade{Letter: "A", MinPercent: 90},
+			&model.LetterGrade{Letter: "F", MinPercent: 0},
+		},
+	}
+
+	report, err := GetCourseScoringReport(course, nil)
+	if err != nil {
+		test.Fatalf("Failed to get course report: '%v'.", err)
+	}
+
+	expectedGrades := &CourseGradesReport{
+		NumberOfStudents: 1,
+		Grades: []*ScoringReportQuestionStats{
+			newQuestionStats(CURRENT_GRADE_NAME, []float64{100}),
+			newQuestionStats(PROJECTED_GRADE_NAME, []float64{100}),
+		},
+		CurrentLetters: []*LetterGradeCount{
+			&LetterGradeCount{Letter: "A", Count: 1},
+			&LetterGradeCount{Letter: "F", Count: 0},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedGrades, report.CourseGrades) {
+		test.Fatalf("Course grades not as expected.\n--- Expected ---\n%s\n--- Actual ---\n%s\n",
+			util.MustToJSONIndent(expectedGrades), util.MustToJSONIndent(report.CourseGrades))
+	}
+
+	reportHTML, err := report.ToHTML()
+	if err != nil {
+		test.Fatalf("Failed to generate HTML for report: '%v'.", err)
+	}
+
+	if !strings.Contains(reportHTML, "Current Letter Grades: A: 1, F: 0") {
+		test.Fatalf("Report HTML does not contain letter grades: '%s'.", reportHTML)
+	}
+}
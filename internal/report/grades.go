@@ -0,0 +1,85 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/grades"
+)
+
+const (
+	CURRENT_GRADE_NAME   = "Current Grade"
+	PROJECTED_GRADE_NAME = "Projected Grade"
+)
+
+type CourseGradesReport struct {
+	NumberOfStudents int `json:"number-of-students"`
+
+	// Stats (as percents) for the current and projected course grades.
+	Grades []*ScoringReportQuestionStats `json:"grades"`
+
+	// The number of students with each current letter grade (if the grade scheme has letter grades).
+	CurrentLetters []*LetterGradeCount `json:"current-letters,omitempty"`
+}
+
+type LetterGradeCount struct {
+	Letter string `json:"letter"`
+	Count  int    `json:"count"`
+}
+
+// Get a report of the course grades of all students (see grades.ComputeCourseGrades()).
+// Returns nil if the course does not have a grade scheme.
+// If a (non-empty) filter is provided, then only matching users will be included.
+func GetCourseGradesReport(course *model.Course, filter *model.CourseUserFilter) (*CourseGradesReport, error) {
+	scheme := course.GetGradeScheme()
+	if scheme == nil {
+		return nil, nil
+	}
+
+	courseGrades, err := grades.ComputeCourseGrades(course, model.CourseRoleStudent, true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to compute course grades: '%w'.", err)
+	}
+
+	courseGrades, err = db.FilterByCourseUsers(course, filter, courseGrades)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to filter course grades: '%w'.", err)
+	}
+
+	current := make([]float64, 0, len(courseGrades))
+	projected := make([]float64, 0, len(courseGrades))
+	letterCounts := make(map[string]int)
+
+	for _, courseGrade := range courseGrades {
+		current = append(current, courseGrade.CurrentPercent)
+		projected = append(projected, courseGrade.ProjectedPercent)
+
+		if courseGrade.CurrentLetter != "" {
+			letterCounts[courseGrade.CurrentLetter]++
+		}
+	}
+
+	// Keep the values in a stable order.
+	slices.Sort(current)
+	slices.Sort(projected)
+
+	report := &CourseGradesReport{
+		NumberOfStudents: len(courseGrades),
+		Grades: []*ScoringReportQuestionStats{
+			newQuestionStats(CURRENT_GRADE_NAME, current),
+			newQuestionStats(PROJECTED_GRADE_NAME, projected),
+		},
+	}
+
+	// Letters are listed in the scheme's order (highest first).
+	for _, letterGrade := range scheme.LetterGrades {
+		report.CurrentLetters = append(report.CurrentLetters, &LetterGradeCount{
+			Letter: letterGrade.Letter,
+			Count:  letterCounts[letterGrade.Letter],
+		})
+	}
+
+	return report, nil
+}
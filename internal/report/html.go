@@ -78,6 +78,44 @@ var courseReportTemplate string = `
             <h1>Course: {{ .CourseName }}</h1>
         </div>
         <div class='ag-body'>
+            {{ with .CourseGrades }}
+                <div class='autograder autograder-assignment-scoring-report'>
+                    <div class='ag-header'>
+                        <h2>Course Grades</h2>
+                        <p>Number of Students: {{ .NumberOfStudents }}</p>
+                        {{ if .CurrentLetters }}
+                            <p>Current Letter Grades: {{ range $i, $letter := .CurrentLetters }}{{ if $i }}, {{ end }}{{ $letter.Letter }}: {{ $letter.Count }}{{ end }}</p>
+                        {{ end }}
+                    </div>
+                    <div class='ag-body'>
+                        <table>
+                            <thead>
+                                <tr>
+                                    <th>Grade (%)</th>
+                                    <th>Mean</th>
+                                    <th>Median</th>
+                                    <th>Min</th>
+                                    <th>Max</th>
+                                    <th>StdDev</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {{ range .Grades }}
+                                    <tr>
+                                        <td class='text'>{{ .QuestionName }}</td>
+                                        <td class='numeric'>{{ .MeanString }}</td>
+                                        <td class='numeric'>{{ .MedianString }}</td>
+                                        <td class='numeric'>{{ .MinString }}</td>
+                                        <td class='numeric'>{{ .MaxString }}</td>
+                                        <td class='numeric'>{{ .StdDevString }}</td>
+                                    </tr>
+                                {{ end }}
+                            </tbody>
+                        </table>
+                    </div>
+                </div>
+            {{ end }}
+
             {{ range .Assignments }}
                 {{ if eq .NumberOfSubmissions 0 }} {{ continue }} {{ end }}
 
@@ -95,6 +133,7 @@ var assignmentReportTemplate string = `
             <h2>Assignment: {{ .AssignmentName }}</h2>
             <p>Number of Submissions: {{ .NumberOfSubmissions }}</p>
             <p>Latest Submission: {{ .LatestSubmission.UnsafePrettyString }}</p>
+            <p>Late Policy: {{ .LatePolicy }}</p>
         </div>
         <div class='ag-body'>
             <table>
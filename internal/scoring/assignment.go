@@ -21,6 +21,13 @@ func FullAssignmentScoringAndUpload(assignment *model.Assignment, dryRun bool) (
 		return nil, fmt.Errorf("Assignment's course has no LMS info associated with it.")
 	}
 
+	// Unreleased scores are never uploaded, including by manual (API/CLI) uploads.
+	// To upload them, release the scores first (e.g., by setting the release time to now).
+	if !assignment.IsScoreReleased() {
+		log.Info("Assignment scores have not been released, skipping scoring.", assignment)
+		return make(map[string]*model.ScoringInfo), nil
+	}
+
 	users, err := db.GetCourseUsers(assignment.GetCourse())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch autograder users: '%w'.", err)
@@ -31,14 +38,14 @@ func FullAssignmentScoringAndUpload(assignment *model.Assignment, dryRun bool) (
 		return nil, fmt.Errorf("Could not fetch LMS grades: '%w'.", err)
 	}
 
-	scoringInfos, err := db.GetExistingScoringInfos(assignment, model.CourseRoleStudent)
+	scoringInfos, err := GetSelectedScoringInfos(assignment, model.CourseRoleStudent)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get scoring information: '%w'.", err)
 	}
 
-	err = ApplyLatePolicy(assignment, users, scoringInfos, dryRun)
+	err = ApplyPenalties(assignment, users, scoringInfos, dryRun)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to apply late policy: '%w'.", err)
+		return nil, err
 	}
 
 	uploadedScores, err := computeFinalScores(assignment, users, scoringInfos, lmsScores, dryRun)
@@ -49,6 +56,35 @@ func FullAssignmentScoringAndUpload(assignment *model.Assignment, dryRun bool) (
 	return uploadedScores, nil
 }
 
+// Apply the late policy and then any integrity penalties to the scoring infos.
+// The resulting scores are the ones uploaded to the LMS (and used in course grades).
+// On a dry run, late days (in the LMS or the ledger) are not updated.
+func ApplyPenalties(assignment *model.Assignment, users map[string]*model.CourseUser, scoringInfos map[string]*model.ScoringInfo, dryRun bool) error {
+	return applyPenalties(assignment, users, scoringInfos, dryRun, false)
+}
+
+// Same as ApplyPenalties() on a dry run, but only data stored in the autograder is used (the LMS is never contacted).
+// The assignment's own due date and max points are used (which may have been synced from the LMS).
+// Late days tracked in the LMS (instead of the late day ledger) are not available,
+// so users are assumed to have enough late days (up to the late policy's max late days).
+func ApplyLocalPenalties(assignment *model.Assignment, users map[string]*model.CourseUser, scoringInfos map[string]*model.ScoringInfo) error {
+	return applyPenalties(assignment, users, scoringInfos, true, true)
+}
+
+func applyPenalties(assignment *model.Assignment, users map[string]*model.CourseUser, scoringInfos map[string]*model.ScoringInfo, dryRun bool, localOnly bool) error {
+	err := applyLatePolicy(assignment, users, scoringInfos, dryRun, localOnly)
+	if err != nil {
+		return fmt.Errorf("Failed to apply late policy: '%w'.", err)
+	}
+
+	err = ApplyIntegrityPenalties(assignment, scoringInfos)
+	if err != nil {
+		return fmt.Errorf("Failed to apply integrity penalties: '%w'.", err)
+	}
+
+	return nil
+}
+
 func computeFinalScores(
 	assignment *model.Assignment, users map[string]*model.CourseUser, scoringInfos map[string]*model.ScoringInfo, lmsScores []*lmstypes.SubmissionScore, dryRun bool) (map[string]*model.ScoringInfo, error) {
 	var err error
@@ -62,6 +98,12 @@ func computeFinalScores(
 	// Next, create the grades that will actually be uploaded and the comments that will be updated..
 	usedScoringInfos, finalScores, commentsToUpdate := filterFinalScores(assignment, users, scoringInfos, locks, existingComments)
 
+	// Attach any additional feedback.
+	err = addFeedback(assignment, users, usedScoringInfos, finalScores)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add feedback to final scores: '%w'.", err)
+	}
+
 	// Upload the grades.
 	if dryRun {
 		log.Debug("Dry Run: Skipping upload of final grades.", assignment, log.NewAttr("grades", finalScores))
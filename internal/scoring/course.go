@@ -7,6 +7,8 @@ import (
 	"github.com/edulinq/autograder/internal/model"
 )
 
+// Score and upload each of the course's assignments.
+// Course grades are not uploaded here (see grades.FullCourseScoringAndUpload()).
 // Returns: {assignmentID: {email: scoringInfo, ...}, ...}.
 func FullCourseScoringAndUpload(course *model.Course, dryRun bool) (map[string]map[string]*model.ScoringInfo, error) {
 	assignments := course.GetSortedAssignments()
@@ -0,0 +1,181 @@
+package scoring
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Add the feedback requested by the course's LMS adapter (a rendered report and/or rubric points) to the scores that will be uploaded.
+// Feedback is based on the submission that each score came from,
+// but the total is always the (penalized) score that is uploaded.
+func addFeedback(assignment *model.Assignment, users map[string]*model.CourseUser,
+	usedScoringInfos map[string]*model.ScoringInfo, finalScores []*lmstypes.SubmissionScore) error {
+	adapter := assignment.GetCourse().GetLMSAdapter()
+	if (adapter == nil) || ((adapter.UploadFeedback == "") && !adapter.UploadRubric) {
+		return nil
+	}
+
+	emails := make(map[string]string, len(users))
+	for email, user := range users {
+		emails[user.GetLMSID()] = email
+	}
+
+	for _, score := range finalScores {
+		email := emails[score.UserID]
+		scoringInfo := usedScoringInfos[email]
+		if scoringInfo == nil {
+			continue
+		}
+
+		gradingInfo, err := db.GetSubmissionResult(assignment, email, scoringInfo.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to get submission result for user '%s': '%w'.", email, err)
+		}
+
+		if gradingInfo == nil {
+			log.Warn("Could not find submission result, skipping feedback upload.",
+				assignment, log.NewUserAttr(email), log.NewAttr("submission-id", scoringInfo.ID))
+			continue
+		}
+
+		// Feedback is shown to students.
+		gradingInfo.RedactHiddenTestCases()
+
+		if adapter.UploadRubric {
+			if hasPenalty(scoringInfo) {
+				log.Debug("Score has a penalty, skipping rubric upload.", assignment, log.NewUserAttr(email))
+			} else {
+				score.Rubric = getRubric(gradingInfo)
+			}
+		}
+
+		if adapter.UploadFeedback != "" {
+			score.Attachments = []*lmstypes.SubmissionAttachment{renderFeedback(assignment, gradingInfo, scoringInfo, adapter.UploadFeedback)}
+		}
+	}
+
+	return nil
+}
+
+// Get the points removed by the late policy and by integrity penalties.
+func getPenalties(scoringInfo *model.ScoringInfo) (float64, float64) {
+	latePenalty := max(0.0, scoringInfo.RawScore-scoringInfo.Score-scoringInfo.IntegrityPenalty)
+	return latePenalty, scoringInfo.IntegrityPenalty
+}
+
+func hasPenalty(scoringInfo *model.ScoringInfo) bool {
+	latePenalty, integrityPenalty := getPenalties(scoringInfo)
+	return !util.IsClose(0.0, latePenalty) || !util.IsClose(0.0, integrityPenalty)
+}
+
+// Get the rows (label and points) that explain how the raw score became the uploaded score.
+// Returns nil if no penalty was applied.
+func getPenaltyRows(scoringInfo *model.ScoringInfo) [][2]string {
+	if !hasPenalty(scoringInfo) {
+		return nil
+	}
+
+	rows := [][2]string{[2]string{"Raw Total", util.FloatToStr(scoringInfo.RawScore)}}
+
+	latePenalty, integrityPenalty := getPenalties(scoringInfo)
+	if !util.IsClose(0.0, latePenalty) {
+		rows = append(rows, [2]string{fmt.Sprintf("Late Penalty (days late: %d)", scoringInfo.NumDaysLate), util.FloatToStr(-latePenalty)})
+	}
+
+	if !util.IsClose(0.0, integrityPenalty) {
+		rows = append(rows, [2]string{"Academic Integrity Penalty", util.FloatToStr(-integrityPenalty)})
+	}
+
+	return rows
+}
+
+// Get the rubric points for a submission (keyed by question name).
+// Rubric points are raw points, so they should not be used when a penalty was applied.
+func getRubric(gradingInfo *model.GradingInfo) map[string]float64 {
+	rubric := make(map[string]float64, len(gradingInfo.Questions))
+	for _, question := range gradingInfo.Questions {
+		rubric[question.Name] = question.Score
+	}
+
+	return rubric
+}
+
+func renderFeedback(assignment *model.Assignment, gradingInfo *model.GradingInfo, scoringInfo *model.ScoringInfo, format string) *lmstypes.SubmissionAttachment {
+	if format == model.LMS_FEEDBACK_FORMAT_HTML {
+		return &lmstypes.SubmissionAttachment{
+			Filename:    fmt.Sprintf("%s-feedback.html", assignment.GetID()),
+			ContentType: "text/html",
+			Content:     []byte(renderFeedbackHTML(gradingInfo, scoringInfo)),
+		}
+	}
+
+	return &lmstypes.SubmissionAttachment{
+		Filename:    fmt.Sprintf("%s-feedback.md", assignment.GetID()),
+		ContentType: "text/markdown",
+		Content:     []byte(renderFeedbackMarkdown(gradingInfo, scoringInfo)),
+	}
+}
+
+func renderFeedbackMarkdown(gradingInfo *model.GradingInfo, scoringInfo *model.ScoringInfo) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("# Autograder Feedback: %s\n\n", gradingInfo.Name))
+
+	builder.WriteString("| Question | Score | Max Points |\n")
+	builder.WriteString("|---|---|---|\n")
+
+	for _, question := range gradingInfo.Questions {
+		builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n",
+			strings.ReplaceAll(question.Name, "|", "\\|"), util.FloatToStr(question.Score), util.FloatToStr(question.MaxPoints)))
+	}
+
+	for _, row := range getPenaltyRows(scoringInfo) {
+		builder.WriteString(fmt.Sprintf("| %s | %s | |\n", row[0], row[1]))
+	}
+
+	builder.WriteString(fmt.Sprintf("| **Total** | %s | %s |\n\n", util.FloatToStr(scoringInfo.Score), util.FloatToStr(gradingInfo.MaxPoints)))
+
+	builder.WriteString("## Transcript\n\n")
+	builder.WriteString("```\n")
+	builder.WriteString(strings.ReplaceAll(gradingInfo.Report(), "```", "'''"))
+	builder.WriteString("\n```\n")
+
+	return builder.String()
+}
+
+func renderFeedbackHTML(gradingInfo *model.GradingInfo, scoringInfo *model.ScoringInfo) string {
+	var builder strings.Builder
+
+	builder.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	builder.WriteString(fmt.Sprintf("<title>Autograder Feedback: %s</title>\n", html.EscapeString(gradingInfo.Name)))
+	builder.WriteString("</head>\n<body>\n")
+	builder.WriteString(fmt.Sprintf("<h1>Autograder Feedback: %s</h1>\n", html.EscapeString(gradingInfo.Name)))
+
+	builder.WriteString("<table>\n")
+	builder.WriteString("<tr><th>Question</th><th>Score</th><th>Max Points</th></tr>\n")
+
+	for _, question := range gradingInfo.Questions {
+		builder.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(question.Name), util.FloatToStr(question.Score), util.FloatToStr(question.MaxPoints)))
+	}
+
+	for _, row := range getPenaltyRows(scoringInfo) {
+		builder.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td></td></tr>\n", html.EscapeString(row[0]), row[1]))
+	}
+
+	builder.WriteString(fmt.Sprintf("<tr><th>Total</th><th>%s</th><th>%s</th></tr>\n", util.FloatToStr(scoringInfo.Score), util.FloatToStr(gradingInfo.MaxPoints)))
+	builder.WriteString("</table>\n")
+
+	builder.WriteString("<h2>Transcript</h2>\n")
+	builder.WriteString(fmt.Sprintf("<pre>%s</pre>\n", html.EscapeString(gradingInfo.Report())))
+	builder.WriteString("</body>\n</html>\n")
+
+	return builder.String()
+}
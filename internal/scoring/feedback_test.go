@@ -0,0 +1,106 @@
+package scoring
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestAddFeedback(test *testing.T) {
+	testCases := []struct {
+		format           string
+		rubric           bool
+		latePenalty      float64
+		integrityPenalty float64
+		expectedRubric   map[string]float64
+		expectedFile     string
+		expectedText     []string
+	}{
+		{"", false, 0, 0, nil, "", nil},
+		{"", true, 0, 0, map[string]float64{"Q1": 1, "Q2": 1, "Style": 0}, "", nil},
+		{model.LMS_FEEDBACK_FORMAT_MARKDOWN, false, 0, 0, nil, "hw0-feedback.md", []string{"| **Total** | 2 | 2 |"}},
+		{model.LMS_FEEDBACK_FORMAT_HTML, true, 0, 0, map[string]float64{"Q1": 1, "Q2": 1, "Style": 0}, "hw0-feedback.html", []string{"<tr><th>Total</th><th>2</th><th>2</th></tr>"}},
+
+		// Penalties are shown and the total is the penalized score, rubric points are not uploaded.
+		{"", true, 0.5, 0, nil, "", nil},
+		{
+			model.LMS_FEEDBACK_FORMAT_MARKDOWN, true, 0.5, 0.25, nil, "hw0-feedback.md",
+			[]string{"| Raw Total | 2 | |", "| Late Penalty (days late: 1) | -0.5 | |", "| Academic Integrity Penalty | -0.25 | |", "| **Total** | 1.25 | 2 |"},
+		},
+		{
+			model.LMS_FEEDBACK_FORMAT_HTML, false, 0, 1, nil, "hw0-feedback.html",
+			[]string{"<tr><td>Raw Total</td><td>2</td><td></td></tr>", "<tr><td>Academic Integrity Penalty</td><td>-1</td><td></td></tr>", "<tr><th>Total</th><th>1</th><th>2</th></tr>"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		assignment := db.MustGetTestAssignment()
+		assignment.GetCourse().GetLMSAdapter().UploadFeedback = testCase.format
+		assignment.GetCourse().GetLMSAdapter().UploadRubric = testCase.rubric
+
+		users, err := db.GetCourseUsers(assignment.GetCourse())
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get users: '%v'.", i, err)
+		}
+
+		scoringInfos, err := db.GetExistingScoringInfos(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get scoring infos: '%v'.", i, err)
+		}
+
+		email := "course-student@test.edulinq.org"
+		score := &lmstypes.SubmissionScore{UserID: users[email].GetLMSID()}
+
+		scoringInfo := scoringInfos[email]
+		scoringInfo.Score = scoringInfo.RawScore - testCase.latePenalty - testCase.integrityPenalty
+		scoringInfo.IntegrityPenalty = testCase.integrityPenalty
+		if testCase.latePenalty > 0 {
+			scoringInfo.NumDaysLate = 1
+		}
+
+		err = addFeedback(assignment, users, map[string]*model.ScoringInfo{email: scoringInfo}, []*lmstypes.SubmissionScore{score})
+		if err != nil {
+			test.Errorf("Case %d: Failed to add feedback: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedRubric, score.Rubric) {
+			test.Errorf("Case %d: Unexpected rubric. Expected: '%v', Actual: '%v'.", i, testCase.expectedRubric, score.Rubric)
+			continue
+		}
+
+		if testCase.expectedFile == "" {
+			if score.Attachments != nil {
+				test.Errorf("Case %d: Found unexpected attachments.", i)
+			}
+
+			continue
+		}
+
+		if len(score.Attachments) != 1 {
+			test.Errorf("Case %d: Unexpected number of attachments. Expected: 1, Actual: %d.", i, len(score.Attachments))
+			continue
+		}
+
+		attachment := score.Attachments[0]
+		if testCase.expectedFile != attachment.Filename {
+			test.Errorf("Case %d: Unexpected filename. Expected: '%s', Actual: '%s'.", i, testCase.expectedFile, attachment.Filename)
+			continue
+		}
+
+		for _, expectedText := range testCase.expectedText {
+			if !strings.Contains(string(attachment.Content), expectedText) {
+				test.Errorf("Case %d: Attachment does not contain expected text. Expected: '%s', Actual: '%s'.", i, expectedText, string(attachment.Content))
+				break
+			}
+		}
+	}
+
+	db.ResetForTesting()
+}
@@ -0,0 +1,55 @@
+package scoring
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Apply the penalties of any resolved integrity cases (with a violation outcome) for this assignment.
+// This should be called after the late policy has been applied (since that resets scores).
+// Users with multiple cases will have each case's penalty applied (in order of case creation).
+func ApplyIntegrityPenalties(assignment *model.Assignment, scores map[string]*model.ScoringInfo) error {
+	cases, err := db.GetIntegrityCases(assignment.GetCourse())
+	if err != nil {
+		return fmt.Errorf("Failed to get integrity cases: '%w'.", err)
+	}
+
+	activeCases := make([]*model.IntegrityCase, 0)
+	for _, integrityCase := range cases {
+		if (integrityCase.AssignmentID == assignment.GetID()) && integrityCase.IsPenaltyActive() {
+			activeCases = append(activeCases, integrityCase)
+		}
+	}
+
+	slices.SortFunc(activeCases, func(a *model.IntegrityCase, b *model.IntegrityCase) int {
+		if a.CreatedTime != b.CreatedTime {
+			return cmp.Compare(a.CreatedTime, b.CreatedTime)
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for _, integrityCase := range activeCases {
+		for _, email := range integrityCase.Users {
+			score, ok := scores[email]
+			if !ok || (score == nil) {
+				continue
+			}
+
+			newScore := integrityCase.Penalty.Apply(score.Score)
+			score.IntegrityPenalty += (score.Score - newScore)
+			score.Score = newScore
+
+			log.Debug("Applied integrity penalty.", assignment, log.NewUserAttr(email),
+				log.NewAttr("case", integrityCase.ID), log.NewAttr("penalty", integrityCase.Penalty.String()))
+		}
+	}
+
+	return nil
+}
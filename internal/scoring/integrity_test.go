@@ -0,0 +1,77 @@
+package scoring
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestApplyIntegrityPenalties(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	course := assignment.GetCourse()
+
+	// An active penalty.
+	addTestIntegrityCase(test, course, 100, "hw0", []string{"a@test.edulinq.org", "b@test.edulinq.org"},
+		model.IntegrityCaseOutcomeViolation, &model.IntegrityPenalty{Type: model.IntegrityPenaltyPoints, Value: 2, ApplyToScore: true})
+
+	// A second active penalty (applied after the first).
+	addTestIntegrityCase(test, course, 200, "hw0", []string{"a@test.edulinq.org"},
+		model.IntegrityCaseOutcomeViolation, &model.IntegrityPenalty{Type: model.IntegrityPenaltyPercentage, Value: 0.5, ApplyToScore: true})
+
+	// Not applied to scores.
+	addTestIntegrityCase(test, course, 300, "hw0", []string{"c@test.edulinq.org"},
+		model.IntegrityCaseOutcomeViolation, &model.IntegrityPenalty{Type: model.IntegrityPenaltyZero, ApplyToScore: false})
+
+	// No violation.
+	addTestIntegrityCase(test, course, 400, "hw0", []string{"c@test.edulinq.org"},
+		model.IntegrityCaseOutcomeNoViolation, &model.IntegrityPenalty{Type: model.IntegrityPenaltyZero, ApplyToScore: true})
+
+	// Another assignment.
+	addTestIntegrityCase(test, course, 500, "hw1", []string{"c@test.edulinq.org"},
+		model.IntegrityCaseOutcomeViolation, &model.IntegrityPenalty{Type: model.IntegrityPenaltyZero, ApplyToScore: true})
+
+	scores := map[string]*model.ScoringInfo{
+		"a@test.edulinq.org": &model.ScoringInfo{RawScore: 10, Score: 10},
+		"b@test.edulinq.org": &model.ScoringInfo{RawScore: 10, Score: 1},
+		"c@test.edulinq.org": &model.ScoringInfo{RawScore: 10, Score: 10},
+	}
+
+	expected := map[string]*model.ScoringInfo{
+		"a@test.edulinq.org": &model.ScoringInfo{RawScore: 10, Score: 4, IntegrityPenalty: 6},
+		"b@test.edulinq.org": &model.ScoringInfo{RawScore: 10, Score: 0, IntegrityPenalty: 1},
+		"c@test.edulinq.org": &model.ScoringInfo{RawScore: 10, Score: 10},
+	}
+
+	err := ApplyIntegrityPenalties(assignment, scores)
+	if err != nil {
+		test.Fatalf("Failed to apply penalties: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, scores) {
+		test.Fatalf("Unexpected scores. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(scores))
+	}
+}
+
+func addTestIntegrityCase(test *testing.T, course *model.Course, createdTime int64, assignmentID string, users []string, outcome model.IntegrityCaseOutcome, penalty *model.IntegrityPenalty) {
+	integrityCase := model.NewIntegrityCase(course.GetID(), assignmentID, "Test", "course-admin@test.edulinq.org")
+	integrityCase.Users = users
+	integrityCase.Penalty = penalty
+	integrityCase.CreatedTime = timestamp.FromMSecs(createdTime)
+
+	err := integrityCase.SetStatus(model.IntegrityCaseStatusResolved, outcome, "course-admin@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to resolve case: '%v'.", err)
+	}
+
+	err = db.UpsertIntegrityCase(course, integrityCase)
+	if err != nil {
+		test.Fatalf("Failed to store case: '%v'.", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/lms"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/log"
@@ -29,12 +30,23 @@ type LateDaysInfo struct {
 	LMSCommentAuthorID string `json:"-"`
 }
 
-// This assumes that all assignments are in the LMS.
+// The due date and max points come from the LMS if the assignment is in the LMS,
+// otherwise they come from the assignment's own config.
+// Users with an extension use their extension's due date instead.
 func ApplyLatePolicy(
 	assignment *model.Assignment,
 	users map[string]*model.CourseUser,
 	scores map[string]*model.ScoringInfo,
 	dryRun bool) error {
+	return applyLatePolicy(assignment, users, scores, dryRun, false)
+}
+
+// If localOnly is true, then the LMS is never contacted (see ApplyLocalPenalties()).
+func applyLatePolicy(
+	assignment *model.Assignment,
+	users map[string]*model.CourseUser,
+	scores map[string]*model.ScoringInfo,
+	dryRun bool, localOnly bool) error {
 	policy := assignment.GetLatePolicy()
 
 	// Start with each submission getting the raw score.
@@ -47,16 +59,14 @@ func ApplyLatePolicy(
 		return nil
 	}
 
-	lmsAssignment, err := lms.FetchAssignment(assignment.GetCourse(), assignment.GetLMSID())
+	rawDueDate, maxPoints, err := fetchDueDateAndMaxPoints(assignment, localOnly)
 	if err != nil {
 		return err
 	}
 
-	if lmsAssignment.DueDate == nil {
-		return fmt.Errorf("Assignment does not have a due date.")
-	}
+	dueDates := computeUserDueDates(assignment, scores, rawDueDate)
 
-	applyBaselinePolicy(assignment, policy, users, scores, *lmsAssignment.DueDate)
+	applyBaselinePolicy(assignment, policy, users, scores, dueDates)
 
 	// Baseline policy is complete.
 	if policy.Type == model.BaselinePolicy {
@@ -66,7 +76,7 @@ func ApplyLatePolicy(
 	if (policy.Type == model.ConstantPenalty) || (policy.Type == model.PercentagePenalty) {
 		penalty := policy.Penalty
 		if policy.Type == model.PercentagePenalty {
-			penalty = lmsAssignment.MaxPoints * policy.Penalty
+			penalty = maxPoints * policy.Penalty
 		}
 
 		applyConstantPolicy(policy, scores, penalty)
@@ -74,8 +84,8 @@ func ApplyLatePolicy(
 	}
 
 	if policy.Type == model.LateDays {
-		penalty := lmsAssignment.MaxPoints * policy.Penalty
-		err = applyLateDaysPolicy(policy, assignment, users, scores, penalty, dryRun)
+		penalty := maxPoints * policy.Penalty
+		err = applyLateDaysPolicy(policy, assignment, users, scores, penalty, dryRun, localOnly)
 		if err != nil {
 			return fmt.Errorf("Failed to apply late days policy: '%w'.", err)
 		}
@@ -83,13 +93,73 @@ func ApplyLatePolicy(
 		return nil
 	}
 
+	if policy.Type == model.ExponentialPenalty {
+		applyExponentialPolicy(policy, scores)
+		return nil
+	}
+
+	if policy.Type == model.HourlyPenalty {
+		penalty := maxPoints * policy.Penalty
+		applyHourlyPolicy(scores, dueDates, penalty)
+		return nil
+	}
+
+	if policy.Type == model.BestOfOnTime {
+		penalty := maxPoints * policy.Penalty
+		err = applyBestOfOnTimePolicy(assignment, users, scores, dueDates, penalty)
+		if err != nil {
+			return fmt.Errorf("Failed to apply best of on-time policy: '%w'.", err)
+		}
+
+		return nil
+	}
+
 	return fmt.Errorf("Unknown late policy type: '%s'.", policy.Type)
 }
 
+func fetchDueDateAndMaxPoints(assignment *model.Assignment, localOnly bool) (timestamp.Timestamp, float64, error) {
+	dueDate, maxPoints, err := fetchOptionalDueDateAndMaxPoints(assignment, localOnly)
+	if err != nil {
+		return timestamp.Zero(), 0.0, err
+	}
+
+	if dueDate == nil {
+		return timestamp.Zero(), 0.0, fmt.Errorf("Assignment does not have a due date.")
+	}
+
+	return *dueDate, maxPoints, nil
+}
+
+// Same as fetchDueDateAndMaxPoints(), but a missing due date is returned as nil instead of an error.
+// If localOnly is true, then the assignment's own config is always used.
+func fetchOptionalDueDateAndMaxPoints(assignment *model.Assignment, localOnly bool) (*timestamp.Timestamp, float64, error) {
+	if !localOnly && assignment.GetCourse().HasLMSAdapter() && (assignment.GetLMSID() != "") {
+		lmsAssignment, err := lms.FetchAssignment(assignment.GetCourse(), assignment.GetLMSID())
+		if err != nil {
+			return nil, 0.0, err
+		}
+
+		return lmsAssignment.DueDate, lmsAssignment.MaxPoints, nil
+	}
+
+	return assignment.DueDate, assignment.MaxPoints, nil
+}
+
+// Get the effective due date (including any extension and grace period) for each scored user.
+func computeUserDueDates(assignment *model.Assignment, scores map[string]*model.ScoringInfo, rawDueDate timestamp.Timestamp) map[string]timestamp.Timestamp {
+	dueDates := make(map[string]timestamp.Timestamp, len(scores))
+
+	for email, _ := range scores {
+		dueDates[email] = *assignment.ResolveEffectiveUserDueDate(&rawDueDate, email)
+	}
+
+	return dueDates
+}
+
 // Apply a common policy.
-func applyBaselinePolicy(assignment *model.Assignment, policy model.LateGradingPolicy, users map[string]*model.CourseUser, scores map[string]*model.ScoringInfo, dueDate timestamp.Timestamp) {
+func applyBaselinePolicy(assignment *model.Assignment, policy model.LateGradingPolicy, users map[string]*model.CourseUser, scores map[string]*model.ScoringInfo, dueDates map[string]timestamp.Timestamp) {
 	for email, score := range scores {
-		score.NumDaysLate = computeLateDays(dueDate, score.SubmissionTime)
+		score.NumDaysLate = computeLateDays(dueDates[email], score.SubmissionTime)
 
 		_, ok := users[email]
 		if !ok {
@@ -116,11 +186,145 @@ func applyConstantPolicy(policy model.LateGradingPolicy, scores map[string]*mode
 	}
 }
 
+// Multiply the score by (1 - penalty) for each late day.
+func applyExponentialPolicy(policy model.LateGradingPolicy, scores map[string]*model.ScoringInfo) {
+	for _, score := range scores {
+		if score.NumDaysLate <= 0 {
+			continue
+		}
+
+		score.Score = math.Max(0.0, score.RawScore*math.Pow(1.0-policy.Penalty, float64(score.NumDaysLate)))
+	}
+}
+
+// Apply a constant penalty per late hour.
+func applyHourlyPolicy(scores map[string]*model.ScoringInfo, dueDates map[string]timestamp.Timestamp, penalty float64) {
+	for email, score := range scores {
+		if score.NumDaysLate <= 0 {
+			continue
+		}
+
+		numHoursLate := computeLateHours(dueDates[email], score.SubmissionTime)
+		score.Score = math.Max(0.0, score.RawScore-(penalty*float64(numHoursLate)))
+	}
+}
+
+// Use the better of the penalized late score and the best on-time score.
+// If the on-time score is better, then the on-time submission will be used instead of the late one.
+// A submission that the student explicitly selected as their final submission is never replaced (only penalized).
+func applyBestOfOnTimePolicy(
+	assignment *model.Assignment, users map[string]*model.CourseUser,
+	scores map[string]*model.ScoringInfo, dueDates map[string]timestamp.Timestamp, penalty float64) error {
+	lateEmails := make([]string, 0)
+	for email, score := range scores {
+		// Unknown users were already rejected in the baseline policy.
+		_, ok := users[email]
+		if !ok || (score.NumDaysLate <= 0) {
+			continue
+		}
+
+		lateEmails = append(lateEmails, email)
+	}
+
+	if len(lateEmails) == 0 {
+		return nil
+	}
+
+	finalSelections, err := fetchExplicitFinalSelections(assignment)
+	if err != nil {
+		return err
+	}
+
+	histories, err := db.GetSubmissionHistories(assignment, lateEmails)
+	if err != nil {
+		return err
+	}
+
+	for _, email := range lateEmails {
+		score := scores[email]
+
+		if !score.Reject {
+			score.Score = math.Max(0.0, score.RawScore-(penalty*float64(score.NumDaysLate)))
+		}
+
+		finalSelection := finalSelections[email]
+		if (finalSelection != "") && (finalSelection == common.GetShortSubmissionID(score.ID)) {
+			continue
+		}
+
+		onTime := selectBestOnTimeSubmission(histories[email], dueDates[email])
+		if onTime == nil {
+			continue
+		}
+
+		// A rejected (too late) submission can still fall back to an on-time submission.
+		if !score.Reject && (score.Score >= onTime.Score) {
+			continue
+		}
+
+		score.ID = onTime.ID
+		score.SubmissionTime = onTime.GradingStartTime
+		score.RawScore = onTime.Score
+		score.Score = onTime.Score
+		score.NumDaysLate = 0
+		score.Reject = false
+	}
+
+	return nil
+}
+
+// Get the final submissions (short IDs keyed by email) that students explicitly selected.
+// Selections only apply when the assignment uses student-selected submissions.
+func fetchExplicitFinalSelections(assignment *model.Assignment) (map[string]string, error) {
+	if assignment.GetSubmissionSelection() != model.SubmissionSelectionStudentSelected {
+		return map[string]string{}, nil
+	}
+
+	finalSelections, err := db.GetFinalSubmissionSelections(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get final submission selections: '%w'.", err)
+	}
+
+	return finalSelections, nil
+}
+
+// Get the highest scoring submission that was made on time (ties go to the most recent submission).
+// Returns nil if there is no on-time submission.
+func selectBestOnTimeSubmission(history []*model.SubmissionHistoryItem, dueDate timestamp.Timestamp) *model.SubmissionHistoryItem {
+	var best *model.SubmissionHistoryItem = nil
+	for _, item := range history {
+		if item.GradingStartTime > dueDate {
+			continue
+		}
+
+		if (best == nil) || (item.Score > best.Score) || ((item.Score == best.Score) && (item.GradingStartTime > best.GradingStartTime)) {
+			best = item
+		}
+	}
+
+	return best
+}
+
 func applyLateDaysPolicy(
 	policy model.LateGradingPolicy,
 	assignment *model.Assignment, users map[string]*model.CourseUser,
 	scores map[string]*model.ScoringInfo, penalty float64,
-	dryRun bool) error {
+	dryRun bool, localOnly bool) error {
+	if policy.UsesLateDayLedger() {
+		return applyLedgerLateDaysPolicy(policy, assignment, scores, penalty, dryRun)
+	}
+
+	// Late days in the LMS are not available locally, so assume users have enough of them.
+	if localOnly {
+		for _, scoringInfo := range scores {
+			if !scoringInfo.Reject {
+				useLateDays(policy, scoringInfo, policy.MaxLateDays, penalty)
+			}
+		}
+
+		return nil
+	}
+
 	allLateDays, err := fetchLateDays(policy, assignment)
 	if err != nil {
 		return err
@@ -166,16 +370,7 @@ func applyLateDaysPolicy(
 			continue
 		}
 
-		// We will use late days limited by:
-		// - The number of late days the user has to use.
-		// - The maximum number of late days that can be used on this assignment.
-		// - The number of days late the submission actually is.
-		lateDaysToUse := min(lateDaysAvailable, policy.MaxLateDays, scoringInfo.NumDaysLate)
-		scoringInfo.LateDayUsage = lateDaysToUse
-
-		// Enforce a penalty for any remaining late days.
-		remainingDaysLate := scoringInfo.NumDaysLate - lateDaysToUse
-		scoringInfo.Score = math.Max(0.0, scoringInfo.RawScore-(penalty*float64(remainingDaysLate)))
+		lateDaysToUse := useLateDays(policy, scoringInfo, lateDaysAvailable, penalty)
 
 		// Check if the number of allocated late days has changed.
 		// If so, we need to update the late days in the LMS.
@@ -196,6 +391,95 @@ func applyLateDaysPolicy(
 	return nil
 }
 
+// Same as applyLateDaysPolicy(), but late days are tracked in the autograder's late day ledger.
+// The ledger is read and the new entries are added as one database operation,
+// so concurrent scoring (or manual adjustments) cannot spend the same late days twice.
+func applyLedgerLateDaysPolicy(
+	policy model.LateGradingPolicy, assignment *model.Assignment,
+	scores map[string]*model.ScoringInfo, penalty float64,
+	dryRun bool) error {
+	course := assignment.GetCourse()
+	now := timestamp.Now()
+
+	computeEntries := func(allEntries map[string][]*model.LateDayEntry) ([]*model.LateDayEntry, error) {
+		newEntries := make([]*model.LateDayEntry, 0)
+
+		for email, scoringInfo := range scores {
+			entries := allEntries[email]
+			allocatedDays := model.ComputeAllocatedLateDays(entries, assignment.GetID())
+
+			// Assignment is not late and there are no late days to refund, skip.
+			if (scoringInfo.NumDaysLate <= 0) && (allocatedDays == 0) {
+				continue
+			}
+
+			// Rejected scores do not use any late days (so any that were already used are refunded).
+			lateDaysToUse := 0
+			if !scoringInfo.Reject {
+				// Reclaim any late days that have already been used on this assignment.
+				lateDaysAvailable := model.ComputeLateDayBalance(course.GetTotalLateDays(), entries) + allocatedDays
+				lateDaysToUse = useLateDays(policy, scoringInfo, lateDaysAvailable, penalty)
+			}
+
+			if allocatedDays == lateDaysToUse {
+				continue
+			}
+
+			entry := &model.LateDayEntry{
+				Email:        email,
+				Type:         model.LateDayEntryCharge,
+				Days:         allocatedDays - lateDaysToUse,
+				AssignmentID: assignment.GetID(),
+				Timestamp:    now,
+				Comment:      fmt.Sprintf("Submission '%s'.", scoringInfo.ID),
+			}
+
+			if entry.Days > 0 {
+				entry.Type = model.LateDayEntryRefund
+			}
+
+			newEntries = append(newEntries, entry)
+		}
+
+		return newEntries, nil
+	}
+
+	if dryRun {
+		allEntries, err := db.GetLateDayEntries(course)
+		if err != nil {
+			return fmt.Errorf("Failed to get late day ledger: '%w'.", err)
+		}
+
+		newEntries, _ := computeEntries(allEntries)
+		log.Debug("Dry Run: Skipping update of late day ledger.", assignment, log.NewAttr("entries", newEntries))
+		return nil
+	}
+
+	err := db.UpdateLateDayEntries(course, computeEntries)
+	if err != nil {
+		return fmt.Errorf("Failed to update late day ledger: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Use late days on a score (penalizing any late days that could not be covered).
+// Returns the number of late days used.
+func useLateDays(policy model.LateGradingPolicy, scoringInfo *model.ScoringInfo, lateDaysAvailable int, penalty float64) int {
+	// We will use late days limited by:
+	// - The number of late days the user has to use.
+	// - The maximum number of late days that can be used on this assignment.
+	// - The number of days late the submission actually is.
+	lateDaysToUse := max(0, min(lateDaysAvailable, policy.MaxLateDays, scoringInfo.NumDaysLate))
+	scoringInfo.LateDayUsage = lateDaysToUse
+
+	// Enforce a penalty for any remaining late days.
+	remainingDaysLate := scoringInfo.NumDaysLate - lateDaysToUse
+	scoringInfo.Score = math.Max(0.0, scoringInfo.RawScore-(penalty*float64(remainingDaysLate)))
+
+	return lateDaysToUse
+}
+
 func updateLateDays(policy model.LateGradingPolicy, assignment *model.Assignment, lateDaysToUpdate map[string]*LateDaysInfo, dryRun bool) error {
 	// Update late days.
 	// Info that does NOT have a LMSCommentID will get the autograder comment added in.
@@ -324,3 +608,14 @@ func computeLateDays(dueDate timestamp.Timestamp, submissionTime timestamp.Times
 	// Convert delta (msecs) to seconds -> minutes -> hours -> days.
 	return int(math.Ceil(float64(delta) / 1000.0 / 60.0 / 60.0 / 24.0))
 }
+
+func computeLateHours(dueDate timestamp.Timestamp, submissionTime timestamp.Timestamp) int {
+	if dueDate >= submissionTime {
+		return 0
+	}
+
+	delta := submissionTime.ToMSecs() - dueDate.ToMSecs()
+
+	// Convert delta (msecs) to seconds -> minutes -> hours.
+	return int(math.Ceil(float64(delta) / 1000.0 / 60.0 / 60.0))
+}
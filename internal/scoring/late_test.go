@@ -1,10 +1,17 @@
 package scoring
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -63,3 +70,462 @@ func TestComputeLateDays(test *testing.T) {
 		}
 	}
 }
+
+func TestComputeLateHours(test *testing.T) {
+	var hourMSecs int64 = 60 * 60 * 1000
+
+	testCases := []struct {
+		dueDate        timestamp.Timestamp
+		submissionTime timestamp.Timestamp
+		expected       int
+	}{
+		{timestamp.Timestamp(0), timestamp.Timestamp(0), 0},
+		{timestamp.Timestamp(0), timestamp.Timestamp(-1), 0},
+
+		{timestamp.Timestamp(0), timestamp.Timestamp(1), 1},
+		{timestamp.Timestamp(0), timestamp.Timestamp(hourMSecs), 1},
+		{timestamp.Timestamp(0), timestamp.Timestamp(hourMSecs + 1), 2},
+		{timestamp.Timestamp(hourMSecs), timestamp.Timestamp(25 * hourMSecs), 24},
+	}
+
+	for i, testCase := range testCases {
+		actual := computeLateHours(testCase.dueDate, testCase.submissionTime)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Bad late hours. Expected: %d, Actual: %d.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestApplyExponentialPolicy(test *testing.T) {
+	policy := model.LateGradingPolicy{
+		Type:    model.ExponentialPenalty,
+		Penalty: 0.5,
+	}
+
+	testCases := []struct {
+		rawScore    float64
+		numDaysLate int
+		expected    float64
+	}{
+		{100, 0, 100},
+		{100, -1, 100},
+		{100, 1, 50},
+		{100, 2, 25},
+		{80, 3, 10},
+		{0, 3, 0},
+	}
+
+	for i, testCase := range testCases {
+		scores := map[string]*model.ScoringInfo{
+			"alice": &model.ScoringInfo{RawScore: testCase.rawScore, Score: testCase.rawScore, NumDaysLate: testCase.numDaysLate},
+		}
+
+		applyExponentialPolicy(policy, scores)
+
+		if !util.IsClose(testCase.expected, scores["alice"].Score) {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expected, scores["alice"].Score)
+		}
+	}
+}
+
+func TestApplyHourlyPolicy(test *testing.T) {
+	var hourMSecs int64 = 60 * 60 * 1000
+	dueDate := timestamp.FromMSecs(10 * hourMSecs)
+
+	testCases := []struct {
+		rawScore       float64
+		submissionTime timestamp.Timestamp
+		expected       float64
+	}{
+		{100, timestamp.FromMSecs(9 * hourMSecs), 100},
+		{100, dueDate, 100},
+		{100, timestamp.FromMSecs(10*hourMSecs + 1), 98},
+		{100, timestamp.FromMSecs(13 * hourMSecs), 94},
+		{100, timestamp.FromMSecs(70 * hourMSecs), 0},
+	}
+
+	for i, testCase := range testCases {
+		scores := map[string]*model.ScoringInfo{
+			"alice": &model.ScoringInfo{
+				RawScore:       testCase.rawScore,
+				Score:          testCase.rawScore,
+				SubmissionTime: testCase.submissionTime,
+				NumDaysLate:    computeLateDays(dueDate, testCase.submissionTime),
+			},
+		}
+
+		applyHourlyPolicy(scores, map[string]timestamp.Timestamp{"alice": dueDate}, 2.0)
+
+		if !util.IsClose(testCase.expected, scores["alice"].Score) {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expected, scores["alice"].Score)
+		}
+	}
+}
+
+func TestApplyBestOfOnTimePolicy(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+	lateID := "course101::hw0::course-student@test.edulinq.org::1697406272"
+	onTimeID := "course101::hw0::course-student@test.edulinq.org::1697406265"
+
+	// Between the second (score: 1) and third (score: 2) submissions.
+	dueDate := timestamp.FromMSecs(1697406270000)
+	lateTime := timestamp.FromMSecs(1697406273000)
+	onTimeTime := timestamp.FromMSecs(1697406266000)
+
+	testCases := []struct {
+		penalty        float64
+		reject         bool
+		hasUser        bool
+		finalSelection string
+		expected       *model.ScoringInfo
+	}{
+		// The penalized late score is better.
+		{
+			0.25,
+			false,
+			true,
+			"",
+			&model.ScoringInfo{ID: lateID, SubmissionTime: lateTime, RawScore: 2, Score: 1.75, NumDaysLate: 1},
+		},
+
+		// The on-time score is better.
+		{
+			1.5,
+			false,
+			true,
+			"",
+			&model.ScoringInfo{ID: onTimeID, SubmissionTime: onTimeTime, RawScore: 1, Score: 1, NumDaysLate: 0},
+		},
+
+		// A tie goes to the late submission.
+		{
+			1.0,
+			false,
+			true,
+			"",
+			&model.ScoringInfo{ID: lateID, SubmissionTime: lateTime, RawScore: 2, Score: 1, NumDaysLate: 1},
+		},
+
+		// The late submission was rejected, fall back to the on-time one.
+		{
+			0.25,
+			true,
+			true,
+			"",
+			&model.ScoringInfo{ID: onTimeID, SubmissionTime: onTimeTime, RawScore: 1, Score: 1, NumDaysLate: 0},
+		},
+
+		// Unknown users are left alone.
+		{
+			1.5,
+			true,
+			false,
+			"",
+			&model.ScoringInfo{ID: lateID, SubmissionTime: lateTime, RawScore: 2, Score: 2, NumDaysLate: 1, Reject: true},
+		},
+
+		// The student explicitly selected the late submission, only penalize it.
+		{
+			1.5,
+			false,
+			true,
+			lateID,
+			&model.ScoringInfo{ID: lateID, SubmissionTime: lateTime, RawScore: 2, Score: 0.5, NumDaysLate: 1},
+		},
+
+		// The student selected a different submission, the on-time score is still better.
+		{
+			1.5,
+			false,
+			true,
+			"1697406256",
+			&model.ScoringInfo{ID: onTimeID, SubmissionTime: onTimeTime, RawScore: 1, Score: 1, NumDaysLate: 0},
+		},
+	}
+
+	assignment := db.MustGetTestAssignment()
+
+	for i, testCase := range testCases {
+		assignment.SubmissionSelection = ""
+		if testCase.finalSelection != "" {
+			assignment.SubmissionSelection = model.SubmissionSelectionStudentSelected
+		}
+
+		err := db.SetFinalSubmissionSelection(assignment, email, testCase.finalSelection)
+		if err != nil {
+			test.Errorf("Case %d: Failed to set final submission selection: '%v'.", i, err)
+			continue
+		}
+
+		users := map[string]*model.CourseUser{}
+		if testCase.hasUser {
+			users[email] = &model.CourseUser{Email: email, Role: model.CourseRoleStudent}
+		}
+
+		scores := map[string]*model.ScoringInfo{
+			email: &model.ScoringInfo{
+				ID:             lateID,
+				SubmissionTime: lateTime,
+				RawScore:       2,
+				Score:          2,
+				NumDaysLate:    1,
+				Reject:         testCase.reject,
+			},
+		}
+
+		err = applyBestOfOnTimePolicy(assignment, users, scores, map[string]timestamp.Timestamp{email: dueDate}, testCase.penalty)
+		if err != nil {
+			test.Errorf("Case %d: Failed to apply policy: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, scores[email]) {
+			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(scores[email]))
+		}
+	}
+}
+
+// A user with an extension is scored against their own due date.
+func TestApplyLatePolicyExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	var dayMSecs int64 = 24 * 60 * 60 * 1000
+
+	studentEmail := "course-student@test.edulinq.org"
+	otherEmail := "course-other@test.edulinq.org"
+
+	dueDate := timestamp.FromMSecs(1697406270000)
+	extension := dueDate + timestamp.FromMSecs(2*dayMSecs)
+
+	// One day after the due date, but before the extension.
+	submissionTime := dueDate + timestamp.FromMSecs(dayMSecs)
+
+	testCases := []struct {
+		policy        model.LateGradingPolicy
+		expectedScore float64
+	}{
+		{model.LateGradingPolicy{Type: model.BaselinePolicy}, 10},
+		{model.LateGradingPolicy{Type: model.ConstantPenalty, Penalty: 1}, 9},
+		{model.LateGradingPolicy{Type: model.PercentagePenalty, Penalty: 0.2}, 8},
+		{model.LateGradingPolicy{Type: model.ExponentialPenalty, Penalty: 0.5}, 5},
+		{model.LateGradingPolicy{Type: model.HourlyPenalty, Penalty: 0.01}, 7.6},
+		{model.LateGradingPolicy{Type: model.BestOfOnTime, Penalty: 0.3}, 7},
+	}
+
+	users := map[string]*model.CourseUser{
+		studentEmail: &model.CourseUser{Email: studentEmail, Role: model.CourseRoleStudent},
+		otherEmail:   &model.CourseUser{Email: otherEmail, Role: model.CourseRoleStudent},
+	}
+
+	for i, testCase := range testCases {
+		assignment := db.MustGetTestAssignment()
+		assignment.DueDate = &dueDate
+		assignment.MaxPoints = 10
+		assignment.LatePolicy = &testCase.policy
+		assignment.Extensions = map[string]timestamp.Timestamp{
+			studentEmail: extension,
+		}
+
+		scores := make(map[string]*model.ScoringInfo)
+		for email, _ := range users {
+			scores[email] = &model.ScoringInfo{
+				ID:             fmt.Sprintf("course101::hw0::%s::1697406272", email),
+				SubmissionTime: submissionTime,
+				RawScore:       10,
+			}
+		}
+
+		err := ApplyLatePolicy(assignment, users, scores, true)
+		if err != nil {
+			test.Errorf("Case %d: Failed to apply late policy: '%v'.", i, err)
+			continue
+		}
+
+		if scores[studentEmail].NumDaysLate != 0 {
+			test.Errorf("Case %d: Submission with an extension is late. Days late: %d.", i, scores[studentEmail].NumDaysLate)
+		}
+
+		if !util.IsClose(10, scores[studentEmail].Score) {
+			test.Errorf("Case %d: Submission with an extension was penalized. Score: %f.", i, scores[studentEmail].Score)
+		}
+
+		if scores[otherEmail].NumDaysLate != 1 {
+			test.Errorf("Case %d: Unexpected days late without an extension. Expected: 1, Actual: %d.", i, scores[otherEmail].NumDaysLate)
+		}
+
+		if !util.IsClose(testCase.expectedScore, scores[otherEmail].Score) {
+			test.Errorf("Case %d: Unexpected score without an extension. Expected: %f, Actual: %f.",
+				i, testCase.expectedScore, scores[otherEmail].Score)
+		}
+	}
+}
+
+// Local penalties only use the assignment's own due date and max points,
+// and assume users have enough late days when late days are tracked in the LMS.
+func TestApplyLocalPenalties(test *testing.T) {
+	db.ResetForTesting()
+	lmstest.ClearAssignments()
+	defer db.ResetForTesting()
+	defer lmstest.ClearAssignments()
+
+	var dayMSecs int64 = 24 * 60 * 60 * 1000
+
+	dueDate := timestamp.FromMSecs(1697406270000)
+
+	// No submissions are late according to the LMS.
+	lmsDueDate := dueDate + timestamp.FromMSecs(10*dayMSecs)
+
+	assignment := db.MustGetTestAssignment()
+	assignment.DueDate = &dueDate
+	assignment.MaxPoints = 10
+	assignment.LatePolicy = &model.LateGradingPolicy{
+		Type:          model.LateDays,
+		Penalty:       0.1,
+		MaxLateDays:   2,
+		LateDaysLMSID: "late-days",
+	}
+
+	lmsAssignment, err := lms.CreateAssignment(assignment.GetCourse(), &lmstypes.Assignment{Name: assignment.GetName(), DueDate: &lmsDueDate, MaxPoints: 100})
+	if err != nil {
+		test.Fatalf("Failed to create LMS assignment: '%v'.", err)
+	}
+
+	assignment.LMSID = lmsAssignment.ID
+
+	testCases := []struct {
+		numDaysLate   int
+		expectedScore float64
+		expectedUsage int
+	}{
+		{0, 10, 0},
+		{1, 10, 1},
+		{2, 10, 2},
+		{3, 9, 2},
+	}
+
+	for i, testCase := range testCases {
+		email := "course-student@test.edulinq.org"
+		users := map[string]*model.CourseUser{
+			email: &model.CourseUser{Email: email, Role: model.CourseRoleStudent},
+		}
+
+		scores := map[string]*model.ScoringInfo{
+			email: &model.ScoringInfo{
+				ID:             "course101::hw0::course-student@test.edulinq.org::1697406272",
+				SubmissionTime: dueDate + timestamp.FromMSecs(int64(testCase.numDaysLate)*dayMSecs),
+				RawScore:       10,
+			},
+		}
+
+		err := ApplyLocalPenalties(assignment, users, scores)
+		if err != nil {
+			test.Errorf("Case %d: Failed to apply local penalties: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.numDaysLate != scores[email].NumDaysLate {
+			test.Errorf("Case %d: Unexpected days late. Expected: %d, Actual: %d.", i, testCase.numDaysLate, scores[email].NumDaysLate)
+		}
+
+		if !util.IsClose(testCase.expectedScore, scores[email].Score) {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expectedScore, scores[email].Score)
+		}
+
+		if testCase.expectedUsage != scores[email].LateDayUsage {
+			test.Errorf("Case %d: Unexpected late day usage. Expected: %d, Actual: %d.", i, testCase.expectedUsage, scores[email].LateDayUsage)
+		}
+	}
+}
+
+func TestApplyLedgerLateDaysPolicy(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+	assignment := db.MustGetTestAssignment()
+
+	policy := model.LateGradingPolicy{
+		Type:          model.LateDays,
+		Penalty:       0.1,
+		MaxLateDays:   2,
+		TotalLateDays: 3,
+	}
+
+	// The total late days always come from the course.
+	assignment.GetCourse().LatePolicy = &policy
+
+	// Each case builds on the ledger from the previous cases.
+	testCases := []struct {
+		numDaysLate     int
+		dryRun          bool
+		reject          bool
+		expectedScore   float64
+		expectedUsage   int
+		expectedEntries int
+		expectedBalance int
+	}{
+		// Use a late day.
+		{1, false, false, 100, 1, 1, 2},
+
+		// Rescoring the same submission does not change anything.
+		{1, false, false, 100, 1, 1, 2},
+
+		// More late, but limited by the max late days.
+		{3, false, false, 90, 2, 2, 1},
+
+		// No longer late, refund the late days.
+		{0, false, false, 100, 0, 3, 3},
+
+		// Dry runs do not change the ledger.
+		{2, true, false, 100, 2, 3, 3},
+
+		// Use late days again.
+		{2, false, false, 100, 2, 4, 1},
+
+		// The late submission is now rejected, refund the late days (the score is left alone).
+		{2, false, true, 0, 0, 5, 3},
+	}
+
+	for i, testCase := range testCases {
+		scores := map[string]*model.ScoringInfo{
+			email: &model.ScoringInfo{
+				ID:          "course101::hw0::course-student@test.edulinq.org::1697406272",
+				RawScore:    100,
+				NumDaysLate: testCase.numDaysLate,
+				Reject:      testCase.reject,
+			},
+		}
+
+		err := applyLateDaysPolicy(policy, assignment, nil, scores, 10, testCase.dryRun, false)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to apply policy: '%v'.", i, err)
+		}
+
+		if !util.IsClose(testCase.expectedScore, scores[email].Score) {
+			test.Errorf("Case %d: Unexpected score. Expected: %f, Actual: %f.", i, testCase.expectedScore, scores[email].Score)
+		}
+
+		if testCase.expectedUsage != scores[email].LateDayUsage {
+			test.Errorf("Case %d: Unexpected late day usage. Expected: %d, Actual: %d.", i, testCase.expectedUsage, scores[email].LateDayUsage)
+		}
+
+		entries, err := db.GetUserLateDayEntries(assignment.GetCourse(), email)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to get late day entries: '%v'.", i, err)
+		}
+
+		if testCase.expectedEntries != len(entries) {
+			test.Errorf("Case %d: Unexpected number of entries. Expected: %d, Actual: %d.", i, testCase.expectedEntries, len(entries))
+		}
+
+		balance := model.ComputeLateDayBalance(assignment.GetCourse().GetTotalLateDays(), entries)
+		if testCase.expectedBalance != balance {
+			test.Errorf("Case %d: Unexpected balance. Expected: %d, Actual: %d.", i, testCase.expectedBalance, balance)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Get the submission result for each of the given users, as chosen by the assignment's submission selection policy.
+// When selecting the highest score before the due date, the same due date as the late policy is used (which may come from the LMS).
+// Users without a submission will be represented with a nil map value.
+func GetSelectedSubmissions(assignment *model.Assignment, emails []string) (map[string]*model.GradingInfo, error) {
+	return getSelectedSubmissions(assignment, emails, false)
+}
+
+// Same as GetSelectedSubmissions(), but the due date always comes from the assignment's own config (the LMS is never contacted).
+func GetLocalSelectedSubmissions(assignment *model.Assignment, emails []string) (map[string]*model.GradingInfo, error) {
+	return getSelectedSubmissions(assignment, emails, true)
+}
+
+func getSelectedSubmissions(assignment *model.Assignment, emails []string, localOnly bool) (map[string]*model.GradingInfo, error) {
+	dueDate, err := fetchSelectionDueDate(assignment, localOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetUsersSelectedSubmissions(assignment, emails, dueDate)
+}
+
+// Get the scoring infos for the selected submission (see GetSelectedSubmissions()) of each user of the given role that has a submission.
+func GetSelectedScoringInfos(assignment *model.Assignment, filterRole model.CourseUserRole) (map[string]*model.ScoringInfo, error) {
+	if assignment.GetSubmissionSelection() != model.SubmissionSelectionHighestScoreBeforeDue {
+		return db.GetExistingScoringInfos(assignment, filterRole)
+	}
+
+	dueDate, err := fetchSelectionDueDate(assignment, false)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := db.GetSelectedSubmissionsWithDueDate(assignment, filterRole, dueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	scoringInfos := make(map[string]*model.ScoringInfo, len(submissions))
+	for email, submission := range submissions {
+		if submission != nil {
+			scoringInfos[email] = submission.ToScoringInfo()
+		}
+	}
+
+	return scoringInfos, nil
+}
+
+// Get the due date used for submission selection.
+// The due date is only fetched (possibly from the LMS) if the selection policy needs it.
+func fetchSelectionDueDate(assignment *model.Assignment, localOnly bool) (*timestamp.Timestamp, error) {
+	if assignment.GetSubmissionSelection() != model.SubmissionSelectionHighestScoreBeforeDue {
+		return assignment.DueDate, nil
+	}
+
+	dueDate, _, err := fetchOptionalDueDateAndMaxPoints(assignment, localOnly)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch due date: '%w'.", err)
+	}
+
+	return dueDate, nil
+}
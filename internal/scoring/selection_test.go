@@ -0,0 +1,82 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lms"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Selecting the highest score before the due date uses the same (LMS) due date as the late policy.
+func TestGetSelectedSubmissionsLMSDueDate(test *testing.T) {
+	db.ResetForTesting()
+	lmstest.ClearAssignments()
+	defer db.ResetForTesting()
+	defer lmstest.ClearAssignments()
+
+	email := "course-student@test.edulinq.org"
+
+	// Between the second (score: 1) and third (score: 2) submissions.
+	lmsDueDate := timestamp.FromMSecs(1697406270000)
+
+	// Before all the submissions.
+	localDueDate := timestamp.Zero()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.SubmissionSelection = model.SubmissionSelectionHighestScoreBeforeDue
+	assignment.DueDate = &localDueDate
+
+	lmsAssignment, err := lms.CreateAssignment(assignment.GetCourse(), &lmstypes.Assignment{Name: assignment.GetName(), DueDate: &lmsDueDate})
+	if err != nil {
+		test.Fatalf("Failed to create LMS assignment: '%v'.", err)
+	}
+
+	assignment.LMSID = lmsAssignment.ID
+
+	testCases := []struct {
+		extension       *timestamp.Timestamp
+		expectedShortID string
+	}{
+		{nil, "1697406265"},
+
+		// The user's extension replaces the LMS due date.
+		{timestamp.ZeroPointer(), "1697406272"},
+	}
+
+	for i, testCase := range testCases {
+		assignment.Extensions = nil
+		if testCase.extension != nil {
+			assignment.Extensions = map[string]timestamp.Timestamp{email: *testCase.extension}
+		}
+
+		submissions, err := GetSelectedSubmissions(assignment, []string{email})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get selected submissions: '%v'.", i, err)
+			continue
+		}
+
+		if submissions[email] == nil {
+			test.Errorf("Case %d: Missing selected submission.", i)
+			continue
+		}
+
+		if submissions[email].ShortID != testCase.expectedShortID {
+			test.Errorf("Case %d: Unexpected submission. Expected: '%s', Actual: '%s'.", i, testCase.expectedShortID, submissions[email].ShortID)
+			continue
+		}
+
+		scoringInfos, err := GetSelectedScoringInfos(assignment, model.CourseRoleStudent)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get selected scoring infos: '%v'.", i, err)
+			continue
+		}
+
+		if scoringInfos[email].ID != submissions[email].ID {
+			test.Errorf("Case %d: Unexpected scoring info. Expected: '%s', Actual: '%s'.", i, submissions[email].ID, scoringInfos[email].ID)
+		}
+	}
+}
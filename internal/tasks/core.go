@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -15,8 +16,15 @@ import (
 const MIN_WAIT_MSECS = 1000
 
 var (
+	// Protects the state of the engine (e.g., enableTaskEngine), and is never held while a task runs.
 	lock             sync.Mutex
 	enableTaskEngine bool = false
+
+	// Held while running a task, so only one task will be run at a time.
+	runLock sync.Mutex
+
+	// Manual runs (see RunTaskNow()) that have not yet completed.
+	manualRuns sync.WaitGroup
 )
 
 func Start() {
@@ -31,16 +39,29 @@ func Start() {
 // Once this returns, no more tasks will be run (and none will be in-progress).
 // No tasks will be interrupted, so this call may block until any in-progress tasks stop.
 func Stop() {
+	lock.Lock()
+	enableTaskEngine = false
+	lock.Unlock()
+
+	// Wait for any in-progress task.
+	runLock.Lock()
+	runLock.Unlock()
+
+	// Pending manual runs will see that the engine is stopped and exit without running.
+	manualRuns.Wait()
+}
+
+func isEnabled() bool {
 	lock.Lock()
 	defer lock.Unlock()
 
-	enableTaskEngine = false
+	return enableTaskEngine
 }
 
 // Run tasks until Stop() is called.
 // Even when Stop is called, no tasks will be interrupted.
 func runTasks() {
-	for enableTaskEngine {
+	for isEnabled() {
 		runNextTask()
 
 		task, err := db.GetNextActiveTask()
@@ -60,10 +81,10 @@ func runTasks() {
 }
 
 func runNextTask() {
-	lock.Lock()
-	defer lock.Unlock()
+	runLock.Lock()
+	defer runLock.Unlock()
 
-	if !enableTaskEngine {
+	if !isEnabled() {
 		return
 	}
 
@@ -81,19 +102,18 @@ func runNextTask() {
 		return
 	}
 
-	startTimestamp := timestamp.Now()
-	if task.NextRunTime > startTimestamp {
+	if task.NextRunTime > timestamp.Now() {
 		return
 	}
 
 	log.Debug("Task started.", task)
-	runTask(task)
+	run := runTask(task, "")
 	log.Debug("Task finished.", task)
 
 	metric := stats.Metric{
-		Timestamp: startTimestamp,
+		Timestamp: run.StartTime,
 		Type:      stats.MetricTypeTaskTime,
-		Value:     float64((timestamp.Now() - startTimestamp).ToMSecs()),
+		Value:     float64((run.EndTime - run.StartTime).ToMSecs()),
 		Attributes: map[stats.MetricAttribute]any{
 			stats.MetricAttributeTaskType: task.Type,
 			stats.MetricAttributeCourseID: task.CourseID,
@@ -106,20 +126,102 @@ func runNextTask() {
 
 	stats.AsyncStoreMetric(&metric)
 
-	task.AdvanceRunTimes()
-
-	err = db.UpsertActiveTask(task)
+	// Update the stored task, since it may have been changed (e.g., paused or removed) while it was running.
+	// A removed task will not be updated.
+	_, err = db.UpdateActiveTask(task.Hash, func(task *model.FullScheduledTask) error {
+		task.AdvanceRunTimes()
+		return nil
+	})
 	if err != nil {
-		log.Error("Failed to save task.", err)
+		log.Error("Failed to save task.", err, task)
 		return
 	}
 }
 
-func runTask(task *model.FullScheduledTask) {
+// Run a task right away (outside of its schedule), recording that the given user triggered the run.
+// The task's schedule is not changed.
+// The task is always run in the background, and (like scheduled tasks) only one task will be run at a time.
+// Tasks will not be run if the engine is stopped or tasks are disabled (including if the engine is stopped before the run starts).
+// If |wait| is true, then this will block until the run completes and return the run (without blocking other tasks or Stop()).
+// Otherwise, a nil run will be returned right away and the run can be found in the task's run history once it completes.
+func RunTaskNow(task *model.FullScheduledTask, triggeredBy string, wait bool) (*model.TaskRun, error) {
+	lock.Lock()
+
+	if !enableTaskEngine {
+		lock.Unlock()
+		return nil, fmt.Errorf("Task engine is not running.")
+	}
+
+	if config.NO_TASKS.Get() {
+		lock.Unlock()
+		return nil, fmt.Errorf("Tasks are disabled.")
+	}
+
+	// Add while holding the lock, so that Stop() will wait for this run.
+	manualRuns.Add(1)
+	lock.Unlock()
+
+	done := make(chan *model.TaskRun, 1)
+
+	go func() {
+		defer manualRuns.Done()
+		done <- runManualTask(task, triggeredBy)
+	}()
+
+	if !wait {
+		return nil, nil
+	}
+
+	run := <-done
+	if run == nil {
+		return nil, fmt.Errorf("Task engine was stopped before the task could run.")
+	}
+
+	return run, nil
+}
+
+// Wait for any in-progress task and then run the given task.
+// Returns nil if the task was not run.
+func runManualTask(task *model.FullScheduledTask, triggeredBy string) *model.TaskRun {
+	runLock.Lock()
+	defer runLock.Unlock()
+
+	// The engine may have been stopped while we were waiting.
+	if !isEnabled() || config.NO_TASKS.Get() {
+		return nil
+	}
+
+	log.Debug("Task manually started.", task, log.NewAttr("triggered-by", triggeredBy))
+	run := runTask(task, triggeredBy)
+	log.Debug("Task finished.", task)
+
+	return run
+}
+
+// Run a task and record the run.
+func runTask(task *model.FullScheduledTask, triggeredBy string) *model.TaskRun {
 	if task == nil {
-		return
+		return nil
+	}
+
+	run := model.NewTaskRun(task, triggeredBy)
+
+	output, err := runTaskType(task)
+	run.Finish(output, err)
+
+	if err != nil {
+		log.Error("Failed to run task.", task, err)
 	}
 
+	err = db.AddTaskRun(run)
+	if err != nil {
+		log.Error("Failed to save task run.", task, err)
+	}
+
+	return run
+}
+
+func runTaskType(task *model.FullScheduledTask) (output string, err error) {
 	defer func() {
 		value := recover()
 		if value == nil {
@@ -127,29 +229,30 @@ func runTask(task *model.FullScheduledTask) {
 		}
 
 		log.Error("Task paniced.", task, log.NewAttr("recover-value", value))
+		output = ""
+		err = fmt.Errorf("Task paniced: '%v'.", value)
 	}()
 
-	var err error = nil
-
 	switch task.Type {
+	case model.TaskTypeCourseAnalysis:
+		return RunCourseAnalysisTask(task)
 	case model.TaskTypeCourseBackup:
-		err = RunCourseBackupTask(task)
+		return RunCourseBackupTask(task)
 	case model.TaskTypeCourseEmailLogs:
-		err = RunCourseEmailLogsTask(task)
+		return RunCourseEmailLogsTask(task)
+	case model.TaskTypeCourseLMSSync:
+		return RunCourseLMSSyncTask(task)
+	case model.TaskTypeCourseReminder:
+		return RunCourseReminderTask(task)
 	case model.TaskTypeCourseReport:
-		err = RunCourseReportTask(task)
+		return RunCourseReportTask(task)
 	case model.TaskTypeCourseScoringUpload:
-		err = RunCourseScoringUploadTask(task)
+		return RunCourseScoringUploadTask(task)
 	case model.TaskTypeCourseUpdate:
-		err = RunCourseUpdateTask(task)
+		return RunCourseUpdateTask(task)
 	case model.TaskTypeTest:
-		err = RunTestTask(task)
+		return RunTestTask(task)
 	default:
-		log.Error("Unknown task type.", task)
-		return
-	}
-
-	if err != nil {
-		log.Error("Failed to run task.", task, err)
+		return "", fmt.Errorf("Unknown task type: '%s'.", task.Type)
 	}
 }
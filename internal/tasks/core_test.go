@@ -1,8 +1,11 @@
 package tasks
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
@@ -21,7 +24,149 @@ func TestTaskCoreRunOneTask(test *testing.T) {
 		enableTaskEngine = false
 	}()
 
-	task := &model.FullScheduledTask{
+	task := getCoreTestTask()
+	db.MustUpsertActiveTask(task)
+
+	if testTaskCalls != 0 {
+		test.Fatalf("Intial value for test task is wrong. Expected: %d, Actual: %d.", 0, testTaskCalls)
+	}
+
+	runNextTask()
+
+	if testTaskCalls != 1 {
+		test.Fatalf("Final value for test task is wrong. Expected: %d, Actual: %d.", 1, testTaskCalls)
+	}
+
+	runs, err := db.GetTaskRuns(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task runs: '%v'.", err)
+	}
+
+	if len(runs) != 1 {
+		test.Fatalf("Unexpected number of task runs. Expected: %d, Actual: %d.", 1, len(runs))
+	}
+
+	if !runs[0].Success || (runs[0].Output != "Ran test task.") || (runs[0].TriggeredBy != "") {
+		test.Fatalf("Unexpected task run: '%s'.", util.MustToJSONIndent(runs[0]))
+	}
+
+	newTask, err := db.GetActiveTask(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task: '%v'.", err)
+	}
+
+	if newTask.NextRunTime <= task.NextRunTime {
+		test.Fatalf("Task run times were not advanced.")
+	}
+}
+
+func TestTaskCoreRunOneTaskPaused(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	resetTestTaskCalls()
+	defer resetTestTaskCalls()
+
+	enableTaskEngine = true
+	defer func() {
+		enableTaskEngine = false
+	}()
+
+	task := getCoreTestTask()
+	task.Paused = true
+	db.MustUpsertActiveTask(task)
+
+	runNextTask()
+
+	if testTaskCalls != 0 {
+		test.Fatalf("Paused task was run.")
+	}
+
+	runs, err := db.GetTaskRuns(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task runs: '%v'.", err)
+	}
+
+	if len(runs) != 0 {
+		test.Fatalf("Found runs for a paused task: '%s'.", util.MustToJSONIndent(runs))
+	}
+}
+
+func TestTaskCoreRunTaskNow(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	resetTestTaskCalls()
+	defer resetTestTaskCalls()
+
+	// Manual runs ignore pausing.
+	task := getCoreTestTask()
+	task.Paused = true
+	db.MustUpsertActiveTask(task)
+
+	// Manual runs are not allowed when the engine is stopped.
+	_, err := RunTaskNow(task, "course-admin@test.edulinq.org", true)
+	if err == nil {
+		test.Fatalf("Did not get an error when running a task with the engine stopped.")
+	}
+
+	enableTaskEngine = true
+	defer func() {
+		enableTaskEngine = false
+	}()
+
+	// Manual runs are not allowed when tasks are disabled.
+	config.NO_TASKS.Set(true)
+	_, err = RunTaskNow(task, "course-admin@test.edulinq.org", true)
+	config.NO_TASKS.Set(false)
+
+	if err == nil {
+		test.Fatalf("Did not get an error when running a task with tasks disabled.")
+	}
+
+	if testTaskCalls != 0 {
+		test.Fatalf("Task was run when it should not have been.")
+	}
+
+	run, err := RunTaskNow(task, "course-admin@test.edulinq.org", true)
+	if err != nil {
+		test.Fatalf("Failed to run task: '%v'.", err)
+	}
+
+	if run == nil {
+		test.Fatalf("Did not get a task run.")
+	}
+
+	if testTaskCalls != 1 {
+		test.Fatalf("Task was not run. Expected: %d, Actual: %d.", 1, testTaskCalls)
+	}
+
+	if !run.Success || (run.TriggeredBy != "course-admin@test.edulinq.org") {
+		test.Fatalf("Unexpected task run: '%s'.", util.MustToJSONIndent(run))
+	}
+
+	runs, err := db.GetTaskRuns(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task runs: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual([]*model.TaskRun{run}, runs) {
+		test.Fatalf("Unexpected task runs. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent([]*model.TaskRun{run}), util.MustToJSONIndent(runs))
+	}
+
+	// The schedule should not change.
+	newTask, err := db.GetActiveTask(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task: '%v'.", err)
+	}
+
+	if newTask.NextRunTime != task.NextRunTime {
+		test.Fatalf("Manual run changed the schedule. Expected: %d, Actual: %d.", task.NextRunTime, newTask.NextRunTime)
+	}
+}
+
+func getCoreTestTask() *model.FullScheduledTask {
+	return &model.FullScheduledTask{
 		UserTaskInfo: model.UserTaskInfo{
 			Type: model.TaskTypeTest,
 			When: &util.ScheduledTime{
@@ -35,16 +180,160 @@ func TestTaskCoreRunOneTask(test *testing.T) {
 			Hash:        "ABC",
 		},
 	}
+}
+
+func TestTaskCoreStopWaitsForManualRuns(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	resetTestTaskCalls()
+	defer resetTestTaskCalls()
 
+	task := getCoreTestTask()
 	db.MustUpsertActiveTask(task)
 
-	if testTaskCalls != 0 {
-		test.Fatalf("Intial value for test task is wrong. Expected: %d, Actual: %d.", 0, testTaskCalls)
+	enableTaskEngine = true
+
+	_, err := RunTaskNow(task, "course-admin@test.edulinq.org", false)
+	if err != nil {
+		test.Fatalf("Failed to run task: '%v'.", err)
 	}
 
-	runNextTask()
+	Stop()
+
+	if testTaskCalls > 1 {
+		test.Fatalf("Task was run too many times: %d.", testTaskCalls)
+	}
+
+	// After Stop() returns, the background run must have either completed or been skipped.
+	runs, err := db.GetTaskRuns(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task runs: '%v'.", err)
+	}
+
+	if len(runs) != testTaskCalls {
+		test.Fatalf("Task runs do not match task calls. Runs: %d, Calls: %d.", len(runs), testTaskCalls)
+	}
+}
+
+func TestTaskCoreRunTaskNowInProgressTask(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	resetTestTaskCalls()
+	defer resetTestTaskCalls()
+
+	task := getCoreTestTask()
+	db.MustUpsertActiveTask(task)
+
+	// Block the first run until released.
+	started := make(chan bool, 1)
+	release := make(chan bool)
+	testTaskHook = func() {
+		testTaskHook = nil
+		started <- true
+		<-release
+	}
+	defer func() {
+		testTaskHook = nil
+	}()
+
+	enableTaskEngine = true
+
+	_, err := RunTaskNow(task, "course-admin@test.edulinq.org", false)
+	if err != nil {
+		test.Fatalf("Failed to run first task: '%v'.", err)
+	}
+
+	<-started
+
+	// Triggering a run while another task is in progress should not block.
+	_, err = RunTaskNow(task, "course-admin@test.edulinq.org", false)
+	if err != nil {
+		test.Fatalf("Failed to run second task: '%v'.", err)
+	}
+
+	waitResult := make(chan error, 1)
+	go func() {
+		_, err := RunTaskNow(task, "course-admin@test.edulinq.org", true)
+		waitResult <- err
+	}()
+
+	close(release)
+
+	err = <-waitResult
+	if err != nil {
+		test.Fatalf("Failed to run waited task: '%v'.", err)
+	}
+
+	Stop()
+
+	if testTaskCalls != 3 {
+		test.Fatalf("Unexpected number of task calls. Expected: %d, Actual: %d.", 3, testTaskCalls)
+	}
+}
+
+func TestTaskCoreStopSkipsQueuedManualRuns(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	resetTestTaskCalls()
+	defer resetTestTaskCalls()
+
+	task := getCoreTestTask()
+	db.MustUpsertActiveTask(task)
+
+	// Block the first run until released.
+	started := make(chan bool, 1)
+	release := make(chan bool)
+	testTaskHook = func() {
+		testTaskHook = nil
+		started <- true
+		<-release
+	}
+	defer func() {
+		testTaskHook = nil
+	}()
+
+	enableTaskEngine = true
+
+	_, err := RunTaskNow(task, "course-admin@test.edulinq.org", false)
+	if err != nil {
+		test.Fatalf("Failed to run first task: '%v'.", err)
+	}
+
+	<-started
+
+	// This run will be queued behind the first one.
+	_, err = RunTaskNow(task, "course-admin@test.edulinq.org", false)
+	if err != nil {
+		test.Fatalf("Failed to run second task: '%v'.", err)
+	}
+
+	stopped := make(chan bool)
+	go func() {
+		Stop()
+		close(stopped)
+	}()
+
+	// Only release the first run once the engine is marked as stopped.
+	for isEnabled() {
+		time.Sleep(time.Millisecond)
+	}
+
+	close(release)
+	<-stopped
 
 	if testTaskCalls != 1 {
-		test.Fatalf("Final value for test task is wrong. Expected: %d, Actual: %d.", 1, testTaskCalls)
+		test.Fatalf("Unexpected number of task calls. Expected: %d, Actual: %d.", 1, testTaskCalls)
+	}
+
+	runs, err := db.GetTaskRuns(task.Hash)
+	if err != nil {
+		test.Fatalf("Failed to get task runs: '%v'.", err)
+	}
+
+	if len(runs) != 1 {
+		test.Fatalf("Unexpected number of task runs. Expected: %d, Actual: %d.", 1, len(runs))
 	}
 }
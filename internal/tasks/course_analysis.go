@@ -0,0 +1,171 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/analysis"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func RunCourseAnalysisTask(task *model.FullScheduledTask) (string, error) {
+	course, err := db.GetCourse(task.CourseID)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+	}
+
+	if course == nil {
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+	}
+
+	to, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "to", []string{})
+	if err != nil {
+		return "", fmt.Errorf("Unable to get recipients: '%w'.", err)
+	}
+
+	assignmentIDs, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "assignments", []string{})
+	if err != nil {
+		return "", fmt.Errorf("Unable to get assignments: '%w'.", err)
+	}
+
+	topPairs, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "top-pairs", model.DEFAULT_ANALYSIS_TASK_TOP_COUNT)
+	if err != nil {
+		return "", fmt.Errorf("Unable to get top pairs: '%w'.", err)
+	}
+
+	topFlags, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "top-flags", model.DEFAULT_ANALYSIS_TASK_TOP_COUNT)
+	if err != nil {
+		return "", fmt.Errorf("Unable to get top flags: '%w'.", err)
+	}
+
+	afterDue := (task.Options["after-due"] == true)
+
+	// An empty list means all assignments.
+	assignments := make([]*model.Assignment, 0, len(assignmentIDs))
+	if len(assignmentIDs) == 0 {
+		assignments = course.GetSortedAssignments()
+	} else {
+		for _, assignmentID := range assignmentIDs {
+			assignment := course.GetAssignment(assignmentID)
+			if assignment == nil {
+				return "", fmt.Errorf("Unable to find assignment '%s' in course '%s'.", assignmentID, course.GetID())
+			}
+
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Code analysis summary for %s.\n", course.GetName()))
+
+	now := timestamp.Now()
+	for _, assignment := range assignments {
+		content.WriteString(fmt.Sprintf("\n== %s (%s) ==\n", assignment.GetName(), assignment.GetID()))
+
+		if afterDue {
+			dueDate := assignment.GetEffectiveDueDate()
+			if (dueDate == nil) || (*dueDate > now) {
+				content.WriteString("Skipped, the assignment is not yet due.\n")
+				continue
+			}
+		}
+
+		err = writeAssignmentAnalysisSummary(&content, assignment, topPairs, topFlags)
+		if err != nil {
+			return "", fmt.Errorf("Failed to analyze assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+	}
+
+	subject := fmt.Sprintf("Autograder Code Analysis for %s", course.GetName())
+
+	to, err = db.ResolveCourseUsers(course, to)
+	if err != nil {
+		return "", fmt.Errorf("Failed to resolve users for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	err = email.Send(to, subject, content.String(), false)
+	if err != nil {
+		return "", fmt.Errorf("Failed to send code analysis summary for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	log.Debug("Code analysis completed successfully.", course, log.NewAttr("to", to))
+	return fmt.Sprintf("Sent a code analysis summary of %d assignments to %d recipients.", len(assignments), len(to)), nil
+}
+
+// Run individual and pairwise analysis on the most recent submissions for an assignment,
+// and write out the most similar pairs and most severe anomaly flags.
+func writeAssignmentAnalysisSummary(content *strings.Builder, assignment *model.Assignment, topPairs int, topFlags int) error {
+	specs := []string{strings.Join([]string{assignment.GetCourse().GetID(), assignment.GetID()}, common.SUBMISSION_ID_DELIM)}
+
+	fullSubmissionIDs, _, userErrors, systemErrors := analysis.ResolveSubmissionSpecs(specs)
+	if systemErrors != nil {
+		return fmt.Errorf("Failed to resolve submissions: '%w'.", systemErrors)
+	}
+
+	if userErrors != nil {
+		return fmt.Errorf("Failed to resolve submissions: '%w'.", userErrors)
+	}
+
+	options := analysis.AnalysisOptions{
+		RawSubmissionSpecs:    specs,
+		WaitForCompletion:     true,
+		ResolvedSubmissionIDs: fullSubmissionIDs,
+	}
+
+	individualResults, _, err := analysis.IndividualAnalysis(options, model.RootUserEmail)
+	if err != nil {
+		return fmt.Errorf("Failed to perform individual analysis: '%w'.", err)
+	}
+
+	pairwiseResults, _, err := analysis.PairwiseAnalysis(options, model.RootUserEmail)
+	if err != nil {
+		return fmt.Errorf("Failed to perform pairwise analysis: '%w'.", err)
+	}
+
+	content.WriteString(fmt.Sprintf("Analyzed %d submissions (%d pairs).\n", len(individualResults), len(pairwiseResults)))
+
+	pairs := make([]*model.PairwiseAnalysis, 0, len(pairwiseResults))
+	for _, result := range pairwiseResults {
+		if (result != nil) && !result.Failure {
+			pairs = append(pairs, result)
+		}
+	}
+
+	slices.SortStableFunc(pairs, func(a *model.PairwiseAnalysis, b *model.PairwiseAnalysis) int {
+		if a.TotalMeanSimilarity > b.TotalMeanSimilarity {
+			return -1
+		} else if a.TotalMeanSimilarity < b.TotalMeanSimilarity {
+			return 1
+		}
+
+		return 0
+	})
+
+	content.WriteString("\nTop Similar Pairs:\n")
+	if len(pairs) == 0 {
+		content.WriteString("  None.\n")
+	}
+
+	for i, pair := range pairs[:min(topPairs, len(pairs))] {
+		content.WriteString(fmt.Sprintf("  %d. %.2f -- %s vs %s\n", i+1, pair.TotalMeanSimilarity, pair.SubmissionIDs[0], pair.SubmissionIDs[1]))
+	}
+
+	flags := analysis.DetectAnomalies(individualResults, analysis.AnomalyOptions{})
+
+	content.WriteString("\nTop Flags:\n")
+	if len(flags) == 0 {
+		content.WriteString("  None.\n")
+	}
+
+	for _, flag := range flags[:min(topFlags, len(flags))] {
+		content.WriteString(fmt.Sprintf("  %d. [%s] %s -- %s\n", flag.Rank, flag.Type, flag.SubmissionID, flag.Explanation))
+	}
+
+	return nil
+}
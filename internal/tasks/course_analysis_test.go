@@ -0,0 +1,108 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestRunCourseAnalysisTaskBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		options          map[string]any
+		expectedContents []string
+		errorSubstring   string
+	}{
+		{
+			map[string]any{
+				"to":          []string{"course-admin@test.edulinq.org"},
+				"assignments": []string{"hw0"},
+			},
+			[]string{
+				"== Homework 0 (hw0) ==",
+				"Analyzed 1 submissions (0 pairs).",
+				"Top Similar Pairs:\n  None.",
+				"Top Flags:\n  None.",
+			},
+			"",
+		},
+		// All assignments.
+		{
+			map[string]any{
+				"to": []string{"course-admin@test.edulinq.org"},
+			},
+			[]string{
+				"== Homework 0 (hw0) ==",
+			},
+			"",
+		},
+		// hw0 has no due date.
+		{
+			map[string]any{
+				"to":          []string{"course-admin@test.edulinq.org"},
+				"assignments": []string{"hw0"},
+				"after-due":   true,
+			},
+			[]string{
+				"== Homework 0 (hw0) ==\nSkipped, the assignment is not yet due.",
+			},
+			"",
+		},
+		{
+			map[string]any{
+				"to":          []string{"course-admin@test.edulinq.org"},
+				"assignments": []string{"zzz"},
+			},
+			nil,
+			"Unable to find assignment 'zzz'",
+		},
+	}
+
+	for i, testCase := range testCases {
+		email.ClearTestMessages()
+
+		task := &model.FullScheduledTask{
+			UserTaskInfo: model.UserTaskInfo{
+				Type:    model.TaskTypeCourseAnalysis,
+				Options: testCase.options,
+			},
+			SystemTaskInfo: model.SystemTaskInfo{
+				CourseID: db.TEST_COURSE_ID,
+			},
+		}
+
+		_, err := RunCourseAnalysisTask(task)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Got an unexpected error running task: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Error is not as expected. Expected Substring: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+
+		messages := email.GetTestMessages()
+		if len(messages) != 1 {
+			test.Errorf("Case %d: Unexpected number of emails. Expected: 1, Actual: %d.", i, len(messages))
+			continue
+		}
+
+		for _, expectedContent := range testCase.expectedContents {
+			if !strings.Contains(messages[0].Body, expectedContent) {
+				test.Errorf("Case %d: Email does not contain expected content. Expected Substring: '%s', Actual: '%s'.", i, expectedContent, messages[0].Body)
+				break
+			}
+		}
+	}
+}
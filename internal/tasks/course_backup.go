@@ -7,15 +7,15 @@ import (
 	"github.com/edulinq/autograder/internal/procedures/backup"
 )
 
-func RunCourseBackupTask(task *model.FullScheduledTask) error {
+func RunCourseBackupTask(task *model.FullScheduledTask) (string, error) {
 	if task.CourseID == "" {
-		return fmt.Errorf("Course backup task has no course.")
+		return "", fmt.Errorf("Course backup task has no course.")
 	}
 
 	err := backup.BackupCourse(task.CourseID)
 	if err != nil {
-		return fmt.Errorf("Failed to run course backup task: '%w'.", err)
+		return "", fmt.Errorf("Failed to run course backup task: '%w'.", err)
 	}
 
-	return nil
+	return fmt.Sprintf("Backed up course '%s'.", task.CourseID), nil
 }
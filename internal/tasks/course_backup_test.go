@@ -14,7 +14,7 @@ func TestRunCourseBackupTaskBase(test *testing.T) {
 		},
 	}
 
-	err := RunCourseBackupTask(task)
+	_, err := RunCourseBackupTask(task)
 	if err != nil {
 		test.Fatalf("Got an unexpected error running task: '%v'.", err)
 	}
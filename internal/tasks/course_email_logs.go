@@ -11,24 +11,24 @@ import (
 	"github.com/edulinq/autograder/internal/procedures/logs"
 )
 
-func RunCourseEmailLogsTask(task *model.FullScheduledTask) error {
+func RunCourseEmailLogsTask(task *model.FullScheduledTask) (string, error) {
 	course, err := db.GetCourse(task.CourseID)
 	if err != nil {
-		return fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
 	}
 
 	if course == nil {
-		return fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
 	}
 
 	to, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "to", []string{})
 	if err != nil {
-		return fmt.Errorf("Unable to get recipients: '%w'.", err)
+		return "", fmt.Errorf("Unable to get recipients: '%w'.", err)
 	}
 
 	rawQuery, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "query", log.RawLogQuery{})
 	if err != nil {
-		return fmt.Errorf("Unable to get query: '%w'.", err)
+		return "", fmt.Errorf("Unable to get query: '%w'.", err)
 	}
 
 	sendEmpty := (task.Options["send-empty"] == true)
@@ -40,27 +40,27 @@ func RunCourseEmailLogsTask(task *model.FullScheduledTask) error {
 	// Since we have already limited the query context to this course, this is safe.
 	user, err := db.GetServerUser(model.RootUserEmail)
 	if err != nil {
-		return fmt.Errorf("Could not get root user: '%w'.", err)
+		return "", fmt.Errorf("Could not get root user: '%w'.", err)
 	}
 
 	if user == nil {
-		return fmt.Errorf("Could not find root user.")
+		return "", fmt.Errorf("Could not find root user.")
 	}
 
 	records, locatableErr, err := logs.Query(rawQuery, user)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if locatableErr != nil {
-		return locatableErr.ToError()
+		return "", locatableErr.ToError()
 	}
 
 	var content strings.Builder
 	content.WriteString(fmt.Sprintf("Found %d log records matching query: [%s].\n", len(records), rawQuery.String()))
 
 	if (len(records) == 0) && !sendEmpty {
-		return nil
+		return "Found no matching log records, no email sent.", nil
 	}
 
 	for _, record := range records {
@@ -72,8 +72,8 @@ func RunCourseEmailLogsTask(task *model.FullScheduledTask) error {
 
 	err = email.Send(to, subject, content.String(), false)
 	if err != nil {
-		return fmt.Errorf("Failed to send logs for course '%s': '%w'.", course.GetName(), err)
+		return "", fmt.Errorf("Failed to send logs for course '%s': '%w'.", course.GetName(), err)
 	}
 
-	return nil
+	return fmt.Sprintf("Sent %d log records to %d recipients.", len(records), len(to)), nil
 }
@@ -19,7 +19,7 @@ func TestRunCourseEmailLogsTaskBase(test *testing.T) {
 		},
 	}
 
-	err := RunCourseEmailLogsTask(task)
+	_, err := RunCourseEmailLogsTask(task)
 	if err != nil {
 		test.Fatalf("Got an unexpected error running task: '%v'.", err)
 	}
@@ -0,0 +1,131 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/lms/lmssync"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func RunCourseLMSSyncTask(task *model.FullScheduledTask) (string, error) {
+	course, err := db.GetCourse(task.CourseID)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+	}
+
+	if course == nil {
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+	}
+
+	to, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "to", []string{})
+	if err != nil {
+		return "", fmt.Errorf("Unable to get recipients: '%w'.", err)
+	}
+
+	sendEmpty := (task.Options["send-empty"] == true)
+	skipEmails := (task.Options["skip-emails"] == true)
+
+	result, err := lmssync.SyncLMS(course, false, !skipEmails)
+	if err != nil {
+		return "", fmt.Errorf("Failed to sync course '%s' with LMS: '%w'.", course.GetID(), err)
+	}
+
+	if result == nil {
+		log.Warn("LMS sync task is scheduled for a course without an LMS, skipping.", course)
+		return "Course has no LMS, skipped sync.", nil
+	}
+
+	content, summary, hasChanges := getLMSSyncSummary(course, result)
+
+	if len(to) == 0 {
+		log.Debug("LMS sync completed successfully.", course)
+		return summary, nil
+	}
+
+	if !hasChanges && !sendEmpty {
+		log.Debug("LMS sync completed successfully, no changes to report.", course)
+		return summary, nil
+	}
+
+	subject := fmt.Sprintf("Autograder LMS Sync for %s", course.GetName())
+
+	to, err = db.ResolveCourseUsers(course, to)
+	if err != nil {
+		return "", fmt.Errorf("Failed to resolve users for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	err = email.Send(to, subject, content, false)
+	if err != nil {
+		return "", fmt.Errorf("Failed to send LMS sync summary for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	log.Debug("LMS sync completed successfully.", course, log.NewAttr("to", to))
+	return summary, nil
+}
+
+// Build a summary of the enrollment (and assignment) changes made by an LMS sync.
+// Returns the full summary, a single-line summary of counts, and if any changes (or errors) were found.
+func getLMSSyncSummary(course *model.Course, result *model.LMSSyncResult) (string, string, bool) {
+	added := make([]string, 0)
+	dropped := make([]string, 0)
+	updated := make([]string, 0)
+	errors := make([]string, 0)
+
+	for _, userResult := range result.UserSync {
+		if userResult.SystemError != nil {
+			errors = append(errors, fmt.Sprintf("%s: %s", userResult.Email, userResult.SystemError.InternalMessage))
+		} else if userResult.ValidationError != nil {
+			errors = append(errors, fmt.Sprintf("%s: %s", userResult.Email, userResult.ValidationError.InternalMessage))
+		} else if userResult.Added || slices.Contains(userResult.Enrolled, course.GetID()) {
+			added = append(added, userResult.Email)
+		} else if slices.Contains(userResult.Dropped, course.GetID()) {
+			dropped = append(dropped, userResult.Email)
+		} else if userResult.Modified {
+			updated = append(updated, userResult.Email)
+		}
+	}
+
+	syncedAssignments := make([]string, 0)
+	if result.AssignmentSync != nil {
+		for _, info := range result.AssignmentSync.CreatedAssignments {
+			syncedAssignments = append(syncedAssignments, fmt.Sprintf("%s (created)", info.ID))
+		}
+
+		for _, info := range result.AssignmentSync.SyncedAssignments {
+			syncedAssignments = append(syncedAssignments, info.ID)
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("LMS sync summary for %s.\n", course.GetName()))
+
+	writeLMSSyncSection(&content, "Added Users", added)
+	writeLMSSyncSection(&content, "Dropped Users", dropped)
+	writeLMSSyncSection(&content, "Updated Users", updated)
+	writeLMSSyncSection(&content, "Synced Assignments", syncedAssignments)
+	writeLMSSyncSection(&content, "Errors", errors)
+
+	summary := fmt.Sprintf("Synced with LMS: %d added users, %d dropped users, %d updated users, %d synced assignments, %d errors.",
+		len(added), len(dropped), len(updated), len(syncedAssignments), len(errors))
+
+	hasChanges := ((len(added) + len(dropped) + len(updated) + len(syncedAssignments) + len(errors)) > 0)
+
+	return content.String(), summary, hasChanges
+}
+
+func writeLMSSyncSection(content *strings.Builder, title string, lines []string) {
+	content.WriteString(fmt.Sprintf("\n%s (%d):\n", title, len(lines)))
+
+	if len(lines) == 0 {
+		content.WriteString("  None.\n")
+	}
+
+	for _, line := range lines {
+		content.WriteString(fmt.Sprintf("  %s\n", line))
+	}
+}
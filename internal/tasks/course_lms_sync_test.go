@@ -0,0 +1,172 @@
+package tasks
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	lmstest "github.com/edulinq/autograder/internal/lms/backend/test"
+	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestRunCourseLMSSyncTaskBase(test *testing.T) {
+	defer db.ResetForTesting()
+	defer lmstest.ClearUsersModifier()
+
+	testCases := []struct {
+		options          map[string]any
+		syncUsers        bool
+		usersModifier    lmstest.FetchUsersModifier
+		numMessages      int
+		expectedContents []string
+	}{
+		// Enrollment changes.
+		{
+			map[string]any{
+				"to":          []string{"course-admin@test.edulinq.org"},
+				"skip-emails": true,
+			},
+			true,
+			lmsSyncTestUsers,
+			1,
+			[]string{
+				"Added Users (1):\n  add@test.edulinq.org\n",
+				"Dropped Users (1):\n  course-other@test.edulinq.org\n",
+				"Errors (0):\n  None.\n",
+			},
+		},
+		// Enrollment changes, with emails to new users.
+		{
+			map[string]any{
+				"to": []string{"course-admin@test.edulinq.org"},
+			},
+			true,
+			lmsSyncTestUsers,
+			2,
+			[]string{
+				"Added Users (1):\n  add@test.edulinq.org\n",
+			},
+		},
+		// No recipients, only the new user is emailed.
+		{
+			map[string]any{},
+			true,
+			lmsSyncTestUsers,
+			1,
+			nil,
+		},
+		// No changes.
+		{
+			map[string]any{
+				"to": []string{"course-admin@test.edulinq.org"},
+			},
+			false,
+			nil,
+			0,
+			nil,
+		},
+		// No changes, send empty.
+		{
+			map[string]any{
+				"to":         []string{"course-admin@test.edulinq.org"},
+				"send-empty": true,
+			},
+			false,
+			nil,
+			1,
+			[]string{
+				"Added Users (0):\n  None.\n",
+				"Dropped Users (0):\n  None.\n",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		email.ClearTestMessages()
+		lmstest.ClearUsersModifier()
+
+		if testCase.syncUsers {
+			course := db.MustGetTestCourse()
+			course.GetLMSAdapter().SyncUserAdds = true
+			course.GetLMSAdapter().SyncUserRemoves = true
+			db.MustSaveCourse(course)
+		}
+
+		if testCase.usersModifier != nil {
+			lmstest.SetUsersModifier(testCase.usersModifier)
+		}
+
+		task := &model.FullScheduledTask{
+			UserTaskInfo: model.UserTaskInfo{
+				Type:    model.TaskTypeCourseLMSSync,
+				Options: testCase.options,
+			},
+			SystemTaskInfo: model.SystemTaskInfo{
+				CourseID: db.TEST_COURSE_ID,
+			},
+		}
+
+		_, err := RunCourseLMSSyncTask(task)
+		if err != nil {
+			test.Errorf("Case %d: Got an unexpected error running task: '%v'.", i, err)
+			continue
+		}
+
+		messages := email.GetTestMessages()
+		if len(messages) != testCase.numMessages {
+			test.Errorf("Case %d: Unexpected number of emails. Expected: %d, Actual: %d.", i, testCase.numMessages, len(messages))
+			continue
+		}
+
+		if len(testCase.expectedContents) == 0 {
+			continue
+		}
+
+		// The summary is always the last message sent.
+		body := messages[len(messages)-1].Body
+		for _, expectedContent := range testCase.expectedContents {
+			if !strings.Contains(body, expectedContent) {
+				test.Errorf("Case %d: Email does not contain expected content. Expected Substring: '%s', Actual: '%s'.", i, expectedContent, body)
+				break
+			}
+		}
+
+		if testCase.syncUsers {
+			users, err := db.GetCourseUsers(db.MustGetTestCourse())
+			if err != nil {
+				test.Errorf("Case %d: Failed to get course users: '%v'.", i, err)
+				continue
+			}
+
+			if users["add@test.edulinq.org"] == nil {
+				test.Errorf("Case %d: Added user is not enrolled in the course.", i)
+				continue
+			}
+
+			if users["course-other@test.edulinq.org"] != nil {
+				test.Errorf("Case %d: Dropped user is still enrolled in the course.", i)
+				continue
+			}
+		}
+	}
+}
+
+// Drop one user and add another.
+func lmsSyncTestUsers(users []*lmstypes.User) []*lmstypes.User {
+	users = slices.DeleteFunc(users, func(user *lmstypes.User) bool {
+		return (user.Email == "course-other@test.edulinq.org")
+	})
+
+	users = append(users, &lmstypes.User{
+		ID:    "lms-add@test.edulinq.org",
+		Name:  "add",
+		Email: "add@test.edulinq.org",
+		Role:  model.CourseRoleStudent,
+	})
+
+	return users
+}
@@ -0,0 +1,243 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The information available to the subject and body templates of a reminder email.
+type ReminderTemplateData struct {
+	CourseID       string
+	CourseName     string
+	AssignmentID   string
+	AssignmentName string
+
+	// The user's due date (including any extension).
+	DueDate string
+
+	Email string
+	Name  string
+
+	// Students with a submission are only reminded once scores are released,
+	// so the score is never an unreleased one.
+	HasSubmission bool
+	Score         float64
+	MaxPoints     float64
+}
+
+// Email students that have an assignment due soon and either have no submission or are below the score threshold.
+// The score threshold is only used for assignments with released scores (so unreleased scores are never revealed).
+// Each user is only reminded once per due date, so the task can be scheduled to run often.
+func RunCourseReminderTask(task *model.FullScheduledTask) (string, error) {
+	course, err := db.GetCourse(task.CourseID)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+	}
+
+	if course == nil {
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+	}
+
+	before, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "before", util.DurationSpec{})
+	if err != nil {
+		return "", fmt.Errorf("Unable to get reminder duration: '%w'.", err)
+	}
+
+	assignmentIDs, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "assignments", []string{})
+	if err != nil {
+		return "", fmt.Errorf("Unable to get assignments: '%w'.", err)
+	}
+
+	threshold, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "score-threshold", 0.0)
+	if err != nil {
+		return "", fmt.Errorf("Unable to get score threshold: '%w'.", err)
+	}
+
+	subjectText, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "subject", model.DEFAULT_REMINDER_TASK_SUBJECT)
+	if err != nil {
+		return "", fmt.Errorf("Unable to get subject: '%w'.", err)
+	}
+
+	bodyText, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "template", model.DEFAULT_REMINDER_TASK_TEMPLATE)
+	if err != nil {
+		return "", fmt.Errorf("Unable to get template: '%w'.", err)
+	}
+
+	subjectTemplate, err := template.New("subject").Parse(subjectText)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse subject template: '%w'.", err)
+	}
+
+	bodyTemplate, err := template.New("template").Parse(bodyText)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse body template: '%w'.", err)
+	}
+
+	// An empty list means all assignments.
+	assignments := make([]*model.Assignment, 0, len(assignmentIDs))
+	if len(assignmentIDs) == 0 {
+		assignments = course.GetSortedAssignments()
+	} else {
+		for _, assignmentID := range assignmentIDs {
+			assignment := course.GetAssignment(assignmentID)
+			if assignment == nil {
+				return "", fmt.Errorf("Unable to find assignment '%s' in course '%s'.", assignmentID, course.GetID())
+			}
+
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	users, err := db.GetCourseUsers(course)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get users for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	optOuts, err := db.GetReminderOptOuts(course)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get reminder opt-outs for course '%s': '%w'.", course.GetID(), err)
+	}
+
+	now := timestamp.Now()
+	windowEnd := now + timestamp.FromMSecs(before.TotalMSecs())
+
+	count := 0
+	for _, assignment := range assignments {
+		sentCount, err := sendAssignmentReminders(assignment, users, optOuts, threshold, subjectTemplate, bodyTemplate, now, windowEnd)
+		count += sentCount
+
+		if err != nil {
+			return "", fmt.Errorf("Failed to send reminders for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+	}
+
+	return fmt.Sprintf("Sent %d reminders.", count), nil
+}
+
+func sendAssignmentReminders(
+	assignment *model.Assignment, users map[string]*model.CourseUser, optOuts map[string]bool, threshold float64,
+	subjectTemplate *template.Template, bodyTemplate *template.Template,
+	now timestamp.Timestamp, windowEnd timestamp.Timestamp) (int, error) {
+	sentReminders, err := db.GetSentReminders(assignment)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get sent reminders: '%w'.", err)
+	}
+
+	// Only fetch submissions once we know someone may need a reminder.
+	var submissions map[string]*model.GradingInfo = nil
+
+	emails := make([]string, 0, len(users))
+	for email, _ := range users {
+		emails = append(emails, email)
+	}
+
+	slices.Sort(emails)
+
+	scoreReleased := assignment.IsScoreReleased()
+
+	newReminders := make(map[string]timestamp.Timestamp)
+
+	// Record reminders as they are sent, so a failure does not cause duplicates on the next run.
+	defer func() {
+		err := db.AddSentReminders(assignment, newReminders)
+		if err != nil {
+			log.Error("Failed to record sent reminders.", err, assignment)
+		}
+	}()
+
+	for _, userEmail := range emails {
+		user := users[userEmail]
+		if (user.Role != model.CourseRoleStudent) || optOuts[userEmail] {
+			continue
+		}
+
+		dueDate := assignment.GetUserDueDate(userEmail)
+		if (dueDate == nil) || (*dueDate <= now) || (*dueDate > windowEnd) {
+			continue
+		}
+
+		lastDueDate, ok := sentReminders[userEmail]
+		if ok && (lastDueDate == *dueDate) {
+			continue
+		}
+
+		if submissions == nil {
+			submissions, err = db.GetSelectedSubmissions(assignment, model.CourseRoleStudent)
+			if err != nil {
+				return len(newReminders), fmt.Errorf("Failed to get submissions: '%w'.", err)
+			}
+		}
+
+		data := ReminderTemplateData{
+			CourseID:       assignment.GetCourse().GetID(),
+			CourseName:     assignment.GetCourse().GetName(),
+			AssignmentID:   assignment.GetID(),
+			AssignmentName: assignment.GetName(),
+			DueDate:        dueDate.UnsafePrettyString(),
+			Email:          userEmail,
+			Name:           user.GetDisplayName(),
+		}
+
+		submission := submissions[userEmail]
+		if submission != nil {
+			// Unreleased scores cannot be used (even to check the threshold).
+			if !scoreReleased || !isBelowScoreThreshold(submission, threshold) {
+				continue
+			}
+
+			data.HasSubmission = true
+			data.Score = submission.Score
+			data.MaxPoints = submission.MaxPoints
+		}
+
+		subject, err := executeReminderTemplate(subjectTemplate, data)
+		if err != nil {
+			return len(newReminders), err
+		}
+
+		body, err := executeReminderTemplate(bodyTemplate, data)
+		if err != nil {
+			return len(newReminders), err
+		}
+
+		// The email package handles throttling.
+		err = email.Send([]string{userEmail}, strings.TrimSpace(subject), body, false)
+		if err != nil {
+			return len(newReminders), fmt.Errorf("Failed to send reminder to '%s': '%w'.", userEmail, err)
+		}
+
+		newReminders[userEmail] = *dueDate
+	}
+
+	log.Debug("Sent assignment reminders.", assignment, log.NewAttr("count", len(newReminders)))
+	return len(newReminders), nil
+}
+
+// A threshold of zero means that only users without a submission are reminded.
+func isBelowScoreThreshold(submission *model.GradingInfo, threshold float64) bool {
+	if (threshold <= 0.0) || (submission.MaxPoints <= 0.0) {
+		return false
+	}
+
+	return ((submission.Score / submission.MaxPoints * 100.0) < threshold)
+}
+
+func executeReminderTemplate(tmpl *template.Template, data ReminderTemplateData) (string, error) {
+	var builder strings.Builder
+
+	err := tmpl.Execute(&builder, data)
+	if err != nil {
+		return "", fmt.Errorf("Failed to execute '%s' template: '%w'.", tmpl.Name(), err)
+	}
+
+	return builder.String(), nil
+}
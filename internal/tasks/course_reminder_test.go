@@ -0,0 +1,228 @@
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRunCourseReminderTaskBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	hour := timestamp.FromMSecs(60 * 60 * 1000)
+
+	testCases := []struct {
+		options          map[string]any
+		extensions       map[string]timestamp.Timestamp
+		optOuts          []string
+		selectFirst      bool
+		expectedTo       []string
+		expectedContents []string
+		scoreRelease     *model.ScoreReleasePolicy
+	}{
+		// Only users without a submission.
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}},
+			nil,
+			nil,
+			false,
+			[]string{"course-other@test.edulinq.org"},
+			[]string{"You have not made a submission yet."},
+			nil,
+		},
+
+		// Below the score threshold (the first submission has a score of 0).
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}, "score-threshold": 50.0},
+			nil,
+			nil,
+			true,
+			[]string{"course-other@test.edulinq.org", "course-student@test.edulinq.org"},
+			nil,
+			nil,
+		},
+
+		// Above the score threshold.
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}, "score-threshold": 50.0},
+			nil,
+			nil,
+			false,
+			[]string{"course-other@test.edulinq.org"},
+			nil,
+			nil,
+		},
+
+		// Opt-out.
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}},
+			nil,
+			[]string{"course-other@test.edulinq.org"},
+			false,
+			[]string{},
+			nil,
+			nil,
+		},
+
+		// Due date is outside the window.
+		{
+			map[string]any{"before": util.DurationSpec{Minutes: 30}},
+			nil,
+			nil,
+			false,
+			[]string{},
+			nil,
+			nil,
+		},
+
+		// Extension moves the due date outside the window.
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}},
+			map[string]timestamp.Timestamp{"course-other@test.edulinq.org": timestamp.Now() + (72 * hour)},
+			nil,
+			false,
+			[]string{},
+			nil,
+			nil,
+		},
+
+		// Extension moves the due date into the window.
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}, "score-threshold": 50.0},
+			map[string]timestamp.Timestamp{"course-other@test.edulinq.org": timestamp.Now() - (72 * hour)},
+			nil,
+			true,
+			[]string{"course-student@test.edulinq.org"},
+			nil,
+			nil,
+		},
+
+		// Custom templates.
+		{
+			map[string]any{
+				"before":   util.DurationSpec{Hours: 2},
+				"subject":  "{{.AssignmentID}} is due soon",
+				"template": "Hi {{.Email}}, {{.AssignmentName}} in {{.CourseID}}.",
+			},
+			nil,
+			nil,
+			false,
+			[]string{"course-other@test.edulinq.org"},
+			[]string{"Hi course-other@test.edulinq.org, Homework 0 in course101."},
+			nil,
+		},
+
+		// Below the score threshold, but scores are not released.
+		{
+			map[string]any{"before": util.DurationSpec{Hours: 2}, "score-threshold": 50.0},
+			nil,
+			nil,
+			true,
+			[]string{"course-other@test.edulinq.org"},
+			nil,
+			&model.ScoreReleasePolicy{Mode: model.ScoreReleaseReceived},
+		},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		email.ClearTestMessages()
+
+		course := db.MustGetTestCourse()
+		assignment := course.GetAssignment("hw0")
+
+		dueDate := timestamp.Now() + hour
+		assignment.DueDate = &dueDate
+		assignment.Extensions = testCase.extensions
+		assignment.ScoreRelease = testCase.scoreRelease
+
+		if testCase.selectFirst {
+			assignment.SubmissionSelection = model.SubmissionSelectionStudentSelected
+		}
+
+		db.MustSaveCourse(course)
+
+		if testCase.selectFirst {
+			err := db.SetFinalSubmissionSelection(assignment, "course-student@test.edulinq.org", "1697406256")
+			if err != nil {
+				test.Fatalf("Case %d: Failed to set final submission: '%v'.", i, err)
+			}
+		}
+
+		// Make other a student (without any submissions).
+		other := db.MustGetServerUser("course-other@test.edulinq.org")
+		other.CourseInfo[course.GetID()].Role = model.CourseRoleStudent
+		db.MustUpsertUser(other)
+
+		for _, optOut := range testCase.optOuts {
+			err := db.SetReminderOptOut(course, optOut, true)
+			if err != nil {
+				test.Fatalf("Case %d: Failed to set opt-out: '%v'.", i, err)
+			}
+		}
+
+		userTaskInfo := model.UserTaskInfo{
+			Type:    model.TaskTypeCourseReminder,
+			When:    &util.ScheduledTime{Daily: "3:00"},
+			Options: testCase.options,
+		}
+
+		err := userTaskInfo.Validate()
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate task: '%v'.", i, err)
+			continue
+		}
+
+		task := &model.FullScheduledTask{
+			UserTaskInfo: userTaskInfo,
+			SystemTaskInfo: model.SystemTaskInfo{
+				CourseID: db.TEST_COURSE_ID,
+			},
+		}
+
+		_, err = RunCourseReminderTask(task)
+		if err != nil {
+			test.Errorf("Case %d: Got an unexpected error running task: '%v'.", i, err)
+			continue
+		}
+
+		messages := email.GetTestMessages()
+
+		actualTo := make([]string, 0, len(messages))
+		for _, message := range messages {
+			actualTo = append(actualTo, message.To...)
+		}
+
+		if !reflect.DeepEqual(testCase.expectedTo, actualTo) {
+			test.Errorf("Case %d: Unexpected recipients. Expected: '%v', Actual: '%v'.", i, testCase.expectedTo, actualTo)
+			continue
+		}
+
+		for _, expectedContent := range testCase.expectedContents {
+			if !strings.Contains(messages[0].Body, expectedContent) {
+				test.Errorf("Case %d: Email does not contain expected content. Expected Substring: '%s', Actual: '%s'.", i, expectedContent, messages[0].Body)
+				break
+			}
+		}
+
+		// Running again should not send any more reminders.
+		email.ClearTestMessages()
+
+		_, err = RunCourseReminderTask(task)
+		if err != nil {
+			test.Errorf("Case %d: Got an unexpected error running task a second time: '%v'.", i, err)
+			continue
+		}
+
+		if len(email.GetTestMessages()) != 0 {
+			test.Errorf("Case %d: Reminders were sent a second time: '%s'.", i, util.MustToJSONIndent(email.GetTestMessages()))
+			continue
+		}
+	}
+}
@@ -10,43 +10,55 @@ import (
 	"github.com/edulinq/autograder/internal/report"
 )
 
-func RunCourseReportTask(task *model.FullScheduledTask) error {
+func RunCourseReportTask(task *model.FullScheduledTask) (string, error) {
 	course, err := db.GetCourse(task.CourseID)
 	if err != nil {
-		return fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
 	}
 
 	if course == nil {
-		return fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
 	}
 
 	to, err := model.GetTaskOptionAsType(&task.UserTaskInfo, "to", []string{})
 	if err != nil {
-		return fmt.Errorf("Unable to get recipients: '%w'.", err)
+		return "", fmt.Errorf("Unable to get recipients: '%w'.", err)
 	}
 
-	report, err := report.GetCourseScoringReport(course)
+	filter := &model.CourseUserFilter{}
+
+	filter.FilterSection, err = model.GetTaskOptionAsType(&task.UserTaskInfo, "filter-section", "")
+	if err != nil {
+		return "", fmt.Errorf("Unable to get section filter: '%w'.", err)
+	}
+
+	filter.FilterGroup, err = model.GetTaskOptionAsType(&task.UserTaskInfo, "filter-group", "")
+	if err != nil {
+		return "", fmt.Errorf("Unable to get group filter: '%w'.", err)
+	}
+
+	report, err := report.GetCourseScoringReport(course, filter)
 	if err != nil {
-		return fmt.Errorf("Failed to get scoring report for course '%s': '%w'.", course.GetID(), err)
+		return "", fmt.Errorf("Failed to get scoring report for course '%s': '%w'.", course.GetID(), err)
 	}
 
 	html, err := report.ToHTML()
 	if err != nil {
-		return fmt.Errorf("Failed to generate HTML for scoring report for course '%s': '%w'.", course.GetID(), err)
+		return "", fmt.Errorf("Failed to generate HTML for scoring report for course '%s': '%w'.", course.GetID(), err)
 	}
 
 	subject := fmt.Sprintf("Autograder Scoring Report for %s", course.GetName())
 
 	to, err = db.ResolveCourseUsers(course, to)
 	if err != nil {
-		return fmt.Errorf("Failed to resolve users for course '%s': '%w'.", course.GetID(), err)
+		return "", fmt.Errorf("Failed to resolve users for course '%s': '%w'.", course.GetID(), err)
 	}
 
 	err = email.Send(to, subject, html, true)
 	if err != nil {
-		return fmt.Errorf("Failed to send scoring report for course '%s': '%w'.", course.GetID(), err)
+		return "", fmt.Errorf("Failed to send scoring report for course '%s': '%w'.", course.GetID(), err)
 	}
 
 	log.Debug("Report completed successfully.", course, log.NewAttr("to", to))
-	return nil
+	return fmt.Sprintf("Sent scoring report to %d recipients.", len(to)), nil
 }
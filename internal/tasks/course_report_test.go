@@ -19,7 +19,7 @@ func TestRunCourseReportTaskBase(test *testing.T) {
 		},
 	}
 
-	err := RunCourseReportTask(task)
+	_, err := RunCourseReportTask(task)
 	if err != nil {
 		test.Fatalf("Got an unexpected error running task: '%v'.", err)
 	}
@@ -5,19 +5,23 @@ import (
 
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
-	"github.com/edulinq/autograder/internal/scoring"
+	"github.com/edulinq/autograder/internal/procedures/grades"
 )
 
-func RunCourseScoringUploadTask(task *model.FullScheduledTask) error {
+func RunCourseScoringUploadTask(task *model.FullScheduledTask) (string, error) {
 	course, err := db.GetCourse(task.CourseID)
 	if err != nil {
-		return fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
 	}
 
 	if course == nil {
-		return fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
 	}
 
-	_, err = scoring.FullCourseScoringAndUpload(course, false)
-	return err
+	results, err := grades.FullCourseScoringAndUpload(course, false)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Scored and uploaded %d assignments.", len(results)), nil
 }
@@ -14,7 +14,7 @@ func TestRunCourseScoringUploadTaskBase(test *testing.T) {
 		},
 	}
 
-	err := RunCourseScoringUploadTask(task)
+	_, err := RunCourseScoringUploadTask(task)
 	if err != nil {
 		test.Fatalf("Got an unexpected error running task: '%v'.", err)
 	}
@@ -8,14 +8,14 @@ import (
 	"github.com/edulinq/autograder/internal/procedures/courses"
 )
 
-func RunCourseUpdateTask(task *model.FullScheduledTask) error {
+func RunCourseUpdateTask(task *model.FullScheduledTask) (string, error) {
 	course, err := db.GetCourse(task.CourseID)
 	if err != nil {
-		return fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
+		return "", fmt.Errorf("Failed to get course '%s': '%w'.", task.CourseID, err)
 	}
 
 	if course == nil {
-		return fmt.Errorf("Unable to find course '%s'.", task.CourseID)
+		return "", fmt.Errorf("Unable to find course '%s'.", task.CourseID)
 	}
 
 	options := courses.CourseUpsertOptions{
@@ -23,5 +23,9 @@ func RunCourseUpdateTask(task *model.FullScheduledTask) error {
 	}
 
 	_, err = courses.UpdateFromLocalSource(course, options)
-	return err
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Updated course '%s' from source.", course.GetID()), nil
 }
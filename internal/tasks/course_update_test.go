@@ -14,7 +14,7 @@ func TestRunCourseUpdateTaskBase(test *testing.T) {
 		},
 	}
 
-	err := RunCourseUpdateTask(task)
+	_, err := RunCourseUpdateTask(task)
 	if err != nil {
 		test.Fatalf("Got an unexpected error running task: '%v'.", err)
 	}
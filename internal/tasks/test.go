@@ -6,11 +6,19 @@ import (
 
 var testTaskCalls int = 0
 
+// If set, called whenever the test task runs (e.g., to block a run).
+var testTaskHook func() = nil
+
 func resetTestTaskCalls() {
 	testTaskCalls = 0
 }
 
-func RunTestTask(task *model.FullScheduledTask) error {
+func RunTestTask(task *model.FullScheduledTask) (string, error) {
 	testTaskCalls++
-	return nil
+
+	if testTaskHook != nil {
+		testTaskHook()
+	}
+
+	return "Ran test task.", nil
 }
@@ -12,7 +12,7 @@ func TestRunTestTaskBase(test *testing.T) {
 
 	task := &model.FullScheduledTask{}
 
-	err := RunTestTask(task)
+	_, err := RunTestTask(task)
 	if err != nil {
 		test.Fatalf("Got an unexpected error running task: '%v'.", err)
 	}
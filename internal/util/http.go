@@ -36,6 +36,19 @@ type SavedHTTPRequest struct {
 	ResponseBody    string
 }
 
+// The error returned when a request gets a non-OK (non-2xx) response.
+// Callers can inspect the status code and response headers (e.g., to decide if a request should be retried).
+type HTTPStatusError struct {
+	URL     string
+	Method  string
+	Code    int
+	Headers map[string][]string
+}
+
+func (this *HTTPStatusError) Error() string {
+	return fmt.Sprintf("Got a non-OK status code '%d' from %s on URL '%s'.", this.Code, this.Method, this.URL)
+}
+
 func SetStoreHTTPDir(value string) string {
 	oldValue := storeHTTPDir
 	storeHTTPDir = value
@@ -109,6 +122,33 @@ func PostWithHeadersNoCheck(uri string, form map[string]string, headers map[stri
 	return postPutWithHeaders("POST", uri, form, headers, false)
 }
 
+// Post a raw body, the content type should be set in the headers.
+// Returns: (body, headers (response), error)
+func PostBodyWithHeaders(uri string, body string, headers map[string][]string) (string, map[string][]string, error) {
+	return sendBodyWithHeaders("POST", uri, body, headers)
+}
+
+// Put a raw body, the content type should be set in the headers.
+// Returns: (body, headers (response), error)
+func PutBodyWithHeaders(uri string, body string, headers map[string][]string) (string, map[string][]string, error) {
+	return sendBodyWithHeaders("PUT", uri, body, headers)
+}
+
+func sendBodyWithHeaders(verb string, uri string, body string, headers map[string][]string) (string, map[string][]string, error) {
+	request, err := http.NewRequest(verb, uri, strings.NewReader(body))
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to create %s request on URL '%s': '%w'.", verb, uri, err)
+	}
+
+	for key, values := range headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	return doRequest(uri, request, verb, true)
+}
+
 // Returns: (body, error)
 func Put(uri string, form map[string]string) (string, error) {
 	body, _, err := PutWithHeaders(uri, form, make(map[string][]string))
@@ -185,6 +225,51 @@ func PostFiles(uri string, form map[string]string, paths []string, checkResult b
 	return body, err
 }
 
+// Post a multipart form with a single file (given as content).
+// The file will be the last part of the form (some services, e.g. S3, require this).
+// Returns: (body, headers (response), error)
+func PostFileContentWithHeaders(uri string, form map[string]string, fieldName string, filename string, content []byte, headers map[string][]string) (string, map[string][]string, error) {
+	var buffer bytes.Buffer
+	formWriter := multipart.NewWriter(&buffer)
+
+	for key, value := range form {
+		err := formWriter.WriteField(key, value)
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed to write form field '%s': '%w'.", key, err)
+		}
+	}
+
+	fileWriter, err := formWriter.CreateFormFile(fieldName, filename)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to create form file for '%s': '%w'.", filename, err)
+	}
+
+	_, err = fileWriter.Write(content)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to write file '%s' into form: '%w'.", filename, err)
+	}
+
+	err = formWriter.Close()
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to close multipart form: '%w'.", err)
+	}
+
+	request, err := http.NewRequest("POST", uri, &buffer)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to create POST request (with file) on URL '%s': '%w'.", uri, err)
+	}
+
+	request.Header.Add("Content-Type", formWriter.FormDataContentType())
+
+	for key, values := range headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	return doRequest(uri, request, "POST", true)
+}
+
 // Returns: (body, headers (response), error)
 func doRequest(uri string, request *http.Request, verb string, checkResult bool) (string, map[string][]string, error) {
 	client := http.Client{}
@@ -225,11 +310,17 @@ func doRequest(uri string, request *http.Request, verb string, checkResult bool)
 		}
 	}
 
-	if checkResult && (response.StatusCode != http.StatusOK) {
+	// Any success (2xx) status is OK (e.g., some APIs respond with "201 Created" or "204 No Content").
+	if checkResult && ((response.StatusCode < 200) || (response.StatusCode >= 300)) {
 		log.Error("Got a non-OK status.",
 			log.NewAttr("code", response.StatusCode), log.NewAttr("body", body),
 			log.NewAttr("headers", response.Header), log.NewAttr("url", uri))
-		return "", nil, fmt.Errorf("Got a non-OK status code '%d' from %s on URL '%s'.", response.StatusCode, verb, uri)
+		return "", nil, &HTTPStatusError{
+			URL:     uri,
+			Method:  verb,
+			Code:    response.StatusCode,
+			Headers: response.Header,
+		}
 	}
 
 	return body, response.Header, nil
@@ -82,6 +82,39 @@ func Median(values []float64) float64 {
 	}
 }
 
+// Get the value at the given percentile (0 - 100) of some sorted values,
+// interpolating linearly between the closest values.
+func Percentile(sortedValues []float64, percentile float64) float64 {
+	if len(sortedValues) == 0 {
+		return 0.0
+	}
+
+	percentile = math.Max(0.0, math.Min(100.0, percentile))
+
+	position := (percentile / 100.0) * float64(len(sortedValues)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+
+	return sortedValues[lower] + ((position - float64(lower)) * (sortedValues[upper] - sortedValues[lower]))
+}
+
+// Get the percentile rank (0 - 100) of a value within some sorted values,
+// i.e., the percentage of values below it (with values equal to it counting as half).
+func PercentileRank(sortedValues []float64, value float64) float64 {
+	if len(sortedValues) == 0 {
+		return 0.0
+	}
+
+	below, _ := slices.BinarySearch(sortedValues, value)
+
+	equal := 0
+	for i := below; (i < len(sortedValues)) && (sortedValues[i] == value); i++ {
+		equal++
+	}
+
+	return 100.0 * (float64(below) + (0.5 * float64(equal))) / float64(len(sortedValues))
+}
+
 // Input values will be sorted (in place).
 func ComputeAggregates(values []float64) AggregateValues {
 	if (values == nil) || (len(values) == 0) {
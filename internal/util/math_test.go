@@ -57,3 +57,55 @@ func TestComputeAggregatesBase(test *testing.T) {
 		}
 	}
 }
+
+func TestPercentileBase(test *testing.T) {
+	values := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+
+	testCases := []struct {
+		values     []float64
+		percentile float64
+		expected   float64
+	}{
+		{nil, 50.0, 0.0},
+		{[]float64{3.0}, 25.0, 3.0},
+		{values, 0.0, 1.0},
+		{values, 25.0, 2.0},
+		{values, 50.0, 3.0},
+		{values, 90.0, 4.6},
+		{values, 100.0, 5.0},
+		{values, 150.0, 5.0},
+		{values, -10.0, 1.0},
+	}
+
+	for i, testCase := range testCases {
+		actual := Percentile(testCase.values, testCase.percentile)
+		if !IsClose(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected result. Expected: '%f', Actual: '%f'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestPercentileRankBase(test *testing.T) {
+	values := []float64{1.0, 2.0, 2.0, 3.0}
+
+	testCases := []struct {
+		values   []float64
+		value    float64
+		expected float64
+	}{
+		{nil, 1.0, 0.0},
+		{values, 0.0, 0.0},
+		{values, 1.0, 12.5},
+		{values, 2.0, 50.0},
+		{values, 2.5, 75.0},
+		{values, 3.0, 87.5},
+		{values, 4.0, 100.0},
+	}
+
+	for i, testCase := range testCases {
+		actual := PercentileRank(testCase.values, testCase.value)
+		if !IsClose(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected result. Expected: '%f', Actual: '%f'.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -25,3 +25,7 @@ func PointerToInt(target *int) int {
 
 	return *target
 }
+
+func BoolPointer(target bool) *bool {
+	return &target
+}
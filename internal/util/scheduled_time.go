@@ -5,6 +5,8 @@ import (
 	"math"
 	"strings"
 	"time"
+	// Embed the timezone database, so timezones work on servers without one.
+	_ "time/tzdata"
 
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/timestamp"
@@ -29,9 +31,16 @@ const (
 
 // This struct should always have Validate() called after construction.
 // All other methods will assume Validate() returns no error.
+// Only one of the time specifications may be populated.
 type ScheduledTime struct {
-	Every DurationSpec  `json:"every,omitempty"`
-	Daily TimeOfDaySpec `json:"daily,omitempty"`
+	Every  DurationSpec  `json:"every,omitempty"`
+	Daily  TimeOfDaySpec `json:"daily,omitempty"`
+	Weekly *WeeklySpec   `json:"weekly,omitempty"`
+	Cron   CronSpec      `json:"cron,omitempty"`
+
+	// The IANA timezone (e.g., "America/Los_Angeles") to interpret clock times (daily, weekly, and cron) in.
+	// Defaults to the server's local time.
+	Timezone string `json:"timezone,omitempty"`
 }
 
 type timeSpec interface {
@@ -169,47 +178,50 @@ func (this TimeOfDaySpec) IsEmpty() bool {
 }
 
 func (this TimeOfDaySpec) ComputeNextTime(startTime timestamp.Timestamp) timestamp.Timestamp {
-	var thisGoTime time.Time
+	return this.computeNextTimeInLocation(startTime, time.Local)
+}
 
-	instance, err := this.getGoTime()
-	if err != nil {
-		log.Error("Failed to parse time of day spec.", err, log.NewAttr("contents", string(this)))
-		thisGoTime, _ = time.ParseInLocation(TIME_LAYOUT_MINS, "00:00", time.Local)
-	} else {
-		thisGoTime = instance
-	}
+func (this TimeOfDaySpec) String() string {
+	return fmt.Sprintf("daily at %s", this.timeString())
+}
 
-	startGoTime := startTime.ToGoTime()
+// Unless otherwise specified, the scheduled time will be in the server's local time.
+func (this TimeOfDaySpec) computeNextTimeInLocation(startTime timestamp.Timestamp, location *time.Location) timestamp.Timestamp {
+	thisGoTime := this.mustGetGoTime()
 
-	// The scheduled time will always be in the server's local time.
-	// Adjust the start time to match.
-	startGoTime = startGoTime.In(time.Local)
+	// Adjust the start time to match the scheduled time's location.
+	startGoTime := startTime.ToGoTime().In(location)
 
-	// Get a time with the same date as startTime, but the time of day for this scheduled time.
-	nextTime := time.Date(
-		startGoTime.Year(), startGoTime.Month(), startGoTime.Day(),
-		thisGoTime.Hour(), thisGoTime.Minute(), thisGoTime.Second(), thisGoTime.Nanosecond(),
-		time.Local)
+	// Walk the calendar dates (in UTC to avoid any DST issues when stepping days),
+	// and get a time with that date, but the time of day for this scheduled time.
+	for dayOffset := 0; ; dayOffset++ {
+		date := time.Date(startGoTime.Year(), startGoTime.Month(), startGoTime.Day()+dayOffset, 0, 0, 0, 0, time.UTC)
 
-	// The constructed time may be before the start time.
-	for nextTime.Before(startGoTime) {
-		nextTime = nextTime.AddDate(0, 0, 1)
-	}
+		nextTime := wallClockTime(
+			date.Year(), date.Month(), date.Day(),
+			thisGoTime.Hour(), thisGoTime.Minute(), thisGoTime.Second(), thisGoTime.Nanosecond(),
+			location)
 
-	return timestamp.FromGoTime(nextTime)
+		// The constructed time may be before the start time.
+		if !nextTime.Before(startGoTime) {
+			return timestamp.FromGoTime(nextTime)
+		}
+	}
 }
 
-func (this TimeOfDaySpec) String() string {
-	timeOfDay := "00:00:00"
+func (this TimeOfDaySpec) timeString() string {
+	return this.mustGetGoTime().Format(time.TimeOnly)
+}
 
+// Get the time of day, logging any error and falling back to midnight.
+func (this TimeOfDaySpec) mustGetGoTime() time.Time {
 	instance, err := this.getGoTime()
 	if err != nil {
 		log.Error("Failed to parse time of day spec.", err, log.NewAttr("contents", string(this)))
-	} else {
-		timeOfDay = instance.Format(time.TimeOnly)
+		instance, _ = time.ParseInLocation(TIME_LAYOUT_MINS, "00:00", time.Local)
 	}
 
-	return fmt.Sprintf("daily at %s", timeOfDay)
+	return instance
 }
 
 // This TimeOfDaySpec should have already been validated,
@@ -252,18 +264,54 @@ func (this *ScheduledTime) Validate() error {
 		return fmt.Errorf("Schedule time 'every' component is invalid: '%w'.", err)
 	}
 
-	if this.Daily.IsEmpty() && this.Every.IsEmpty() {
-		return fmt.Errorf("Both 'daily' and 'every' cannot be empty.")
+	err = this.Weekly.Validate()
+	if err != nil {
+		return fmt.Errorf("Schedule time 'weekly' component is invalid: '%w'.", err)
+	}
+
+	err = this.Cron.Validate()
+	if err != nil {
+		return fmt.Errorf("Schedule time 'cron' component is invalid: '%w'.", err)
 	}
 
-	if !this.Daily.IsEmpty() && !this.Every.IsEmpty() {
-		return fmt.Errorf("Both 'daily' and 'every' cannot be populated.")
+	count := 0
+	for _, spec := range []timeSpec{&this.Every, this.Daily, this.Weekly, this.Cron} {
+		if !spec.IsEmpty() {
+			count++
+		}
+	}
+
+	if count == 0 {
+		return fmt.Errorf("One of 'every', 'daily', 'weekly', or 'cron' must be populated.")
+	}
+
+	if count > 1 {
+		return fmt.Errorf("Only one of 'every', 'daily', 'weekly', or 'cron' may be populated.")
+	}
+
+	if this.Timezone != "" {
+		if !this.Every.IsEmpty() {
+			return fmt.Errorf("A timezone cannot be used with 'every'.")
+		}
+
+		_, err = time.LoadLocation(this.Timezone)
+		if err != nil {
+			return fmt.Errorf("Unknown timezone '%s': '%w'.", this.Timezone, err)
+		}
 	}
 
 	return nil
 }
 
 func (this *ScheduledTime) TotalMSecs() int64 {
+	if !this.Weekly.IsEmpty() {
+		return this.Weekly.TotalMSecs()
+	}
+
+	if !this.Cron.IsEmpty() {
+		return this.Cron.TotalMSecs()
+	}
+
 	if this.Daily.IsEmpty() {
 		return this.Every.TotalMSecs()
 	}
@@ -272,7 +320,12 @@ func (this *ScheduledTime) TotalMSecs() int64 {
 }
 
 func (this *ScheduledTime) IsEmpty() bool {
-	return (this.Daily.IsEmpty() && this.Every.IsEmpty())
+	return (this.Daily.IsEmpty() && this.Every.IsEmpty() && this.Weekly.IsEmpty() && this.Cron.IsEmpty())
+}
+
+// Check if this time is based on the calendar/clock (daily, weekly, or cron) rather than a period (every).
+func (this *ScheduledTime) IsCalendarBased() bool {
+	return this.Every.IsEmpty()
 }
 
 func (this *ScheduledTime) ComputeNextTimeFromNow() timestamp.Timestamp {
@@ -280,17 +333,87 @@ func (this *ScheduledTime) ComputeNextTimeFromNow() timestamp.Timestamp {
 }
 
 func (this *ScheduledTime) ComputeNextTime(startTime timestamp.Timestamp) timestamp.Timestamp {
+	if !this.Weekly.IsEmpty() {
+		return this.Weekly.computeNextTimeInLocation(startTime, this.getLocation())
+	}
+
+	if !this.Cron.IsEmpty() {
+		return this.Cron.computeNextTimeInLocation(startTime, this.getLocation())
+	}
+
 	if this.Daily.IsEmpty() {
 		return this.Every.ComputeNextTime(startTime)
 	}
 
-	return this.Daily.ComputeNextTime(startTime)
+	return this.Daily.computeNextTimeInLocation(startTime, this.getLocation())
 }
 
 func (this *ScheduledTime) String() string {
-	if this.Daily.IsEmpty() {
-		return this.Every.String()
+	var value string
+
+	if !this.Weekly.IsEmpty() {
+		value = this.Weekly.String()
+	} else if !this.Cron.IsEmpty() {
+		value = this.Cron.String()
+	} else if this.Daily.IsEmpty() {
+		value = this.Every.String()
+	} else {
+		value = this.Daily.String()
+	}
+
+	if this.Timezone != "" {
+		value = fmt.Sprintf("%s (%s)", value, this.Timezone)
+	}
+
+	return value
+}
+
+// Get the instant for a wall clock time in a location.
+// Times that do not exist (skipped by a DST transition) are shifted forward by the length of the transition
+// (e.g., 02:30 becomes 03:30 when clocks spring forward from 02:00 to 03:00).
+// Times that occur twice (repeated by a DST transition) will use the first occurrence.
+func wallClockTime(year int, month time.Month, day int, hour int, minute int, second int, nanosecond int, location *time.Location) time.Time {
+	// Try the offsets from before and after any nearby transition.
+	// Go does not guarantee which offset is used for skipped or repeated times, so we cannot rely on time.Date().
+	// Transitions are never within two days of each other.
+	wallTime := time.Date(year, month, day, hour, minute, second, nanosecond, time.UTC)
+	_, beforeOffset := wallTime.Add(-48 * time.Hour).In(location).Zone()
+	_, afterOffset := wallTime.Add(48 * time.Hour).In(location).Zone()
+
+	beforeInstance := wallTime.Add(-time.Duration(beforeOffset) * time.Second).In(location)
+	afterInstance := wallTime.Add(-time.Duration(afterOffset) * time.Second).In(location)
+
+	beforeMatches := matchesWallClock(beforeInstance, day, hour, minute)
+	afterMatches := matchesWallClock(afterInstance, day, hour, minute)
+
+	if beforeMatches && afterMatches && afterInstance.Before(beforeInstance) {
+		return afterInstance
+	}
+
+	if beforeMatches || !afterMatches {
+		// Skipped times use the offset from before the transition.
+		return beforeInstance
+	}
+
+	return afterInstance
+}
+
+func matchesWallClock(instance time.Time, day int, hour int, minute int) bool {
+	return (instance.Day() == day) && (instance.Hour() == hour) && (instance.Minute() == minute)
+}
+
+// Get the location to interpret clock times in.
+// Defaults to the server's local time.
+func (this *ScheduledTime) getLocation() *time.Location {
+	if this.Timezone == "" {
+		return time.Local
+	}
+
+	location, err := time.LoadLocation(this.Timezone)
+	if err != nil {
+		log.Error("Failed to load timezone for scheduled time.", err, log.NewAttr("timezone", this.Timezone))
+		return time.Local
 	}
 
-	return this.Daily.String()
+	return location
 }
@@ -0,0 +1,374 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// How far ahead (in days) to look for the next time that matches a cron expression
+// (and how many days to check when finding the shortest gap between matching days).
+// This is long enough to cover expressions like "0 0 29 2 1" (Feb 29 on a Monday).
+const CRON_MAX_SEARCH_DAYS = 366 * 30
+
+var cronMacros map[string]string = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames map[string]int = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames map[string]int = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronFields []cronField = []cronField{
+	cronField{"minute", 0, 59, nil},
+	cronField{"hour", 0, 23, nil},
+	cronField{"day of month", 1, 31, nil},
+	cronField{"month", 1, 12, cronMonthNames},
+	// Both 0 and 7 are Sunday.
+	cronField{"day of week", 0, 7, cronDayNames},
+}
+
+// A standard five-field cron expression: "minute hour day-of-month month day-of-week".
+// Each field may be a "*", a value, a range ("1-5"), a step ("*/15" or "0-30/10"), or a comma-separated list of those.
+// Months and days of the week may also use three-letter names ("jan", "mon").
+// The common macros (e.g., "@daily", "@weekly") are also supported.
+// Like standard cron, if both the day of month and day of week are restricted, then a day matching either will run.
+type CronSpec string
+
+type cronSchedule struct {
+	minutes     [60]bool
+	hours       [24]bool
+	daysOfMonth [32]bool
+	months      [13]bool
+	daysOfWeek  [7]bool
+
+	// Whether the day of month/week fields start with a "*" (are unrestricted).
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+func (this CronSpec) Validate() error {
+	if this.IsEmpty() {
+		return nil
+	}
+
+	schedule, err := this.parse()
+	if err != nil {
+		return err
+	}
+
+	_, ok := schedule.nextTime(timestamp.Zero(), time.UTC)
+	if !ok {
+		return fmt.Errorf("Cron expression '%s' never matches a valid date.", string(this))
+	}
+
+	return nil
+}
+
+func (this CronSpec) IsEmpty() bool {
+	return (strings.TrimSpace(string(this)) == "")
+}
+
+// Cron expressions do not have a fixed period, so use the shortest time between consecutive runs (on the wall clock).
+func (this CronSpec) TotalMSecs() int64 {
+	schedule, err := this.parse()
+	if err != nil {
+		log.Error("Failed to parse cron spec.", err, log.NewAttr("contents", string(this)))
+		return 0
+	}
+
+	return schedule.minPeriodMSecs()
+}
+
+func (this CronSpec) ComputeNextTime(startTime timestamp.Timestamp) timestamp.Timestamp {
+	return this.computeNextTimeInLocation(startTime, time.Local)
+}
+
+func (this CronSpec) String() string {
+	return fmt.Sprintf("cron '%s'", strings.TrimSpace(string(this)))
+}
+
+func (this CronSpec) computeNextTimeInLocation(startTime timestamp.Timestamp, location *time.Location) timestamp.Timestamp {
+	schedule, err := this.parse()
+	if err != nil {
+		log.Error("Failed to parse cron spec.", err, log.NewAttr("contents", string(this)))
+		return startTime + timestamp.FromMSecs(24*60*60*1000)
+	}
+
+	nextTime, ok := schedule.nextTime(startTime, location)
+	if !ok {
+		log.Error("Failed to find next time for cron spec.", log.NewAttr("contents", string(this)))
+		return startTime + timestamp.FromMSecs(24*60*60*1000)
+	}
+
+	return nextTime
+}
+
+func (this CronSpec) parse() (*cronSchedule, error) {
+	contents := strings.ToLower(strings.TrimSpace(string(this)))
+
+	if strings.HasPrefix(contents, "@") {
+		expanded, ok := cronMacros[contents]
+		if !ok {
+			return nil, fmt.Errorf("Unknown cron macro: '%s'.", contents)
+		}
+
+		contents = expanded
+	}
+
+	parts := strings.Fields(contents)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("Cron expression must have %d fields (minute, hour, day of month, month, day of week), found %d: '%s'.",
+			len(cronFields), len(parts), string(this))
+	}
+
+	values := make([][]bool, len(cronFields))
+	for i, field := range cronFields {
+		fieldValues, err := field.parse(parts[i])
+		if err != nil {
+			return nil, fmt.Errorf("Cron expression '%s' has an invalid %s field: '%w'.", string(this), field.name, err)
+		}
+
+		values[i] = fieldValues
+	}
+
+	schedule := &cronSchedule{
+		anyDayOfMonth: strings.HasPrefix(parts[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(parts[4], "*"),
+	}
+
+	copy(schedule.minutes[:], values[0])
+	copy(schedule.hours[:], values[1])
+	copy(schedule.daysOfMonth[:], values[2])
+	copy(schedule.months[:], values[3])
+	copy(schedule.daysOfWeek[:], values[4][0:7])
+
+	// Sunday may also be 7.
+	if values[4][7] {
+		schedule.daysOfWeek[0] = true
+	}
+
+	return schedule, nil
+}
+
+// Parse a single field, returning a list (indexed by value) of which values match.
+func (this *cronField) parse(text string) ([]bool, error) {
+	values := make([]bool, this.max+1)
+
+	for _, part := range strings.Split(text, ",") {
+		rangeText := part
+		step := 1
+
+		index := strings.Index(part, "/")
+		if index >= 0 {
+			rangeText = part[:index]
+
+			var err error
+			step, err = strconv.Atoi(part[index+1:])
+			if (err != nil) || (step <= 0) {
+				return nil, fmt.Errorf("Step must be a positive integer, found '%s'.", part[index+1:])
+			}
+		}
+
+		start := this.min
+		end := this.max
+
+		if rangeText != "*" {
+			var err error
+
+			bounds := strings.SplitN(rangeText, "-", 2)
+
+			start, err = this.parseValue(bounds[0])
+			if err != nil {
+				return nil, err
+			}
+
+			if len(bounds) == 2 {
+				end, err = this.parseValue(bounds[1])
+				if err != nil {
+					return nil, err
+				}
+			} else if index < 0 {
+				// A single value (without a step).
+				end = start
+			}
+
+			if start > end {
+				return nil, fmt.Errorf("Range start (%d) is after its end (%d).", start, end)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+
+	return values, nil
+}
+
+func (this *cronField) parseValue(text string) (int, error) {
+	value, ok := this.names[text]
+	if ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse value '%s'.", text)
+	}
+
+	if (value < this.min) || (value > this.max) {
+		return 0, fmt.Errorf("Value %d is out of range [%d, %d].", value, this.min, this.max)
+	}
+
+	return value, nil
+}
+
+func (this *cronSchedule) matchesDay(date time.Time) bool {
+	if !this.months[int(date.Month())] {
+		return false
+	}
+
+	dayOfMonth := this.daysOfMonth[date.Day()]
+	dayOfWeek := this.daysOfWeek[int(date.Weekday())]
+
+	// Standard cron behavior: when both day fields are restricted, either may match.
+	if !this.anyDayOfMonth && !this.anyDayOfWeek {
+		return (dayOfMonth || dayOfWeek)
+	}
+
+	return (dayOfMonth && dayOfWeek)
+}
+
+// Get the shortest time between consecutive runs on the wall clock (ignoring DST transitions).
+// The shortest gap is either between two times in the same day,
+// or between the last time of a day and the first time of the next matching day.
+func (this *cronSchedule) minPeriodMSecs() int64 {
+	// Matching times of the day (in minutes).
+	minutesOfDay := make([]int, 0)
+	for hour := 0; hour < len(this.hours); hour++ {
+		if !this.hours[hour] {
+			continue
+		}
+
+		for minute := 0; minute < len(this.minutes); minute++ {
+			if this.minutes[minute] {
+				minutesOfDay = append(minutesOfDay, (hour*60)+minute)
+			}
+		}
+	}
+
+	if len(minutesOfDay) == 0 {
+		return 0
+	}
+
+	minMinutes := 0
+	for i := 1; i < len(minutesOfDay); i++ {
+		delta := minutesOfDay[i] - minutesOfDay[i-1]
+		if (minMinutes == 0) || (delta < minMinutes) {
+			minMinutes = delta
+		}
+	}
+
+	minDays := this.minDayGap()
+	if minDays > 0 {
+		delta := (minDays * 24 * 60) - (minutesOfDay[len(minutesOfDay)-1] - minutesOfDay[0])
+		if (minMinutes == 0) || (delta < minMinutes) {
+			minMinutes = delta
+		}
+	}
+
+	return int64(minMinutes) * 60 * 1000
+}
+
+// Get the fewest days between two consecutive matching days (or zero if fewer than two days match).
+func (this *cronSchedule) minDayGap() int {
+	// Start at a fixed date so the gap is stable.
+	startDate := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	minDays := 0
+	previousOffset := -1
+
+	for dayOffset := 0; dayOffset < CRON_MAX_SEARCH_DAYS; dayOffset++ {
+		if !this.matchesDay(startDate.AddDate(0, 0, dayOffset)) {
+			continue
+		}
+
+		if previousOffset >= 0 {
+			days := dayOffset - previousOffset
+			if (minDays == 0) || (days < minDays) {
+				minDays = days
+			}
+		}
+
+		previousOffset = dayOffset
+	}
+
+	return minDays
+}
+
+// Get the first matching time at or after the start time.
+// Times are matched against the wall clock of the given location (see wallClockTime() for DST transitions).
+// Times in a day are checked in wall clock order, which is also the order they occur in.
+// A time skipped by a DST transition is shifted to the same instant as a later time
+// (e.g., 02:30 and 03:30 when clocks spring forward from 02:00 to 03:00), but will only run once.
+func (this *cronSchedule) nextTime(startTime timestamp.Timestamp, location *time.Location) (timestamp.Timestamp, bool) {
+	startGoTime := startTime.ToGoTime().In(location)
+
+	// Cron runs on minute boundaries, so round the start time up to the next minute.
+	startMinute := startGoTime.Truncate(time.Minute)
+	if startMinute.Before(startGoTime) {
+		startMinute = startMinute.Add(time.Minute)
+	}
+
+	// Walk the calendar dates (in UTC to avoid any DST issues when stepping days).
+	startDate := time.Date(startGoTime.Year(), startGoTime.Month(), startGoTime.Day(), 0, 0, 0, 0, time.UTC)
+
+	for dayOffset := 0; dayOffset < CRON_MAX_SEARCH_DAYS; dayOffset++ {
+		date := startDate.AddDate(0, 0, dayOffset)
+		if !this.matchesDay(date) {
+			continue
+		}
+
+		for hour := 0; hour < len(this.hours); hour++ {
+			if !this.hours[hour] {
+				continue
+			}
+
+			for minute := 0; minute < len(this.minutes); minute++ {
+				if !this.minutes[minute] {
+					continue
+				}
+
+				candidate := wallClockTime(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, location)
+				if !candidate.Before(startMinute) {
+					return timestamp.FromGoTime(candidate), true
+				}
+			}
+		}
+	}
+
+	return timestamp.Zero(), false
+}
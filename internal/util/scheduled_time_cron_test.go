@@ -0,0 +1,327 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestCronSpecValid(test *testing.T) {
+	testCases := []struct {
+		spec       CronSpec
+		nextTime   timestamp.Timestamp
+		totalMSecs int64
+		string     string
+	}{
+		{"* * * * *", utcTime(2023, time.October, 1, 0, 0), NSECS_PER_MIN / NSECS_PER_MSEC, "cron '* * * * *'"},
+		{"0 7 * * 1-5", utcTime(2023, time.October, 2, 7, 0), NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 7 * * 1-5'"},
+		{"0 7 * * MON-FRI", utcTime(2023, time.October, 2, 7, 0), NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 7 * * MON-FRI'"},
+		{"0 0 * * 0", utcTime(2023, time.October, 1, 0, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 0 * * 0'"},
+		{"0 0 * * 7", utcTime(2023, time.October, 1, 0, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 0 * * 7'"},
+		{" 0 0 * * sun ", utcTime(2023, time.October, 1, 0, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 0 * * sun'"},
+		{"30 8 15 * *", utcTime(2023, time.October, 15, 8, 30), 28 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '30 8 15 * *'"},
+		{"*/15 9-17 * * mon-fri", utcTime(2023, time.October, 2, 9, 0), 15 * NSECS_PER_MIN / NSECS_PER_MSEC, "cron '*/15 9-17 * * mon-fri'"},
+		{"5/20 * * * *", utcTime(2023, time.October, 1, 0, 5), 20 * NSECS_PER_MIN / NSECS_PER_MSEC, "cron '5/20 * * * *'"},
+		{"0 0 1,15 * *", utcTime(2023, time.October, 1, 0, 0), 14 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 0 1,15 * *'"},
+		{"0 0 1 jan,jul *", utcTime(2024, time.January, 1, 0, 0), 181 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 0 1 jan,jul *'"},
+		{"0 0 29 2 *", utcTime(2024, time.February, 29, 0, 0), 1461 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 0 29 2 *'"},
+
+		// When both the day of month and day of week are restricted, either may match.
+		{"0 12 13 * 5", utcTime(2023, time.October, 6, 12, 0), NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 12 13 * 5'"},
+		// When only one is restricted, both must match.
+		{"0 12 */2 * 5", utcTime(2023, time.October, 13, 12, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '0 12 */2 * 5'"},
+
+		// Macros.
+		{"@hourly", utcTime(2023, time.October, 1, 0, 0), NSECS_PER_HOUR / NSECS_PER_MSEC, "cron '@hourly'"},
+		{"@daily", utcTime(2023, time.October, 1, 0, 0), NSECS_PER_DAY / NSECS_PER_MSEC, "cron '@daily'"},
+		{"@weekly", utcTime(2023, time.October, 1, 0, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '@weekly'"},
+		{"@monthly", utcTime(2023, time.October, 1, 0, 0), 28 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '@monthly'"},
+		{"@yearly", utcTime(2024, time.January, 1, 0, 0), 365 * NSECS_PER_DAY / NSECS_PER_MSEC, "cron '@yearly'"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.spec.Validate()
+		if err != nil {
+			test.Errorf("Case %d ('%s'): Failed to validate: '%v'.", i, testCase.spec, err)
+			continue
+		}
+
+		schedule := &ScheduledTime{Cron: testCase.spec, Timezone: "UTC"}
+
+		err = schedule.Validate()
+		if err != nil {
+			test.Errorf("Case %d ('%s'): Failed to validate scheduled time: '%v'.", i, testCase.spec, err)
+			continue
+		}
+
+		nextTime := schedule.ComputeNextTime(baseTime)
+		if testCase.nextTime != nextTime {
+			test.Errorf("Case %d ('%s'): Incorrect next time. Expected: '%s', Actual: '%s'.",
+				i, testCase.spec, testCase.nextTime.SafeString(), nextTime.SafeString())
+			continue
+		}
+
+		totalMSecs := schedule.TotalMSecs()
+		if testCase.totalMSecs != totalMSecs {
+			test.Errorf("Case %d ('%s'): Incorrect total msecs. Expected: %d, Actual: %d.", i, testCase.spec, testCase.totalMSecs, totalMSecs)
+			continue
+		}
+
+		if testCase.string != testCase.spec.String() {
+			test.Errorf("Case %d ('%s'): Incorrect string. Expected: '%s', Actual: '%s'.", i, testCase.spec, testCase.string, testCase.spec.String())
+			continue
+		}
+	}
+}
+
+func TestCronSpecInvalid(test *testing.T) {
+	testCases := []CronSpec{
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/a * * * *",
+		"5-1 * * * *",
+		"1-2-3 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"1, * * * *",
+		"@reboot",
+		// Never matches.
+		"0 0 30 2 *",
+		"0 0 31 4,6,9,11 *",
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.Validate()
+		if err == nil {
+			test.Errorf("Case %d ('%s'): Did not get an expected error.", i, testCase)
+			continue
+		}
+	}
+}
+
+func TestWeeklySpecValid(test *testing.T) {
+	testCases := []struct {
+		spec       *WeeklySpec
+		nextTime   timestamp.Timestamp
+		totalMSecs int64
+		string     string
+	}{
+		{&WeeklySpec{Days: []string{"monday"}, Time: "07:00"}, utcTime(2023, time.October, 2, 7, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "weekly on Monday at 07:00:00"},
+		{&WeeklySpec{Days: []string{"Sun"}}, utcTime(2023, time.October, 1, 0, 0), 7 * NSECS_PER_DAY / NSECS_PER_MSEC, "weekly on Sunday at 00:00:00"},
+		{&WeeklySpec{Days: []string{"fri", "MON"}}, utcTime(2023, time.October, 2, 0, 0), 3 * NSECS_PER_DAY / NSECS_PER_MSEC, "weekly on Monday, Friday at 00:00:00"},
+		{
+			&WeeklySpec{Days: []string{"weekdays"}, Time: "07:00"},
+			utcTime(2023, time.October, 2, 7, 0),
+			NSECS_PER_DAY / NSECS_PER_MSEC,
+			"weekly on Monday, Tuesday, Wednesday, Thursday, Friday at 07:00:00",
+		},
+		{
+			&WeeklySpec{Days: []string{"weekends", "saturday"}, Time: "23:00:30"},
+			timestamp.FromGoTime(time.Date(2023, time.October, 1, 23, 0, 30, 0, time.UTC)),
+			NSECS_PER_DAY / NSECS_PER_MSEC,
+			"weekly on Sunday, Saturday at 23:00:30",
+		},
+	}
+
+	for i, testCase := range testCases {
+		schedule := &ScheduledTime{Weekly: testCase.spec, Timezone: "UTC"}
+
+		err := schedule.Validate()
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			continue
+		}
+
+		nextTime := schedule.ComputeNextTime(baseTime)
+		if testCase.nextTime != nextTime {
+			test.Errorf("Case %d: Incorrect next time. Expected: '%s', Actual: '%s'.", i, testCase.nextTime.SafeString(), nextTime.SafeString())
+			continue
+		}
+
+		totalMSecs := schedule.TotalMSecs()
+		if testCase.totalMSecs != totalMSecs {
+			test.Errorf("Case %d: Incorrect total msecs. Expected: %d, Actual: %d.", i, testCase.totalMSecs, totalMSecs)
+			continue
+		}
+
+		if testCase.string != testCase.spec.String() {
+			test.Errorf("Case %d: Incorrect string. Expected: '%s', Actual: '%s'.", i, testCase.string, testCase.spec.String())
+			continue
+		}
+	}
+}
+
+func TestScheduledTimeValidateCalendar(test *testing.T) {
+	testCases := []struct {
+		schedule *ScheduledTime
+		valid    bool
+		string   string
+	}{
+		{&ScheduledTime{Cron: "0 7 * * 1-5", Timezone: "America/New_York"}, true, "cron '0 7 * * 1-5' (America/New_York)"},
+		{&ScheduledTime{Daily: "07:00", Timezone: "Europe/Paris"}, true, "daily at 07:00:00 (Europe/Paris)"},
+		{&ScheduledTime{Weekly: &WeeklySpec{Days: []string{"sunday"}}}, true, "weekly on Sunday at 00:00:00"},
+
+		{&ScheduledTime{}, false, ""},
+		{&ScheduledTime{Timezone: "UTC"}, false, ""},
+		{&ScheduledTime{Weekly: &WeeklySpec{}}, false, ""},
+		{&ScheduledTime{Cron: "* * * * *", Daily: "07:00"}, false, ""},
+		{&ScheduledTime{Cron: "* * * * *", Weekly: &WeeklySpec{Days: []string{"mon"}}}, false, ""},
+		{&ScheduledTime{Every: DurationSpec{Hours: 1}, Weekly: &WeeklySpec{Days: []string{"mon"}}}, false, ""},
+		{&ScheduledTime{Every: DurationSpec{Hours: 1}, Timezone: "UTC"}, false, ""},
+		{&ScheduledTime{Daily: "07:00", Timezone: "ZZZ/ZZZ"}, false, ""},
+		{&ScheduledTime{Cron: "* * *"}, false, ""},
+		{&ScheduledTime{Weekly: &WeeklySpec{Days: []string{"funday"}}}, false, ""},
+		{&ScheduledTime{Weekly: &WeeklySpec{Days: []string{"mon"}, Time: "25:00"}}, false, ""},
+		{&ScheduledTime{Weekly: &WeeklySpec{Time: "07:00"}}, false, ""},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.schedule.Validate()
+		if testCase.valid && (err != nil) {
+			test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			continue
+		}
+
+		if !testCase.valid {
+			if err == nil {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+			}
+
+			continue
+		}
+
+		if testCase.string != testCase.schedule.String() {
+			test.Errorf("Case %d: Incorrect string. Expected: '%s', Actual: '%s'.", i, testCase.string, testCase.schedule.String())
+			continue
+		}
+	}
+}
+
+// Ensure that clock times stay on the wall clock across DST transitions.
+// In 2024, New York springs forward at 2024-03-10 02:00 (to 03:00) and falls back at 2024-11-03 02:00 (to 01:00).
+func TestScheduledTimeComputeNextTimeDST(test *testing.T) {
+	testCases := []struct {
+		schedule  *ScheduledTime
+		startTime string
+		expected  string
+	}{
+		// Normal days, EST (UTC-5) and EDT (UTC-4).
+		{&ScheduledTime{Daily: "09:00"}, "2024-01-10T00:00:00Z", "2024-01-10T14:00:00Z"},
+		{&ScheduledTime{Daily: "09:00"}, "2024-07-10T00:00:00Z", "2024-07-10T13:00:00Z"},
+
+		// A day after the spring transition is only 23 hours later.
+		{&ScheduledTime{Daily: "09:00"}, "2024-03-09T14:00:00.001Z", "2024-03-10T13:00:00Z"},
+		{&ScheduledTime{Cron: "0 9 * * *"}, "2024-03-09T14:00:00.001Z", "2024-03-10T13:00:00Z"},
+		{&ScheduledTime{Weekly: &WeeklySpec{Days: []string{"sunday"}, Time: "09:00"}}, "2024-03-09T00:00:00Z", "2024-03-10T13:00:00Z"},
+
+		// A day after the fall transition is 25 hours later.
+		{&ScheduledTime{Daily: "09:00"}, "2024-11-02T13:00:00.001Z", "2024-11-03T14:00:00Z"},
+		{&ScheduledTime{Cron: "0 9 * * *"}, "2024-11-02T13:00:00.001Z", "2024-11-03T14:00:00Z"},
+
+		// Times skipped by the spring transition run right after it.
+		{&ScheduledTime{Daily: "02:30"}, "2024-03-10T05:00:00Z", "2024-03-10T07:30:00Z"},
+		{&ScheduledTime{Cron: "30 2 * * *"}, "2024-03-10T05:00:00Z", "2024-03-10T07:30:00Z"},
+		{&ScheduledTime{Cron: "0 * * * *"}, "2024-03-10T06:30:00Z", "2024-03-10T07:00:00Z"},
+		{&ScheduledTime{Cron: "0 * * * *"}, "2024-03-10T07:00:00.001Z", "2024-03-10T08:00:00Z"},
+		// Skipped times will not run before earlier times.
+		{&ScheduledTime{Cron: "30 2 * * *"}, "2024-03-10T07:30:00.001Z", "2024-03-11T06:30:00Z"},
+		{&ScheduledTime{Cron: "30 2,3 * * *"}, "2024-03-10T05:00:00Z", "2024-03-10T07:30:00Z"},
+		// Skipped times that land on an existing time only run once.
+		{&ScheduledTime{Cron: "*/30 * * * *"}, "2024-03-10T06:30:00.001Z", "2024-03-10T07:00:00Z"},
+		{&ScheduledTime{Cron: "*/30 * * * *"}, "2024-03-10T07:00:00.001Z", "2024-03-10T07:30:00Z"},
+		{&ScheduledTime{Cron: "*/30 * * * *"}, "2024-03-10T07:30:00.001Z", "2024-03-10T08:00:00Z"},
+
+		// Times repeated by the fall transition only run the first time.
+		{&ScheduledTime{Daily: "01:30"}, "2024-11-03T04:00:00Z", "2024-11-03T05:30:00Z"},
+		{&ScheduledTime{Daily: "01:30"}, "2024-11-03T05:30:00.001Z", "2024-11-04T06:30:00Z"},
+		{&ScheduledTime{Cron: "30 1 * * *"}, "2024-11-03T04:00:00Z", "2024-11-03T05:30:00Z"},
+		{&ScheduledTime{Cron: "30 1 * * *"}, "2024-11-03T05:30:00.001Z", "2024-11-04T06:30:00Z"},
+		{&ScheduledTime{Cron: "0 * * * *"}, "2024-11-03T05:00:00.001Z", "2024-11-03T07:00:00Z"},
+
+		// An explicit timezone overrides the server's timezone.
+		{&ScheduledTime{Daily: "09:00", Timezone: "UTC"}, "2024-01-10T00:00:00Z", "2024-01-10T09:00:00Z"},
+		{&ScheduledTime{Cron: "0 9 * * *", Timezone: "Asia/Tokyo"}, "2024-01-10T00:00:00.001Z", "2024-01-11T00:00:00Z"},
+
+		// Sydney falls back from 03:00 AEDT (+11) to 02:00 AEST (+10), so 02:30 happens twice.
+		// The first occurrence is used.
+		{&ScheduledTime{Daily: "02:30", Timezone: "Australia/Sydney"}, "2026-04-04T12:00:00Z", "2026-04-04T15:30:00Z"},
+		{&ScheduledTime{Daily: "02:30", Timezone: "Australia/Sydney"}, "2026-04-04T15:30:00.001Z", "2026-04-05T16:30:00Z"},
+		{&ScheduledTime{Cron: "30 2 * * *", Timezone: "Australia/Sydney"}, "2026-04-04T12:00:00Z", "2026-04-04T15:30:00Z"},
+
+		// Sydney springs forward from 02:00 AEST (+10) to 03:00 AEDT (+11), so 02:30 is shifted to 03:30.
+		{&ScheduledTime{Daily: "02:30", Timezone: "Australia/Sydney"}, "2026-10-03T12:00:00Z", "2026-10-03T16:30:00Z"},
+	}
+
+	// Use New York as the server's timezone.
+	oldLocal := time.Local
+	defer func() {
+		time.Local = oldLocal
+	}()
+
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		test.Fatalf("Failed to load timezone: '%v'.", err)
+	}
+
+	time.Local = location
+
+	for i, testCase := range testCases {
+		err := testCase.schedule.Validate()
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			continue
+		}
+
+		startTime := timestamp.MustGuessFromString(testCase.startTime)
+		expected := timestamp.MustGuessFromString(testCase.expected)
+
+		actual := testCase.schedule.ComputeNextTime(startTime)
+		if expected != actual {
+			test.Errorf("Case %d (%s): Incorrect next time. Expected: '%s', Actual: '%s'.",
+				i, testCase.schedule.String(), expected.SafeString(), actual.SafeString())
+			continue
+		}
+	}
+}
+
+// The period of a cron expression is measured on the wall clock, so DST transitions do not shorten it.
+func TestCronSpecTotalMSecsDST(test *testing.T) {
+	testCases := []struct {
+		spec     CronSpec
+		expected int64
+	}{
+		{"0 9 * * *", NSECS_PER_DAY / NSECS_PER_MSEC},
+		{"30 2 * * *", NSECS_PER_DAY / NSECS_PER_MSEC},
+		{"*/30 * * * *", 30 * NSECS_PER_MIN / NSECS_PER_MSEC},
+		{"0 23 * * 5", 7 * NSECS_PER_DAY / NSECS_PER_MSEC},
+		{"0 1,23 * * *", 2 * NSECS_PER_HOUR / NSECS_PER_MSEC},
+	}
+
+	for i, testCase := range testCases {
+		schedule := &ScheduledTime{Cron: testCase.spec, Timezone: "America/New_York"}
+
+		err := schedule.Validate()
+		if err != nil {
+			test.Errorf("Case %d ('%s'): Failed to validate: '%v'.", i, testCase.spec, err)
+			continue
+		}
+
+		actual := schedule.TotalMSecs()
+		if testCase.expected != actual {
+			test.Errorf("Case %d ('%s'): Incorrect total msecs. Expected: %d, Actual: %d.", i, testCase.spec, testCase.expected, actual)
+		}
+	}
+}
+
+func utcTime(year int, month time.Month, day int, hour int, minute int) timestamp.Timestamp {
+	return timestamp.FromGoTime(time.Date(year, month, day, hour, minute, 0, 0, time.UTC))
+}
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Names that can be used for days of the week (as well as the full day names, e.g., "monday").
+var weeklyDayNames map[string][]time.Weekday = map[string][]time.Weekday{
+	"sun":      []time.Weekday{time.Sunday},
+	"mon":      []time.Weekday{time.Monday},
+	"tue":      []time.Weekday{time.Tuesday},
+	"wed":      []time.Weekday{time.Wednesday},
+	"thu":      []time.Weekday{time.Thursday},
+	"fri":      []time.Weekday{time.Friday},
+	"sat":      []time.Weekday{time.Saturday},
+	"weekdays": []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": []time.Weekday{time.Saturday, time.Sunday},
+}
+
+// Run on specific days of the week at a time of day.
+type WeeklySpec struct {
+	// Days may be full names ("monday"), three-letter names ("mon"), "weekdays", or "weekends".
+	Days []string `json:"days,omitempty"`
+
+	// Defaults to midnight.
+	Time TimeOfDaySpec `json:"time,omitempty"`
+}
+
+func (this *WeeklySpec) Validate() error {
+	if this == nil {
+		return nil
+	}
+
+	if this.IsEmpty() {
+		if this.Time.IsEmpty() {
+			return nil
+		}
+
+		return fmt.Errorf("Weekly schedule has a time, but no days.")
+	}
+
+	_, err := this.getWeekdays()
+	if err != nil {
+		return err
+	}
+
+	err = this.Time.Validate()
+	if err != nil {
+		return fmt.Errorf("Weekly schedule time is invalid: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *WeeklySpec) IsEmpty() bool {
+	return ((this == nil) || (len(this.Days) == 0))
+}
+
+// The shortest time between two of the chosen days.
+func (this *WeeklySpec) TotalMSecs() int64 {
+	weekdays, err := this.getWeekdays()
+	if (err != nil) || (len(weekdays) == 0) {
+		return 0
+	}
+
+	minDays := 7
+	for i, weekday := range weekdays {
+		nextWeekday := weekdays[(i+1)%len(weekdays)]
+
+		days := (int(nextWeekday) - int(weekday) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+
+		minDays = min(minDays, days)
+	}
+
+	return int64(minDays) * 24 * 60 * 60 * 1000
+}
+
+func (this *WeeklySpec) ComputeNextTime(startTime timestamp.Timestamp) timestamp.Timestamp {
+	return this.computeNextTimeInLocation(startTime, time.Local)
+}
+
+func (this *WeeklySpec) String() string {
+	weekdays, err := this.getWeekdays()
+	if err != nil {
+		log.Error("Failed to parse weekly spec days.", err, log.NewAttr("days", this.Days))
+	}
+
+	names := make([]string, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		names = append(names, weekday.String())
+	}
+
+	return fmt.Sprintf("weekly on %s at %s", strings.Join(names, ", "), this.Time.timeString())
+}
+
+func (this *WeeklySpec) computeNextTimeInLocation(startTime timestamp.Timestamp, location *time.Location) timestamp.Timestamp {
+	weekdays, err := this.getWeekdays()
+	if (err != nil) || (len(weekdays) == 0) {
+		log.Error("Failed to parse weekly spec days.", err, log.NewAttr("days", this.Days))
+		weekdays = []time.Weekday{time.Sunday}
+	}
+
+	timeOfDay := this.Time.mustGetGoTime()
+	startGoTime := startTime.ToGoTime().In(location)
+
+	// Check the next week (plus today) for a matching day.
+	for dayOffset := 0; dayOffset <= 7; dayOffset++ {
+		// Walk the calendar dates in UTC to avoid any DST issues when stepping days.
+		date := time.Date(startGoTime.Year(), startGoTime.Month(), startGoTime.Day()+dayOffset, 0, 0, 0, 0, time.UTC)
+		if !slices.Contains(weekdays, date.Weekday()) {
+			continue
+		}
+
+		nextTime := wallClockTime(
+			date.Year(), date.Month(), date.Day(),
+			timeOfDay.Hour(), timeOfDay.Minute(), timeOfDay.Second(), timeOfDay.Nanosecond(),
+			location)
+
+		if !nextTime.Before(startGoTime) {
+			return timestamp.FromGoTime(nextTime)
+		}
+	}
+
+	// Unreachable, every chosen day will come up within a week.
+	return startTime
+}
+
+// Get the sorted (and deduplicated) days of the week for this spec.
+func (this *WeeklySpec) getWeekdays() ([]time.Weekday, error) {
+	weekdays := make([]time.Weekday, 0, 7)
+
+	for _, day := range this.Days {
+		name := strings.ToLower(strings.TrimSpace(day))
+
+		// Allow full day names.
+		if (len(name) > 3) && strings.HasSuffix(name, "day") && (name != "weekdays") {
+			name = name[0:3]
+		}
+
+		days, ok := weeklyDayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown day of the week: '%s'.", day)
+		}
+
+		for _, weekday := range days {
+			if !slices.Contains(weekdays, weekday) {
+				weekdays = append(weekdays, weekday)
+			}
+		}
+	}
+
+	slices.Sort(weekdays)
+
+	return weekdays, nil
+}